@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"clean-architecture/config"
+	outboundadapterkafka "clean-architecture/internal/adapter/outbound/kafka"
+	"clean-architecture/internal/domain/service"
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/labstack/gommon/log"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	dlqTopic string
+	dlqLimit int
+	dlqAll   bool
+)
+
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Inspect and replay Kafka dead-letter topics",
+}
+
+var dlqInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "Print dead-letter messages of a topic as JSON",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+		deadLetterService, closeFn := newDeadLetterService(cfg, false)
+		defer closeFn()
+
+		messages, err := deadLetterService.Inspect(context.Background(), dlqTopicOrDefault(cfg), dlqLimit, dlqAll)
+		if err != nil {
+			log.Fatalf("[RunDLQ-1] failed to read dead-letter topic: %v", err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		for _, msg := range messages {
+			if err := encoder.Encode(msg); err != nil {
+				log.Fatalf("[RunDLQ-2] %v", err)
+			}
+		}
+
+		log.Infof("Inspect completed: %d messages", len(messages))
+	},
+}
+
+var dlqReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Publish dead-letter messages back onto their original topic",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+		deadLetterService, closeFn := newDeadLetterService(cfg, true)
+		defer closeFn()
+
+		replayed, err := deadLetterService.Replay(context.Background(), dlqTopicOrDefault(cfg), dlqLimit)
+		if err != nil {
+			log.Errorf("[RunDLQ-3] replay stopped: %v", err)
+		}
+
+		log.Infof("Replay completed: %d messages replayed", replayed)
+	},
+}
+
+// newDeadLetterService offset replay disimpan di group <KAFKA_CONSUMER_GROUP>-dlq-replay.
+func newDeadLetterService(cfg *config.Config, withProducer bool) (service.DeadLetterServiceInterface, func()) {
+	if cfg.Kafka.ConsumerGroup == "" {
+		log.Fatalf("[RunDLQ-4] KAFKA_CONSUMER_GROUP is required")
+	}
+
+	reader, err := outboundadapterkafka.NewDeadLetterReader(cfg.Kafka.Brokers, cfg.Kafka.ConsumerGroup+"-dlq-replay",
+		cfg.NewKafkaConsumerConfig())
+	if err != nil {
+		log.Fatalf("[RunDLQ-5] failed to init Kafka reader: %v", err)
+	}
+
+	if !withProducer {
+		return service.NewDeadLetterService(reader, nil), func() { reader.Close() }
+	}
+
+	producer, err := outboundadapterkafka.NewKafkaProducer(cfg.Kafka.Brokers, cfg.NewKafkaConfig(),
+		cfg.Kafka.MessageFormat, cfg.Kafka.CloudEventsSource, nil)
+	if err != nil {
+		log.Fatalf("[RunDLQ-6] failed to init Kafka producer: %v", err)
+	}
+
+	return service.NewDeadLetterService(reader, producer), func() {
+		producer.Close()
+		reader.Close()
+	}
+}
+
+func dlqTopicOrDefault(cfg *config.Config) string {
+	if dlqTopic != "" {
+		return dlqTopic
+	}
+	if len(cfg.Kafka.ConsumerTopics) == 0 {
+		log.Fatalf("[RunDLQ-7] --topic is required when KAFKA_CONSUMER_TOPICS is empty")
+	}
+	return cfg.Kafka.ConsumerTopics[0]
+}
+
+func init() {
+	dlqCmd.PersistentFlags().StringVar(&dlqTopic, "topic", "", "main topic whose dead-letter topic is read (default first of KAFKA_CONSUMER_TOPICS)")
+	dlqCmd.PersistentFlags().IntVar(&dlqLimit, "limit", 100, "maximum messages read, 0 for no limit")
+	dlqInspectCmd.Flags().BoolVar(&dlqAll, "all", false, "include messages that were already replayed")
+
+	dlqCmd.AddCommand(dlqInspectCmd)
+	dlqCmd.AddCommand(dlqReplayCmd)
+	rootCmd.AddCommand(dlqCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"clean-architecture/config"
+	outboundadapterminio "clean-architecture/internal/adapter/outbound/minio"
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/internal/domain/service"
+	"context"
+	"time"
+
+	"github.com/labstack/gommon/log"
+
+	"github.com/spf13/cobra"
+)
+
+var eraseBatchSize int
+
+var eraseCmd = &cobra.Command{
+	Use:   "erase",
+	Short: "Anonymize users whose account deletion grace period has passed",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+
+		if eraseBatchSize <= 0 {
+			log.Fatalf("[RunErase-1] batch-size must be greater than zero")
+		}
+
+		db, err := cfg.ConnectionPostgres()
+		if err != nil {
+			log.Fatalf("[RunErase-2] failed to connect to DB Gorm: %v", err)
+		}
+
+		initMinio, err := cfg.InitMinio()
+		if err != nil {
+			log.Fatalf("[RunErase-3] failed to connect Minio: %v", err)
+		}
+
+		privacyService := service.NewPrivacyService(
+			outboundadapterpostgres.NewPrivacyRepository(db.DB),
+			outboundadapterpostgres.NewUserRepository(db.DB),
+			outboundadapterpostgres.NewAddressRepository(db.DB),
+			outboundadapterminio.NewMinioStorage(initMinio, cfg.Minio.Bucket),
+			cfg.RedisConfig(),
+			cfg,
+		)
+
+		now := time.Now()
+		erased, err := privacyService.ProcessDueDeletions(context.Background(), now, eraseBatchSize)
+		if err != nil {
+			log.Errorf("[RunErase-4] some users failed to erase: %v", err)
+		}
+
+		log.Infof("Erase completed: %d users erased, due before %s", erased, now.Format(time.RFC3339))
+	},
+}
+
+func init() {
+	eraseCmd.Flags().IntVar(&eraseBatchSize, "batch-size", 100, "maximum deletion requests processed per run")
+	rootCmd.AddCommand(eraseCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"clean-architecture/config"
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"context"
+	"time"
+
+	"github.com/labstack/gommon/log"
+
+	"github.com/spf13/cobra"
+)
+
+var purgeRetentionDays int
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Hard delete soft deleted customers & roles older than the retention window",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+
+		retentionDays := purgeRetentionDays
+		if !cmd.Flags().Changed("retention-days") && cfg.App.SoftDeleteRetentionDays > 0 {
+			retentionDays = cfg.App.SoftDeleteRetentionDays
+		}
+
+		if retentionDays < 0 {
+			log.Fatalf("[RunPurge-1] retention-days must not be negative")
+		}
+
+		db, err := cfg.ConnectionPostgres()
+		if err != nil {
+			log.Fatalf("[RunPurge-2] failed to connect to DB Gorm: %v", err)
+		}
+
+		ctx := context.Background()
+		deletedBefore := time.Now().AddDate(0, 0, -retentionDays)
+
+		userRepo := outboundadapterpostgres.NewUserRepository(db.DB)
+		roleRepo := outboundadapterpostgres.NewRoleRepository(db.DB)
+
+		purgedUsers, err := userRepo.PurgeDeletedCustomers(ctx, deletedBefore)
+		if err != nil {
+			log.Fatalf("[RunPurge-3] failed to purge customers: %v", err)
+		}
+
+		purgedRoles, err := roleRepo.PurgeDeleted(ctx, deletedBefore)
+		if err != nil {
+			log.Fatalf("[RunPurge-4] failed to purge roles: %v", err)
+		}
+
+		log.Infof("Purge completed: %d customers, %d roles deleted before %s", purgedUsers, purgedRoles, deletedBefore.Format(time.RFC3339))
+	},
+}
+
+func init() {
+	purgeCmd.Flags().IntVar(&purgeRetentionDays, "retention-days", 30, "hard delete records soft deleted more than N days ago")
+	rootCmd.AddCommand(purgeCmd)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"clean-architecture/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Consume Kafka events (notification delivery status)",
+	Run: func(cmd *cobra.Command, args []string) {
+		app.RunWorker()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
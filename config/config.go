@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/spf13/viper"
@@ -9,11 +10,16 @@ import (
 type App struct {
 	AppPort       string `json:"app_port"`
 	AppEnv        string `json:"app_env"`
+	AppName       string `json:"app_name"`
 	PrefixURL     string `json:"prefix_url"`
 	ServerTimeOut int    `json:"server_timeout"`
 	JwtSecretKey  string `json:"jwt_secret_key"`
 	JwtIssuer     string `json:"jwt_issuer"`
 	UrlFrontFE    string `json:"url_front_fe"`
+
+	SoftDeleteRetentionDays  int    `json:"soft_delete_retention_days"`
+	PhoneDefaultCountryCode  string `json:"phone_default_country_code"`
+	AccountDeletionGraceDays int    `json:"account_deletion_grace_days"`
 }
 
 type PsqlDB struct {
@@ -37,6 +43,59 @@ type Kafka struct {
 	TimeoutInMS int      `json:"timeoutInMS"`
 	MaxRetry    int      `json:"maxRetry"`
 	Topic       string   `json:"topic"`
+	EventTopic  string   `json:"eventTopic"`
+
+	// MessageFormat legacy, cloudevents-structured atau cloudevents-binary
+	MessageFormat     string `json:"messageFormat"`
+	CloudEventsSource string `json:"cloudEventsSource"`
+
+	// Serializer json, protobuf atau avro (butuh cloudevents-binary), kosong berarti JSON tanpa schema
+	Serializer             string `json:"serializer"`
+	SchemaRegistryURL      string `json:"schemaRegistryURL"`
+	SchemaRegistryUsername string `json:"schemaRegistryUsername"`
+	SchemaRegistryPassword string `json:"schemaRegistryPassword"`
+
+	// ProducerMode sync (default) atau async, batch dan linger hanya berlaku di mode async
+	ProducerMode        string `json:"producerMode"`
+	ProducerCompression string `json:"producerCompression"`
+	ProducerLingerInMS  int    `json:"producerLingerInMS"`
+	ProducerBatchSize   int    `json:"producerBatchSize"`
+	ProducerBatchBytes  int    `json:"producerBatchBytes"`
+
+	OutboxBatchSize    int `json:"outboxBatchSize"`
+	OutboxIntervalInMS int `json:"outboxIntervalInMS"`
+	OutboxMaxAttempts  int `json:"outboxMaxAttempts"`
+	// OutboxDeliveryTimeoutInMS batas menunggu hasil kirim satu batch, default sama dengan lease claim (1 menit)
+	OutboxDeliveryTimeoutInMS int `json:"outboxDeliveryTimeoutInMS"`
+
+	ConsumerGroup  string   `json:"consumerGroup"`
+	ConsumerTopics []string `json:"consumerTopics"`
+	// ConsumerRetryDelaysInMS delay tiap retry topic, jumlahnya menentukan berapa kali pesan dicoba ulang sebelum ke DLQ
+	ConsumerRetryDelaysInMS []int `json:"consumerRetryDelaysInMS"`
+}
+
+// Notification transport pengiriman notifikasi. Selain kafka, service berjalan tanpa broker dan
+// domain event hanya dicatat di log.
+type Notification struct {
+	// Transport kafka (default), smtp, webhook, stdout atau file
+	Transport   string `json:"transport"`
+	TimeoutInMS int    `json:"timeoutInMS"`
+
+	SMTPHost     string `json:"smtpHost"`
+	SMTPPort     string `json:"smtpPort"`
+	SMTPUsername string `json:"smtpUsername"`
+	SMTPPassword string `json:"smtpPassword"`
+	SMTPFrom     string `json:"smtpFrom"`
+
+	WebhookURL    string `json:"webhookURL"`
+	WebhookSecret string `json:"webhookSecret"`
+
+	FilePath string `json:"filePath"`
+
+	// TemplateDir direktori template yang menimpa template bawaan, DefaultLocale dipakai jika
+	// template tidak tersedia untuk locale user
+	TemplateDir   string `json:"templateDir"`
+	DefaultLocale string `json:"defaultLocale"`
 }
 
 type Minio struct {
@@ -53,6 +112,8 @@ type Config struct {
 	Redis Redis  `json:"redis"`
 	Kafka Kafka  `json:"kafka"`
 	Minio Minio  `json:"minio"`
+
+	Notification Notification `json:"notification"`
 }
 
 func NewConfig() *Config {
@@ -60,11 +121,16 @@ func NewConfig() *Config {
 		App: App{
 			AppPort:       viper.GetString("APP_PORT"),
 			AppEnv:        viper.GetString("APP_ENV"),
+			AppName:       viper.GetString("APP_NAME"),
 			PrefixURL:     viper.GetString("PREFIX_URL"),
 			ServerTimeOut: viper.GetInt("SERVER_TIMEOUT"),
 			JwtSecretKey:  viper.GetString("JWT_SECRET_KEY"),
 			JwtIssuer:     viper.GetString("JWT_ISSUER"),
 			UrlFrontFE:    viper.GetString("URL_FRONT_FE"),
+
+			SoftDeleteRetentionDays:  viper.GetInt("SOFT_DELETE_RETENTION_DAYS"),
+			PhoneDefaultCountryCode:  viper.GetString("PHONE_DEFAULT_COUNTRY_CODE"),
+			AccountDeletionGraceDays: viper.GetInt("ACCOUNT_DELETION_GRACE_DAYS"),
 		},
 		Psql: PsqlDB{
 			Host:      viper.GetString("DATABASE_HOST"),
@@ -85,6 +151,31 @@ func NewConfig() *Config {
 			TimeoutInMS: viper.GetInt("KAFKA_TIMEOUT_IN_MS"),
 			MaxRetry:    viper.GetInt("KAFKA_MAX_RETRY"),
 			Topic:       viper.GetString("KAFKA_TOPIC"),
+			EventTopic:  viper.GetString("KAFKA_EVENT_TOPIC"),
+
+			MessageFormat:     viper.GetString("KAFKA_MESSAGE_FORMAT"),
+			CloudEventsSource: viper.GetString("KAFKA_CLOUDEVENTS_SOURCE"),
+
+			Serializer:             viper.GetString("KAFKA_SERIALIZER"),
+			SchemaRegistryURL:      viper.GetString("KAFKA_SCHEMA_REGISTRY_URL"),
+			SchemaRegistryUsername: viper.GetString("KAFKA_SCHEMA_REGISTRY_USERNAME"),
+			SchemaRegistryPassword: viper.GetString("KAFKA_SCHEMA_REGISTRY_PASSWORD"),
+
+			ProducerMode:        viper.GetString("KAFKA_PRODUCER_MODE"),
+			ProducerCompression: viper.GetString("KAFKA_PRODUCER_COMPRESSION"),
+			ProducerLingerInMS:  viper.GetInt("KAFKA_PRODUCER_LINGER_IN_MS"),
+			ProducerBatchSize:   viper.GetInt("KAFKA_PRODUCER_BATCH_SIZE"),
+			ProducerBatchBytes:  viper.GetInt("KAFKA_PRODUCER_BATCH_BYTES"),
+
+			OutboxBatchSize:           viper.GetInt("KAFKA_OUTBOX_BATCH_SIZE"),
+			OutboxIntervalInMS:        viper.GetInt("KAFKA_OUTBOX_INTERVAL_IN_MS"),
+			OutboxMaxAttempts:         viper.GetInt("KAFKA_OUTBOX_MAX_ATTEMPTS"),
+			OutboxDeliveryTimeoutInMS: viper.GetInt("KAFKA_OUTBOX_DELIVERY_TIMEOUT_IN_MS"),
+
+			ConsumerGroup:  viper.GetString("KAFKA_CONSUMER_GROUP"),
+			ConsumerTopics: splitNonEmpty(viper.GetString("KAFKA_CONSUMER_TOPICS")),
+
+			ConsumerRetryDelaysInMS: splitInts(viper.GetString("KAFKA_CONSUMER_RETRY_DELAYS_IN_MS")),
 		},
 		Minio: Minio{
 			Endpoint:  viper.GetString("MINIO_ENDPOINT"),
@@ -93,5 +184,45 @@ func NewConfig() *Config {
 			Bucket:    viper.GetString("MINIO_BUCKET"),
 			UseSSL:    viper.GetBool("MINIO_USE_SSL"),
 		},
+		Notification: Notification{
+			Transport:   viper.GetString("NOTIFICATION_TRANSPORT"),
+			TimeoutInMS: viper.GetInt("NOTIFICATION_TIMEOUT_IN_MS"),
+
+			SMTPHost:     viper.GetString("NOTIFICATION_SMTP_HOST"),
+			SMTPPort:     viper.GetString("NOTIFICATION_SMTP_PORT"),
+			SMTPUsername: viper.GetString("NOTIFICATION_SMTP_USERNAME"),
+			SMTPPassword: viper.GetString("NOTIFICATION_SMTP_PASSWORD"),
+			SMTPFrom:     viper.GetString("NOTIFICATION_SMTP_FROM"),
+
+			WebhookURL:    viper.GetString("NOTIFICATION_WEBHOOK_URL"),
+			WebhookSecret: viper.GetString("NOTIFICATION_WEBHOOK_SECRET"),
+
+			FilePath: viper.GetString("NOTIFICATION_FILE_PATH"),
+
+			TemplateDir:   viper.GetString("NOTIFICATION_TEMPLATE_DIR"),
+			DefaultLocale: viper.GetString("NOTIFICATION_DEFAULT_LOCALE"),
+		},
+	}
+}
+
+// splitNonEmpty memecah nilai env yang dipisah koma, nilai kosong diabaikan.
+func splitNonEmpty(value string) []string {
+	var result []string
+	for _, val := range strings.Split(value, ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			result = append(result, val)
+		}
+	}
+	return result
+}
+
+// splitInts seperti splitNonEmpty untuk daftar angka, nilai yang bukan angka positif diabaikan.
+func splitInts(value string) []int {
+	var result []int
+	for _, val := range splitNonEmpty(value) {
+		if number, err := strconv.Atoi(val); err == nil && number > 0 {
+			result = append(result, number)
+		}
 	}
+	return result
 }
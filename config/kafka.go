@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -15,3 +16,45 @@ func (cfg Config) NewKafkaConfig() *sarama.Config {
 	config.Version = sarama.V2_1_0_0
 	return config
 }
+
+// NewKafkaProducerConfig config producer RunServer: kompresi (KAFKA_PRODUCER_COMPRESSION) dan,
+// untuk mode async, batch dikirim saat mencapai KAFKA_PRODUCER_BATCH_SIZE pesan,
+// KAFKA_PRODUCER_BATCH_BYTES bytes atau setelah KAFKA_PRODUCER_LINGER_IN_MS.
+func (cfg Config) NewKafkaProducerConfig() (*sarama.Config, error) {
+	config := cfg.NewKafkaConfig()
+
+	if cfg.Kafka.ProducerCompression != "" {
+		if err := config.Producer.Compression.UnmarshalText([]byte(cfg.Kafka.ProducerCompression)); err != nil {
+			return nil, fmt.Errorf("KAFKA_PRODUCER_COMPRESSION: %w", err)
+		}
+	}
+
+	if cfg.Kafka.ProducerMode == "async" {
+		config.Producer.Return.Errors = true
+		config.Producer.Flush.Frequency = time.Duration(cfg.Kafka.ProducerLingerInMS) * time.Millisecond
+		config.Producer.Flush.Messages = cfg.Kafka.ProducerBatchSize
+		config.Producer.Flush.Bytes = cfg.Kafka.ProducerBatchBytes
+	}
+
+	return config, config.Validate()
+}
+
+// NewKafkaConsumerConfig offset di-commit manual setelah pesan selesai diproses (at-least-once).
+func (cfg Config) NewKafkaConsumerConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	config.Consumer.Offsets.Initial = sarama.OffsetOldest
+	config.Consumer.Offsets.AutoCommit.Enable = false
+	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{sarama.NewBalanceStrategySticky()}
+	config.Version = sarama.V2_1_0_0
+	return config
+}
+
+// KafkaConsumerRetryDelays delay retry topic ke-1, ke-2, dst dari KAFKA_CONSUMER_RETRY_DELAYS_IN_MS.
+func (cfg Config) KafkaConsumerRetryDelays() []time.Duration {
+	delays := make([]time.Duration, 0, len(cfg.Kafka.ConsumerRetryDelaysInMS))
+	for _, val := range cfg.Kafka.ConsumerRetryDelaysInMS {
+		delays = append(delays, time.Duration(val)*time.Millisecond)
+	}
+	return delays
+}
@@ -0,0 +1,330 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/request"
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"clean-architecture/utils/conv"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type addressHandler struct {
+	addressService service.AddressServiceInterface
+}
+
+func NewAddressHandler(addressService service.AddressServiceInterface) inbound.AddressHandlerInterface {
+	return &addressHandler{addressService: addressService}
+}
+
+func (a *addressHandler) GetAll(c echo.Context) error {
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[AddressHandler-1] GetAll", err)
+	}
+	return a.getAll(c, userID, "GetAll")
+}
+
+func (a *addressHandler) GetByID(c echo.Context) error {
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[AddressHandler-1] GetByID", err)
+	}
+	return a.getByID(c, userID, "GetByID")
+}
+
+func (a *addressHandler) Create(c echo.Context) error {
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[AddressHandler-1] Create", err)
+	}
+	return a.create(c, userID, "Create")
+}
+
+func (a *addressHandler) Update(c echo.Context) error {
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[AddressHandler-1] Update", err)
+	}
+	return a.update(c, userID, "Update")
+}
+
+func (a *addressHandler) Delete(c echo.Context) error {
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[AddressHandler-1] Delete", err)
+	}
+	return a.delete(c, userID, "Delete")
+}
+
+func (a *addressHandler) GetCustomerAddressAll(c echo.Context) error {
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[AddressHandler-1] GetCustomerAddressAll", err)
+	}
+	return a.getAll(c, customerID, "GetCustomerAddressAll")
+}
+
+func (a *addressHandler) GetCustomerAddressByID(c echo.Context) error {
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[AddressHandler-1] GetCustomerAddressByID", err)
+	}
+	return a.getByID(c, customerID, "GetCustomerAddressByID")
+}
+
+func (a *addressHandler) CreateCustomerAddress(c echo.Context) error {
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[AddressHandler-1] CreateCustomerAddress", err)
+	}
+	return a.create(c, customerID, "CreateCustomerAddress")
+}
+
+func (a *addressHandler) UpdateCustomerAddress(c echo.Context) error {
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[AddressHandler-1] UpdateCustomerAddress", err)
+	}
+	return a.update(c, customerID, "UpdateCustomerAddress")
+}
+
+func (a *addressHandler) DeleteCustomerAddress(c echo.Context) error {
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[AddressHandler-1] DeleteCustomerAddress", err)
+	}
+	return a.delete(c, customerID, "DeleteCustomerAddress")
+}
+
+func (a *addressHandler) getAll(c echo.Context, userID int64, method string) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		respAddress = []response.AddressResponse{}
+	)
+
+	results, err := a.addressService.GetAll(ctx, userID)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("address not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[AddressHandler-2] "+method, errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[AddressHandler-2] "+method, err)
+	}
+
+	for _, val := range results {
+		respAddress = append(respAddress, addressResponse(val))
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respAddress
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *addressHandler) getByID(c echo.Context, userID int64, method string) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	addressID, err := conv.StringToInt64(c.Param("address_id"))
+	if err != nil {
+		errBadRequest := errors.New("invalid address ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-2] "+method, errBadRequest)
+	}
+
+	result, err := a.addressService.GetByID(ctx, userID, addressID)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("address not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[AddressHandler-3] "+method, errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[AddressHandler-3] "+method, err)
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = addressResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *addressHandler) create(c echo.Context, userID int64, method string) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.AddressRequest{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-2] "+method, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-3] "+method, err)
+	}
+
+	reqEntity := addressEntityFromRequest(req)
+	reqEntity.UserID = userID
+
+	result, err := a.addressService.Create(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("customer not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[AddressHandler-4] "+method, errNotFound)
+		}
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-4] "+method, errBadRequest)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[AddressHandler-4] "+method, err)
+	}
+
+	resp.Message = "Success"
+	resp.Data = addressResponse(*result)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (a *addressHandler) update(c echo.Context, userID int64, method string) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+		req  = request.AddressRequest{}
+	)
+
+	addressID, err := conv.StringToInt64(c.Param("address_id"))
+	if err != nil {
+		errBadRequest := errors.New("invalid address ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-2] "+method, errBadRequest)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-3] "+method, err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-4] "+method, err)
+	}
+
+	reqEntity := addressEntityFromRequest(req)
+	reqEntity.ID = addressID
+	reqEntity.UserID = userID
+
+	result, err := a.addressService.Update(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("address not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[AddressHandler-5] "+method, errNotFound)
+		}
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-5] "+method, errBadRequest)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[AddressHandler-5] "+method, err)
+	}
+
+	resp.Message = "Success"
+	resp.Data = addressResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *addressHandler) delete(c echo.Context, userID int64, method string) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	addressID, err := conv.StringToInt64(c.Param("address_id"))
+	if err != nil {
+		errBadRequest := errors.New("invalid address ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[AddressHandler-2] "+method, errBadRequest)
+	}
+
+	if err := a.addressService.Delete(ctx, userID, addressID); err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("address not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[AddressHandler-3] "+method, errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[AddressHandler-3] "+method, err)
+	}
+
+	resp.Message = "Address deleted successfully"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// addressOwnerFromToken user ID pemilik alamat dari token (/auth/addresses).
+func addressOwnerFromToken(c echo.Context) (int64, error) {
+	jwtUserData := entity.JwtUserData{}
+
+	user := c.Get("user").(string)
+	if user == "" {
+		return 0, errors.New("data token not found")
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return 0, err
+	}
+
+	if jwtUserData.UserID == 0 {
+		return 0, errors.New("data token not valid")
+	}
+
+	return jwtUserData.UserID, nil
+}
+
+// addressOwnerFromParam customer ID pemilik alamat dari path (/admin/customers/:id/addresses).
+func addressOwnerFromParam(c echo.Context) (int64, int, error) {
+	user := c.Get("user").(string)
+	if user == "" {
+		return 0, http.StatusNotFound, errors.New("data token not found")
+	}
+
+	customerID, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		return 0, http.StatusBadRequest, errors.New("invalid customer ID")
+	}
+
+	return customerID, 0, nil
+}
+
+func addressEntityFromRequest(req request.AddressRequest) entity.AddressEntity {
+	return entity.AddressEntity{
+		Label:         req.Label,
+		RecipientName: req.RecipientName,
+		Phone:         req.Phone,
+		AddressLine:   req.AddressLine,
+		District:      req.District,
+		City:          req.City,
+		Province:      req.Province,
+		PostalCode:    req.PostalCode,
+		Notes:         req.Notes,
+		Lat:           req.Lat,
+		Lng:           req.Lng,
+		IsDefault:     req.IsDefault,
+	}
+}
+
+func addressResponse(val entity.AddressEntity) response.AddressResponse {
+	return response.AddressResponse{
+		ID:            val.ID,
+		Label:         val.Label,
+		RecipientName: val.RecipientName,
+		Phone:         val.Phone,
+		AddressLine:   val.AddressLine,
+		District:      val.District,
+		City:          val.City,
+		Province:      val.Province,
+		PostalCode:    val.PostalCode,
+		Notes:         val.Notes,
+		Lat:           val.Lat,
+		Lng:           val.Lng,
+		IsDefault:     val.IsDefault,
+		CreatedAt:     val.CreatedAt,
+		UpdatedAt:     val.UpdatedAt,
+	}
+}
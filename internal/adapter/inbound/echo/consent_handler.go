@@ -0,0 +1,142 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/request"
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type consentHandler struct {
+	consentService service.ConsentServiceInterface
+}
+
+func NewConsentHandler(consentService service.ConsentServiceInterface) inbound.ConsentHandlerInterface {
+	return &consentHandler{consentService: consentService}
+}
+
+// GetCurrentDocuments versi syarat & ketentuan dan kebijakan privasi yang berlaku, dipakai form sign up.
+func (h *consentHandler) GetCurrentDocuments(c echo.Context) error {
+	var (
+		resp         = response.DefaultResponse{}
+		ctx          = c.Request().Context()
+		respDocument = []response.LegalDocumentResponse{}
+	)
+
+	results, err := h.consentService.GetCurrentDocuments(ctx)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusInternalServerError, "[ConsentHandler-1] GetCurrentDocuments", err)
+	}
+
+	for _, val := range results {
+		respDocument = append(respDocument, legalDocumentResponse(val))
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respDocument
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PublishDocument hanya untuk Super Admin karena versi baru memaksa semua user menyetujui ulang.
+func (h *consentHandler) PublishDocument(c echo.Context) error {
+	var (
+		req         = request.LegalDocumentRequest{}
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entity.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[ConsentHandler-1] PublishDocument", err)
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[ConsentHandler-2] PublishDocument", err)
+	}
+
+	if jwtUserData.RoleName != "Super Admin" {
+		err := errors.New("only Super Admin can publish legal documents")
+		return response.RespondWithError(c, http.StatusForbidden, "[ConsentHandler-3] PublishDocument", err)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[ConsentHandler-4] PublishDocument", err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[ConsentHandler-5] PublishDocument", err)
+	}
+
+	reqEntity := entity.LegalDocumentEntity{
+		DocType: req.DocType,
+		Version: req.Version,
+		URL:     req.URL,
+	}
+	if req.PublishedAt != nil {
+		reqEntity.PublishedAt = *req.PublishedAt
+	}
+
+	result, err := h.consentService.PublishDocument(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "409" {
+			errConflict := errors.New("legal document version already exists")
+			return response.RespondWithError(c, http.StatusConflict, "[ConsentHandler-6] PublishDocument", errConflict)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[ConsentHandler-6] PublishDocument", err)
+	}
+
+	resp.Message = "Success"
+	resp.Data = legalDocumentResponse(*result)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *consentHandler) GetAcceptanceReport(c echo.Context) error {
+	var (
+		resp       = response.DefaultResponse{}
+		ctx        = c.Request().Context()
+		respReport = []response.ConsentReportResponse{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[ConsentHandler-1] GetAcceptanceReport", err)
+	}
+
+	results, err := h.consentService.GetAcceptanceReport(ctx)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusInternalServerError, "[ConsentHandler-2] GetAcceptanceReport", err)
+	}
+
+	for _, val := range results {
+		respReport = append(respReport, response.ConsentReportResponse{
+			LegalDocumentResponse: legalDocumentResponse(val.Document),
+			IsCurrent:             val.IsCurrent,
+			AcceptedCount:         val.AcceptedCount,
+			TotalUsers:            val.TotalUsers,
+			AcceptanceRate:        val.AcceptanceRate,
+		})
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respReport
+	return c.JSON(http.StatusOK, resp)
+}
+
+func legalDocumentResponse(val entity.LegalDocumentEntity) response.LegalDocumentResponse {
+	return response.LegalDocumentResponse{
+		ID:          val.ID,
+		DocType:     val.DocType,
+		Version:     val.Version,
+		URL:         val.URL,
+		PublishedAt: val.PublishedAt,
+	}
+}
@@ -0,0 +1,281 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/request"
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"clean-architecture/utils/conv"
+	"clean-architecture/utils/spreadsheet"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// file dengan baris lebih dari ini diproses sebagai background job
+	customerImportSyncMaxRows = 50
+	customerImportMaxRows     = 10000
+)
+
+type customerImportHandler struct {
+	importService service.CustomerImportServiceInterface
+}
+
+func NewCustomerImportHandler(importService service.CustomerImportServiceInterface) inbound.CustomerImportHandlerInterface {
+	return &customerImportHandler{importService: importService}
+}
+
+func (h *customerImportHandler) ImportCustomers(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not valid")
+		return response.RespondWithError(c, http.StatusUnauthorized, "[CustomerImportHandler-1] ImportCustomers", err)
+	}
+
+	dryRun := false
+	if dryRunStr := c.FormValue("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			err = errors.New("dry_run must be a boolean")
+			return response.RespondWithError(c, http.StatusBadRequest, "[CustomerImportHandler-2] ImportCustomers", err)
+		}
+		dryRun = parsed
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[CustomerImportHandler-3] ImportCustomers", err)
+	}
+
+	format, err := spreadsheet.DetectFormat(file.Filename)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[CustomerImportHandler-4] ImportCustomers", err)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[CustomerImportHandler-5] ImportCustomers", err)
+	}
+	defer func() {
+		if cerr := src.Close(); cerr != nil {
+			log.Errorf("[CustomerImportHandler-defer] failed to close src: %v", cerr)
+		}
+	}()
+
+	records, err := spreadsheet.ReadRows(format, src, customerImportMaxRows)
+	if err != nil {
+		if errors.Is(err, spreadsheet.ErrTooManyRows) {
+			err = fmt.Errorf("file exceeds the maximum of %d rows", customerImportMaxRows)
+			return response.RespondWithError(c, http.StatusRequestEntityTooLarge, "[CustomerImportHandler-6] ImportCustomers", err)
+		}
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[CustomerImportHandler-6] ImportCustomers", err)
+	}
+
+	if len(records) < 2 {
+		err = errors.New("file must contain a header row and at least one data row")
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[CustomerImportHandler-7] ImportCustomers", err)
+	}
+
+	// Ambil validator dari echo (bukan dari context) karena validasi bisa berjalan
+	// di goroutine setelah request selesai
+	validator := c.Echo().Validator
+	defaultRoleID := c.FormValue("role_id")
+
+	if len(records)-1 > customerImportSyncMaxRows {
+		job, err := h.importService.CreateImportJob(ctx, len(records)-1, dryRun)
+		if err != nil {
+			return response.RespondWithError(c, http.StatusInternalServerError, "[CustomerImportHandler-8] ImportCustomers", err)
+		}
+
+		go h.importService.RunImportJob(context.Background(), job.JobID, func() []entity.CustomerImportRowEntity {
+			return buildCustomerImportRows(validator, records, defaultRoleID)
+		}, dryRun)
+
+		resp.Message = "Import job accepted"
+		resp.Data = toCustomerImportResponse(job)
+		return c.JSON(http.StatusAccepted, resp)
+	}
+
+	rows := buildCustomerImportRows(validator, records, defaultRoleID)
+	result, err := h.importService.ImportCustomers(ctx, rows, dryRun)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusInternalServerError, "[CustomerImportHandler-9] ImportCustomers", err)
+	}
+
+	resp.Message = "Import finished"
+	if dryRun {
+		resp.Message = "Dry run finished"
+	}
+	resp.Data = toCustomerImportResponse(result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *customerImportHandler) GetImportJob(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not valid")
+		return response.RespondWithError(c, http.StatusUnauthorized, "[CustomerImportHandler-1] GetImportJob", err)
+	}
+
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		err := errors.New("missing or invalid job ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[CustomerImportHandler-2] GetImportJob", err)
+	}
+
+	job, err := h.importService.GetImportJob(ctx, jobID)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("import job not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[CustomerImportHandler-3] GetImportJob", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[CustomerImportHandler-3] GetImportJob", err)
+	}
+
+	resp.Message = "success"
+	resp.Data = toCustomerImportResponse(job)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// buildCustomerImportRows mengubah setiap baris file menjadi CustomerRequest lalu
+// menjalankan validasi yang sama dengan POST /admin/customers (termasuk uniqueEmail).
+// Nomor baris mengikuti nomor baris pada file (header = baris 1).
+func buildCustomerImportRows(validator echo.Validator, records [][]string, defaultRoleID string) []entity.CustomerImportRowEntity {
+	header := spreadsheet.HeaderIndex(records[0])
+	seenEmails := map[string]int{}
+	rows := make([]entity.CustomerImportRowEntity, 0, len(records)-1)
+
+	for i, record := range records[1:] {
+		lineNumber := i + 2
+		req, err := parseCustomerImportRecord(record, header, defaultRoleID)
+
+		if err == nil {
+			err = validator.Validate(&req)
+		}
+
+		if err == nil && req.Password != req.PasswordConfirmation {
+			err = errors.New("password and confirm password does not match")
+		}
+
+		email := strings.ToLower(req.Email)
+		if err == nil {
+			if firstLine, exists := seenEmails[email]; exists {
+				err = fmt.Errorf("email is duplicated with row %d", firstLine)
+			}
+		}
+		if _, exists := seenEmails[email]; !exists && email != "" {
+			seenEmails[email] = lineNumber
+		}
+
+		row := entity.CustomerImportRowEntity{
+			Row: lineNumber,
+			User: entity.UserEntity{
+				Name:     req.Name,
+				Email:    req.Email,
+				Password: req.Password,
+				Phone:    req.Phone,
+				Address:  req.Address,
+				Lat:      req.Lat,
+				Lng:      req.Lng,
+				Photo:    req.Photo,
+				RoleID:   req.RoleID,
+			},
+		}
+		if err != nil {
+			row.ValidationError = err.Error()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func parseCustomerImportRecord(record []string, header map[string]int, defaultRoleID string) (request.CustomerRequest, error) {
+	req := request.CustomerRequest{
+		Name:                 spreadsheet.Cell(record, header, "name"),
+		Email:                spreadsheet.Cell(record, header, "email"),
+		Password:             spreadsheet.Cell(record, header, "password"),
+		PasswordConfirmation: spreadsheet.Cell(record, header, "password_confirmation"),
+		Phone:                spreadsheet.Cell(record, header, "phone"),
+		Address:              spreadsheet.Cell(record, header, "address"),
+		Photo:                spreadsheet.Cell(record, header, "photo"),
+	}
+
+	// Kolom password_confirmation opsional pada file import
+	if _, ok := header["password_confirmation"]; !ok {
+		req.PasswordConfirmation = req.Password
+	}
+
+	var err error
+	if lat := spreadsheet.Cell(record, header, "lat"); lat != "" {
+		val, err := strconv.ParseFloat(lat, 64)
+		if err != nil {
+			return req, errors.New("lat must be a number")
+		}
+		req.Lat = &val
+	}
+
+	if lng := spreadsheet.Cell(record, header, "lng"); lng != "" {
+		val, err := strconv.ParseFloat(lng, 64)
+		if err != nil {
+			return req, errors.New("lng must be a number")
+		}
+		req.Lng = &val
+	}
+
+	roleID := spreadsheet.Cell(record, header, "role_id")
+	if roleID == "" {
+		roleID = defaultRoleID
+	}
+	if roleID != "" {
+		if req.RoleID, err = conv.StringToInt64(roleID); err != nil {
+			return req, errors.New("role_id must be a number")
+		}
+	}
+
+	return req, nil
+}
+
+func toCustomerImportResponse(job *entity.CustomerImportJobEntity) response.CustomerImportResponse {
+	errs := make([]response.CustomerImportErrorResponse, 0, len(job.Errors))
+	for _, val := range job.Errors {
+		errs = append(errs, response.CustomerImportErrorResponse{
+			Row:     val.Row,
+			Email:   val.Email,
+			Message: val.Message,
+		})
+	}
+
+	return response.CustomerImportResponse{
+		JobID:         job.JobID,
+		Status:        job.Status,
+		DryRun:        job.DryRun,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		SuccessRows:   job.SuccessRows,
+		FailedRows:    job.FailedRows,
+		Errors:        errs,
+		Error:         job.Error,
+		CreatedAt:     job.CreatedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
@@ -0,0 +1,45 @@
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerETag    = "ETag"
+	headerIfMatch = "If-Match"
+)
+
+// setETag mengisi header ETag dari kolom version, dipakai client sebagai If-Match saat update.
+func setETag(c echo.Context, version int64) {
+	c.Response().Header().Set(headerETag, fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersion membaca version dari header If-Match dan mengembalikan status HTTP jika tidak valid.
+// "*" berarti client sengaja menimpa versi apa pun, dikembalikan sebagai version 0 (tanpa pengecekan).
+func ifMatchVersion(c echo.Context) (int64, int, error) {
+	header := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	if header == "" {
+		return 0, http.StatusPreconditionRequired, errors.New("If-Match header is required")
+	}
+	if header == "*" {
+		return 0, 0, nil
+	}
+
+	// If-Match memakai strong comparison sehingga weak ETag tidak pernah cocok
+	if strings.HasPrefix(header, "W/") {
+		return 0, http.StatusPreconditionFailed, errors.New("resource has been modified")
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, http.StatusBadRequest, errors.New("invalid If-Match header")
+	}
+
+	return version, 0, nil
+}
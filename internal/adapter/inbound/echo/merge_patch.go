@@ -0,0 +1,74 @@
+package echo
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+)
+
+const mimeMergePatchJSON = "application/merge-patch+json"
+
+var (
+	profilePatchFields  = []string{"name", "email", "phone", "address", "lat", "lng", "photo"}
+	customerPatchFields = []string{"name", "email", "phone", "address", "lat", "lng", "photo", "role_id"}
+
+	// Kolom NOT NULL yang tidak boleh dikosongkan lewat patch
+	userPatchNotNullFields = []string{"name", "email", "role_id"}
+)
+
+// readMergePatch membaca body sebagai dokumen JSON Merge Patch (RFC 7396). Dokumen harus object
+// dan hanya berisi field pada allowed, nilainya di-decode ke dst untuk divalidasi.
+// Map yang dikembalikan dipakai untuk membedakan field yang dikirim null dengan yang tidak dikirim.
+func readMergePatch(c echo.Context, allowed []string, dst interface{}) (map[string]json.RawMessage, int, error) {
+	body, fields, code, err := readMergePatchDocument(c)
+	if err != nil {
+		return nil, code, err
+	}
+
+	for key, raw := range fields {
+		if !slices.Contains(allowed, key) {
+			return nil, http.StatusBadRequest, fmt.Errorf("field %q cannot be patched", key)
+		}
+		if string(raw) == "null" && slices.Contains(userPatchNotNullFields, key) {
+			return nil, http.StatusBadRequest, fmt.Errorf("field %q cannot be null", key)
+		}
+	}
+
+	if err := json.Unmarshal(body, dst); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return fields, 0, nil
+}
+
+// readMergePatchDocument memastikan content type & bentuk dokumen patch (object JSON).
+func readMergePatchDocument(c echo.Context) ([]byte, map[string]json.RawMessage, int, error) {
+	mediaType, _, err := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if err != nil || (mediaType != mimeMergePatchJSON && mediaType != echo.MIMEApplicationJSON) {
+		return nil, nil, http.StatusUnsupportedMediaType, fmt.Errorf("content type must be %s", mimeMergePatchJSON)
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return nil, nil, http.StatusBadRequest, errors.New("patch document must be a JSON object")
+	}
+
+	return body, fields, 0, nil
+}
+
+func patchField[T any](fields map[string]json.RawMessage, key string, value *T) entity.PatchField[T] {
+	_, ok := fields[key]
+	return entity.PatchField[T]{Set: ok, Value: value}
+}
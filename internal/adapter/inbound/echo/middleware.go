@@ -6,6 +6,7 @@ import (
 	"clean-architecture/internal/domain/entity"
 	"clean-architecture/internal/domain/service"
 	"clean-architecture/internal/port/inbound"
+	"clean-architecture/utils/traceparent"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -71,3 +72,18 @@ func (m *middlewareAdapter) CheckToken() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// TraceParent meneruskan header traceparent dari client (atau membuat trace baru) ke context
+// request, sehingga pesan Kafka yang dihasilkan request ini berada di trace yang sama.
+func (m *middlewareAdapter) TraceParent() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			value := traceparent.Child(c.Request().Header.Get(traceparent.Header))
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(traceparent.NewContext(req.Context(), value)))
+			c.Response().Header().Set(traceparent.Header, value)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"clean-architecture/utils/conv"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type notificationHandler struct {
+	notificationService service.NotificationServiceInterface
+}
+
+func NewNotificationHandler(notificationService service.NotificationServiceInterface) inbound.NotificationHandlerInterface {
+	return &notificationHandler{notificationService: notificationService}
+}
+
+func (n *notificationHandler) GetAll(c echo.Context) error {
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[NotificationHandler-1] GetAll", err)
+	}
+	return n.getAll(c, userID, false, "GetAll")
+}
+
+// GetCustomerNotificationAll dipakai support untuk mengecek apakah notifikasi sudah sampai ke customer.
+func (n *notificationHandler) GetCustomerNotificationAll(c echo.Context) error {
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[NotificationHandler-1] GetCustomerNotificationAll", err)
+	}
+	return n.getAll(c, customerID, true, "GetCustomerNotificationAll")
+}
+
+func (n *notificationHandler) Resend(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[NotificationHandler-1] Resend", err)
+	}
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		errBadRequest := errors.New("invalid notification ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[NotificationHandler-2] Resend", errBadRequest)
+	}
+
+	result, err := n.notificationService.Resend(ctx, id)
+	if err != nil {
+		switch err.Error() {
+		case "404":
+			errNotFound := errors.New("notification not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[NotificationHandler-3] Resend", errNotFound)
+		case "409":
+			errConflict := errors.New("notification is still being sent")
+			return response.RespondWithError(c, http.StatusConflict, "[NotificationHandler-3] Resend", errConflict)
+		case "422":
+			errUnprocessable := errors.New("notification cannot be resent, its content or recipient is no longer available")
+			return response.RespondWithError(c, http.StatusUnprocessableEntity, "[NotificationHandler-3] Resend", errUnprocessable)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[NotificationHandler-3] Resend", err)
+	}
+
+	resp.Message = "Notification queued for resend"
+	resp.Data = notificationResponse(*result, true)
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+func (n *notificationHandler) getAll(c echo.Context, userID int64, admin bool, method string) error {
+	var (
+		resp             = response.DefaultResponseWithPaginations{}
+		ctx              = c.Request().Context()
+		respNotification = []response.NotificationResponse{}
+	)
+
+	query := parseNotificationQuery(c)
+	query.UserID = userID
+
+	results, countData, totalPages, err := n.notificationService.GetAll(ctx, query)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid status filter")
+			return response.RespondWithError(c, http.StatusBadRequest, "[NotificationHandler-2] "+method, errBadRequest)
+		}
+		if err.Error() == "404" {
+			errNotFound := errors.New("notification not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[NotificationHandler-2] "+method, errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[NotificationHandler-2] "+method, err)
+	}
+
+	for _, val := range results {
+		respNotification = append(respNotification, notificationResponse(val, admin))
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respNotification
+	resp.Pagination = &response.Pagination{
+		Page:       query.Page,
+		TotalCount: countData,
+		Limit:      query.Limit,
+		TotalPage:  totalPages,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// parseNotificationQuery page default 1, limit default 10, status kosong berarti semua status.
+func parseNotificationQuery(c echo.Context) entity.NotificationHistoryQueryEntity {
+	query := entity.NotificationHistoryQueryEntity{
+		Status: c.QueryParam("status"),
+		Page:   1,
+		Limit:  10,
+	}
+
+	if page, err := conv.StringToInt64(c.QueryParam("page")); err == nil && page > 0 {
+		query.Page = page
+	}
+	if limit, err := conv.StringToInt64(c.QueryParam("limit")); err == nil && limit > 0 {
+		query.Limit = limit
+	}
+
+	return query
+}
+
+// notificationResponse error pengiriman berisi detail transport, hanya untuk admin.
+func notificationResponse(val entity.NotificationHistoryEntity, admin bool) response.NotificationResponse {
+	// Notifikasi lama tanpa template memakai nama antrean yang sama dengan nama template
+	template := val.Template
+	if template == "" {
+		template = val.QueueName
+	}
+
+	resp := response.NotificationResponse{
+		ID:          val.ID,
+		MessageID:   val.MessageID,
+		ResendOfID:  val.ResendOfID,
+		Type:        val.Type,
+		Template:    template,
+		Recipient:   val.Recipient,
+		Status:      val.Status,
+		Attempts:    val.Attempts,
+		CreatedAt:   val.CreatedAt,
+		SentAt:      val.SentAt,
+		DeliveredAt: val.DeliveredAt,
+		UpdatedAt:   val.UpdatedAt,
+	}
+	if admin {
+		resp.LastError = val.LastError
+	}
+	return resp
+}
@@ -0,0 +1,120 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/request"
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type phoneVerificationHandler struct {
+	phoneVerificationService service.PhoneVerificationServiceInterface
+}
+
+func NewPhoneVerificationHandler(phoneVerificationService service.PhoneVerificationServiceInterface) inbound.PhoneVerificationHandlerInterface {
+	return &phoneVerificationHandler{phoneVerificationService: phoneVerificationService}
+}
+
+// SendOTP mengirim OTP via SMS ke nomor yang akan diverifikasi untuk user yang login.
+func (p *phoneVerificationHandler) SendOTP(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.PhoneOTPRequest{}
+		jwtUserData = entity.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[PhoneVerificationHandler-1] SendOTP", err)
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-2] SendOTP", err)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-3] SendOTP", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-4] SendOTP", err)
+	}
+
+	phoneNumber, err := p.phoneVerificationService.SendOTP(ctx, jwtUserData.UserID, req.Phone)
+	if err != nil {
+		switch err.Error() {
+		case "400":
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-5] SendOTP", errBadRequest)
+		case "409":
+			errConflict := errors.New("phone number already verified by another account")
+			return response.RespondWithError(c, http.StatusConflict, "[PhoneVerificationHandler-5] SendOTP", errConflict)
+		case "429":
+			errTooMany := errors.New("please wait before requesting another OTP")
+			return response.RespondWithError(c, http.StatusTooManyRequests, "[PhoneVerificationHandler-5] SendOTP", errTooMany)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PhoneVerificationHandler-5] SendOTP", err)
+	}
+
+	resp.Message = "OTP has been sent"
+	resp.Data = response.PhoneOTPResponse{Phone: phoneNumber}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// VerifyOTP mencocokkan OTP, jika benar nomor telepon user ditandai terverifikasi.
+func (p *phoneVerificationHandler) VerifyOTP(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		req         = request.VerifyPhoneOTPRequest{}
+		jwtUserData = entity.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[PhoneVerificationHandler-1] VerifyOTP", err)
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-2] VerifyOTP", err)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-3] VerifyOTP", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PhoneVerificationHandler-4] VerifyOTP", err)
+	}
+
+	if err := p.phoneVerificationService.VerifyOTP(ctx, jwtUserData.UserID, req.OTP); err != nil {
+		switch err.Error() {
+		case "404":
+			errNotFound := errors.New("OTP expired or not requested")
+			return response.RespondWithError(c, http.StatusNotFound, "[PhoneVerificationHandler-5] VerifyOTP", errNotFound)
+		case "422":
+			errInvalid := errors.New("invalid OTP")
+			return response.RespondWithError(c, http.StatusUnprocessableEntity, "[PhoneVerificationHandler-5] VerifyOTP", errInvalid)
+		case "409":
+			errConflict := errors.New("phone number already verified by another account")
+			return response.RespondWithError(c, http.StatusConflict, "[PhoneVerificationHandler-5] VerifyOTP", errConflict)
+		case "429":
+			errTooMany := errors.New("too many invalid attempts for this phone number, please try again later")
+			return response.RespondWithError(c, http.StatusTooManyRequests, "[PhoneVerificationHandler-5] VerifyOTP", errTooMany)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PhoneVerificationHandler-5] VerifyOTP", err)
+	}
+
+	resp.Message = "Phone number verified"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
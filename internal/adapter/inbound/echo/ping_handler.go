@@ -2,6 +2,7 @@ package echo
 
 import (
 	"clean-architecture/internal/port/inbound"
+	"clean-architecture/internal/port/outbound"
 	pingutils "clean-architecture/utils/ping"
 	"fmt"
 	"net/http"
@@ -10,10 +11,13 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-type pingHandler struct{}
+type pingHandler struct {
+	publisher outbound.KafkaProducerInterface
+}
 
-func NewPingHandler() inbound.PingHandlerInterface {
-	return &pingHandler{}
+// NewPingHandler publisher boleh nil, metrics producer Kafka hanya ditampilkan jika diisi.
+func NewPingHandler(publisher outbound.KafkaProducerInterface) inbound.PingHandlerInterface {
+	return &pingHandler{publisher: publisher}
 }
 
 func (h *pingHandler) Ping(c echo.Context) error {
@@ -28,7 +32,7 @@ func (h *pingHandler) Ping(c echo.Context) error {
 	total, free, buffers, cached := pingutils.GetMemorySample()
 	coreCount := pingutils.GetCoreSample()
 
-	return c.JSON(http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"message": "pong",
 		"core": []map[string]any{
 			{"core": fmt.Sprintf("%d Core", coreCount)},
@@ -49,5 +53,11 @@ func (h *pingHandler) Ping(c echo.Context) error {
 				"cached": fmt.Sprintf("%f MB", float64(cached)/1024),
 			},
 		},
-	})
+	}
+
+	if h.publisher != nil {
+		resp["kafka_producer"] = h.publisher.Metrics()
+	}
+
+	return c.JSON(http.StatusOK, resp)
 }
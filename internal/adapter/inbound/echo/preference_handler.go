@@ -0,0 +1,119 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type preferenceHandler struct {
+	preferenceService service.PreferenceServiceInterface
+}
+
+func NewPreferenceHandler(preferenceService service.PreferenceServiceInterface) inbound.PreferenceHandlerInterface {
+	return &preferenceHandler{preferenceService: preferenceService}
+}
+
+func (p *preferenceHandler) GetPreferences(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[PreferenceHandler-1] GetPreferences", err)
+	}
+
+	results, err := p.preferenceService.GetPreferences(ctx, userID)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PreferenceHandler-2] GetPreferences", err)
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = preferenceResponses(results)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdatePreferences menerima object {key: value} dengan semantik merge patch,
+// nilai null mengembalikan key tersebut ke default.
+func (p *preferenceHandler) UpdatePreferences(c echo.Context) error {
+	var (
+		resp    = response.DefaultResponse{}
+		ctx     = c.Request().Context()
+		changes = map[string]interface{}{}
+	)
+
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[PreferenceHandler-1] UpdatePreferences", err)
+	}
+
+	body, _, code, err := readMergePatchDocument(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[PreferenceHandler-2] UpdatePreferences", err)
+	}
+
+	if err := json.Unmarshal(body, &changes); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[PreferenceHandler-3] UpdatePreferences", err)
+	}
+
+	results, err := p.preferenceService.UpdatePreferences(ctx, userID, changes)
+	if err != nil {
+		if err.Error() == "422" {
+			errInvalid := errors.New("invalid preference key or value")
+			return response.RespondWithError(c, http.StatusUnprocessableEntity, "[PreferenceHandler-4] UpdatePreferences", errInvalid)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PreferenceHandler-4] UpdatePreferences", err)
+	}
+
+	resp.Message = "Success"
+	resp.Data = preferenceResponses(results)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (p *preferenceHandler) GetCustomerPreferences(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	customerID, code, err := addressOwnerFromParam(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[PreferenceHandler-1] GetCustomerPreferences", err)
+	}
+
+	results, err := p.preferenceService.GetCustomerPreferences(ctx, customerID)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("customer not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[PreferenceHandler-2] GetCustomerPreferences", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PreferenceHandler-2] GetCustomerPreferences", err)
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = preferenceResponses(results)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func preferenceResponses(results []entity.PreferenceEntity) []response.PreferenceResponse {
+	respPreferences := []response.PreferenceResponse{}
+	for _, val := range results {
+		respPreferences = append(respPreferences, response.PreferenceResponse{
+			Key:       val.Key,
+			Type:      val.Type,
+			Value:     val.Value,
+			Default:   val.Default,
+			IsDefault: val.IsDefault,
+			UpdatedAt: val.UpdatedAt,
+		})
+	}
+	return respPreferences
+}
@@ -0,0 +1,268 @@
+package echo
+
+import (
+	"archive/zip"
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+)
+
+type privacyHandler struct {
+	privacyService service.PrivacyServiceInterface
+}
+
+func NewPrivacyHandler(privacyService service.PrivacyServiceInterface) inbound.PrivacyHandlerInterface {
+	return &privacyHandler{privacyService: privacyService}
+}
+
+// ExportMyData mengirim ZIP berisi seluruh data pribadi user yang login (GDPR data portability).
+func (p *privacyHandler) ExportMyData(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[PrivacyHandler-1] ExportMyData", err)
+	}
+
+	data, err := p.privacyService.GetUserDataExport(ctx, userID)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("user not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[PrivacyHandler-2] ExportMyData", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PrivacyHandler-2] ExportMyData", err)
+	}
+
+	fileName := fmt.Sprintf("my_data_%s.zip", data.GeneratedAt.Format("20060102_150405"))
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Response().WriteHeader(http.StatusOK)
+
+	// Header sudah terkirim, error setelah ini hanya bisa dicatat di log
+	zipWriter := zip.NewWriter(c.Response())
+	if err := writeUserDataExport(ctx, zipWriter, data, p.privacyService); err != nil {
+		log.Errorf("[PrivacyHandler-3] ExportMyData: %v", err)
+		return nil
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		log.Errorf("[PrivacyHandler-4] ExportMyData: %v", err)
+		return nil
+	}
+
+	log.Infof("[PrivacyHandler-5] ExportMyData: data user %d exported with %d files", userID, len(data.Files))
+	return nil
+}
+
+func (p *privacyHandler) RequestDeletion(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[PrivacyHandler-1] RequestDeletion", err)
+	}
+
+	result, err := p.privacyService.RequestDeletion(ctx, userID)
+	if err != nil {
+		if err.Error() == "409" {
+			errConflict := errors.New("deletion already requested")
+			return response.RespondWithError(c, http.StatusConflict, "[PrivacyHandler-2] RequestDeletion", errConflict)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PrivacyHandler-2] RequestDeletion", err)
+	}
+
+	resp.Message = "Account deletion scheduled"
+	resp.Data = deletionRequestResponse(*result)
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+func (p *privacyHandler) GetDeletionRequest(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[PrivacyHandler-1] GetDeletionRequest", err)
+	}
+
+	result, err := p.privacyService.GetDeletionRequest(ctx, userID)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("deletion request not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[PrivacyHandler-2] GetDeletionRequest", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PrivacyHandler-2] GetDeletionRequest", err)
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = deletionRequestResponse(*result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (p *privacyHandler) CancelDeletion(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID, err := addressOwnerFromToken(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusUnauthorized, "[PrivacyHandler-1] CancelDeletion", err)
+	}
+
+	if err := p.privacyService.CancelDeletion(ctx, userID); err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("deletion request not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[PrivacyHandler-2] CancelDeletion", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[PrivacyHandler-2] CancelDeletion", err)
+	}
+
+	resp.Message = "Account deletion cancelled"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// writeUserDataExport menulis tiap bagian data sebagai file JSON, lalu file di storage
+// disalin apa adanya ke folder files/ di dalam ZIP.
+func writeUserDataExport(ctx context.Context, zipWriter *zip.Writer,
+	data *entity.UserDataExportEntity, privacyService service.PrivacyServiceInterface) error {
+	profile := response.ProfileResponse{
+		RoleName:        data.Profile.RoleName,
+		ID:              data.Profile.ID,
+		Name:            data.Profile.Name,
+		Email:           data.Profile.Email,
+		Phone:           data.Profile.Phone,
+		Lat:             data.Profile.Lat,
+		Lng:             data.Profile.Lng,
+		Address:         data.Profile.Address,
+		Photo:           data.Profile.Photo,
+		PhoneVerifiedAt: data.Profile.PhoneVerifiedAt,
+	}
+
+	roles := []response.RoleResponse{}
+	for _, val := range data.Roles {
+		roles = append(roles, response.RoleResponse{ID: val.ID, Name: val.Name})
+	}
+
+	addresses := []response.AddressResponse{}
+	for _, val := range data.Addresses {
+		addresses = append(addresses, addressResponse(val))
+	}
+
+	sessions := []response.SessionResponse{}
+	for _, val := range data.Sessions {
+		sessions = append(sessions, response.SessionResponse{
+			TokenHint: val.TokenHint,
+			CreatedAt: val.CreatedAt,
+			ExpiresAt: val.ExpiresAt,
+		})
+	}
+
+	tokens := []response.VerificationTokenExportResponse{}
+	for _, val := range data.Tokens {
+		tokens = append(tokens, response.VerificationTokenExportResponse{
+			ID:        val.ID,
+			TokenType: val.TokenType,
+			CreatedAt: val.CreatedAt,
+			ExpiresAt: val.ExpiresAt,
+		})
+	}
+
+	files := []string{}
+	for _, path := range data.Files {
+		files = append(files, exportFileName(path))
+	}
+
+	jsonFiles := []struct {
+		name string
+		data any
+	}{
+		{"export.json", response.UserExportManifestResponse{UserID: data.Profile.ID, GeneratedAt: data.GeneratedAt, Files: files}},
+		{"profile.json", profile},
+		{"roles.json", roles},
+		{"addresses.json", addresses},
+		{"sessions.json", sessions},
+		{"tokens.json", tokens},
+	}
+
+	for _, val := range jsonFiles {
+		writer, err := zipWriter.CreateHeader(&zip.FileHeader{Name: val.name, Method: zip.Deflate, Modified: data.GeneratedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(val.data); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range data.Files {
+		if err := copyExportFile(ctx, zipWriter, path, data.GeneratedAt, privacyService); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyExportFile(ctx context.Context, zipWriter *zip.Writer, path string, modified time.Time,
+	privacyService service.PrivacyServiceInterface) error {
+	object, err := privacyService.OpenUserFile(ctx, path)
+	if err != nil {
+		// File yang sudah tidak ada di storage dilewati agar export tetap bisa diunduh
+		log.Errorf("[PrivacyHandler-6] ExportMyData: skip file %s: %v", path, err)
+		return nil
+	}
+	defer object.Close()
+
+	writer, err := zipWriter.CreateHeader(&zip.FileHeader{Name: exportFileName(path), Method: zip.Store, Modified: modified})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(writer, object)
+	return err
+}
+
+// exportFileName path file di dalam ZIP, prefix folder upload dibuang agar lebih ringkas.
+func exportFileName(path string) string {
+	return "files/" + strings.TrimPrefix(path, "public/uploads/")
+}
+
+func deletionRequestResponse(val entity.DeletionRequestEntity) response.DeletionRequestResponse {
+	status := "pending"
+	switch {
+	case val.CompletedAt != nil:
+		status = "completed"
+	case val.CancelledAt != nil:
+		status = "cancelled"
+	}
+
+	return response.DeletionRequestResponse{
+		ID:          val.ID,
+		Status:      status,
+		RequestedAt: val.RequestedAt,
+		ScheduledAt: val.ScheduledAt,
+		CancelledAt: val.CancelledAt,
+		CompletedAt: val.CompletedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package echo
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// filter[field]=value atau filter[field][operator]=value
+var filterParamPattern = regexp.MustCompile(`^filter\[([a-z_]+)\](?:\[([a-z]+)\])?$`)
+
+// parseListFilters membaca semua query param filter[...] dan memastikan field & operator
+// ada di whitelist allowed. Operator default adalah eq.
+func parseListFilters(values url.Values, allowed map[string][]string) ([]entity.FilterEntity, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys) // urutan filter deterministik
+
+	filters := make([]entity.FilterEntity, 0, len(keys))
+	for _, key := range keys {
+		match := filterParamPattern.FindStringSubmatch(key)
+		if match == nil {
+			return nil, fmt.Errorf("invalid filter parameter %q", key)
+		}
+
+		field, operator := match[1], match[2]
+		if operator == "" {
+			operator = entity.FilterOpEq
+		}
+
+		operators, ok := allowed[field]
+		if !ok {
+			return nil, fmt.Errorf("filter on field %q is not supported", field)
+		}
+		if !slices.Contains(operators, operator) {
+			return nil, fmt.Errorf("operator %q is not supported for field %q", operator, field)
+		}
+
+		for _, value := range values[key] {
+			filters = append(filters, entity.FilterEntity{
+				Field:    field,
+				Operator: operator,
+				Value:    value,
+			})
+		}
+	}
+
+	return filters, nil
+}
+
+// parseListSorts membaca sort=field1,-field2 (prefix "-" berarti descending).
+func parseListSorts(sortParam string, allowed []string) ([]entity.SortEntity, error) {
+	if strings.TrimSpace(sortParam) == "" {
+		return nil, nil
+	}
+
+	var sorts []entity.SortEntity
+	for _, part := range strings.Split(sortParam, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		desc := strings.HasPrefix(part, "-")
+		field := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("sort on field %q is not supported", field)
+		}
+
+		sorts = append(sorts, entity.SortEntity{Field: field, Desc: desc})
+	}
+
+	return sorts, nil
+}
+
+// parseListFields membaca sparse fieldset fields=field1,field2.
+func parseListFields(fieldsParam string, allowed []string) ([]string, error) {
+	if strings.TrimSpace(fieldsParam) == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, field := range strings.Split(fieldsParam, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("field %q is not supported", field)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,16 @@
+package request
+
+type AddressRequest struct {
+	Label         string   `json:"label" validate:"required,max=50"`
+	RecipientName string   `json:"recipient_name" validate:"required,max=255"`
+	Phone         string   `json:"phone" validate:"required,phone"`
+	AddressLine   string   `json:"address_line" validate:"required"`
+	District      string   `json:"district" validate:"max=100"`
+	City          string   `json:"city" validate:"max=100"`
+	Province      string   `json:"province" validate:"max=100"`
+	PostalCode    string   `json:"postal_code" validate:"omitempty,number,max=10"`
+	Notes         string   `json:"notes"`
+	Lat           *float64 `json:"lat" validate:"omitempty,latitude"`
+	Lng           *float64 `json:"lng" validate:"omitempty,longitude"`
+	IsDefault     bool     `json:"is_default"`
+}
@@ -0,0 +1,10 @@
+package request
+
+import "time"
+
+type LegalDocumentRequest struct {
+	DocType     string     `json:"doc_type" validate:"required,oneof=terms privacy"`
+	Version     string     `json:"version" validate:"required,max=50"`
+	URL         string     `json:"url" validate:"required,url"`
+	PublishedAt *time.Time `json:"published_at"`
+}
@@ -1,24 +1,36 @@
 package request
 
 type CustomerRequest struct {
-	Name                 string  `json:"name" validate:"required"`
-	Email                string  `json:"email" validate:"required,email,uniqueEmail"`
-	Password             string  `json:"password" validate:"required,min=8"`
-	PasswordConfirmation string  `json:"password_confirmation" validate:"required,min=8"`
-	Phone                string  `json:"phone" validate:"required,number"`
-	Address              string  `json:"address"`
-	Lat                  float64 `json:"lat"`
-	Lng                  float64 `json:"lng"`
-	Photo                string  `json:"photo"`
-	RoleID               int64   `json:"role_id" validate:"required"`
+	Name                 string   `json:"name" validate:"required"`
+	Email                string   `json:"email" validate:"required,email,uniqueEmail"`
+	Password             string   `json:"password" validate:"required,min=8"`
+	PasswordConfirmation string   `json:"password_confirmation" validate:"required,min=8"`
+	Phone                string   `json:"phone" validate:"required,phone"`
+	Address              string   `json:"address"`
+	Lat                  *float64 `json:"lat" validate:"omitempty,latitude"`
+	Lng                  *float64 `json:"lng" validate:"omitempty,longitude"`
+	Photo                string   `json:"photo"`
+	RoleID               int64    `json:"role_id" validate:"required"`
 }
 
 type UpdateCustomerRequest struct {
-	Name    string  `json:"name"`
-	Email   string  `json:"email" validate:"omitempty,email,uniqueEmail"`
-	Phone   string  `json:"phone" validate:"number"`
-	Address string  `json:"address"`
-	Lat     float64 `json:"lat"`
-	Lng     float64 `json:"lng"`
-	Photo   string  `json:"photo"`
+	Name    string   `json:"name"`
+	Email   string   `json:"email" validate:"omitempty,email,uniqueEmail"`
+	Phone   string   `json:"phone" validate:"omitempty,phone"`
+	Address string   `json:"address"`
+	Lat     *float64 `json:"lat" validate:"omitempty,latitude"`
+	Lng     *float64 `json:"lng" validate:"omitempty,longitude"`
+	Photo   string   `json:"photo"`
+}
+
+// PatchCustomerRequest keunikan email dicek saat patch (kecuali milik customer itu sendiri), bukan lewat uniqueEmail.
+type PatchCustomerRequest struct {
+	Name    *string  `json:"name" validate:"omitnil,min=1"`
+	Email   *string  `json:"email" validate:"omitnil,email"`
+	Phone   *string  `json:"phone" validate:"omitnil,phone"`
+	Address *string  `json:"address"`
+	Lat     *float64 `json:"lat" validate:"omitnil,latitude"`
+	Lng     *float64 `json:"lng" validate:"omitnil,longitude"`
+	Photo   *string  `json:"photo"`
+	RoleID  *int64   `json:"role_id" validate:"omitnil,min=1"`
 }
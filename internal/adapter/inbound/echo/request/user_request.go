@@ -1,7 +1,9 @@
 package request
 
+// SignInRequest login memakai email atau nomor telepon yang sudah diverifikasi.
 type SignInRequest struct {
-	Email    string `json:"email" validate:"email,required"`
+	Email    string `json:"email" validate:"required_without=Phone,omitempty,email"`
+	Phone    string `json:"phone" validate:"required_without=Email,omitempty,phone"`
 	Password string `json:"password" validate:"min=8,required"`
 }
 
@@ -10,6 +12,16 @@ type SignUpRequest struct {
 	Email                string `json:"email" validate:"email,required"`
 	Password             string `json:"password" validate:"required,min=8"`
 	PasswordConfirmation string `json:"password_confirmation" validate:"required,min=8"`
+
+	// Versi syarat & ketentuan dan kebijakan privasi yang berlaku (GET /legal-documents/current)
+	TermsVersion   string `json:"terms_version" validate:"required"`
+	PrivacyVersion string `json:"privacy_version" validate:"required"`
+}
+
+// SignInConsentRequest menyetujui dokumen legal terbaru memakai challenge token dari /signin.
+type SignInConsentRequest struct {
+	ChallengeToken string  `json:"challenge_token" validate:"required"`
+	DocumentIDs    []int64 `json:"document_ids" validate:"required,min=1"`
 }
 
 type ForgotPasswordRequest struct {
@@ -23,11 +35,31 @@ type UpdatePasswordRequest struct {
 }
 
 type UpdateDataUserRequest struct {
-	Name    string `json:"name"`
-	Email   string `json:"email" validate:"omitempty,email"`
-	Phone   string `json:"phone"`
-	Address string `json:"address"`
-	Lat     string `json:"lat"`
-	Lng     string `json:"lng"`
-	Photo   string `json:"photo"`
+	Name    string   `json:"name"`
+	Email   string   `json:"email" validate:"omitempty,email"`
+	Phone   string   `json:"phone" validate:"omitempty,phone"`
+	Address string   `json:"address"`
+	Lat     *float64 `json:"lat" validate:"omitempty,latitude"`
+	Lng     *float64 `json:"lng" validate:"omitempty,longitude"`
+	Photo   string   `json:"photo"`
+}
+
+type PhoneOTPRequest struct {
+	Phone string `json:"phone" validate:"required,phone"`
+}
+
+type VerifyPhoneOTPRequest struct {
+	OTP string `json:"otp" validate:"required,len=6,numeric"`
+}
+
+// PatchProfileRequest nilai field dari dokumen merge patch, field yang tidak dikirim maupun
+// dikirim null sama-sama nil di sini. Keberadaan field dibaca terpisah dari dokumen patch.
+type PatchProfileRequest struct {
+	Name    *string  `json:"name" validate:"omitnil,min=1"`
+	Email   *string  `json:"email" validate:"omitnil,email"`
+	Phone   *string  `json:"phone" validate:"omitnil,phone"`
+	Address *string  `json:"address"`
+	Lat     *float64 `json:"lat" validate:"omitnil,latitude"`
+	Lng     *float64 `json:"lng" validate:"omitnil,longitude"`
+	Photo   *string  `json:"photo"`
 }
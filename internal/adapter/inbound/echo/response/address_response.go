@@ -0,0 +1,21 @@
+package response
+
+import "time"
+
+type AddressResponse struct {
+	ID            int64      `json:"id"`
+	Label         string     `json:"label"`
+	RecipientName string     `json:"recipient_name"`
+	Phone         string     `json:"phone"`
+	AddressLine   string     `json:"address_line"`
+	District      string     `json:"district"`
+	City          string     `json:"city"`
+	Province      string     `json:"province"`
+	PostalCode    string     `json:"postal_code"`
+	Notes         string     `json:"notes"`
+	Lat           *float64   `json:"lat"`
+	Lng           *float64   `json:"lng"`
+	IsDefault     bool       `json:"is_default"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+}
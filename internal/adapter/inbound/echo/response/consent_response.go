@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+type LegalDocumentResponse struct {
+	ID          int64     `json:"id"`
+	DocType     string    `json:"doc_type"`
+	Version     string    `json:"version"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type ConsentReportResponse struct {
+	LegalDocumentResponse
+	IsCurrent      bool    `json:"is_current"`
+	AcceptedCount  int64   `json:"accepted_count"`
+	TotalUsers     int64   `json:"total_users"`
+	AcceptanceRate float64 `json:"acceptance_rate"`
+}
+
+type ConsentChallengeResponse struct {
+	ChallengeToken string `json:"challenge_token"`
+}
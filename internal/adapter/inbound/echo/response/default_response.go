@@ -17,10 +17,14 @@ type DefaultResponseWithPaginations struct {
 }
 
 type Pagination struct {
-	Page       int64 `json:"page"`
-	TotalCount int64 `json:"total_count"`
-	Limit      int64 `json:"limit"`
-	TotalPage  int64 `json:"total_page"`
+	Page       int64  `json:"page,omitempty"`
+	TotalCount int64  `json:"total_count,omitempty"`
+	Limit      int64  `json:"limit"`
+	TotalPage  int64  `json:"total_page,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
 }
 
 func ResponseSuccess(message string, data any) DefaultResponse {
@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+// NotificationResponse LastError hanya ditampilkan ke admin.
+type NotificationResponse struct {
+	ID          int64      `json:"id"`
+	MessageID   string     `json:"message_id"`
+	ResendOfID  *int64     `json:"resend_of_id"`
+	Type        string     `json:"type"`
+	Template    string     `json:"template"`
+	Recipient   string     `json:"recipient"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at"`
+	DeliveredAt *time.Time `json:"delivered_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
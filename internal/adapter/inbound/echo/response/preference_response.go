@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+type PreferenceResponse struct {
+	Key       string      `json:"key"`
+	Type      string      `json:"type"`
+	Value     interface{} `json:"value"`
+	Default   interface{} `json:"default"`
+	IsDefault bool        `json:"is_default"`
+	UpdatedAt *time.Time  `json:"updated_at"`
+}
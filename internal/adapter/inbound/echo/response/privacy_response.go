@@ -0,0 +1,33 @@
+package response
+
+import "time"
+
+// UserExportManifestResponse isi export.json di dalam ZIP export data user.
+type UserExportManifestResponse struct {
+	UserID      int64     `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+type SessionResponse struct {
+	TokenHint string     `json:"token_hint"`
+	CreatedAt string     `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// VerificationTokenExportResponse nilai token sengaja tidak ikut di-export.
+type VerificationTokenExportResponse struct {
+	ID        int64     `json:"id"`
+	TokenType string    `json:"token_type"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type DeletionRequestResponse struct {
+	ID          int64      `json:"id"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	CancelledAt *time.Time `json:"cancelled_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
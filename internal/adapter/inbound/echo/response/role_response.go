@@ -1,6 +1,14 @@
 package response
 
+import "time"
+
 type RoleResponse struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
 }
+
+type DeletedRoleResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
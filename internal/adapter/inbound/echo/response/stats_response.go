@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type DashboardStatsResponse struct {
+	TotalCustomers       int64                `json:"total_customers"`
+	VerifiedCustomers    int64                `json:"verified_customers"`
+	PendingVerifications int64                `json:"pending_verifications"`
+	ActiveSessions       int64                `json:"active_sessions"`
+	SignupsPerDay        []DailyCountResponse `json:"signups_per_day"`
+	UsersPerRole         []RoleCountResponse  `json:"users_per_role"`
+	From                 string               `json:"from"`
+	To                   string               `json:"to"`
+	GeneratedAt          time.Time            `json:"generated_at"`
+}
+
+type DailyCountResponse struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+type RoleCountResponse struct {
+	RoleID   int64  `json:"role_id"`
+	RoleName string `json:"role_name"`
+	Count    int64  `json:"count"`
+}
@@ -1,26 +1,30 @@
 package response
 
+import "time"
+
 type SignInResponse struct {
-	AccessToken string `json:"access_token"`
-	Role        string `json:"role"`
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	Email       string `json:"email"`
-	Phone       string `json:"phone"`
-	Lat         string `json:"lat"`
-	Lng         string `json:"lng"`
+	AccessToken string   `json:"access_token"`
+	Role        string   `json:"role"`
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Email       string   `json:"email"`
+	Phone       string   `json:"phone"`
+	Lat         *float64 `json:"lat"`
+	Lng         *float64 `json:"lng"`
 }
 
 type ProfileResponse struct {
-	RoleName string `json:"role"`
-	ID       int64  `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Phone    string `json:"phone"`
-	Lat      string `json:"lat"`
-	Lng      string `json:"lng"`
-	Address  string `json:"address"`
-	Photo    string `json:"photo"`
+	RoleName string   `json:"role"`
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Lat      *float64 `json:"lat"`
+	Lng      *float64 `json:"lng"`
+	Address  string   `json:"address"`
+	Photo    string   `json:"photo"`
+
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
 }
 
 type CustomerListResponse struct {
@@ -31,15 +35,65 @@ type CustomerListResponse struct {
 	Photo string `json:"photo"`
 }
 
+type CustomerExportResponse struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email"`
+	Phone      string    `json:"phone"`
+	Role       string    `json:"role"`
+	Address    string    `json:"address"`
+	Lat        *float64  `json:"lat"`
+	Lng        *float64  `json:"lng"`
+	Photo      string    `json:"photo"`
+	IsVerified bool      `json:"is_verified"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type DeletedCustomerResponse struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	Phone     string     `json:"phone"`
+	Photo     string     `json:"photo"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
 type CustomerResponse struct {
-	RoleName string `json:"role,omitempty"`
-	RoleID   int64  `json:"role_id"`
-	ID       int64  `json:"id"`
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Phone    string `json:"phone"`
-	Lat      string `json:"lat"`
-	Lng      string `json:"lng"`
-	Address  string `json:"address"`
-	Photo    string `json:"photo"`
+	RoleName string   `json:"role,omitempty"`
+	RoleID   int64    `json:"role_id"`
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Phone    string   `json:"phone"`
+	Lat      *float64 `json:"lat"`
+	Lng      *float64 `json:"lng"`
+	Address  string   `json:"address"`
+	Photo    string   `json:"photo"`
+}
+
+type CustomerSearchResponse struct {
+	ID         int64             `json:"id"`
+	Name       string            `json:"name"`
+	Email      string            `json:"email"`
+	Phone      string            `json:"phone"`
+	Photo      string            `json:"photo"`
+	IsVerified bool              `json:"is_verified"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type NearbyCustomerResponse struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Email      string   `json:"email"`
+	Phone      string   `json:"phone"`
+	Photo      string   `json:"photo"`
+	Address    string   `json:"address"`
+	Lat        *float64 `json:"lat"`
+	Lng        *float64 `json:"lng"`
+	DistanceKm float64  `json:"distance_km"`
+}
+
+type PhoneOTPResponse struct {
+	Phone string `json:"phone"`
 }
@@ -200,6 +200,7 @@ func (r *roleHandler) GetByID(c echo.Context) error {
 	respRole.Name = role.Name
 	resp.Message = "success"
 	resp.Data = respRole
+	setETag(c, role.Version)
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -246,9 +247,15 @@ func (r *roleHandler) Update(c echo.Context) error {
 		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[RoleHandler-7] Update", err)
 	}
 
+	version, code, err := ifMatchVersion(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[RoleHandler-9] Update", err)
+	}
+
 	reqEntity := entity.RoleEntity{
-		ID:   int64(roleID),
-		Name: req.Name,
+		ID:      int64(roleID),
+		Name:    req.Name,
+		Version: version,
 	}
 
 	err = r.roleService.Update(ctx, reqEntity)
@@ -258,6 +265,10 @@ func (r *roleHandler) Update(c echo.Context) error {
 			errNotFound := errors.New("role not found")
 			return response.RespondWithError(c, http.StatusNotFound, "[RoleHandler-8] Update", errNotFound)
 		}
+		if err.Error() == "412" {
+			errPrecondition := errors.New("role has been modified by another request")
+			return response.RespondWithError(c, http.StatusPreconditionFailed, "[RoleHandler-8] Update", errPrecondition)
+		}
 		return response.RespondWithError(c, http.StatusInternalServerError, "[RoleHandler-8] Update", err)
 	}
 
@@ -266,3 +277,100 @@ func (r *roleHandler) Update(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+func (r *roleHandler) GetDeletedAll(c echo.Context) error {
+	var (
+		respRole    []response.DeletedRoleResponse
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entity.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[RoleHandler-1] GetDeletedAll", err)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[RoleHandler-2] GetDeletedAll", err)
+	}
+
+	if jwtUserData.RoleName != "Super Admin" {
+		err := errors.New("only Super Admin can access API role")
+		return response.RespondWithError(c, http.StatusForbidden, "[RoleHandler-3] GetDeletedAll", err)
+	}
+
+	search := c.QueryParam("search")
+
+	roles, err := r.roleService.GetDeletedAll(ctx, search)
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("deleted role not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[RoleHandler-4] GetDeletedAll", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[RoleHandler-4] GetDeletedAll", err)
+	}
+
+	for _, role := range roles {
+		respRole = append(respRole, response.DeletedRoleResponse{
+			ID:        role.ID,
+			Name:      role.Name,
+			DeletedAt: role.DeletedAt,
+		})
+	}
+
+	resp.Message = "success"
+	resp.Data = respRole
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (r *roleHandler) Restore(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		ctx         = c.Request().Context()
+		jwtUserData = entity.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[RoleHandler-1] Restore", err)
+	}
+
+	err := json.Unmarshal([]byte(user), &jwtUserData)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[RoleHandler-2] Restore", err)
+	}
+
+	if jwtUserData.RoleName != "Super Admin" {
+		err := errors.New("only Super Admin can access API role")
+		return response.RespondWithError(c, http.StatusForbidden, "[RoleHandler-3] Restore", err)
+	}
+
+	roleIDString := c.Param("id")
+	if roleIDString == "" {
+		err := errors.New("missing or invalid role ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[RoleHandler-4] Restore", err)
+	}
+
+	roleID, err := strconv.Atoi(roleIDString)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[RoleHandler-5] Restore", err)
+	}
+
+	err = r.roleService.Restore(ctx, int64(roleID))
+	if err != nil {
+		log.Errorf("[RoleHandler-6] Restore: %v", err)
+		if err.Error() == "404" {
+			errNotFound := errors.New("deleted role not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[RoleHandler-6] Restore", errNotFound)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[RoleHandler-6] Restore", err)
+	}
+
+	resp.Message = "Role restored successfully"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
@@ -14,32 +14,80 @@ func InitRoutes(
 	userHandler inbound.UserHandlerInterface,
 	roleHandler inbound.RoleHandlerInterface,
 	uploadImageHandler inbound.UploadImageInterface,
+	customerImportHandler inbound.CustomerImportHandlerInterface,
+	addressHandler inbound.AddressHandlerInterface,
+	phoneVerificationHandler inbound.PhoneVerificationHandlerInterface,
+	privacyHandler inbound.PrivacyHandlerInterface,
+	preferenceHandler inbound.PreferenceHandlerInterface,
+	consentHandler inbound.ConsentHandlerInterface,
+	statsHandler inbound.StatsHandlerInterface,
+	notificationHandler inbound.NotificationHandlerInterface,
 ) {
 	e.Use(middleware.Recover())
+	e.Use(mid.TraceParent())
 
 	e.GET("/ping", pingHandler.Ping)
 
 	e.POST("/signin", userHandler.SignIn)
+	e.POST("/signin/consent", userHandler.SignInWithConsent)
 	e.POST("/signup", userHandler.CreateUserAccount)
 	e.POST("/forgot-password", userHandler.ForgotPassword)
 	e.GET("/verify-account", userHandler.VerifyAccount)
 	e.PUT("/update-password", userHandler.UpdatePassword)
+	e.GET("/legal-documents/current", consentHandler.GetCurrentDocuments)
 
 	adminGroup := e.Group("/admin", mid.CheckToken())
+	adminGroup.GET("/stats", statsHandler.GetDashboardStats)
 	adminGroup.GET("/customers", userHandler.GetCustomerAll)
+	adminGroup.GET("/customers/search", userHandler.SearchCustomers)
+	adminGroup.GET("/customers/nearby", userHandler.GetCustomerNearby)
+	adminGroup.GET("/customers/deleted", userHandler.GetDeletedCustomerAll)
+	adminGroup.GET("/customers/export", userHandler.ExportCustomers)
+	adminGroup.POST("/customers/import", customerImportHandler.ImportCustomers)
+	adminGroup.GET("/customers/import/:job_id", customerImportHandler.GetImportJob)
 	adminGroup.POST("/customers", userHandler.CreateCustomer)
 	adminGroup.PUT("/customers/:id", userHandler.UpdateCustomer)
+	adminGroup.PATCH("/customers/:id", userHandler.PatchCustomer)
 	adminGroup.GET("/customers/:id", userHandler.GetCustomerByID)
 	adminGroup.DELETE("/customers/:id", userHandler.DeleteCustomer)
+	adminGroup.POST("/customers/:id/restore", userHandler.RestoreCustomer)
+	adminGroup.GET("/customers/:id/addresses", addressHandler.GetCustomerAddressAll)
+	adminGroup.POST("/customers/:id/addresses", addressHandler.CreateCustomerAddress)
+	adminGroup.GET("/customers/:id/addresses/:address_id", addressHandler.GetCustomerAddressByID)
+	adminGroup.PUT("/customers/:id/addresses/:address_id", addressHandler.UpdateCustomerAddress)
+	adminGroup.DELETE("/customers/:id/addresses/:address_id", addressHandler.DeleteCustomerAddress)
+	adminGroup.GET("/customers/:id/preferences", preferenceHandler.GetCustomerPreferences)
+	adminGroup.GET("/customers/:id/notifications", notificationHandler.GetCustomerNotificationAll)
+	adminGroup.POST("/notifications/:id/resend", notificationHandler.Resend)
+
+	adminGroup.POST("/legal-documents", consentHandler.PublishDocument)
+	adminGroup.GET("/legal-documents/report", consentHandler.GetAcceptanceReport)
 
 	adminGroup.GET("/roles", roleHandler.GetAll)
+	adminGroup.GET("/roles/deleted", roleHandler.GetDeletedAll)
 	adminGroup.POST("/roles", roleHandler.Create)
 	adminGroup.PUT("/roles/:id", roleHandler.Update)
 	adminGroup.DELETE("/roles/:id", roleHandler.Delete)
 	adminGroup.GET("/roles/:id", roleHandler.GetByID)
+	adminGroup.POST("/roles/:id/restore", roleHandler.Restore)
 
 	authGroup := e.Group("/auth", mid.CheckToken())
 	authGroup.GET("/profile", userHandler.GetProfileUser)
 	authGroup.PUT("/profile", userHandler.UpdateDataUser)
+	authGroup.PATCH("/profile", userHandler.PatchProfile)
 	authGroup.POST("/profile/image-upload", uploadImageHandler.UploadImage)
+	authGroup.POST("/phone/otp", phoneVerificationHandler.SendOTP)
+	authGroup.POST("/phone/verify", phoneVerificationHandler.VerifyOTP)
+	authGroup.GET("/addresses", addressHandler.GetAll)
+	authGroup.POST("/addresses", addressHandler.Create)
+	authGroup.GET("/addresses/:address_id", addressHandler.GetByID)
+	authGroup.PUT("/addresses/:address_id", addressHandler.Update)
+	authGroup.DELETE("/addresses/:address_id", addressHandler.Delete)
+	authGroup.GET("/preferences", preferenceHandler.GetPreferences)
+	authGroup.PATCH("/preferences", preferenceHandler.UpdatePreferences)
+	authGroup.GET("/notifications", notificationHandler.GetAll)
+	authGroup.GET("/me/export", privacyHandler.ExportMyData)
+	authGroup.POST("/me/deletion-request", privacyHandler.RequestDeletion)
+	authGroup.GET("/me/deletion-request", privacyHandler.GetDeletionRequest)
+	authGroup.DELETE("/me/deletion-request", privacyHandler.CancelDeletion)
 }
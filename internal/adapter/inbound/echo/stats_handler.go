@@ -0,0 +1,98 @@
+package echo
+
+import (
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// statsDefaultRangeDays rentang default signups_per_day jika from/to tidak dikirim.
+const statsDefaultRangeDays = 30
+
+type statsHandler struct {
+	statsService service.StatsServiceInterface
+}
+
+func NewStatsHandler(statsService service.StatsServiceInterface) inbound.StatsHandlerInterface {
+	return &statsHandler{statsService: statsService}
+}
+
+// GetDashboardStats query param from & to berformat YYYY-MM-DD, default 30 hari terakhir.
+func (s *statsHandler) GetDashboardStats(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[StatsHandler-1] GetDashboardStats", err)
+	}
+
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if c.QueryParam("to") != "" {
+		parsed, err := time.Parse(time.DateOnly, c.QueryParam("to"))
+		if err != nil {
+			errBadRequest := errors.New("invalid to date, expected YYYY-MM-DD")
+			return response.RespondWithError(c, http.StatusBadRequest, "[StatsHandler-2] GetDashboardStats", errBadRequest)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(statsDefaultRangeDays - 1))
+	if c.QueryParam("from") != "" {
+		parsed, err := time.Parse(time.DateOnly, c.QueryParam("from"))
+		if err != nil {
+			errBadRequest := errors.New("invalid from date, expected YYYY-MM-DD")
+			return response.RespondWithError(c, http.StatusBadRequest, "[StatsHandler-3] GetDashboardStats", errBadRequest)
+		}
+		from = parsed
+	}
+
+	result, err := s.statsService.GetDashboardStats(ctx, from, to)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid date range, from must not be after to and range is at most 366 days")
+			return response.RespondWithError(c, http.StatusBadRequest, "[StatsHandler-4] GetDashboardStats", errBadRequest)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[StatsHandler-4] GetDashboardStats", err)
+	}
+
+	respStats := response.DashboardStatsResponse{
+		TotalCustomers:       result.TotalCustomers,
+		VerifiedCustomers:    result.VerifiedCustomers,
+		PendingVerifications: result.PendingVerifications,
+		ActiveSessions:       result.ActiveSessions,
+		SignupsPerDay:        []response.DailyCountResponse{},
+		UsersPerRole:         []response.RoleCountResponse{},
+		From:                 result.From.Format(time.DateOnly),
+		To:                   result.To.Format(time.DateOnly),
+		GeneratedAt:          result.GeneratedAt,
+	}
+
+	for _, val := range result.SignupsPerDay {
+		respStats.SignupsPerDay = append(respStats.SignupsPerDay, response.DailyCountResponse{
+			Date:  val.Date.Format(time.DateOnly),
+			Count: val.Count,
+		})
+	}
+
+	for _, val := range result.UsersPerRole {
+		respStats.UsersPerRole = append(respStats.UsersPerRole, response.RoleCountResponse{
+			RoleID:   val.RoleID,
+			RoleName: val.RoleName,
+			Count:    val.Count,
+		})
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respStats
+	return c.JSON(http.StatusOK, resp)
+}
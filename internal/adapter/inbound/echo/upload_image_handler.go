@@ -3,8 +3,12 @@ package echo
 import (
 	"bytes"
 	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
 	"clean-architecture/internal/port/inbound"
 	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,7 +28,20 @@ func NewUploadImageHandler(storageHandler outbound.MinioInterface) inbound.Uploa
 }
 
 func (u *uploadImageHandler) UploadImage(c echo.Context) error {
-	var resp = response.DefaultResponse{}
+	var (
+		resp        = response.DefaultResponse{}
+		jwtUserData = entity.JwtUserData{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[UploadImage-3] UploadImage", err)
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UploadImage-3] UploadImage", err)
+	}
 
 	file, err := c.FormFile("photo")
 	if err != nil {
@@ -54,7 +71,8 @@ func (u *uploadImageHandler) UploadImage(c echo.Context) error {
 		getExtension(file.Filename),
 	)
 
-	uploadPath := fmt.Sprintf("public/uploads/%s", newFileName)
+	// Disimpan per user agar bisa ikut di-export dan dihapus saat permintaan hapus akun
+	uploadPath := fmt.Sprintf(utils.USER_UPLOAD_PREFIX, jwtUserData.UserID) + newFileName
 
 	_, err = u.storageHandler.UploadFile(uploadPath, fileBuffer)
 	if err != nil {
@@ -7,10 +7,18 @@ import (
 	"clean-architecture/internal/domain/service"
 	"clean-architecture/internal/port/inbound"
 	"clean-architecture/utils/conv"
+	"clean-architecture/utils/cursor"
+	"clean-architecture/utils/geo"
+	"clean-architecture/utils/spreadsheet"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/gommon/log"
@@ -84,16 +92,6 @@ func (u *userHandler) UpdateCustomer(c echo.Context) error {
 		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] UpdateCustomer", err)
 	}
 
-	latString := ""
-	lngString := ""
-	if req.Lat != 0 {
-		latString = strconv.FormatFloat(req.Lat, 'g', -1, 64)
-	}
-
-	if req.Lng != 0 {
-		lngString = strconv.FormatFloat(req.Lng, 'g', -1, 64)
-	}
-
 	idParamStr := c.Param("id")
 	if idParamStr == "" {
 		err := errors.New("missing or invalid customer ID")
@@ -106,14 +104,20 @@ func (u *userHandler) UpdateCustomer(c echo.Context) error {
 		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-5] UpdateCustomer", err)
 	}
 
+	version, code, err := ifMatchVersion(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[UserHandler-7] UpdateCustomer", err)
+	}
+
 	reqEntity := entity.UserEntity{
 		ID:      id,
+		Version: version,
 		Name:    req.Name,
 		Email:   req.Email,
 		Phone:   req.Phone,
 		Address: req.Address,
-		Lat:     latString,
-		Lng:     lngString,
+		Lat:     req.Lat,
+		Lng:     req.Lng,
 		Photo:   req.Photo,
 	}
 
@@ -124,6 +128,14 @@ func (u *userHandler) UpdateCustomer(c echo.Context) error {
 			errNotFound := errors.New("customer not found")
 			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-6] UpdateCustomer", errNotFound)
 		}
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-6] UpdateCustomer", errBadRequest)
+		}
+		if err.Error() == "412" {
+			errPrecondition := errors.New("customer has been modified by another request")
+			return response.RespondWithError(c, http.StatusPreconditionFailed, "[UserHandler-6] UpdateCustomer", errPrecondition)
+		}
 		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-6] UpdateCustomer", err)
 
 	}
@@ -160,23 +172,24 @@ func (u *userHandler) CreateCustomer(c echo.Context) error {
 		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[UserHandler-4] CreateCustomer", err)
 	}
 
-	latString := strconv.FormatFloat(req.Lat, 'g', -1, 64)
-	lngString := strconv.FormatFloat(req.Lng, 'g', -1, 64)
-
 	reqEntity := entity.UserEntity{
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
 		Phone:    req.Phone,
 		Address:  req.Address,
-		Lat:      latString,
-		Lng:      lngString,
+		Lat:      req.Lat,
+		Lng:      req.Lng,
 		Photo:    req.Photo,
 		RoleID:   req.RoleID,
 	}
 
 	err := u.userService.CreateCustomer(ctx, reqEntity)
 	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-5] CreateCustomer", errBadRequest)
+		}
 		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-5] CreateCustomer", err)
 	}
 
@@ -187,6 +200,104 @@ func (u *userHandler) CreateCustomer(c echo.Context) error {
 	return c.JSON(http.StatusCreated, resp)
 }
 
+// PatchCustomer update sebagian data customer dengan JSON Merge Patch (RFC 7396).
+// Field yang dikirim null dikosongkan, field yang tidak dikirim tidak diubah. Wajib If-Match.
+func (u *userHandler) PatchCustomer(c echo.Context) error {
+	var (
+		resp     = response.DefaultResponse{}
+		ctx      = c.Request().Context()
+		req      = request.PatchCustomerRequest{}
+		respUser = response.CustomerResponse{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not valid")
+		return response.RespondWithError(c, http.StatusUnauthorized, "[UserHandler-1] PatchCustomer", err)
+	}
+
+	jwtUserData := entity.JwtUserData{}
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-1] PatchCustomer", err)
+	}
+
+	id, err := conv.StringToInt64(c.Param("id"))
+	if err != nil {
+		err := errors.New("invalid customer ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] PatchCustomer", err)
+	}
+
+	version, code, err := ifMatchVersion(c)
+	if err != nil {
+		return response.RespondWithError(c, code, "[UserHandler-3] PatchCustomer", err)
+	}
+
+	fields, code, err := readMergePatch(c, customerPatchFields, &req)
+	if err != nil {
+		return response.RespondWithError(c, code, "[UserHandler-4] PatchCustomer", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-5] PatchCustomer", err)
+	}
+
+	patch := entity.UserPatchEntity{
+		ID:       id,
+		Version:  version,
+		CallerID: jwtUserData.UserID,
+		Name:     patchField(fields, "name", req.Name),
+		Email:    patchField(fields, "email", req.Email),
+		Phone:    patchField(fields, "phone", req.Phone),
+		Address:  patchField(fields, "address", req.Address),
+		Photo:    patchField(fields, "photo", req.Photo),
+		Lat:      patchField(fields, "lat", req.Lat),
+		Lng:      patchField(fields, "lng", req.Lng),
+		RoleID:   patchField(fields, "role_id", req.RoleID),
+	}
+
+	result, err := u.userService.PatchCustomer(ctx, patch)
+	if err != nil {
+		log.Errorf("[UserHandler-6] PatchCustomer: %v", err)
+		switch err.Error() {
+		case "404":
+			errNotFound := errors.New("customer not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-6] PatchCustomer", errNotFound)
+		case "400":
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-6] PatchCustomer", errBadRequest)
+		case "403":
+			errForbidden := errors.New("photo must be uploaded by the current user")
+			return response.RespondWithError(c, http.StatusForbidden, "[UserHandler-6] PatchCustomer", errForbidden)
+		case "409":
+			errConflict := errors.New("email already used by another user")
+			return response.RespondWithError(c, http.StatusConflict, "[UserHandler-6] PatchCustomer", errConflict)
+		case "412":
+			errPrecondition := errors.New("customer has been modified by another request")
+			return response.RespondWithError(c, http.StatusPreconditionFailed, "[UserHandler-6] PatchCustomer", errPrecondition)
+		case "422":
+			errRole := errors.New("role not found")
+			return response.RespondWithError(c, http.StatusUnprocessableEntity, "[UserHandler-6] PatchCustomer", errRole)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-6] PatchCustomer", err)
+	}
+
+	respUser.ID = result.ID
+	respUser.RoleID = result.RoleID
+	respUser.Name = result.Name
+	respUser.Email = result.Email
+	respUser.Phone = result.Phone
+	respUser.Address = result.Address
+	respUser.Photo = result.Photo
+	respUser.Lat = result.Lat
+	respUser.Lng = result.Lng
+
+	resp.Message = "Success"
+	resp.Data = respUser
+
+	setETag(c, result.Version)
+	return c.JSON(http.StatusOK, resp)
+}
+
 func (u *userHandler) GetCustomerByID(c echo.Context) error {
 	var (
 		resp     = response.DefaultResponseWithPaginations{}
@@ -235,23 +346,401 @@ func (u *userHandler) GetCustomerByID(c echo.Context) error {
 	resp.Data = respUser
 	resp.Pagination = nil
 
+	setETag(c, result.Version)
 	return c.JSON(http.StatusOK, resp)
 }
 
 func (u *userHandler) GetCustomerAll(c echo.Context) error {
+	var (
+		resp = response.DefaultResponseWithPaginations{}
+		ctx  = c.Request().Context()
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] GetCustomerAll", err)
+
+	}
+
+	reqEntity, err := parseCustomerQuery(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] GetCustomerAll", err)
+	}
+
+	// Keyset pagination dipakai jika client mengirim cursor atau pagination=cursor,
+	// selain itu tetap memakai page & limit seperti sebelumnya
+	cursorStr := c.QueryParam("cursor")
+	if cursorStr != "" || c.QueryParam("pagination") == "cursor" {
+		return u.getCustomerAllByCursor(c, reqEntity, cursorStr)
+	}
+
+	results, countData, totalPages, err := u.userService.GetCustomerAll(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid filter value")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] GetCustomerAll", errBadRequest)
+		}
+		if err.Error() == "404" {
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-3] GetCustomerAll", err)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-3] GetCustomerAll", err)
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = customerListData(results, reqEntity.Fields)
+	resp.Pagination = &response.Pagination{
+		Page:       reqEntity.Page,
+		TotalCount: countData,
+		Limit:      reqEntity.Limit, // PerPage (sebelumnya)
+		TotalPage:  totalPages,
+	}
+	if reqEntity.Page < totalPages {
+		resp.Pagination.Next = paginationLink(c, map[string]string{"page": strconv.FormatInt(reqEntity.Page+1, 10)})
+	}
+	if reqEntity.Page > 1 {
+		resp.Pagination.Prev = paginationLink(c, map[string]string{"page": strconv.FormatInt(reqEntity.Page-1, 10)})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (u *userHandler) getCustomerAllByCursor(c echo.Context, reqEntity entity.QueryStringEntity, cursorStr string) error {
+	var (
+		resp = response.DefaultResponseWithPaginations{}
+		ctx  = c.Request().Context()
+	)
+
+	if cursorStr != "" {
+		reqCursor := entity.CursorEntity{}
+		if err := cursor.Decode(cursorStr, &reqCursor); err != nil {
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-1] GetCustomerAllByCursor", err)
+		}
+		// Urutan mengikuti cursor supaya halaman berikutnya konsisten dengan halaman sebelumnya
+		reqEntity.OrderBy = reqCursor.OrderBy
+		reqEntity.OrderType = reqCursor.OrderType
+		reqEntity.Cursor = &reqCursor
+	}
+
+	// Keyset pagination hanya bisa mengurutkan satu kolom NOT NULL (ditambah id)
+	if len(reqEntity.Sorts) > 1 || !slices.Contains(entity.CustomerCursorSortFields, strings.ToLower(reqEntity.OrderBy)) {
+		err := fmt.Errorf("cursor pagination only supports sorting by one of %s", strings.Join(entity.CustomerCursorSortFields, ", "))
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] GetCustomerAllByCursor", err)
+	}
+
+	results, page, err := u.userService.GetCustomerAllByCursor(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid cursor or filter value")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] GetCustomerAllByCursor", errBadRequest)
+		}
+		if err.Error() == "404" {
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-3] GetCustomerAllByCursor", err)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-3] GetCustomerAllByCursor", err)
+	}
+
+	pagination := &response.Pagination{Limit: reqEntity.Limit}
+	if page.Next != nil {
+		if pagination.NextCursor, err = cursor.Encode(page.Next); err != nil {
+			return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] GetCustomerAllByCursor", err)
+		}
+		pagination.Next = paginationLink(c, map[string]string{"cursor": pagination.NextCursor, "page": ""})
+	}
+	if page.Prev != nil {
+		if pagination.PrevCursor, err = cursor.Encode(page.Prev); err != nil {
+			return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-5] GetCustomerAllByCursor", err)
+		}
+		pagination.Prev = paginationLink(c, map[string]string{"cursor": pagination.PrevCursor, "page": ""})
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = customerListData(results, reqEntity.Fields)
+	resp.Pagination = pagination
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SearchCustomers pencarian customer berdasarkan relevansi (full-text + trigram).
+// Kata kunci dikirim lewat q (atau search), filter[...] & page/limit sama seperti GetCustomerAll.
+func (u *userHandler) SearchCustomers(c echo.Context) error {
 	var (
 		resp     = response.DefaultResponseWithPaginations{}
 		ctx      = c.Request().Context()
-		respUser = []response.CustomerListResponse{}
+		respUser = []response.CustomerSearchResponse{}
 	)
 
 	user := c.Get("user").(string)
 	if user == "" {
 		err := errors.New("data token not found")
-		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] GetCustomerAll", err)
+		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] SearchCustomers", err)
+	}
+
+	reqEntity, err := parseCustomerQuery(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] SearchCustomers", err)
+	}
+	if q := strings.TrimSpace(c.QueryParam("q")); q != "" {
+		reqEntity.Search = q
+	}
+	if strings.TrimSpace(reqEntity.Search) == "" {
+		err := errors.New("search keyword is required")
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] SearchCustomers", err)
+	}
+
+	results, countData, totalPages, err := u.userService.SearchCustomers(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid search keyword or filter value")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-4] SearchCustomers", errBadRequest)
+		}
+		if err.Error() == "404" {
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-4] SearchCustomers", err)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] SearchCustomers", err)
+	}
+
+	for _, val := range results {
+		respUser = append(respUser, response.CustomerSearchResponse{
+			ID:         val.User.ID,
+			Name:       val.User.Name,
+			Email:      val.User.Email,
+			Phone:      val.User.Phone,
+			Photo:      val.User.Photo,
+			IsVerified: val.User.IsVerified,
+			Score:      val.Score,
+			Highlights: val.Highlights,
+		})
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respUser
+	resp.Pagination = &response.Pagination{
+		Page:       reqEntity.Page,
+		TotalCount: countData,
+		Limit:      reqEntity.Limit,
+		TotalPage:  totalPages,
+	}
+	if reqEntity.Page < totalPages {
+		resp.Pagination.Next = paginationLink(c, map[string]string{"page": strconv.FormatInt(reqEntity.Page+1, 10)})
+	}
+	if reqEntity.Page > 1 {
+		resp.Pagination.Prev = paginationLink(c, map[string]string{"page": strconv.FormatInt(reqEntity.Page-1, 10)})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+const (
+	nearbyDefaultRadiusKm = 5
+	nearbyMaxRadiusKm     = 500
+)
+
+// GetCustomerNearby customer terdekat dari titik lat & lng dalam radius_km (default 5 km),
+// atau di dalam box min_lat, min_lng, max_lat, max_lng. Hasil diurutkan berdasarkan jarak.
+func (u *userHandler) GetCustomerNearby(c echo.Context) error {
+	var (
+		resp     = response.DefaultResponseWithPaginations{}
+		ctx      = c.Request().Context()
+		respUser = []response.NearbyCustomerResponse{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] GetCustomerNearby", err)
+	}
+
+	listQuery, err := parseCustomerQuery(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] GetCustomerNearby", err)
+	}
+
+	reqEntity, err := parseNearbyQuery(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] GetCustomerNearby", err)
+	}
+	reqEntity.Page = listQuery.Page
+	reqEntity.Limit = listQuery.Limit
+	reqEntity.Filters = listQuery.Filters
+
+	results, countData, totalPages, err := u.userService.GetCustomerNearby(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid filter value")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-4] GetCustomerNearby", errBadRequest)
+		}
+		if err.Error() == "404" {
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-4] GetCustomerNearby", err)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] GetCustomerNearby", err)
+	}
+
+	for _, val := range results {
+		respUser = append(respUser, response.NearbyCustomerResponse{
+			ID:         val.User.ID,
+			Name:       val.User.Name,
+			Email:      val.User.Email,
+			Phone:      val.User.Phone,
+			Photo:      val.User.Photo,
+			Address:    val.User.Address,
+			Lat:        val.User.Lat,
+			Lng:        val.User.Lng,
+			DistanceKm: math.Round(val.DistanceKm*1000) / 1000,
+		})
+	}
+
+	resp.Message = "Data retrieved successfully"
+	resp.Data = respUser
+	resp.Pagination = &response.Pagination{
+		Page:       reqEntity.Page,
+		TotalCount: countData,
+		Limit:      reqEntity.Limit,
+		TotalPage:  totalPages,
+	}
+	if reqEntity.Page < totalPages {
+		resp.Pagination.Next = paginationLink(c, map[string]string{"page": strconv.FormatInt(reqEntity.Page+1, 10)})
+	}
+	if reqEntity.Page > 1 {
+		resp.Pagination.Prev = paginationLink(c, map[string]string{"page": strconv.FormatInt(reqEntity.Page-1, 10)})
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// parseNearbyQuery membaca titik pusat & radius atau box. Pada mode box titik pusat
+// opsional (default tengah box) dan hanya dipakai untuk mengurutkan jarak.
+func parseNearbyQuery(c echo.Context) (entity.NearbyQueryEntity, error) {
+	query := entity.NearbyQueryEntity{RadiusKm: nearbyDefaultRadiusKm}
+
+	boxParams := []string{"min_lat", "min_lng", "max_lat", "max_lng"}
+	hasBox := false
+	for _, key := range boxParams {
+		if c.QueryParam(key) != "" {
+			hasBox = true
+			break
+		}
+	}
+
+	if hasBox {
+		values := make([]float64, len(boxParams))
+		for i, key := range boxParams {
+			val, err := parseCoordinate(c.QueryParam(key), key, strings.HasSuffix(key, "_lat"))
+			if err != nil {
+				return query, err
+			}
+			values[i] = val
+		}
+
+		box := entity.GeoBoxEntity{MinLat: values[0], MinLng: values[1], MaxLat: values[2], MaxLng: values[3]}
+		if box.MinLat > box.MaxLat {
+			return query, errors.New("min_lat must be less than or equal to max_lat")
+		}
+		query.Box = &box
+		query.Lat, query.Lng = geo.Box(box).Center()
+	}
+
+	if c.QueryParam("lat") != "" || c.QueryParam("lng") != "" || !hasBox {
+		lat, err := parseCoordinate(c.QueryParam("lat"), "lat", true)
+		if err != nil {
+			return query, err
+		}
+		lng, err := parseCoordinate(c.QueryParam("lng"), "lng", false)
+		if err != nil {
+			return query, err
+		}
+		query.Lat, query.Lng = lat, lng
+	}
+
+	if radiusStr := c.QueryParam("radius_km"); radiusStr != "" {
+		radius, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radius <= 0 || radius > nearbyMaxRadiusKm {
+			return query, fmt.Errorf("radius_km must be greater than 0 and at most %d", nearbyMaxRadiusKm)
+		}
+		query.RadiusKm = radius
+	}
+
+	return query, nil
+}
+
+func parseCoordinate(value, name string, isLat bool) (float64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("%s is required", name)
+	}
+
+	limit := 180.0
+	if isLat {
+		limit = 90
+	}
+
+	coordinate, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(coordinate) || coordinate < -limit || coordinate > limit {
+		return 0, fmt.Errorf("%s must be a number between %v and %v", name, -limit, limit)
+	}
+	return coordinate, nil
+}
+
+// customerListData membentuk data list customer. Tanpa sparse fieldset response tetap
+// CustomerListResponse, dengan fields=... hanya field yang diminta yang dikirim.
+func customerListData(results []entity.UserEntity, fields []string) any {
+	if len(fields) == 0 {
+		respUser := []response.CustomerListResponse{}
+		for _, val := range results {
+			respUser = append(respUser, response.CustomerListResponse{
+				ID:    val.ID,
+				Name:  val.Name,
+				Email: val.Email,
+				Photo: val.Photo,
+				Phone: val.Phone,
+			})
+		}
+		return respUser
+	}
+
+	respUser := make([]map[string]any, 0, len(results))
+	for _, val := range results {
+		all := map[string]any{
+			"id":          val.ID,
+			"name":        val.Name,
+			"email":       val.Email,
+			"phone":       val.Phone,
+			"photo":       val.Photo,
+			"address":     val.Address,
+			"lat":         val.Lat,
+			"lng":         val.Lng,
+			"is_verified": val.IsVerified,
+			"created_at":  val.CreatedAt,
+			"role":        val.RoleName,
+		}
+
+		item := make(map[string]any, len(fields))
+		for _, field := range fields {
+			item[field] = all[field]
+		}
+		respUser = append(respUser, item)
+	}
+	return respUser
+}
 
+// paginationLink membuat link relatif dari URL request dengan query param yang diganti.
+// Nilai kosong berarti query param tersebut dihapus.
+func paginationLink(c echo.Context, params map[string]string) string {
+	link := *c.Request().URL
+	query := link.Query()
+	for key, val := range params {
+		if val == "" {
+			query.Del(key)
+			continue
+		}
+		query.Set(key, val)
 	}
+	link.RawQuery = query.Encode()
+	return link.RequestURI()
+}
 
+// parseCustomerQuery membaca query param search, order_by, order_type, sort, filter[...],
+// fields, page & limit yang dipakai bersama oleh endpoint list customer.
+func parseCustomerQuery(c echo.Context) (entity.QueryStringEntity, error) {
 	search := c.QueryParam("search")
 	orderBy := "created_at"
 	if c.QueryParam("order_by") != "" {
@@ -281,44 +770,364 @@ func (u *userHandler) GetCustomerAll(c echo.Context) error {
 		}
 	}
 
-	reqEntity := entity.QueryStringEntity{
+	filters, err := parseListFilters(c.QueryParams(), entity.CustomerFilterFields)
+	if err != nil {
+		return entity.QueryStringEntity{}, err
+	}
+
+	// sort=... menggantikan order_by & order_type, field pertama tetap dipakai
+	// sebagai OrderBy/OrderType untuk keyset pagination
+	sorts, err := parseListSorts(c.QueryParam("sort"), entity.CustomerSortFields)
+	if err != nil {
+		return entity.QueryStringEntity{}, err
+	}
+	if len(sorts) > 0 {
+		orderBy = sorts[0].Field
+		orderType = "asc"
+		if sorts[0].Desc {
+			orderType = "desc"
+		}
+	}
+
+	fields, err := parseListFields(c.QueryParam("fields"), entity.CustomerFields)
+	if err != nil {
+		return entity.QueryStringEntity{}, err
+	}
+
+	return entity.QueryStringEntity{
 		Search:    search,
 		Page:      page,
 		Limit:     limit,
 		OrderBy:   orderBy,
 		OrderType: orderType,
+		Filters:   filters,
+		Sorts:     sorts,
+		Fields:    fields,
+	}, nil
+}
+
+const (
+	exportFormatNDJSON = "ndjson"
+
+	// response di-flush ke client setiap N baris
+	exportFlushEvery = 500
+)
+
+var customerExportHeader = []any{"id", "name", "email", "phone", "role", "address", "lat", "lng", "photo", "is_verified", "created_at"}
+
+// ExportCustomers streaming seluruh customer (tanpa pagination) dengan filter search &
+// order_by yang sama seperti GetCustomerAll. Format: csv (default), xlsx atau ndjson.
+func (u *userHandler) ExportCustomers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] ExportCustomers", err)
 	}
 
-	results, countData, totalPages, err := u.userService.GetCustomerAll(ctx, reqEntity)
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = spreadsheet.FormatCSV
+	}
+
+	var contentType string
+	switch format {
+	case spreadsheet.FormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case spreadsheet.FormatXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case exportFormatNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		err := errors.New("format must be one of csv, xlsx, ndjson")
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] ExportCustomers", err)
+	}
+
+	reqEntity, err := parseCustomerQuery(c)
+	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] ExportCustomers", err)
+	}
+
+	var (
+		write func(entity.UserEntity) error
+		flush func() error
+		done  func() error
+	)
+
+	if format == exportFormatNDJSON {
+		encoder := json.NewEncoder(c.Response())
+		write = func(val entity.UserEntity) error {
+			return encoder.Encode(response.CustomerExportResponse{
+				ID:         val.ID,
+				Name:       val.Name,
+				Email:      val.Email,
+				Phone:      val.Phone,
+				Role:       val.RoleName,
+				Address:    val.Address,
+				Lat:        val.Lat,
+				Lng:        val.Lng,
+				Photo:      val.Photo,
+				IsVerified: val.IsVerified,
+				CreatedAt:  val.CreatedAt,
+			})
+		}
+		flush = func() error { return nil }
+		done = func() error { return nil }
+	} else {
+		rowWriter, err := spreadsheet.NewRowWriter(format, c.Response())
+		if err != nil {
+			return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] ExportCustomers", err)
+		}
+		if err := rowWriter.WriteRow(customerExportHeader); err != nil {
+			return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-5] ExportCustomers", err)
+		}
+		write = func(val entity.UserEntity) error {
+			return rowWriter.WriteRow([]any{
+				val.ID, val.Name, val.Email, val.Phone, val.RoleName, val.Address,
+				conv.LatLngToString(val.Lat), conv.LatLngToString(val.Lng), val.Photo, val.IsVerified, val.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		flush = rowWriter.Flush
+		done = rowWriter.Close
+	}
+
+	fileName := fmt.Sprintf("customers_%s.%s", time.Now().Format("20060102_150405"), format)
+	c.Response().Header().Set(echo.HeaderContentType, contentType)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+
+	count := 0
+	err = u.userService.ExportCustomers(ctx, reqEntity, func(val entity.UserEntity) error {
+		if err := write(val); err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			if err := flush(); err != nil {
+				return err
+			}
+			c.Response().Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Status 200 baru terkirim saat byte pertama ditulis, jadi error sebelum itu
+		// (misal nilai filter tidak valid) masih bisa dikembalikan sebagai JSON
+		if !c.Response().Committed {
+			c.Response().Header().Del(echo.HeaderContentDisposition)
+			if err.Error() == "400" {
+				errBadRequest := errors.New("invalid filter value")
+				return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-6] ExportCustomers", errBadRequest)
+			}
+			return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-6] ExportCustomers", err)
+		}
+
+		// Header & sebagian body sudah terkirim, status code tidak bisa diubah lagi
+		log.Errorf("[UserHandler-6] ExportCustomers: %v", err)
+		return nil
+	}
+
+	if err := done(); err != nil {
+		log.Errorf("[UserHandler-7] ExportCustomers: %v", err)
+		return nil
+	}
+	c.Response().Flush()
+
+	log.Infof("[UserHandler-8] ExportCustomers: %d customers exported as %s", count, format)
+	return nil
+}
+
+func (u *userHandler) GetDeletedCustomerAll(c echo.Context) error {
+	var (
+		resp     = response.DefaultResponseWithPaginations{}
+		ctx      = c.Request().Context()
+		respUser = []response.DeletedCustomerResponse{}
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] GetDeletedCustomerAll", err)
+	}
+
+	reqEntity, err := parseCustomerQuery(c)
 	if err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] GetDeletedCustomerAll", err)
+	}
+	if c.QueryParam("order_by") == "" && len(reqEntity.Sorts) == 0 {
+		reqEntity.OrderBy = "deleted_at"
+	}
+
+	results, countData, totalPages, err := u.userService.GetDeletedCustomerAll(ctx, reqEntity)
+	if err != nil {
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid filter value")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] GetDeletedCustomerAll", errBadRequest)
+		}
 		if err.Error() == "404" {
-			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-2] GetCustomerAll", err)
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-3] GetDeletedCustomerAll", err)
 		}
-		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-2] GetCustomerAll", err)
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-3] GetDeletedCustomerAll", err)
 	}
 
 	for _, val := range results {
-		respUser = append(respUser, response.CustomerListResponse{
-			ID:    val.ID,
-			Name:  val.Name,
-			Email: val.Email,
-			Photo: val.Photo,
-			Phone: val.Phone,
+		respUser = append(respUser, response.DeletedCustomerResponse{
+			ID:        val.ID,
+			Name:      val.Name,
+			Email:     val.Email,
+			Phone:     val.Phone,
+			Photo:     val.Photo,
+			DeletedAt: val.DeletedAt,
 		})
 	}
 
 	resp.Message = "Data retrieved successfully"
 	resp.Data = respUser
 	resp.Pagination = &response.Pagination{
-		Page:       page,
+		Page:       reqEntity.Page,
 		TotalCount: countData,
-		Limit:      limit, // PerPage (sebelumnya)
+		Limit:      reqEntity.Limit,
 		TotalPage:  totalPages,
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+func (u *userHandler) RestoreCustomer(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not valid")
+		return response.RespondWithError(c, http.StatusUnauthorized, "[UserHandler-1] RestoreCustomer", err)
+	}
+
+	idParamStr := c.Param("id")
+	if idParamStr == "" {
+		err := errors.New("missing or invalid customer ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] RestoreCustomer", err)
+	}
+
+	id, err := conv.StringToInt64(idParamStr)
+	if err != nil {
+		err := errors.New("invalid customer ID")
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-3] RestoreCustomer", err)
+	}
+
+	err = u.userService.RestoreCustomer(ctx, id)
+	if err != nil {
+		switch err.Error() {
+		case "404":
+			errNotFound := errors.New("deleted customer not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-4] RestoreCustomer", errNotFound)
+		case "409":
+			errErased := errors.New("customer data has been erased and cannot be restored")
+			return response.RespondWithError(c, http.StatusConflict, "[UserHandler-4] RestoreCustomer", errErased)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] RestoreCustomer", err)
+	}
+
+	resp.Message = "Customer restored successfully"
+	resp.Data = nil
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PatchProfile update sebagian profil sendiri dengan JSON Merge Patch (RFC 7396).
+// If-Match opsional, jika dikirim version dicek agar perubahan dari device lain tidak tertimpa.
+func (u *userHandler) PatchProfile(c echo.Context) error {
+	var (
+		resp        = response.DefaultResponse{}
+		respProfile = response.ProfileResponse{}
+		ctx         = c.Request().Context()
+		req         = request.PatchProfileRequest{}
+		jwtUserData = entity.JwtUserData{}
+		version     int64
+	)
+
+	user := c.Get("user").(string)
+	if user == "" {
+		err := errors.New("data token not found")
+		return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-1] PatchProfile", err)
+	}
+
+	if err := json.Unmarshal([]byte(user), &jwtUserData); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-2] PatchProfile", err)
+	}
+
+	if c.Request().Header.Get(headerIfMatch) != "" {
+		ifMatch, code, err := ifMatchVersion(c)
+		if err != nil {
+			return response.RespondWithError(c, code, "[UserHandler-3] PatchProfile", err)
+		}
+		version = ifMatch
+	}
+
+	fields, code, err := readMergePatch(c, profilePatchFields, &req)
+	if err != nil {
+		return response.RespondWithError(c, code, "[UserHandler-4] PatchProfile", err)
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-5] PatchProfile", err)
+	}
+
+	patch := entity.UserPatchEntity{
+		ID:       jwtUserData.UserID,
+		Version:  version,
+		CallerID: jwtUserData.UserID,
+		Name:     patchField(fields, "name", req.Name),
+		Email:    patchField(fields, "email", req.Email),
+		Phone:    patchField(fields, "phone", req.Phone),
+		Address:  patchField(fields, "address", req.Address),
+		Photo:    patchField(fields, "photo", req.Photo),
+		Lat:      patchField(fields, "lat", req.Lat),
+		Lng:      patchField(fields, "lng", req.Lng),
+	}
+
+	dataUser, err := u.userService.PatchProfile(ctx, patch)
+	if err != nil {
+		switch err.Error() {
+		case "404":
+			errNotFound := errors.New("user not found")
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-6] PatchProfile", errNotFound)
+		case "400":
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-6] PatchProfile", errBadRequest)
+		case "403":
+			errForbidden := errors.New("photo must be uploaded by the current user")
+			return response.RespondWithError(c, http.StatusForbidden, "[UserHandler-6] PatchProfile", errForbidden)
+		case "409":
+			errConflict := errors.New("email already used by another user")
+			return response.RespondWithError(c, http.StatusConflict, "[UserHandler-6] PatchProfile", errConflict)
+		case "412":
+			errPrecondition := errors.New("profile has been modified by another request")
+			return response.RespondWithError(c, http.StatusPreconditionFailed, "[UserHandler-6] PatchProfile", errPrecondition)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-6] PatchProfile", err)
+	}
+
+	respProfile.Address = dataUser.Address
+	respProfile.Name = dataUser.Name
+	respProfile.Email = dataUser.Email
+	respProfile.ID = dataUser.ID
+	respProfile.Lat = dataUser.Lat
+	respProfile.Lng = dataUser.Lng
+	respProfile.Phone = dataUser.Phone
+	respProfile.Photo = dataUser.Photo
+	respProfile.RoleName = dataUser.RoleName
+	respProfile.PhoneVerifiedAt = dataUser.PhoneVerifiedAt
+
+	resp.Message = "Success"
+	resp.Data = respProfile
+
+	setETag(c, dataUser.Version)
+	return c.JSON(http.StatusOK, resp)
+}
+
 func (u *userHandler) UpdateDataUser(c echo.Context) error {
 	var (
 		resp        = response.DefaultResponse{}
@@ -365,6 +1174,10 @@ func (u *userHandler) UpdateDataUser(c echo.Context) error {
 			errNotFound := errors.New("user not found")
 			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-5] UpdateDataUser", errNotFound)
 		}
+		if err.Error() == "400" {
+			errBadRequest := errors.New("invalid phone number")
+			return response.RespondWithError(c, http.StatusBadRequest, "[UserHandler-5] UpdateDataUser", errBadRequest)
+		}
 		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-5] UpdateDataUser", err)
 	}
 
@@ -412,10 +1225,12 @@ func (u *userHandler) GetProfileUser(c echo.Context) error {
 	respProfile.Phone = dataUser.Phone
 	respProfile.Photo = dataUser.Photo
 	respProfile.RoleName = dataUser.RoleName
+	respProfile.PhoneVerifiedAt = dataUser.PhoneVerifiedAt
 
 	resp.Message = "success"
 	resp.Data = respProfile
 
+	setETag(c, dataUser.Version)
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -569,10 +1384,18 @@ func (u *userHandler) CreateUserAccount(c echo.Context) error {
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
+		Consents: []entity.ConsentEntity{
+			{DocType: service.LegalDocumentTerms, Version: req.TermsVersion, IPAddress: c.RealIP()},
+			{DocType: service.LegalDocumentPrivacy, Version: req.PrivacyVersion, IPAddress: c.RealIP()},
+		},
 	}
 
 	err := u.userService.CreateUserAccount(ctx, reqEntity)
 	if err != nil {
+		if err.Error() == "422" {
+			errOutdated := errors.New("terms of service or privacy policy version is not the current version")
+			return response.RespondWithError(c, http.StatusUnprocessableEntity, "[UserHandler-4] CreateUserAccount", errOutdated)
+		}
 		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] CreateUserAccount", err)
 	}
 
@@ -582,10 +1405,9 @@ func (u *userHandler) CreateUserAccount(c echo.Context) error {
 
 func (u *userHandler) SignIn(c echo.Context) error {
 	var (
-		req        = request.SignInRequest{}
-		resp       = response.DefaultResponse{}
-		respSignIn = response.SignInResponse{}
-		ctx        = c.Request().Context()
+		req  = request.SignInRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
 	)
 
 	if err := c.Bind(&req); err != nil {
@@ -598,6 +1420,7 @@ func (u *userHandler) SignIn(c echo.Context) error {
 
 	reqEntity := entity.UserEntity{
 		Email:    req.Email,
+		Phone:    req.Phone,
 		Password: req.Password,
 	}
 	user, token, err := u.userService.SignIn(ctx, reqEntity)
@@ -605,9 +1428,54 @@ func (u *userHandler) SignIn(c echo.Context) error {
 		if err.Error() == "404" {
 			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-3] SignIn", err)
 		}
+		if err.Error() == "428" {
+			// Versi baru syarat & ketentuan/kebijakan privasi harus disetujui lewat /signin/consent
+			resp.Message = "please accept the latest terms of service and privacy policy"
+			resp.Data = response.ConsentChallengeResponse{ChallengeToken: token}
+			return c.JSON(http.StatusPreconditionRequired, resp)
+		}
 		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-4] SignIn", err)
 	}
 
+	return signInResponse(c, user, token)
+}
+
+func (u *userHandler) SignInWithConsent(c echo.Context) error {
+	var (
+		req = request.SignInConsentRequest{}
+		ctx = c.Request().Context()
+	)
+
+	if err := c.Bind(&req); err != nil {
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[UserHandler-1] SignInWithConsent", err)
+	}
+
+	if err := c.Validate(req); err != nil {
+		return response.RespondWithError(c, http.StatusUnprocessableEntity, "[UserHandler-2] SignInWithConsent", err)
+	}
+
+	user, token, err := u.userService.SignInWithConsent(ctx, req.ChallengeToken, req.DocumentIDs, c.RealIP())
+	if err != nil {
+		if err.Error() == "404" {
+			errNotFound := errors.New("challenge token not found or expired")
+			return response.RespondWithError(c, http.StatusNotFound, "[UserHandler-3] SignInWithConsent", errNotFound)
+		}
+		if err.Error() == "422" {
+			errInvalid := errors.New("all current legal documents must be accepted")
+			return response.RespondWithError(c, http.StatusUnprocessableEntity, "[UserHandler-3] SignInWithConsent", errInvalid)
+		}
+		return response.RespondWithError(c, http.StatusInternalServerError, "[UserHandler-3] SignInWithConsent", err)
+	}
+
+	return signInResponse(c, user, token)
+}
+
+func signInResponse(c echo.Context, user *entity.UserEntity, token string) error {
+	var (
+		resp       = response.DefaultResponse{}
+		respSignIn = response.SignInResponse{}
+	)
+
 	respSignIn.ID = user.ID
 	respSignIn.Name = user.Name
 	respSignIn.Email = user.Email
@@ -0,0 +1,334 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkaretry"
+	"clean-architecture/utils/traceparent"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	forwardInitialBackoff = time.Second
+	forwardMaxBackoff     = time.Minute
+)
+
+// HandlerFunc memproses satu pesan. Error "400"/"422" berarti pesan tidak valid dan tidak
+// akan berhasil jika diulang sehingga langsung dikirim ke DLQ. Error lain dicoba lagi lewat
+// retry topic sesuai RetryDelays, setelah itu dikirim ke DLQ.
+type HandlerFunc func(ctx context.Context, msg entity.ConsumedMessageEntity) error
+
+type Consumer struct {
+	group       sarama.ConsumerGroup
+	producer    outbound.KafkaProducerInterface
+	serializer  outbound.KafkaSerializerInterface
+	topics      []string
+	retryDelays []time.Duration
+	handlers    map[string]HandlerFunc
+}
+
+// NewKafkaConsumer ikut men-subscribe retry topic tiap topic (<topic>.retry.N, satu per delay).
+// Retry topic dan DLQ (<topic>.dlq) harus sudah ada atau auto create topic aktif di broker.
+// serializer boleh nil (data CloudEvents binary berupa JSON biasa), selain itu data pesan CloudEvents
+// binary dibaca lewat serializer yang sama dengan producer.
+func NewKafkaConsumer(brokers []string, groupID string, topics []string, config *sarama.Config,
+	producer outbound.KafkaProducerInterface, serializer outbound.KafkaSerializerInterface,
+	retryDelays []time.Duration) (*Consumer, error) {
+	if groupID == "" || len(topics) == 0 {
+		return nil, errors.New("kafka consumer group and topics are required")
+	}
+
+	group, err := sarama.NewConsumerGroup(brokers, groupID, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Consumer{
+		group:       group,
+		producer:    producer,
+		serializer:  serializer,
+		topics:      topics,
+		retryDelays: retryDelays,
+		handlers:    map[string]HandlerFunc{},
+	}, nil
+}
+
+// Register mendaftarkan handler untuk nama event (event.name di envelope pesan).
+func (k *Consumer) Register(eventName string, handler HandlerFunc) {
+	k.handlers[eventName] = handler
+}
+
+// Run bergabung ke consumer group sampai ctx selesai atau Close dipanggil. Consume
+// dipanggil ulang setiap kali terjadi rebalance.
+func (k *Consumer) Run(ctx context.Context) error {
+	go func() {
+		for err := range k.group.Errors() {
+			log.Errorf("[KafkaConsumer-1] Run: %v", err)
+		}
+	}()
+
+	topics := k.subscribedTopics()
+	log.Infof("[KafkaConsumer-2] Run: consuming topics %v", topics)
+	for {
+		if err := k.group.Consume(ctx, topics, k); err != nil {
+			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+				return nil
+			}
+			log.Errorf("[KafkaConsumer-3] Run: %v", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(forwardInitialBackoff):
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (k *Consumer) Close() error {
+	if err := k.group.Close(); err != nil {
+		log.Errorf("[KafkaConsumer-4] Failed to close consumer group: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (k *Consumer) Setup(session sarama.ConsumerGroupSession) error {
+	log.Infof("[KafkaConsumer-5] Setup: generation %d assigned %v", session.GenerationID(), session.Claims())
+	return nil
+}
+
+// Cleanup dipanggil sebelum partisi dilepas saat rebalance, offset yang sudah ditandai di-commit dulu.
+func (k *Consumer) Cleanup(session sarama.ConsumerGroupSession) error {
+	session.Commit()
+	log.Infof("[KafkaConsumer-6] Cleanup: generation %d released", session.GenerationID())
+	return nil
+}
+
+func (k *Consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			if !k.process(session.Context(), msg) {
+				// Session berakhir sebelum pesan selesai, offset tidak di-commit
+				// sehingga pesan dibaca ulang oleh pemilik partisi berikutnya
+				return nil
+			}
+
+			session.MarkMessage(msg, "")
+			session.Commit()
+		}
+	}
+}
+
+func (k *Consumer) subscribedTopics() []string {
+	topics := append([]string{}, k.topics...)
+	for _, topic := range k.topics {
+		for attempt := 1; attempt <= len(k.retryDelays); attempt++ {
+			topics = append(topics, kafkaretry.RetryTopic(topic, attempt))
+		}
+	}
+	return topics
+}
+
+// process mengembalikan true jika offset pesan boleh di-commit, yaitu pesan berhasil diproses,
+// dilewati, atau sudah diteruskan ke retry topic / DLQ. False hanya jika ctx selesai lebih dulu.
+func (k *Consumer) process(ctx context.Context, msg *sarama.ConsumerMessage) bool {
+	headers := messageHeaders(msg)
+
+	// Pesan di retry topic ditahan sampai waktu retry-nya tiba. Delay satu retry topic selalu
+	// sama sehingga pesan berikutnya di partisi yang sama tidak mungkin jatuh tempo lebih dulu
+	if notBefore, err := time.Parse(time.RFC3339Nano, headers[kafkaretry.HeaderNotBefore]); err == nil {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(time.Until(notBefore)):
+		}
+	}
+
+	consumed, err := k.decodeMessage(ctx, msg, headers)
+	if err != nil {
+		log.Errorf("[KafkaConsumer-7] process: malformed message topic=%s partition=%d offset=%d: %v",
+			msg.Topic, msg.Partition, msg.Offset, err)
+		return k.forward(ctx, msg, headers, kafkaretry.DeadLetterTopic(consumed.Topic), consumed.Attempt, nil, err)
+	}
+
+	handler, ok := k.handlers[consumed.EventName]
+	if !ok {
+		log.Debugf("[KafkaConsumer-8] process: no handler for event %s", consumed.EventName)
+		return true
+	}
+
+	handlerCtx := ctx
+	if traceparent.Valid(consumed.TraceParent) {
+		handlerCtx = traceparent.NewContext(ctx, consumed.TraceParent)
+	}
+
+	err = handler(handlerCtx, consumed)
+	if err == nil {
+		return true
+	}
+
+	if ctx.Err() != nil {
+		// Handler terhenti karena shutdown/rebalance, bukan kegagalan pesan
+		return false
+	}
+
+	attempt := consumed.Attempt + 1
+	if err.Error() == "400" || err.Error() == "422" || attempt > len(k.retryDelays) {
+		log.Errorf("[KafkaConsumer-9] process: event %s offset=%d sent to DLQ after %d attempts: %v",
+			consumed.EventName, msg.Offset, attempt, err)
+		return k.forward(ctx, msg, headers, kafkaretry.DeadLetterTopic(consumed.Topic), attempt, nil, err)
+	}
+
+	notBefore := time.Now().Add(k.retryDelays[attempt-1])
+	log.Errorf("[KafkaConsumer-10] process: event %s offset=%d retry %d at %s: %v",
+		consumed.EventName, msg.Offset, attempt, notBefore.Format(time.RFC3339), err)
+	return k.forward(ctx, msg, headers, kafkaretry.RetryTopic(consumed.Topic, attempt), attempt, &notBefore, err)
+}
+
+// forward meneruskan pesan ke retry topic / DLQ dengan header asli ditambah alasan gagal.
+// Jika producer gagal, pengiriman diulang di tempat sampai berhasil atau ctx selesai.
+func (k *Consumer) forward(ctx context.Context, msg *sarama.ConsumerMessage, headers map[string]string,
+	topic string, attempt int, notBefore *time.Time, cause error) bool {
+	record := entity.KafkaRecordEntity{
+		Topic:   topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: kafkaretry.OriginalHeaders(headers),
+	}
+
+	// Posisi asal dicatat sekali saat pertama kali gagal dan dibawa terus sampai DLQ
+	originalTopic, ok := headers[kafkaretry.HeaderOriginalTopic]
+	if !ok {
+		originalTopic = msg.Topic
+		headers[kafkaretry.HeaderOriginalPartition] = strconv.FormatInt(int64(msg.Partition), 10)
+		headers[kafkaretry.HeaderOriginalOffset] = strconv.FormatInt(msg.Offset, 10)
+	}
+	record.Headers[kafkaretry.HeaderOriginalTopic] = originalTopic
+	record.Headers[kafkaretry.HeaderOriginalPartition] = headers[kafkaretry.HeaderOriginalPartition]
+	record.Headers[kafkaretry.HeaderOriginalOffset] = headers[kafkaretry.HeaderOriginalOffset]
+	record.Headers[kafkaretry.HeaderAttempt] = strconv.Itoa(attempt)
+	record.Headers[kafkaretry.HeaderError] = cause.Error()
+	record.Headers[kafkaretry.HeaderFailedAt] = time.Now().Format(time.RFC3339)
+	if notBefore != nil {
+		record.Headers[kafkaretry.HeaderNotBefore] = notBefore.Format(time.RFC3339Nano)
+	}
+
+	backoff := forwardInitialBackoff
+	for {
+		err := k.producer.ProduceRecord(record)
+		if err == nil {
+			return true
+		}
+
+		log.Errorf("[KafkaConsumer-11] forward: to %s retry in %s: %v", topic, backoff, err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, forwardMaxBackoff)
+	}
+}
+
+func messageHeaders(msg *sarama.ConsumerMessage) map[string]string {
+	headers := map[string]string{}
+	for _, header := range msg.Headers {
+		if header != nil {
+			headers[string(header.Key)] = string(header.Value)
+		}
+	}
+	return headers
+}
+
+// decodeMessage mengenali CloudEvents binary (header ce_type), CloudEvents structured (field
+// specversion) dan envelope legacy. Topic dan Attempt selalu terisi walaupun pesan tidak valid,
+// dipakai untuk menentukan DLQ. Data CloudEvents binary di-decode dengan serializer jika diisi
+// lalu diteruskan ke handler sebagai JSON.
+func (k *Consumer) decodeMessage(ctx context.Context, msg *sarama.ConsumerMessage, headers map[string]string) (entity.ConsumedMessageEntity, error) {
+	consumed := entity.ConsumedMessageEntity{
+		Topic:       msg.Topic,
+		Partition:   msg.Partition,
+		Offset:      msg.Offset,
+		Key:         string(msg.Key),
+		TraceParent: headers[traceparent.Header],
+		Timestamp:   msg.Timestamp,
+	}
+	if originalTopic, ok := headers[kafkaretry.HeaderOriginalTopic]; ok {
+		consumed.Topic = originalTopic
+	}
+	if attempt, err := strconv.Atoi(headers[kafkaretry.HeaderAttempt]); err == nil {
+		consumed.Attempt = attempt
+	}
+
+	if eventType, ok := headers["ce_type"]; ok {
+		data, err := k.eventData(ctx, eventType, msg.Value)
+		if err != nil {
+			return consumed, err
+		}
+		consumed.ID = headers["ce_id"]
+		consumed.EventName = eventType
+		consumed.Metadata = entity.KafkaMetaData{Sender: headers["ce_source"], SendingAt: headers["ce_time"]}
+		consumed.Data = data
+		if consumed.TraceParent == "" {
+			consumed.TraceParent = headers["ce_traceparent"]
+		}
+		return consumed, nil
+	}
+
+	envelope := entity.KafkaConsumedEnvelope{}
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		return consumed, err
+	}
+
+	if envelope.SpecVersion != "" {
+		consumed.ID = envelope.ID
+		consumed.EventName = envelope.Type
+		consumed.Metadata = entity.KafkaMetaData{Sender: envelope.Source, SendingAt: envelope.Time}
+		consumed.Data = envelope.Data
+		if consumed.TraceParent == "" {
+			consumed.TraceParent = envelope.TraceParent
+		}
+	} else {
+		consumed.EventName = envelope.Event.Name
+		consumed.Metadata = envelope.Metadata
+		consumed.Data = envelope.Body.Data
+	}
+
+	if consumed.EventName == "" {
+		return consumed, errors.New("event name is empty")
+	}
+	return consumed, nil
+}
+
+func (k *Consumer) eventData(ctx context.Context, eventType string, value []byte) (json.RawMessage, error) {
+	if k.serializer == nil {
+		if !json.Valid(value) {
+			return nil, errors.New("event data is not valid JSON")
+		}
+		return value, nil
+	}
+
+	data, err := k.serializer.Deserialize(ctx, eventType, value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
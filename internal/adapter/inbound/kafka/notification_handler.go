@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/inbound"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/labstack/gommon/log"
+)
+
+type notificationEventHandler struct {
+	deliveryService service.NotificationDeliveryServiceInterface
+}
+
+func NewNotificationEventHandler(deliveryService service.NotificationDeliveryServiceInterface) inbound.NotificationEventHandlerInterface {
+	return &notificationEventHandler{deliveryService: deliveryService}
+}
+
+func (n *notificationEventHandler) HandleDelivered(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+	event, err := decodeDeliveryEvent(msg)
+	if err != nil {
+		log.Errorf("[NotificationEventHandler-1] HandleDelivered: %v", err)
+		return errors.New("422")
+	}
+
+	return n.deliveryService.RecordDelivered(ctx, event)
+}
+
+func (n *notificationEventHandler) HandleFailed(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+	event, err := decodeDeliveryEvent(msg)
+	if err != nil {
+		log.Errorf("[NotificationEventHandler-1] HandleFailed: %v", err)
+		return errors.New("422")
+	}
+
+	return n.deliveryService.RecordFailed(ctx, event)
+}
+
+func decodeDeliveryEvent(msg entity.ConsumedMessageEntity) (entity.NotificationDeliveryEventEntity, error) {
+	event := entity.NotificationDeliveryEventEntity{}
+	if len(msg.Data) == 0 {
+		return event, errors.New("event data is empty")
+	}
+
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		return event, err
+	}
+	return event, nil
+}
@@ -0,0 +1,11 @@
+package kafka
+
+import (
+	"clean-architecture/internal/port/inbound"
+	"clean-architecture/utils"
+)
+
+func InitEventRoutes(consumer *Consumer, notificationHandler inbound.NotificationEventHandlerInterface) {
+	consumer.Register(utils.EVENT_NOTIFICATION_DELIVERED, notificationHandler.HandleDelivered)
+	consumer.Register(utils.EVENT_NOTIFICATION_FAILED, notificationHandler.HandleFailed)
+}
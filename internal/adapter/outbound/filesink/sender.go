@@ -0,0 +1,60 @@
+package filesink
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkadelivery"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/labstack/gommon/log"
+)
+
+type sender struct {
+	mu     sync.Mutex
+	writer io.Writer
+	file   *os.File
+}
+
+// NewSender transport stdout/file untuk development dan test: notifikasi tidak dikirim ke mana pun,
+// hanya ditulis satu baris JSON per notifikasi. path kosong berarti stdout, selain itu file di-append.
+func NewSender(path string) (outbound.NotificationSenderInterface, error) {
+	if path == "" {
+		return &sender{writer: os.Stdout}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &sender{writer: file, file: file}, nil
+}
+
+func (s *sender) Send(ctx context.Context, notification entity.NotificationEntity) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		log.Errorf("[FileSink-1] Send: %v", err)
+		return err
+	}
+
+	s.mu.Lock()
+	_, err = s.writer.Write(append(line, '\n'))
+	s.mu.Unlock()
+	if err != nil {
+		log.Errorf("[FileSink-2] Send: notification %s: %v", notification.ID, err)
+		return err
+	}
+
+	kafkadelivery.Report(ctx, nil)
+	return nil
+}
+
+func (s *sender) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
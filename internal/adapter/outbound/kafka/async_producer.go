@@ -0,0 +1,166 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkadelivery"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/labstack/gommon/log"
+)
+
+const asyncFlushPollInterval = 20 * time.Millisecond
+
+// asyncDelivery disimpan di ProducerMessage.Metadata agar hasil pengiriman bisa dikembalikan
+// ke callback pemanggil.
+type asyncDelivery struct {
+	callback kafkadelivery.Callback
+	eventID  string
+}
+
+type AsyncKafka struct {
+	producer   sarama.AsyncProducer
+	format     string
+	source     string
+	serializer outbound.KafkaSerializerInterface
+	metrics    producerMetrics
+
+	mu     sync.RWMutex
+	closed bool
+	done   sync.WaitGroup
+}
+
+// NewKafkaAsyncProducer producer mode async. Batch, linger dan kompresi diatur lewat config
+// (lihat config.NewKafkaProducerConfig), Return.Successes wajib true agar callback bisa dipanggil.
+func NewKafkaAsyncProducer(brokers []string, config *sarama.Config, format, source string,
+	serializer outbound.KafkaSerializerInterface) (outbound.KafkaProducerInterface, error) {
+	if !config.Producer.Return.Successes || !config.Producer.Return.Errors {
+		return nil, errors.New("async kafka producer requires Return.Successes and Return.Errors")
+	}
+
+	format, err := producerFormat(format, serializer)
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewAsyncProducer(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &AsyncKafka{
+		producer:   producer,
+		format:     format,
+		source:     source,
+		serializer: serializer,
+		metrics:    producerMetrics{mode: ProducerModeAsync},
+	}
+
+	k.done.Add(2)
+	go func() {
+		defer k.done.Done()
+		for msg := range producer.Successes() {
+			k.delivered(msg, nil)
+		}
+	}()
+	go func() {
+		defer k.done.Done()
+		for produceErr := range producer.Errors() {
+			k.delivered(produceErr.Msg, produceErr.Err)
+		}
+	}()
+
+	return k, nil
+}
+
+// ProduceEvent kembali setelah pesan masuk antrean, callback di ctx dipanggil dari goroutine
+// producer setelah broker ack atau pengiriman gagal.
+func (k *AsyncKafka) ProduceEvent(ctx context.Context, event entity.KafkaOutgoingEventEntity) error {
+	record, err := encodeEvent(k.format, k.source, k.serializer, event)
+	if err != nil {
+		log.Errorf("[AsyncKafka-1] Failed to encode message: %v", err)
+		return err
+	}
+
+	return k.enqueue(record, asyncDelivery{callback: kafkadelivery.FromContext(ctx), eventID: event.ID})
+}
+
+func (k *AsyncKafka) ProduceRecord(record entity.KafkaRecordEntity) error {
+	return k.enqueue(record, asyncDelivery{})
+}
+
+func (k *AsyncKafka) enqueue(record entity.KafkaRecordEntity, delivery asyncDelivery) error {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if k.closed {
+		return errors.New("kafka producer is closed")
+	}
+
+	msg := newProducerMessage(record)
+	msg.Metadata = delivery
+
+	k.metrics.enqueued.Add(1)
+	k.producer.Input() <- msg
+	return nil
+}
+
+func (k *AsyncKafka) delivered(msg *sarama.ProducerMessage, err error) {
+	delivery, _ := msg.Metadata.(asyncDelivery)
+
+	if err != nil {
+		k.metrics.failed.Add(1)
+		log.Errorf("[AsyncKafka-2] Failed to send message %s to %s: %v", delivery.eventID, msg.Topic, err)
+	} else {
+		k.metrics.delivered.Add(1)
+		log.Debugf("[AsyncKafka-3] Sent → topic=%s partition=%d offset=%d", msg.Topic, msg.Partition, msg.Offset)
+	}
+
+	if delivery.callback != nil {
+		delivery.callback(err)
+	}
+}
+
+// Flush batch yang belum penuh tetap dikirim setelah linger (Flush.Frequency), Flush hanya
+// menunggu sampai semua pesan di antrean selesai.
+func (k *AsyncKafka) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(asyncFlushPollInterval)
+	defer ticker.Stop()
+
+	for k.metrics.snapshot().InFlight > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (k *AsyncKafka) Metrics() entity.KafkaProducerMetricsEntity {
+	return k.metrics.snapshot()
+}
+
+// Close sarama mengirim semua pesan yang masih di antrean sebelum menutup koneksi, lalu
+// menunggu semua callback selesai dipanggil.
+func (k *AsyncKafka) Close() error {
+	k.mu.Lock()
+	if k.closed {
+		k.mu.Unlock()
+		return nil
+	}
+	k.closed = true
+	k.mu.Unlock()
+
+	k.producer.AsyncClose()
+	k.done.Wait()
+
+	metrics := k.metrics.snapshot()
+	log.Infof("[AsyncKafka-4] Producer closed: enqueued=%d delivered=%d failed=%d",
+		metrics.Enqueued, metrics.Delivered, metrics.Failed)
+	return nil
+}
@@ -0,0 +1,222 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkaretry"
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/labstack/gommon/log"
+)
+
+// deadLetterFetchTimeout batas menunggu pesan berikutnya dari satu partisi saat membaca DLQ.
+const deadLetterFetchTimeout = 10 * time.Second
+
+type deadLetterReader struct {
+	client   sarama.Client
+	consumer sarama.Consumer
+	offsets  sarama.OffsetManager
+
+	mu         sync.Mutex
+	partitions map[string]sarama.PartitionOffsetManager
+}
+
+// NewDeadLetterReader group dipakai hanya untuk menyimpan offset replay, tidak bergabung ke consumer group.
+func NewDeadLetterReader(brokers []string, group string, config *sarama.Config) (outbound.DeadLetterReaderInterface, error) {
+	client, err := sarama.NewClient(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	offsets, err := sarama.NewOffsetManagerFromClient(group, client)
+	if err != nil {
+		consumer.Close()
+		client.Close()
+		return nil, err
+	}
+
+	return &deadLetterReader{
+		client:     client,
+		consumer:   consumer,
+		offsets:    offsets,
+		partitions: map[string]sarama.PartitionOffsetManager{},
+	}, nil
+}
+
+func (d *deadLetterReader) Fetch(ctx context.Context, topic string, limit int, fromCommitted bool) ([]entity.DeadLetterEntity, error) {
+	partitions, err := d.client.Partitions(topic)
+	if err != nil {
+		log.Errorf("[DeadLetterReader-1] Fetch: %v", err)
+		return nil, err
+	}
+
+	messages := []entity.DeadLetterEntity{}
+	for _, partition := range partitions {
+		if limit > 0 && len(messages) >= limit {
+			break
+		}
+
+		start, err := d.startOffset(topic, partition, fromCommitted)
+		if err != nil {
+			log.Errorf("[DeadLetterReader-2] Fetch: %v", err)
+			return nil, err
+		}
+
+		highWatermark, err := d.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			log.Errorf("[DeadLetterReader-3] Fetch: %v", err)
+			return nil, err
+		}
+		if start >= highWatermark {
+			continue
+		}
+
+		remaining := int(highWatermark - start)
+		if limit > 0 {
+			remaining = min(remaining, limit-len(messages))
+		}
+
+		result, err := d.fetchPartition(ctx, topic, partition, start, highWatermark, remaining)
+		if err != nil {
+			log.Errorf("[DeadLetterReader-4] Fetch: %v", err)
+			return nil, err
+		}
+		messages = append(messages, result...)
+	}
+
+	return messages, nil
+}
+
+func (d *deadLetterReader) Commit(ctx context.Context, msg entity.DeadLetterEntity) error {
+	pom, err := d.partitionOffsets(msg.Topic, msg.Partition)
+	if err != nil {
+		log.Errorf("[DeadLetterReader-5] Commit: %v", err)
+		return err
+	}
+
+	pom.MarkOffset(msg.Offset+1, "")
+	d.offsets.Commit()
+	return nil
+}
+
+func (d *deadLetterReader) Close() error {
+	d.mu.Lock()
+	for _, pom := range d.partitions {
+		pom.AsyncClose()
+	}
+	d.mu.Unlock()
+
+	if err := d.offsets.Close(); err != nil {
+		log.Errorf("[DeadLetterReader-6] Close: %v", err)
+	}
+	if err := d.consumer.Close(); err != nil {
+		log.Errorf("[DeadLetterReader-6] Close: %v", err)
+	}
+	return d.client.Close()
+}
+
+func (d *deadLetterReader) startOffset(topic string, partition int32, fromCommitted bool) (int64, error) {
+	oldest, err := d.client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, err
+	}
+	if !fromCommitted {
+		return oldest, nil
+	}
+
+	pom, err := d.partitionOffsets(topic, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	// Offset yang belum pernah di-commit atau sudah terhapus retensi dimulai dari yang paling awal
+	next, _ := pom.NextOffset()
+	if next < oldest {
+		return oldest, nil
+	}
+	return next, nil
+}
+
+func (d *deadLetterReader) partitionOffsets(topic string, partition int32) (sarama.PartitionOffsetManager, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%d", topic, partition)
+	if pom, ok := d.partitions[key]; ok {
+		return pom, nil
+	}
+
+	pom, err := d.offsets.ManagePartition(topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	d.partitions[key] = pom
+	return pom, nil
+}
+
+func (d *deadLetterReader) fetchPartition(ctx context.Context, topic string, partition int32,
+	start, highWatermark int64, limit int) ([]entity.DeadLetterEntity, error) {
+	pc, err := d.consumer.ConsumePartition(topic, partition, start)
+	if err != nil {
+		return nil, err
+	}
+	defer pc.AsyncClose()
+
+	messages := []entity.DeadLetterEntity{}
+	for len(messages) < limit {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-pc.Errors():
+			return nil, err
+		case <-time.After(deadLetterFetchTimeout):
+			return nil, fmt.Errorf("timeout reading %s partition %d", topic, partition)
+		case msg := <-pc.Messages():
+			messages = append(messages, deadLetterEntity(msg))
+			if msg.Offset >= highWatermark-1 {
+				return messages, nil
+			}
+		}
+	}
+
+	return messages, nil
+}
+
+func deadLetterEntity(msg *sarama.ConsumerMessage) entity.DeadLetterEntity {
+	headers := map[string]string{}
+	for _, header := range msg.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+
+	result := entity.DeadLetterEntity{
+		Topic:         msg.Topic,
+		Partition:     msg.Partition,
+		Offset:        msg.Offset,
+		Key:           string(msg.Key),
+		Value:         string(msg.Value),
+		Headers:       headers,
+		OriginalTopic: headers[kafkaretry.HeaderOriginalTopic],
+		Error:         headers[kafkaretry.HeaderError],
+		Timestamp:     msg.Timestamp,
+	}
+
+	if attempts, err := strconv.Atoi(headers[kafkaretry.HeaderAttempt]); err == nil {
+		result.Attempts = attempts
+	}
+	if failedAt, err := time.Parse(time.RFC3339, headers[kafkaretry.HeaderFailedAt]); err == nil {
+		result.FailedAt = &failedAt
+	}
+
+	return result
+}
@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/traceparent"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Format envelope pesan (KAFKA_MESSAGE_FORMAT). Legacy dipertahankan selama consumer lama
+// belum pindah ke CloudEvents.
+const (
+	FormatLegacy                = "legacy"
+	FormatCloudEventsStructured = "cloudevents-structured"
+	FormatCloudEventsBinary     = "cloudevents-binary"
+)
+
+const (
+	cloudEventsSpecVersion  = "1.0"
+	cloudEventsContentType  = "application/cloudevents+json; charset=UTF-8"
+	dataContentType         = "application/json"
+	legacySender            = "clean_architecture_service"
+	cloudEventsHeaderPrefix = "ce_"
+)
+
+// cloudEvent representasi JSON CloudEvents 1.0 (structured mode). eventversion dan
+// traceparent adalah extension attribute.
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            string      `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	EventVersion    int         `json:"eventversion,omitempty"`
+	TraceParent     string      `json:"traceparent,omitempty"`
+	Data            interface{} `json:"data"`
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case FormatLegacy, FormatCloudEventsStructured, FormatCloudEventsBinary:
+		return true
+	}
+	return false
+}
+
+// encodeEvent menyusun value dan header pesan sesuai format. Header traceparent selalu
+// dikirim di semua format agar trace tetap tersambung. Jika serializer diisi (hanya untuk
+// cloudevents-binary) value adalah data event dalam wire format schema registry.
+func encodeEvent(format, source string, serializer outbound.KafkaSerializerInterface,
+	event entity.KafkaOutgoingEventEntity) (entity.KafkaRecordEntity, error) {
+	record := entity.KafkaRecordEntity{
+		Topic:   event.Topic,
+		Headers: map[string]string{traceparent.Header: event.TraceParent},
+	}
+	if event.Key != "" {
+		record.Key = []byte(event.Key)
+	}
+
+	eventTime := event.Time.UTC().Format(time.RFC3339Nano)
+
+	var (
+		value interface{}
+		err   error
+	)
+	switch format {
+	case FormatCloudEventsStructured:
+		record.Headers["content-type"] = cloudEventsContentType
+		value = cloudEvent{
+			SpecVersion:     cloudEventsSpecVersion,
+			ID:              event.ID,
+			Source:          source,
+			Type:            event.Type,
+			Subject:         event.Subject,
+			Time:            eventTime,
+			DataContentType: dataContentType,
+			EventVersion:    event.Version,
+			TraceParent:     event.TraceParent,
+			Data:            event.Data,
+		}
+	case FormatCloudEventsBinary:
+		record.Headers["content-type"] = dataContentType
+		record.Headers[cloudEventsHeaderPrefix+"specversion"] = cloudEventsSpecVersion
+		record.Headers[cloudEventsHeaderPrefix+"id"] = event.ID
+		record.Headers[cloudEventsHeaderPrefix+"source"] = source
+		record.Headers[cloudEventsHeaderPrefix+"type"] = event.Type
+		record.Headers[cloudEventsHeaderPrefix+"time"] = eventTime
+		record.Headers[cloudEventsHeaderPrefix+"traceparent"] = event.TraceParent
+		if event.Subject != "" {
+			record.Headers[cloudEventsHeaderPrefix+"subject"] = event.Subject
+		}
+		if event.Version > 0 {
+			record.Headers[cloudEventsHeaderPrefix+"eventversion"] = strconv.Itoa(event.Version)
+		}
+		if serializer != nil {
+			record.Headers["content-type"] = serializer.ContentType()
+			record.Value, err = serializer.Serialize(context.Background(), event.Topic, event)
+			return record, err
+		}
+		value = event.Data
+	case FormatLegacy:
+		record.Headers["content-type"] = dataContentType
+		value = entity.KafkaEventMessage{
+			Event: entity.KafkaEvent{
+				Name:    event.Type,
+				Version: event.Version,
+			},
+			Metadata: entity.KafkaMetaData{
+				Sender:    legacySender,
+				SendingAt: event.Time.Format(time.RFC3339),
+			},
+			Body: entity.KafkaEventBody{
+				Type: "JSON",
+				Data: event.Data,
+			},
+		}
+	default:
+		return record, fmt.Errorf("unknown kafka message format %q", format)
+	}
+
+	record.Value, err = json.Marshal(value)
+	if err != nil {
+		return record, err
+	}
+	return record, nil
+}
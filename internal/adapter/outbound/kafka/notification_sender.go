@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"clean-architecture/utils/traceparent"
+	"context"
+	"strconv"
+)
+
+type notificationSender struct {
+	producer outbound.KafkaProducerInterface
+	topic    string
+}
+
+// NewNotificationSender transport kafka, notifikasi dikirim sebagai event message_published ke topic
+// (KAFKA_TOPIC) untuk diproses notification service.
+func NewNotificationSender(producer outbound.KafkaProducerInterface, topic string) outbound.NotificationSenderInterface {
+	return &notificationSender{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+func (n *notificationSender) Send(ctx context.Context, notification entity.NotificationEntity) error {
+	event := entity.KafkaOutgoingEventEntity{
+		Topic:       n.topic,
+		ID:          notification.ID,
+		Type:        utils.EVENT_MESSAGE_PUBLISHED,
+		Time:        notification.CreatedAt,
+		TraceParent: traceparent.Child(traceparent.FromContext(ctx)),
+		Data: &entity.KafkaData{
+			ReceiverEmail:    notification.Email,
+			ReceiverPhone:    notification.Phone,
+			Message:          notification.Message,
+			ReceiverId:       notification.UserID,
+			Subject:          notification.Subject,
+			NotificationType: notification.Type,
+			MessageId:        notification.ID,
+			HtmlMessage:      notification.HTMLMessage,
+		},
+	}
+	if notification.UserID != 0 {
+		event.Subject = strconv.FormatInt(notification.UserID, 10)
+	}
+
+	return n.producer.ProduceEvent(ctx, event)
+}
+
+// Close producer dipakai bersama domain event, ditutup di RunServer.
+func (n *notificationSender) Close() error {
+	return nil
+}
@@ -1,49 +1,102 @@
 package kafka
 
 import (
+	"clean-architecture/internal/domain/entity"
 	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkadelivery"
+	"context"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/IBM/sarama"
 	"github.com/labstack/gommon/log"
 )
 
+// Mode producer (KAFKA_PRODUCER_MODE). Sync menunggu ack broker untuk setiap pesan, async
+// mengirim pesan dalam batch di background.
+const (
+	ProducerModeSync  = "sync"
+	ProducerModeAsync = "async"
+)
+
 type Kafka struct {
-	producer sarama.SyncProducer
-	brokers  []string
-	config   *sarama.Config
+	producer   sarama.SyncProducer
+	brokers    []string
+	config     *sarama.Config
+	format     string
+	source     string
+	serializer outbound.KafkaSerializerInterface
+	metrics    producerMetrics
 }
 
-func NewKafkaProducer(brokers []string, config *sarama.Config) (outbound.KafkaProducerInterface, error) {
+// NewKafkaProducer format kosong berarti FormatLegacy, source dipakai sebagai atribut source CloudEvents.
+// serializer boleh nil (data dikirim sebagai JSON biasa), selain itu format harus cloudevents-binary
+// karena data hasil serializer tidak bisa dibungkus envelope JSON.
+func NewKafkaProducer(brokers []string, config *sarama.Config, format, source string,
+	serializer outbound.KafkaSerializerInterface) (outbound.KafkaProducerInterface, error) {
 	log.Printf("Return.Successes=%v", config.Producer.Return.Successes)
 
+	format, err := producerFormat(format, serializer)
+	if err != nil {
+		return nil, err
+	}
+
 	producer, err := sarama.NewSyncProducer(brokers, config)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Kafka{
-		brokers:  brokers,
-		config:   config,
-		producer: producer,
+		brokers:    brokers,
+		config:     config,
+		producer:   producer,
+		format:     format,
+		source:     source,
+		serializer: serializer,
+		metrics:    producerMetrics{mode: ProducerModeSync},
 	}, nil
 }
 
-func (k *Kafka) ProduceMessage(topic string, data []byte) error {
-	msg := &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.ByteEncoder(data),
+// ProduceEvent callback di ctx dipanggil sebelum method ini kembali jika broker sudah ack.
+func (k *Kafka) ProduceEvent(ctx context.Context, event entity.KafkaOutgoingEventEntity) error {
+	record, err := encodeEvent(k.format, k.source, k.serializer, event)
+	if err != nil {
+		log.Errorf("[Kafka-4] Failed to encode message: %v", err)
+		return err
 	}
 
-	partition, offset, err := k.producer.SendMessage(msg)
+	if err := k.ProduceRecord(record); err != nil {
+		return err
+	}
+
+	kafkadelivery.Report(ctx, nil)
+	return nil
+}
+
+func (k *Kafka) ProduceRecord(record entity.KafkaRecordEntity) error {
+	k.metrics.enqueued.Add(1)
+
+	partition, offset, err := k.producer.SendMessage(newProducerMessage(record))
 	if err != nil {
+		k.metrics.failed.Add(1)
 		log.Errorf("[Kafka-1] Failed to send message: %v", err)
 		return err
 	}
 
-	log.Infof("[Kafka-2] Sent → topic=%s partition=%d offset=%d", topic, partition, offset)
+	k.metrics.delivered.Add(1)
+	log.Infof("[Kafka-2] Sent → topic=%s partition=%d offset=%d", record.Topic, partition, offset)
+	return nil
+}
+
+// Flush tidak ada antrean di mode sync.
+func (k *Kafka) Flush(ctx context.Context) error {
 	return nil
 }
 
+func (k *Kafka) Metrics() entity.KafkaProducerMetricsEntity {
+	return k.metrics.snapshot()
+}
+
 func (k *Kafka) Close() error {
 	if err := k.producer.Close(); err != nil {
 		log.Errorf("[Kafka-3] Failed to close producer: %v", err)
@@ -51,3 +104,52 @@ func (k *Kafka) Close() error {
 	}
 	return nil
 }
+
+func producerFormat(format string, serializer outbound.KafkaSerializerInterface) (string, error) {
+	if format == "" {
+		format = FormatLegacy
+	}
+	if !validFormat(format) {
+		return "", fmt.Errorf("unknown kafka message format %q", format)
+	}
+	if serializer != nil && format != FormatCloudEventsBinary {
+		return "", fmt.Errorf("kafka serializer requires message format %s", FormatCloudEventsBinary)
+	}
+	return format, nil
+}
+
+func newProducerMessage(record entity.KafkaRecordEntity) *sarama.ProducerMessage {
+	msg := &sarama.ProducerMessage{
+		Topic: record.Topic,
+		Value: sarama.ByteEncoder(record.Value),
+	}
+	if len(record.Key) > 0 {
+		msg.Key = sarama.ByteEncoder(record.Key)
+	}
+	for key, val := range record.Headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(val)})
+	}
+	return msg
+}
+
+type producerMetrics struct {
+	mode      string
+	enqueued  atomic.Int64
+	delivered atomic.Int64
+	failed    atomic.Int64
+}
+
+func (m *producerMetrics) snapshot() entity.KafkaProducerMetricsEntity {
+	// delivered dan failed dibaca lebih dulu agar InFlight tidak pernah negatif
+	delivered := m.delivered.Load()
+	failed := m.failed.Load()
+	enqueued := m.enqueued.Load()
+
+	return entity.KafkaProducerMetricsEntity{
+		Mode:      m.mode,
+		Enqueued:  enqueued,
+		Delivered: delivered,
+		Failed:    failed,
+		InFlight:  enqueued - delivered - failed,
+	}
+}
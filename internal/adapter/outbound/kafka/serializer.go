@@ -0,0 +1,211 @@
+package kafka
+
+import (
+	"bytes"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"context"
+	"embed"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Serializer data event (KAFKA_SERIALIZER). Kosong berarti data dikirim sebagai JSON biasa tanpa schema.
+const (
+	SerializerJSON     = "json"
+	SerializerProtobuf = "protobuf"
+	SerializerAvro     = "avro"
+)
+
+const (
+	// wireMagicByte byte pertama wire format Confluent, diikuti schema ID 4 byte big-endian
+	wireMagicByte    byte = 0
+	wireHeaderLength      = 5
+	schemaNamespace       = "clean_architecture.events"
+)
+
+//go:embed schema
+var schemaFiles embed.FS
+
+// eventSchema kontrak data satu jenis event. Record adalah nama record Avro, message Protobuf
+// dan title JSON Schema, file nama file schema Avro/JSON tanpa ekstensi.
+type eventSchema struct {
+	record  string
+	file    string
+	newData func() interface{}
+}
+
+// eventSchemas daftar event yang punya schema. Perubahan payload event wajib diikuti perubahan
+// schema di folder schema/ dan harus tetap lolos compatibility test.
+var eventSchemas = map[string]eventSchema{
+	utils.EVENT_MESSAGE_PUBLISHED: {"NotificationMessage", "notification_message", func() interface{} { return &entity.KafkaData{} }},
+	utils.EVENT_USER_REGISTERED:   {"UserRegistered", "user_registered", func() interface{} { return &entity.UserRegisteredEventEntity{} }},
+	utils.EVENT_USER_VERIFIED:     {"UserVerified", "user_verified", func() interface{} { return &entity.UserVerifiedEventEntity{} }},
+	utils.EVENT_USER_UPDATED:      {"UserUpdated", "user_updated", func() interface{} { return &entity.UserUpdatedEventEntity{} }},
+	utils.EVENT_USER_DELETED:      {"UserDeleted", "user_deleted", func() interface{} { return &entity.UserDeletedEventEntity{} }},
+	utils.EVENT_ROLE_ASSIGNED:     {"RoleAssigned", "role_assigned", func() interface{} { return &entity.RoleAssignedEventEntity{} }},
+	utils.EVENT_PASSWORD_CHANGED:  {"PasswordChanged", "password_changed", func() interface{} { return &entity.PasswordChangedEventEntity{} }},
+	utils.EVENT_SESSION_REVOKED:   {"SessionRevoked", "session_revoked", func() interface{} { return &entity.SessionRevokedEventEntity{} }},
+}
+
+// schemaCodec encoding satu format schema. Payload yang dihasilkan encode belum termasuk header wire format.
+type schemaCodec interface {
+	schemaType() string
+	contentType() string
+	schema(record eventSchema) string
+	encode(record eventSchema, data interface{}) ([]byte, error)
+	decode(record eventSchema, writer *entity.SchemaEntity, payload []byte, target interface{}) error
+}
+
+type schemaSerializer struct {
+	registry outbound.SchemaRegistryInterface
+	codec    schemaCodec
+
+	mu  sync.Mutex
+	ids map[string]int
+}
+
+// NewSerializer serializer sesuai KAFKA_SERIALIZER. Schema didaftarkan ke registry saat event
+// pertama dikirim, ID-nya disimpan untuk pesan berikutnya.
+func NewSerializer(name string, registry outbound.SchemaRegistryInterface) (outbound.KafkaSerializerInterface, error) {
+	if registry == nil {
+		return nil, errors.New("schema registry is required")
+	}
+
+	codec, err := newSchemaCodec(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaSerializer{registry: registry, codec: codec, ids: map[string]int{}}, nil
+}
+
+// SchemaSubject subject registry untuk event di topic (TopicRecordNameStrategy) sehingga
+// beberapa jenis event bisa berbagi satu topic.
+func SchemaSubject(topic, eventType string) (string, error) {
+	record, ok := eventSchemas[eventType]
+	if !ok {
+		return "", fmt.Errorf("no schema for event type %q", eventType)
+	}
+	return topic + "-" + schemaNamespace + "." + record.record, nil
+}
+
+func newSchemaCodec(name string) (schemaCodec, error) {
+	switch name {
+	case SerializerJSON:
+		return newJSONCodec()
+	case SerializerProtobuf:
+		return newProtobufCodec()
+	case SerializerAvro:
+		return newAvroCodec()
+	}
+	return nil, fmt.Errorf("unknown kafka serializer %q", name)
+}
+
+func (s *schemaSerializer) Serialize(ctx context.Context, topic string, event entity.KafkaOutgoingEventEntity) ([]byte, error) {
+	record, ok := eventSchemas[event.Type]
+	if !ok {
+		return nil, fmt.Errorf("no schema for event type %q", event.Type)
+	}
+
+	data, err := eventData(record, event.Data)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", event.Type, err)
+	}
+
+	id, err := s.schemaID(ctx, topic, event.Type, record)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := s.codec.encode(record, data)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %w", event.Type, err)
+	}
+
+	value := make([]byte, wireHeaderLength, wireHeaderLength+len(payload))
+	value[0] = wireMagicByte
+	binary.BigEndian.PutUint32(value[1:wireHeaderLength], uint32(id))
+	return append(value, payload...), nil
+}
+
+// Deserialize membaca data dengan schema penulis dari registry sehingga pesan yang ditulis
+// dengan schema versi lama tetap bisa dibaca selama schema-nya kompatibel.
+func (s *schemaSerializer) Deserialize(ctx context.Context, eventType string, value []byte) (interface{}, error) {
+	record, ok := eventSchemas[eventType]
+	if !ok {
+		return nil, fmt.Errorf("no schema for event type %q", eventType)
+	}
+
+	if len(value) < wireHeaderLength || value[0] != wireMagicByte {
+		return nil, errors.New("value is not in schema registry wire format")
+	}
+
+	writer, err := s.registry.GetByID(ctx, int(binary.BigEndian.Uint32(value[1:wireHeaderLength])))
+	if err != nil {
+		return nil, err
+	}
+	if writer.Type != s.codec.schemaType() {
+		return nil, fmt.Errorf("schema %d is %s, expected %s", writer.ID, writer.Type, s.codec.schemaType())
+	}
+
+	target := record.newData()
+	if err := s.codec.decode(record, writer, value[wireHeaderLength:], target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func (s *schemaSerializer) ContentType() string {
+	return s.codec.contentType()
+}
+
+func (s *schemaSerializer) schemaID(ctx context.Context, topic, eventType string, record eventSchema) (int, error) {
+	subject, err := SchemaSubject(topic, eventType)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.ids[subject]; ok {
+		return id, nil
+	}
+
+	id, err := s.registry.Register(ctx, subject, entity.SchemaEntity{
+		Type:   s.codec.schemaType(),
+		Schema: s.codec.schema(record),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("register schema %s: %w", subject, err)
+	}
+
+	s.ids[subject] = id
+	return id, nil
+}
+
+// eventData menyamakan data event (struct, pointer atau json.RawMessage dari outbox) menjadi
+// struct data sesuai schema. Field yang tidak ada di struct ditolak agar payload tidak diam-diam
+// keluar dari kontrak.
+func eventData(record eventSchema, data interface{}) (interface{}, error) {
+	raw, ok := data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(data); err != nil {
+			return nil, err
+		}
+	}
+
+	target := record.newData()
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
@@ -0,0 +1,88 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"fmt"
+	"io/fs"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+)
+
+const avroContentType = "application/avro"
+
+// avroCodec satu file .avsc per event. Schema penulis yang sudah di-resolve terhadap schema
+// pembaca disimpan per schema ID di resolved.
+type avroCodec struct {
+	sources  map[string]string
+	schemas  map[string]avro.Schema
+	resolved sync.Map
+}
+
+func newAvroCodec() (schemaCodec, error) {
+	codec := &avroCodec{sources: map[string]string{}, schemas: map[string]avro.Schema{}}
+	for _, record := range eventSchemas {
+		raw, err := fs.ReadFile(schemaFiles, "schema/avro/"+record.file+".avsc")
+		if err != nil {
+			return nil, err
+		}
+
+		schema, err := avro.ParseBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("avro schema %s: %w", record.file, err)
+		}
+
+		codec.sources[record.record] = string(raw)
+		codec.schemas[record.record] = schema
+	}
+	return codec, nil
+}
+
+func (a *avroCodec) schemaType() string {
+	return entity.SchemaTypeAvro
+}
+
+func (a *avroCodec) contentType() string {
+	return avroContentType
+}
+
+func (a *avroCodec) schema(record eventSchema) string {
+	return a.sources[record.record]
+}
+
+func (a *avroCodec) encode(record eventSchema, data interface{}) ([]byte, error) {
+	return avro.Marshal(a.schemas[record.record], data)
+}
+
+func (a *avroCodec) decode(record eventSchema, writer *entity.SchemaEntity, payload []byte, target interface{}) error {
+	schema, err := a.readerSchema(record, writer)
+	if err != nil {
+		return err
+	}
+	return avro.Unmarshal(schema, payload, target)
+}
+
+// readerSchema schema pembaca yang sudah di-resolve dengan schema penulis, field baru
+// yang tidak ada di data lama terisi default.
+func (a *avroCodec) readerSchema(record eventSchema, writer *entity.SchemaEntity) (avro.Schema, error) {
+	reader := a.schemas[record.record]
+	if writer.Schema == a.sources[record.record] {
+		return reader, nil
+	}
+
+	if schema, ok := a.resolved.Load(writer.ID); ok {
+		return schema.(avro.Schema), nil
+	}
+
+	writerSchema, err := avro.Parse(writer.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, err := avro.NewSchemaCompatibility().Resolve(reader, writerSchema)
+	if err != nil {
+		return nil, err
+	}
+	a.resolved.Store(writer.ID, schema)
+	return schema, nil
+}
@@ -0,0 +1,123 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+const jsonSchemaContentType = "application/schema+json"
+
+// jsonSchemaDocument bagian JSON Schema yang dipakai untuk validasi sebelum kirim.
+type jsonSchemaDocument struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+type jsonCodec struct {
+	schemas   map[string]string
+	documents map[string]jsonSchemaDocument
+}
+
+func newJSONCodec() (schemaCodec, error) {
+	codec := &jsonCodec{schemas: map[string]string{}, documents: map[string]jsonSchemaDocument{}}
+	for _, record := range eventSchemas {
+		raw, err := fs.ReadFile(schemaFiles, "schema/json/"+record.file+".json")
+		if err != nil {
+			return nil, err
+		}
+
+		document := jsonSchemaDocument{}
+		if err := json.Unmarshal(raw, &document); err != nil {
+			return nil, fmt.Errorf("json schema %s: %w", record.file, err)
+		}
+
+		codec.schemas[record.record] = string(raw)
+		codec.documents[record.record] = document
+	}
+	return codec, nil
+}
+
+func (j *jsonCodec) schemaType() string {
+	return entity.SchemaTypeJSON
+}
+
+func (j *jsonCodec) contentType() string {
+	return jsonSchemaContentType
+}
+
+func (j *jsonCodec) schema(record eventSchema) string {
+	return j.schemas[record.record]
+}
+
+func (j *jsonCodec) encode(record eventSchema, data interface{}) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.validate(j.documents[record.record], payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (j *jsonCodec) decode(record eventSchema, writer *entity.SchemaEntity, payload []byte, target interface{}) error {
+	return json.Unmarshal(payload, target)
+}
+
+// validate hanya memeriksa field wajib dan tipe field level atas, cukup untuk menjaga payload
+// sesuai kontrak tanpa validator JSON Schema lengkap.
+func (j *jsonCodec) validate(document jsonSchemaDocument, payload []byte) error {
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return err
+	}
+
+	for _, field := range document.Required {
+		if _, ok := values[field]; !ok {
+			return fmt.Errorf("field %s is required", field)
+		}
+	}
+
+	for field, value := range values {
+		property, ok := document.Properties[field]
+		if !ok {
+			return fmt.Errorf("field %s is not defined in schema", field)
+		}
+		if !jsonTypeMatches(property.Type, value) {
+			return fmt.Errorf("field %s must be %s", field, property.Type)
+		}
+	}
+	return nil
+}
+
+func jsonTypeMatches(schemaType string, value json.RawMessage) bool {
+	raw := strings.TrimSpace(string(value))
+	if raw == "null" {
+		return false
+	}
+
+	switch schemaType {
+	case "string":
+		return strings.HasPrefix(raw, `"`)
+	case "integer":
+		var number json.Number
+		if err := json.Unmarshal(value, &number); err != nil {
+			return false
+		}
+		_, err := number.Int64()
+		return err == nil
+	case "boolean":
+		return raw == "true" || raw == "false"
+	case "array":
+		return strings.HasPrefix(raw, "[")
+	case "object":
+		return strings.HasPrefix(raw, "{")
+	}
+	return true
+}
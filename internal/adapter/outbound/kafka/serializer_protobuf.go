@@ -0,0 +1,200 @@
+package kafka
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/utils/protoschema"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	protobufSchemaFile  = "schema/events.proto"
+	protobufContentType = "application/x-protobuf"
+	timestampFullName   = "google.protobuf.Timestamp"
+)
+
+// protobufCodec schema .proto di-compile saat start sehingga tidak perlu kode hasil generate.
+// Schema penulis yang sudah di-compile disimpan per schema ID di writers.
+type protobufCodec struct {
+	source  string
+	file    protoreflect.FileDescriptor
+	writers sync.Map
+}
+
+func newProtobufCodec() (schemaCodec, error) {
+	raw, err := fs.ReadFile(schemaFiles, protobufSchemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := protoschema.Compile(string(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range eventSchemas {
+		if file.Messages().ByName(protoreflect.Name(record.record)) == nil {
+			return nil, fmt.Errorf("protobuf message %s not found", record.record)
+		}
+	}
+
+	return &protobufCodec{source: string(raw), file: file}, nil
+}
+
+func (p *protobufCodec) schemaType() string {
+	return entity.SchemaTypeProtobuf
+}
+
+func (p *protobufCodec) contentType() string {
+	return protobufContentType
+}
+
+// schema satu file .proto dipakai bersama semua event, message yang dimaksud ditunjuk lewat message index.
+func (p *protobufCodec) schema(record eventSchema) string {
+	return p.source
+}
+
+// encode menulis message index (format Confluent) lalu message. Index [0] ditulis sebagai satu byte 0.
+func (p *protobufCodec) encode(record eventSchema, data interface{}) ([]byte, error) {
+	descriptor := p.file.Messages().ByName(protoreflect.Name(record.record))
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	message := dynamicpb.NewMessage(descriptor)
+	if err := protojson.Unmarshal(raw, message); err != nil {
+		return nil, err
+	}
+
+	// Deterministic agar urutan field selalu sama, pesan yang sama menghasilkan bytes yang sama
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	var payload []byte
+	if index := descriptor.Index(); index == 0 {
+		payload = append(payload, 0)
+	} else {
+		payload = binary.AppendVarint(payload, 1)
+		payload = binary.AppendVarint(payload, int64(index))
+	}
+	return append(payload, body...), nil
+}
+
+func (p *protobufCodec) decode(record eventSchema, writer *entity.SchemaEntity, payload []byte, target interface{}) error {
+	indexes, body, err := readMessageIndexes(payload)
+	if err != nil {
+		return err
+	}
+	if len(indexes) != 1 {
+		return errors.New("nested protobuf message is not supported")
+	}
+	index := indexes[0]
+
+	// Nama message diambil dari schema penulis karena urutan message bisa berbeda antar versi
+	file, err := p.writerFile(writer)
+	if err != nil {
+		return err
+	}
+	messages := file.Messages()
+	if index < 0 || int(index) >= messages.Len() || messages.Get(int(index)).Name() != protoreflect.Name(record.record) {
+		return fmt.Errorf("message index %d is not %s", index, record.record)
+	}
+
+	message := dynamicpb.NewMessage(p.file.Messages().ByName(protoreflect.Name(record.record)))
+	if err := proto.Unmarshal(body, message); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(protoMessageValues(message))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+func (p *protobufCodec) writerFile(writer *entity.SchemaEntity) (protoreflect.FileDescriptor, error) {
+	if file, ok := p.writers.Load(writer.ID); ok {
+		return file.(protoreflect.FileDescriptor), nil
+	}
+
+	file, err := protoschema.Compile(writer.Schema)
+	if err != nil {
+		return nil, err
+	}
+	p.writers.Store(writer.ID, file)
+	return file, nil
+}
+
+// readMessageIndexes membaca message index di depan payload: jumlah index lalu tiap index
+// (zigzag varint), jumlah 0 berarti index [0].
+func readMessageIndexes(payload []byte) ([]int64, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 {
+		return nil, nil, errors.New("invalid protobuf message index")
+	}
+	payload = payload[n:]
+	if count == 0 {
+		return []int64{0}, payload, nil
+	}
+
+	indexes := make([]int64, 0, count)
+	for i := int64(0); i < count; i++ {
+		index, n := binary.Varint(payload)
+		if n <= 0 {
+			return nil, nil, errors.New("invalid protobuf message index")
+		}
+		indexes = append(indexes, index)
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
+
+// protoMessageValues isi message dengan tipe Go biasa agar int64 tidak menjadi string seperti
+// output protojson dan bisa di-unmarshal ke struct data event.
+func protoMessageValues(message protoreflect.Message) map[string]interface{} {
+	values := map[string]interface{}{}
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.IsList() {
+			list := value.List()
+			items := make([]interface{}, 0, list.Len())
+			for i := 0; i < list.Len(); i++ {
+				items = append(items, protoFieldValue(field, list.Get(i)))
+			}
+			values[string(field.Name())] = items
+			return true
+		}
+
+		values[string(field.Name())] = protoFieldValue(field, value)
+		return true
+	})
+	return values
+}
+
+func protoFieldValue(field protoreflect.FieldDescriptor, value protoreflect.Value) interface{} {
+	if field.Kind() != protoreflect.MessageKind {
+		return value.Interface()
+	}
+
+	message := value.Message()
+	if message.Descriptor().FullName() == timestampFullName {
+		fields := message.Descriptor().Fields()
+		seconds := message.Get(fields.ByName("seconds")).Int()
+		nanos := message.Get(fields.ByName("nanos")).Int()
+		return time.Unix(seconds, nanos).UTC().Format(time.RFC3339Nano)
+	}
+	return protoMessageValues(message)
+}
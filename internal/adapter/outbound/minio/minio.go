@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"clean-architecture/internal/port/outbound"
 	"context"
+	"io"
 	"net/url"
 	"time"
 
@@ -56,3 +57,61 @@ func (m *MinioStorage) GetPresignedURL(path string, expiry time.Duration) (strin
 
 	return presignedURL.String(), nil
 }
+
+func (m *MinioStorage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	var paths []string
+
+	for object := range m.Client.ListObjects(ctx, m.BucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		paths = append(paths, object.Key)
+	}
+
+	return paths, nil
+}
+
+func (m *MinioStorage) GetObject(ctx context.Context, path string) (io.ReadCloser, error) {
+	object, err := m.Client.GetObject(ctx, m.BucketName, path, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject baru menghubungi server saat dibaca, Stat dipakai untuk memastikan object ada
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// RemoveObjects menghapus beberapa object sekaligus. Object yang tidak ada tidak dianggap error.
+func (m *MinioStorage) RemoveObjects(ctx context.Context, paths []string) error {
+	objectsCh := make(chan minio.ObjectInfo, len(paths))
+	for _, path := range paths {
+		objectsCh <- minio.ObjectInfo{Key: path}
+	}
+	close(objectsCh)
+
+	for removeErr := range m.Client.RemoveObjects(ctx, m.BucketName, objectsCh, minio.RemoveObjectsOptions{}) {
+		if minio.ToErrorResponse(removeErr.Err).Code == "NoSuchKey" {
+			continue
+		}
+		return removeErr.Err
+	}
+
+	return nil
+}
+
+// CopyObject menyalin object di dalam bucket yang sama.
+func (m *MinioStorage) CopyObject(ctx context.Context, srcPath, dstPath string) error {
+	_, err := m.Client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: m.BucketName, Object: dstPath},
+		minio.CopySrcOptions{Bucket: m.BucketName, Object: srcPath},
+	)
+	return err
+}
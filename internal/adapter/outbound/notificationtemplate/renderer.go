@@ -0,0 +1,198 @@
+package notificationtemplate
+
+import (
+	"bytes"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/labstack/gommon/log"
+)
+
+// DefaultLocale sama dengan default preferensi locale user.
+const DefaultLocale = "id"
+
+const (
+	partSubject = "subject"
+	partText    = "text"
+	partHTML    = "html"
+)
+
+// Template bawaan dengan layout <locale>/<nama>.<subject|text|html>.tmpl, nama template sama
+// dengan QueueName notifikasi.
+//
+//go:embed templates
+var embeddedTemplates embed.FS
+
+type templateSet struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+type renderer struct {
+	defaultLocale string
+	// templates locale → nama template
+	templates map[string]map[string]*templateSet
+}
+
+// NewRenderer memuat template bawaan lalu file di overrideDir (layout sama) yang menimpa per bagian,
+// misalnya hanya body HTML satu template. Body text wajib ada, subject dan HTML opsional.
+// Variabel yang dipakai template tapi tidak dikirim pemanggil membuat Render gagal.
+func NewRenderer(overrideDir, defaultLocale string) (outbound.NotificationTemplateInterface, error) {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+
+	r := &renderer{
+		defaultLocale: normalizeLocale(defaultLocale),
+		templates:     map[string]map[string]*templateSet{},
+	}
+
+	embedded, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(embedded); err != nil {
+		return nil, err
+	}
+	if overrideDir != "" {
+		if err := r.load(os.DirFS(overrideDir)); err != nil {
+			return nil, fmt.Errorf("notification template dir %s: %w", overrideDir, err)
+		}
+	}
+
+	for locale, sets := range r.templates {
+		for name, set := range sets {
+			if set.text == nil {
+				return nil, fmt.Errorf("notification template %s/%s has no text body", locale, name)
+			}
+		}
+	}
+
+	return r, nil
+}
+
+func (r *renderer) load(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(file) != ".tmpl" {
+			return nil
+		}
+
+		locale := path.Dir(file)
+		name, part, ok := strings.Cut(strings.TrimSuffix(path.Base(file), ".tmpl"), ".")
+		if locale == "." || strings.Contains(locale, "/") || !ok {
+			return fmt.Errorf("%s: expected <locale>/<name>.<part>.tmpl", file)
+		}
+
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+
+		set := r.templateSet(normalizeLocale(locale), name)
+		switch part {
+		case partSubject:
+			set.subject, err = texttemplate.New(file).Option("missingkey=error").Parse(string(content))
+		case partText:
+			set.text, err = texttemplate.New(file).Option("missingkey=error").Parse(string(content))
+		case partHTML:
+			set.html, err = htmltemplate.New(file).Option("missingkey=error").Parse(string(content))
+		default:
+			return fmt.Errorf("%s: unknown template part %q", file, part)
+		}
+		return err
+	})
+}
+
+func (r *renderer) templateSet(locale, name string) *templateSet {
+	if r.templates[locale] == nil {
+		r.templates[locale] = map[string]*templateSet{}
+	}
+	if r.templates[locale][name] == nil {
+		r.templates[locale][name] = &templateSet{}
+	}
+	return r.templates[locale][name]
+}
+
+func (r *renderer) Render(name, locale string, data map[string]string) (*entity.RenderedNotificationEntity, error) {
+	for _, candidate := range r.localeCandidates(locale) {
+		set, ok := r.templates[candidate][name]
+		if !ok {
+			continue
+		}
+
+		result := &entity.RenderedNotificationEntity{Locale: candidate}
+		var err error
+		if result.Text, err = execute(set.text, data); err != nil {
+			log.Errorf("[NotificationTemplate-1] Render: %s/%s: %v", candidate, name, err)
+			return nil, err
+		}
+		if set.subject != nil {
+			if result.Subject, err = execute(set.subject, data); err != nil {
+				log.Errorf("[NotificationTemplate-2] Render: %s/%s: %v", candidate, name, err)
+				return nil, err
+			}
+		}
+		if set.html != nil {
+			if result.HTML, err = execute(set.html, data); err != nil {
+				log.Errorf("[NotificationTemplate-3] Render: %s/%s: %v", candidate, name, err)
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+
+	err := errors.New("404")
+	log.Errorf("[NotificationTemplate-4] Render: template %s for locale %q: %v", name, locale, err)
+	return nil, err
+}
+
+// localeCandidates urutan locale yang dicoba: locale persis, bahasa dasarnya, lalu locale default.
+func (r *renderer) localeCandidates(locale string) []string {
+	locale = normalizeLocale(locale)
+
+	var candidates []string
+	add := func(value string) {
+		if value != "" && !slices.Contains(candidates, value) {
+			candidates = append(candidates, value)
+		}
+	}
+	add(locale)
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		add(base)
+	}
+	add(r.defaultLocale)
+	return candidates
+}
+
+// normalizeLocale en_US dan EN-us menjadi en-us sesuai nama direktori template.
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// executable dipenuhi text/template maupun html/template.
+type executable interface {
+	Execute(wr io.Writer, data any) error
+}
+
+func execute(tmpl executable, data map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
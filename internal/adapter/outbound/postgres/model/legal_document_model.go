@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type LegalDocument struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	DocType     string    `gorm:"type:varchar(20);not null"`
+	Version     string    `gorm:"type:varchar(50);not null"`
+	URL         string    `gorm:"column:url;type:text;not null"`
+	PublishedAt time.Time `gorm:"type:timestamp;not null"`
+	CreatedAt   time.Time `gorm:"type:timestamp;default:current_timestamp"`
+}
+
+func (LegalDocument) TableName() string {
+	return "legal_documents"
+}
+
+type UserConsent struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	UserID     int64     `gorm:"not null"`
+	DocumentID int64     `gorm:"not null"`
+	AcceptedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	IPAddress  string    `gorm:"type:varchar(45)"`
+}
+
+func (UserConsent) TableName() string {
+	return "user_consents"
+}
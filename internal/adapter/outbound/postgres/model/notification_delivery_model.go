@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type NotificationDelivery struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	MessageID  string    `gorm:"type:varchar(100);not null;unique"`
+	UserID     *int64    `gorm:"index"`
+	Channel    string    `gorm:"type:varchar(20);not null;default:''"`
+	Status     string    `gorm:"type:varchar(20);not null"`
+	Reason     string    `gorm:"type:text;not null;default:''"`
+	OccurredAt time.Time `gorm:"type:timestamp;not null"`
+	CreatedAt  time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	UpdatedAt  time.Time `gorm:"type:timestamp;default:current_timestamp"`
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
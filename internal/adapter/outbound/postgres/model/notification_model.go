@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+type Notification struct {
+	ID              int64  `gorm:"primaryKey;autoIncrement"`
+	MessageID       string `gorm:"type:varchar(100);not null;unique"`
+	OutboxMessageID *int64 `gorm:"index"`
+	ResendOfID      *int64
+	UserID          *int64    `gorm:"index"`
+	Type            string    `gorm:"type:varchar(20);not null"`
+	QueueName       string    `gorm:"type:varchar(50);not null;default:''"`
+	Template        string    `gorm:"type:varchar(50);not null;default:''"`
+	Recipient       string    `gorm:"type:varchar(255);not null;default:''"`
+	Status          string    `gorm:"type:varchar(20);not null;default:pending"`
+	Attempts        int       `gorm:"not null;default:0"`
+	LastError       string    `gorm:"type:text;not null;default:''"`
+	CreatedAt       time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	SentAt          *time.Time
+	DeliveredAt     *time.Time
+	ReportedAt      *time.Time
+	UpdatedAt       time.Time `gorm:"type:timestamp;default:current_timestamp"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type OutboxMessage struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	Kind          string    `gorm:"type:varchar(20);not null"`
+	EventName     string    `gorm:"type:varchar(100);not null;default:''"`
+	EventVersion  int       `gorm:"not null;default:1"`
+	UserID        *int64    `gorm:"index"`
+	Payload       string    `gorm:"type:jsonb;not null"`
+	TraceParent   string    `gorm:"type:varchar(55);not null;default:''"`
+	Status        string    `gorm:"type:varchar(20);not null;default:pending"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     string    `gorm:"type:text;not null;default:''"`
+	NextAttemptAt time.Time `gorm:"type:timestamp;not null"`
+	CreatedAt     time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	SentAt        *time.Time
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
@@ -2,14 +2,17 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Role struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement"`
 	Name      string    `gorm:"type:varchar(255);unique;not null"`
+	Version   int64     `gorm:"not null;default:1"`
 	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
 	UpdatedAt *time.Time
-	DeletedAt *time.Time `gorm:"index"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 
 	// Relasi many-to-many ke User lewat tabel pivot user_role
 	// Walaupun di tabel roles tidak ada kolom user_id,
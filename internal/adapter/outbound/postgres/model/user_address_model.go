@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserAddress struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	UserID        int64     `gorm:"not null;index:idx_user_addresses_user_id"`
+	Label         string    `gorm:"type:varchar(50);not null"`
+	RecipientName string    `gorm:"type:varchar(255);not null"`
+	Phone         string    `gorm:"type:varchar(17);not null"`
+	AddressLine   string    `gorm:"type:text;not null"`
+	District      string    `gorm:"type:varchar(100);not null"`
+	City          string    `gorm:"type:varchar(100);not null"`
+	Province      string    `gorm:"type:varchar(100);not null"`
+	PostalCode    string    `gorm:"type:varchar(10);not null"`
+	Notes         string    `gorm:"type:text;not null"`
+	Lat           *float64  `gorm:"type:numeric(9,6)"`
+	Lng           *float64  `gorm:"type:numeric(9,6)"`
+	IsDefault     bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt     time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	UpdatedAt     *time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+
+	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
+}
+
+func (UserAddress) TableName() string {
+	return "user_addresses"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type UserDeletionRequest struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	UserID      int64     `gorm:"not null"`
+	RequestedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	ScheduledAt time.Time `gorm:"type:timestamp;not null"`
+	CancelledAt *time.Time
+	CompletedAt *time.Time
+}
+
+func (UserDeletionRequest) TableName() string {
+	return "user_deletion_requests"
+}
@@ -1,21 +1,28 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID         int64     `gorm:"primaryKey;autoIncrement"`
-	Name       string    `gorm:"type:varchar(255);not null"`
-	Email      string    `gorm:"type:varchar(255);unique;not null;index:idx_users_email"`
-	Password   string    `gorm:"type:varchar(255);not null"`
-	Phone      string    `gorm:"type:varchar(17)"`
-	Photo      string    `gorm:"type:varchar(255)"`
-	Address    string    `gorm:"type:text"`
-	Lat        string    `gorm:"type:varchar(50)"`
-	Lng        string    `gorm:"type:varchar(50)"`
-	IsVerified bool      `gorm:"type:boolean;default:false;index:idx_users_is_verified"`
-	CreatedAt  time.Time `gorm:"type:timestamp;default:current_timestamp"`
-	UpdatedAt  *time.Time
-	DeletedAt  *time.Time `gorm:"index"`
+	ID              int64    `gorm:"primaryKey;autoIncrement"`
+	Name            string   `gorm:"type:varchar(255);not null"`
+	Email           string   `gorm:"type:varchar(255);unique;not null;index:idx_users_email"`
+	Password        string   `gorm:"type:varchar(255);not null"`
+	Phone           string   `gorm:"type:varchar(17)"`
+	Photo           string   `gorm:"type:varchar(255)"`
+	Address         string   `gorm:"type:text"`
+	Lat             *float64 `gorm:"type:numeric(9,6)"`
+	Lng             *float64 `gorm:"type:numeric(9,6)"`
+	IsVerified      bool     `gorm:"type:boolean;default:false;index:idx_users_is_verified"`
+	PhoneVerifiedAt *time.Time
+	ErasedAt        *time.Time
+	Version         int64     `gorm:"not null;default:1"`
+	CreatedAt       time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	UpdatedAt       *time.Time
+	DeletedAt       gorm.DeletedAt `gorm:"index"`
 
 	// Relasi many-to-many ke Role melalui tabel pivot "user_role".
 	// Meskipun tabel roles tidak memiliki kolom user_id,
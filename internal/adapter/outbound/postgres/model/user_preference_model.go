@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type UserPreference struct {
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false"`
+	Key       string    `gorm:"primaryKey;type:varchar(100)"`
+	Value     string    `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
+}
+
+func (UserPreference) TableName() string {
+	return "user_preferences"
+}
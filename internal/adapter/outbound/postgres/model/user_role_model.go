@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type UserRole struct {
@@ -10,7 +12,7 @@ type UserRole struct {
 	UserID    int64     `gorm:"not null"`
 	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
 	UpdatedAt *time.Time
-	DeletedAt *time.Time `gorm:"index"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 
 	// Relasi ke User & Role
 	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type VerificationToken struct {
@@ -12,7 +14,7 @@ type VerificationToken struct {
 	ExpiresAt time.Time `gorm:"type:timestamp;not null"`
 	CreatedAt time.Time `gorm:"type:timestamp;default:current_timestamp"`
 	UpdatedAt *time.Time
-	DeletedAt *time.Time `gorm:"index"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 
 	// Relasi ke User & Role
 	User User `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE"`
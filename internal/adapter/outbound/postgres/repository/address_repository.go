@@ -0,0 +1,352 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) outbound.AddressRepositoryInterface {
+	return &addressRepository{db: db}
+}
+
+func (a *addressRepository) GetAll(ctx context.Context, userID int64) ([]entity.AddressEntity, error) {
+	var (
+		modelAddresses []model.UserAddress
+		respEntities   []entity.AddressEntity
+	)
+
+	if err := a.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, id ASC").
+		Find(&modelAddresses).Error; err != nil {
+		log.Errorf("[AddressRepository-1] GetAll: %v", err)
+		return nil, err
+	}
+
+	if len(modelAddresses) == 0 {
+		err := errors.New("404")
+		log.Infof("[AddressRepository-2] GetAll: No address found")
+		return nil, err
+	}
+
+	for _, val := range modelAddresses {
+		respEntities = append(respEntities, addressEntity(val))
+	}
+
+	return respEntities, nil
+}
+
+func (a *addressRepository) GetByID(ctx context.Context, userID, addressID int64) (*entity.AddressEntity, error) {
+	modelAddress := model.UserAddress{}
+
+	if err := a.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", addressID, userID).
+		First(&modelAddress).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+			log.Infof("[AddressRepository-1] GetByID: Address not found")
+			return nil, err
+		}
+		log.Errorf("[AddressRepository-2] GetByID: %v", err)
+		return nil, err
+	}
+
+	respEntity := addressEntity(modelAddress)
+	return &respEntity, nil
+}
+
+// Create menyimpan alamat baru. Alamat pertama user otomatis menjadi default,
+// dan alamat default baru menggantikan default sebelumnya.
+func (a *addressRepository) Create(ctx context.Context, req entity.AddressEntity) (int64, error) {
+	modelAddress := model.UserAddress{
+		UserID:        req.UserID,
+		Label:         req.Label,
+		RecipientName: req.RecipientName,
+		Phone:         req.Phone,
+		AddressLine:   req.AddressLine,
+		District:      req.District,
+		City:          req.City,
+		Province:      req.Province,
+		PostalCode:    req.PostalCode,
+		Notes:         req.Notes,
+		Lat:           req.Lat,
+		Lng:           req.Lng,
+		IsDefault:     req.IsDefault,
+	}
+
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var countAddress int64
+		if err := tx.Model(&model.UserAddress{}).Where("user_id = ?", req.UserID).Count(&countAddress).Error; err != nil {
+			log.Errorf("[AddressRepository-1] Create: %v", err)
+			return err
+		}
+		if countAddress == 0 {
+			modelAddress.IsDefault = true
+		}
+
+		if modelAddress.IsDefault {
+			if err := unsetDefaultAddress(tx, req.UserID); err != nil {
+				log.Errorf("[AddressRepository-2] Create: %v", err)
+				return err
+			}
+		}
+
+		if err := tx.Create(&modelAddress).Error; err != nil {
+			log.Errorf("[AddressRepository-3] Create: %v", err)
+			return err
+		}
+
+		if modelAddress.IsDefault {
+			if err := syncDefaultAddress(tx, req.UserID); err != nil {
+				log.Errorf("[AddressRepository-4] Create: %v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return modelAddress.ID, nil
+}
+
+// Update mengganti seluruh field alamat. IsDefault false tidak mencabut status default,
+// default hanya berpindah saat alamat lain dijadikan default.
+func (a *addressRepository) Update(ctx context.Context, req entity.AddressEntity) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelAddress := model.UserAddress{}
+		if err := tx.Where("id = ? AND user_id = ?", req.ID, req.UserID).First(&modelAddress).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[AddressRepository-1] Update: Address not found")
+				return errors.New("404")
+			}
+			log.Errorf("[AddressRepository-2] Update: %v", err)
+			return err
+		}
+
+		isDefault := modelAddress.IsDefault || req.IsDefault
+		if req.IsDefault && !modelAddress.IsDefault {
+			if err := unsetDefaultAddress(tx, req.UserID); err != nil {
+				log.Errorf("[AddressRepository-3] Update: %v", err)
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{
+			"label":          req.Label,
+			"recipient_name": req.RecipientName,
+			"phone":          req.Phone,
+			"address_line":   req.AddressLine,
+			"district":       req.District,
+			"city":           req.City,
+			"province":       req.Province,
+			"postal_code":    req.PostalCode,
+			"notes":          req.Notes,
+			"lat":            req.Lat,
+			"lng":            req.Lng,
+			"is_default":     isDefault,
+		}
+		if err := tx.Model(&modelAddress).Updates(updates).Error; err != nil {
+			log.Errorf("[AddressRepository-4] Update: %v", err)
+			return err
+		}
+
+		if isDefault {
+			if err := syncDefaultAddress(tx, req.UserID); err != nil {
+				log.Errorf("[AddressRepository-5] Update: %v", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Delete menghapus alamat. Jika yang dihapus alamat default, alamat tertua yang
+// tersisa menjadi default baru.
+func (a *addressRepository) Delete(ctx context.Context, userID, addressID int64) error {
+	return a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelAddress := model.UserAddress{}
+		if err := tx.Where("id = ? AND user_id = ?", addressID, userID).First(&modelAddress).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[AddressRepository-1] Delete: Address not found")
+				return errors.New("404")
+			}
+			log.Errorf("[AddressRepository-2] Delete: %v", err)
+			return err
+		}
+
+		if err := deleteAddress(tx, modelAddress); err != nil {
+			log.Errorf("[AddressRepository-3] Delete: %v", err)
+			return err
+		}
+
+		if !modelAddress.IsDefault {
+			return nil
+		}
+
+		if err := syncDefaultAddress(tx, userID); err != nil {
+			log.Errorf("[AddressRepository-4] Delete: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// deleteAddress soft delete alamat, jika alamat default maka alamat tertua yang tersisa menjadi default.
+func deleteAddress(tx *gorm.DB, modelAddress model.UserAddress) error {
+	// Dicatat sebelum update karena gorm ikut mengubah field IsDefault pada modelAddress
+	wasDefault := modelAddress.IsDefault
+	if err := tx.Model(&modelAddress).Update("is_default", false).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Delete(&modelAddress).Error; err != nil {
+		return err
+	}
+
+	if !wasDefault {
+		return nil
+	}
+
+	next := model.UserAddress{}
+	err := tx.Where("user_id = ?", modelAddress.UserID).Order("id ASC").First(&next).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Model(&next).Update("is_default", true).Error
+}
+
+func unsetDefaultAddress(tx *gorm.DB, userID int64) error {
+	return tx.Model(&model.UserAddress{}).
+		Where("user_id = ? AND is_default", userID).
+		Update("is_default", false).Error
+}
+
+// saveDefaultAddress menulis address, lat & lng dari form user/customer (key kolom users) ke alamat
+// default lalu menyalinnya kembali ke users, sehingga users tidak pernah berbeda dari user_addresses.
+// User tanpa alamat default dibuatkan alamat "Utama" (seperti migrasi 000008) jika address diisi,
+// koordinat tanpa address tidak membuat alamat baru. Address kosong menghapus alamat default.
+func saveDefaultAddress(tx *gorm.DB, userID int64, changes map[string]interface{}) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	defaultAddress := model.UserAddress{}
+	err := tx.Where("user_id = ? AND is_default", userID).First(&defaultAddress).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	hasDefault := err == nil
+
+	addressLine, addressSet := changes["address"].(string)
+	updates := map[string]interface{}{}
+	if addressSet {
+		updates["address_line"] = addressLine
+	}
+	for _, column := range []string{"lat", "lng"} {
+		if val, ok := changes[column]; ok {
+			updates[column] = val
+		}
+	}
+
+	switch {
+	case hasDefault && addressSet && addressLine == "":
+		if err := deleteAddress(tx, defaultAddress); err != nil {
+			return err
+		}
+	case hasDefault:
+		if err := tx.Model(&defaultAddress).Updates(updates).Error; err != nil {
+			return err
+		}
+	case addressLine != "":
+		modelUser := model.User{}
+		if err := tx.Select("id", "name", "phone").Where("id = ?", userID).First(&modelUser).Error; err != nil {
+			return err
+		}
+
+		defaultAddress = model.UserAddress{
+			UserID:        userID,
+			Label:         "Utama",
+			RecipientName: modelUser.Name,
+			Phone:         modelUser.Phone,
+			AddressLine:   addressLine,
+			IsDefault:     true,
+		}
+		if err := tx.Create(&defaultAddress).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&defaultAddress).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	return syncDefaultAddress(tx, userID)
+}
+
+// addressUpdates memindahkan address, lat & lng dari map update users ke map tersendiri,
+// kolom itu hanya salinan alamat default dan ditulis lewat saveDefaultAddress.
+func addressUpdates(updates map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for _, column := range []string{"address", "lat", "lng"} {
+		if val, ok := updates[column]; ok {
+			changes[column] = val
+			delete(updates, column)
+		}
+	}
+	return changes
+}
+
+// syncDefaultAddress menyalin alamat default ke users.address, lat & lng agar fitur yang
+// masih memakai kolom tersebut (profile, nearby, export) tetap konsisten.
+func syncDefaultAddress(tx *gorm.DB, userID int64) error {
+	defaultAddress := model.UserAddress{}
+	err := tx.Where("user_id = ? AND is_default", userID).First(&defaultAddress).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"address": defaultAddress.AddressLine,
+		"lat":     defaultAddress.Lat,
+		"lng":     defaultAddress.Lng,
+		"version": gorm.Expr("version + 1"),
+	}).Error
+}
+
+func addressEntity(val model.UserAddress) entity.AddressEntity {
+	return entity.AddressEntity{
+		ID:            val.ID,
+		UserID:        val.UserID,
+		Label:         val.Label,
+		RecipientName: val.RecipientName,
+		Phone:         val.Phone,
+		AddressLine:   val.AddressLine,
+		District:      val.District,
+		City:          val.City,
+		Province:      val.Province,
+		PostalCode:    val.PostalCode,
+		Notes:         val.Notes,
+		Lat:           val.Lat,
+		Lng:           val.Lng,
+		IsDefault:     val.IsDefault,
+		CreatedAt:     val.CreatedAt,
+		UpdatedAt:     val.UpdatedAt,
+	}
+}
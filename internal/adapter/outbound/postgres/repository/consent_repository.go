@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type consentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(db *gorm.DB) outbound.ConsentRepositoryInterface {
+	return &consentRepository{db: db}
+}
+
+// CreateDocument menerbitkan versi baru dokumen legal. "409" jika versi untuk doc_type tersebut sudah ada.
+func (c *consentRepository) CreateDocument(ctx context.Context, req entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error) {
+	modelDocument := model.LegalDocument{
+		DocType:     req.DocType,
+		Version:     req.Version,
+		URL:         req.URL,
+		PublishedAt: req.PublishedAt,
+	}
+
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var countExisting int64
+		if err := tx.Model(&model.LegalDocument{}).
+			Where("doc_type = ? AND version = ?", req.DocType, req.Version).
+			Count(&countExisting).Error; err != nil {
+			log.Errorf("[ConsentRepository-1] CreateDocument: %v", err)
+			return err
+		}
+		if countExisting > 0 {
+			log.Infof("[ConsentRepository-2] CreateDocument: Version %s %s already exists", req.DocType, req.Version)
+			return errors.New("409")
+		}
+
+		if err := tx.Create(&modelDocument).Error; err != nil {
+			log.Errorf("[ConsentRepository-3] CreateDocument: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	respEntity := legalDocumentEntity(modelDocument)
+	return &respEntity, nil
+}
+
+// GetCurrentDocuments versi yang berlaku per doc_type, yaitu versi terakhir yang sudah dipublikasikan.
+func (c *consentRepository) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	var (
+		modelDocuments []model.LegalDocument
+		respEntities   []entity.LegalDocumentEntity
+	)
+
+	if err := c.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (doc_type) *
+		FROM legal_documents
+		WHERE published_at <= ?
+		ORDER BY doc_type, published_at DESC, id DESC`, time.Now()).
+		Scan(&modelDocuments).Error; err != nil {
+		log.Errorf("[ConsentRepository-1] GetCurrentDocuments: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelDocuments {
+		respEntities = append(respEntities, legalDocumentEntity(val))
+	}
+
+	return respEntities, nil
+}
+
+// GetAcceptedDocumentIDs subset dari documentIDs yang sudah disetujui user.
+func (c *consentRepository) GetAcceptedDocumentIDs(ctx context.Context, userID int64, documentIDs []int64) ([]int64, error) {
+	var acceptedIDs []int64
+
+	if len(documentIDs) == 0 {
+		return acceptedIDs, nil
+	}
+
+	if err := c.db.WithContext(ctx).Model(&model.UserConsent{}).
+		Where("user_id = ? AND document_id IN ?", userID, documentIDs).
+		Pluck("document_id", &acceptedIDs).Error; err != nil {
+		log.Errorf("[ConsentRepository-1] GetAcceptedDocumentIDs: %v", err)
+		return nil, err
+	}
+
+	return acceptedIDs, nil
+}
+
+// CreateConsents menyimpan persetujuan, persetujuan yang sudah tercatat sebelumnya tidak diubah.
+func (c *consentRepository) CreateConsents(ctx context.Context, consents []entity.ConsentEntity) error {
+	if len(consents) == 0 {
+		return nil
+	}
+
+	if err := c.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "document_id"}},
+			DoNothing: true,
+		}).
+		Create(userConsentModels(consents)).Error; err != nil {
+		log.Errorf("[ConsentRepository-1] CreateConsents: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAcceptanceReport jumlah user aktif yang menyetujui tiap versi dokumen.
+// User yang sudah dihapus atau di-erase tidak ikut dihitung.
+func (c *consentRepository) GetAcceptanceReport(ctx context.Context) ([]entity.ConsentReportEntity, error) {
+	var (
+		totalUsers   int64
+		respEntities []entity.ConsentReportEntity
+		rows         []struct {
+			model.LegalDocument
+			AcceptedCount int64
+		}
+	)
+
+	if err := c.db.WithContext(ctx).Model(&model.User{}).
+		Where("deleted_at IS NULL AND erased_at IS NULL").
+		Count(&totalUsers).Error; err != nil {
+		log.Errorf("[ConsentRepository-1] GetAcceptanceReport: %v", err)
+		return nil, err
+	}
+
+	if err := c.db.WithContext(ctx).Raw(`
+		SELECT d.*, COUNT(u.id) AS accepted_count
+		FROM legal_documents d
+		LEFT JOIN user_consents uc ON uc.document_id = d.id
+		LEFT JOIN users u ON u.id = uc.user_id AND u.deleted_at IS NULL AND u.erased_at IS NULL
+		GROUP BY d.id
+		ORDER BY d.doc_type ASC, d.published_at DESC, d.id DESC`).
+		Scan(&rows).Error; err != nil {
+		log.Errorf("[ConsentRepository-2] GetAcceptanceReport: %v", err)
+		return nil, err
+	}
+
+	for _, val := range rows {
+		respEntities = append(respEntities, entity.ConsentReportEntity{
+			Document:      legalDocumentEntity(val.LegalDocument),
+			AcceptedCount: val.AcceptedCount,
+			TotalUsers:    totalUsers,
+		})
+	}
+
+	return respEntities, nil
+}
+
+func legalDocumentEntity(val model.LegalDocument) entity.LegalDocumentEntity {
+	return entity.LegalDocumentEntity{
+		ID:          val.ID,
+		DocType:     val.DocType,
+		Version:     val.Version,
+		URL:         val.URL,
+		PublishedAt: val.PublishedAt,
+		CreatedAt:   val.CreatedAt,
+	}
+}
+
+func userConsentModels(consents []entity.ConsentEntity) []model.UserConsent {
+	modelConsents := make([]model.UserConsent, 0, len(consents))
+	for _, val := range consents {
+		acceptedAt := val.AcceptedAt
+		if acceptedAt.IsZero() {
+			acceptedAt = time.Now()
+		}
+		modelConsents = append(modelConsents, model.UserConsent{
+			UserID:     val.UserID,
+			DocumentID: val.DocumentID,
+			AcceptedAt: acceptedAt,
+			IPAddress:  val.IPAddress,
+		})
+	}
+	return modelConsents
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/utils/geo"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// customerOrderColumns whitelist kolom yang boleh dipakai di order_by / sort endpoint customer,
+// supaya nilai dari query string tidak langsung masuk ke ORDER BY.
+var customerOrderColumns = map[string]string{
+	"id":          "users.id",
+	"name":        "users.name",
+	"email":       "users.email",
+	"phone":       "users.phone",
+	"is_verified": "users.is_verified",
+	"created_at":  "users.created_at",
+	"updated_at":  "users.updated_at",
+	"deleted_at":  "users.deleted_at",
+}
+
+// customerFilterColumns kolom untuk filter[...]; "role" ditangani terpisah lewat subquery.
+var customerFilterColumns = map[string]string{
+	"id":          "users.id",
+	"name":        "users.name",
+	"email":       "users.email",
+	"phone":       "users.phone",
+	"is_verified": "users.is_verified",
+	"created_at":  "users.created_at",
+	"updated_at":  "users.updated_at",
+}
+
+// customerSelectColumns kolom untuk sparse fieldset; "role" diambil lewat Preload.
+var customerSelectColumns = map[string]string{
+	"id":          "users.id",
+	"name":        "users.name",
+	"email":       "users.email",
+	"phone":       "users.phone",
+	"photo":       "users.photo",
+	"address":     "users.address",
+	"lat":         "users.lat",
+	"lng":         "users.lng",
+	"is_verified": "users.is_verified",
+	"created_at":  "users.created_at",
+}
+
+var filterComparators = map[string]string{
+	entity.FilterOpEq:  "=",
+	entity.FilterOpNe:  "<>",
+	entity.FilterOpGt:  ">",
+	entity.FilterOpGte: ">=",
+	entity.FilterOpLt:  "<",
+	entity.FilterOpLte: "<=",
+}
+
+func customerOrder(query entity.QueryStringEntity) string {
+	sorts := query.Sorts
+	if len(sorts) == 0 {
+		sorts = []entity.SortEntity{{Field: query.OrderBy, Desc: strings.ToLower(query.OrderType) != "asc"}}
+	}
+
+	var (
+		clauses []string
+		lastDir = "DESC"
+	)
+	for _, val := range sorts {
+		column, ok := customerOrderColumns[strings.ToLower(val.Field)]
+		if !ok {
+			continue
+		}
+
+		lastDir = "ASC"
+		if val.Desc {
+			lastDir = "DESC"
+		}
+		clauses = append(clauses, fmt.Sprintf("%s %s", column, lastDir))
+	}
+
+	if len(clauses) == 0 {
+		clauses = append(clauses, customerOrderColumns["created_at"]+" DESC")
+	}
+
+	// id sebagai tie breaker agar urutan stabil untuk pagination & export
+	clauses = append(clauses, "users.id "+lastDir)
+	return strings.Join(clauses, ", ")
+}
+
+// customerFilterScope menerjemahkan filter[...] menjadi WHERE dengan parameter binding.
+// Field/operator di luar whitelist atau nilai yang tidak valid menghasilkan error "400".
+func customerFilterScope(filters []entity.FilterEntity) (func(db *gorm.DB) *gorm.DB, error) {
+	type condition struct {
+		sql  string
+		args []any
+	}
+	conditions := make([]condition, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Field == "role" {
+			cond, args, err := customerRoleFilter(filter)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition{sql: cond, args: args})
+			continue
+		}
+
+		column, ok := customerFilterColumns[filter.Field]
+		if !ok {
+			return nil, errors.New("400")
+		}
+
+		switch filter.Operator {
+		case entity.FilterOpLike:
+			conditions = append(conditions, condition{sql: column + " ILIKE ?", args: []any{"%" + filter.Value + "%"}})
+
+		case entity.FilterOpIn:
+			values := make([]any, 0)
+			for _, raw := range strings.Split(filter.Value, ",") {
+				value, err := parseCustomerFilterValue(filter.Field, strings.TrimSpace(raw))
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			conditions = append(conditions, condition{sql: column + " IN ?", args: []any{values}})
+
+		default:
+			comparator, ok := filterComparators[filter.Operator]
+			if !ok {
+				return nil, errors.New("400")
+			}
+			value, err := parseCustomerFilterValue(filter.Field, filter.Value)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition{sql: fmt.Sprintf("%s %s ?", column, comparator), args: []any{value}})
+		}
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		for _, cond := range conditions {
+			db = db.Where(cond.sql, cond.args...)
+		}
+		return db
+	}, nil
+}
+
+func customerRoleFilter(filter entity.FilterEntity) (string, []any, error) {
+	const roleExists = `EXISTS (SELECT 1 FROM user_role JOIN roles ON roles.id = user_role.role_id
+		WHERE user_role.user_id = users.id AND roles.deleted_at IS NULL AND roles.name %s)`
+
+	switch filter.Operator {
+	case entity.FilterOpEq:
+		return fmt.Sprintf(roleExists, "= ?"), []any{filter.Value}, nil
+	case entity.FilterOpNe:
+		return "NOT " + fmt.Sprintf(roleExists, "= ?"), []any{filter.Value}, nil
+	case entity.FilterOpIn:
+		values := strings.Split(filter.Value, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return fmt.Sprintf(roleExists, "IN ?"), []any{values}, nil
+	default:
+		return "", nil, errors.New("400")
+	}
+}
+
+func parseCustomerFilterValue(field, value string) (any, error) {
+	switch field {
+	case "id":
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errors.New("400")
+		}
+		return parsed, nil
+
+	case "is_verified":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errors.New("400")
+		}
+		return parsed, nil
+
+	case "created_at", "updated_at":
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"} {
+			if parsed, err := time.Parse(layout, value); err == nil {
+				return parsed, nil
+			}
+		}
+		return nil, errors.New("400")
+
+	default:
+		return value, nil
+	}
+}
+
+// customerSelect mengembalikan kolom untuk sparse fieldset. users.id selalu ikut
+// karena dibutuhkan untuk Preload role. Nil berarti semua kolom.
+func customerSelect(fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	columns := []string{"users.id"}
+	for _, field := range fields {
+		if column, ok := customerSelectColumns[field]; ok && field != "id" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func customerWantsRole(fields []string) bool {
+	return len(fields) == 0 || slices.Contains(fields, "role")
+}
+
+func customerSearchScope(search string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search == "" {
+			return db
+		}
+
+		like := "%" + search + "%"
+		return db.Where("users.name ILIKE ? OR users.email ILIKE ? OR users.phone ILIKE ?", like, like, like)
+	}
+}
+
+// customerCursorColumns kolom untuk setiap field di entity.CustomerCursorSortFields.
+var customerCursorColumns = map[string]string{
+	"id":         "users.id",
+	"name":       "users.name",
+	"email":      "users.email",
+	"created_at": "users.created_at",
+}
+
+func customerCursorValue(orderBy string, user model.User) string {
+	switch orderBy {
+	case "id":
+		return strconv.FormatInt(user.ID, 10)
+	case "name":
+		return user.Name
+	case "email":
+		return user.Email
+	default:
+		// Selalu UTC agar cursor tidak bergantung pada zona waktu koneksi database
+		return user.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+func parseCustomerCursorValue(orderBy, value string) (any, error) {
+	switch orderBy {
+	case "id":
+		return strconv.ParseInt(value, 10, 64)
+	case "name", "email":
+		return value, nil
+	default:
+		createdAt, err := time.Parse(time.RFC3339Nano, value)
+		return createdAt.UTC(), err
+	}
+}
+
+const (
+	searchHighlightStart = "<mark>"
+	searchHighlightStop  = "</mark>"
+)
+
+var searchWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// customerTsQuery mengubah kata kunci menjadi tsquery prefix ("budi:* & san:*") sehingga
+// kata yang belum selesai diketik tetap cocok. Karakter selain huruf/angka dibuang
+// agar input user tidak merusak sintaks to_tsquery.
+func customerTsQuery(search string) (string, error) {
+	words := searchWordPattern.FindAllString(strings.ToLower(search), -1)
+	if len(words) == 0 {
+		return "", errors.New("400")
+	}
+
+	for i := range words {
+		words[i] += ":*"
+	}
+	return strings.Join(words, " & "), nil
+}
+
+// customerSearchHighlights hanya menyimpan field yang benar-benar mengandung highlight.
+func customerSearchHighlights(fields map[string]string) map[string]string {
+	highlights := map[string]string{}
+	for field, val := range fields {
+		if strings.Contains(val, searchHighlightStart) {
+			highlights[field] = val
+		}
+	}
+	return highlights
+}
+
+// customerDistanceSQL jarak haversine (km) dari users.lat/lng ke titik @lat, @lng.
+const customerDistanceSQL = `(2 * @earth_radius * asin(least(1, sqrt(
+	power(sin(radians(users.lat - @lat) / 2), 2) +
+	cos(radians(@lat)) * cos(radians(users.lat)) * power(sin(radians(users.lng - @lng) / 2), 2)))))`
+
+// customerBoxScope membatasi customer di dalam box, termasuk box yang melewati antimeridian.
+func customerBoxScope(box geo.Box) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("users.lat BETWEEN ? AND ?", box.MinLat, box.MaxLat)
+		if box.MinLng <= box.MaxLng {
+			return db.Where("users.lng BETWEEN ? AND ?", box.MinLng, box.MaxLng)
+		}
+		return db.Where("(users.lng >= ? OR users.lng <= ?)", box.MinLng, box.MaxLng)
+	}
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationDeliveryRepository(db *gorm.DB) outbound.NotificationDeliveryRepositoryInterface {
+	return &notificationDeliveryRepository{db: db}
+}
+
+// UpsertStatus idempotent terhadap event yang dikirim ulang maupun datang tidak berurutan.
+func (n *notificationDeliveryRepository) UpsertStatus(ctx context.Context, req entity.NotificationDeliveryEntity) error {
+	modelDelivery := model.NotificationDelivery{
+		MessageID:  req.MessageID,
+		UserID:     outboxUserID(req.UserID),
+		Channel:    req.Channel,
+		Status:     req.Status,
+		Reason:     req.Reason,
+		OccurredAt: req.OccurredAt,
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := n.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "channel", "status", "reason", "occurred_at", "updated_at"}),
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "notification_deliveries.occurred_at <= EXCLUDED.occurred_at"},
+			}},
+		}).
+		Create(&modelDelivery).Error; err != nil {
+		log.Errorf("[NotificationDeliveryRepository-1] UpsertStatus: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) outbound.NotificationRepositoryInterface {
+	return &notificationRepository{db: db}
+}
+
+func (n *notificationRepository) GetAll(ctx context.Context, query entity.NotificationHistoryQueryEntity) ([]entity.NotificationHistoryEntity, int64, int64, error) {
+	var (
+		modelNotifications []model.Notification
+		respEntities       []entity.NotificationHistoryEntity
+		countData          int64
+	)
+
+	offset := (query.Page - 1) * query.Limit
+
+	sqlMain := n.db.WithContext(ctx).Model(&model.Notification{}).Where("user_id = ?", query.UserID)
+	if query.Status != "" {
+		sqlMain = sqlMain.Where("status = ?", query.Status)
+	}
+
+	if err := sqlMain.Count(&countData).Error; err != nil {
+		log.Errorf("[NotificationRepository-1] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+
+	if err := sqlMain.Order("created_at DESC, id DESC").Limit(int(query.Limit)).Offset(int(offset)).
+		Find(&modelNotifications).Error; err != nil {
+		log.Errorf("[NotificationRepository-2] GetAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelNotifications) < 1 {
+		err := errors.New("404")
+		log.Infof("[NotificationRepository-3] GetAll: No notification found")
+		return nil, 0, 0, err
+	}
+
+	for _, val := range modelNotifications {
+		respEntities = append(respEntities, notificationHistoryEntity(val))
+	}
+
+	return respEntities, countData, int64(totalPage), nil
+}
+
+func (n *notificationRepository) GetByID(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error) {
+	modelNotification := model.Notification{}
+	if err := n.db.WithContext(ctx).Where("id = ?", id).First(&modelNotification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Infof("[NotificationRepository-1] GetByID: Notification not found")
+			return nil, errors.New("404")
+		}
+		log.Errorf("[NotificationRepository-2] GetByID: %v", err)
+		return nil, err
+	}
+
+	result := notificationHistoryEntity(modelNotification)
+	return &result, nil
+}
+
+// Create menulis notifikasi ke outbox beserta riwayatnya, dikirim relay seperti notifikasi lain.
+func (n *notificationRepository) Create(ctx context.Context, message entity.PublishMessage) (*entity.NotificationHistoryEntity, error) {
+	var result entity.NotificationHistoryEntity
+
+	err := n.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		created, err := createNotifications(tx, 0, []entity.PublishMessage{message}, nil)
+		if err != nil {
+			return err
+		}
+
+		result = notificationHistoryEntity(created[0])
+		return nil
+	})
+	if err != nil {
+		log.Errorf("[NotificationRepository-1] Create: %v", err)
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Resend notifikasi yang masih pending tidak bisa dikirim ulang ("409") agar user tidak menerima
+// pesan yang sama dua kali saat relay masih mencoba mengirimnya. Notifikasi kredensial (password
+// sementara, token, OTP) tidak bisa dikirim ulang ("422") karena isinya sudah tidak berlaku,
+// penerima diambil dari data user saat ini, bukan alamat lama di payload.
+func (n *notificationRepository) Resend(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error) {
+	var result entity.NotificationHistoryEntity
+
+	err := n.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		original := model.Notification{}
+		if err := tx.Where("id = ?", id).First(&original).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[NotificationRepository-1] Resend: Notification not found")
+				return errors.New("404")
+			}
+			log.Errorf("[NotificationRepository-2] Resend: %v", err)
+			return err
+		}
+
+		if original.Status == entity.NotificationStatusPending {
+			log.Infof("[NotificationRepository-3] Resend: Notification %d is still pending", id)
+			return errors.New("409")
+		}
+
+		if slices.Contains(entity.CredentialNotificationQueues, original.QueueName) {
+			log.Infof("[NotificationRepository-4] Resend: Notification %d contains credentials, a new one must be requested", id)
+			return errors.New("422")
+		}
+
+		if original.OutboxMessageID == nil {
+			log.Infof("[NotificationRepository-4] Resend: Payload of notification %d no longer exists", id)
+			return errors.New("422")
+		}
+
+		outboxMessage := model.OutboxMessage{}
+		if err := tx.Where("id = ?", *original.OutboxMessageID).First(&outboxMessage).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[NotificationRepository-4] Resend: Payload of notification %d no longer exists", id)
+				return errors.New("422")
+			}
+			log.Errorf("[NotificationRepository-5] Resend: %v", err)
+			return err
+		}
+
+		message := entity.PublishMessage{}
+		if err := json.Unmarshal([]byte(outboxMessage.Payload), &message); err != nil {
+			log.Errorf("[NotificationRepository-6] Resend: %v", err)
+			return err
+		}
+		// message_id baru agar laporan pengiriman tidak tercampur dengan notifikasi asal
+		message.MessageId = ""
+
+		if original.UserID != nil {
+			modelUser := model.User{}
+			if err := tx.Where("id = ? AND erased_at IS NULL", *original.UserID).First(&modelUser).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Infof("[NotificationRepository-4] Resend: Recipient of notification %d no longer exists", id)
+					return errors.New("422")
+				}
+				log.Errorf("[NotificationRepository-5] Resend: %v", err)
+				return err
+			}
+			message.Email = modelUser.Email
+			message.Phone = modelUser.Phone
+		}
+
+		created, err := createNotifications(tx, 0, []entity.PublishMessage{message}, &original.ID)
+		if err != nil {
+			log.Errorf("[NotificationRepository-7] Resend: %v", err)
+			return err
+		}
+
+		result = notificationHistoryEntity(created[0])
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (n *notificationRepository) UpdateSendStatus(ctx context.Context, req entity.NotificationSendStatusEntity) error {
+	updates := map[string]interface{}{
+		"attempts":   req.Attempts,
+		"last_error": req.LastError,
+		"updated_at": time.Now(),
+	}
+	if req.Status != entity.NotificationStatusPending {
+		updates["status"] = req.Status
+	}
+	if req.Status == entity.NotificationStatusSent {
+		updates["sent_at"] = req.OccurredAt
+	}
+
+	if err := n.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("outbox_message_id = ? AND status = ?", req.OutboxMessageID, entity.NotificationStatusPending).
+		Updates(updates).Error; err != nil {
+		log.Errorf("[NotificationRepository-1] UpdateSendStatus: %v", err)
+		return err
+	}
+	return nil
+}
+
+// UpdateDeliveryStatus laporan bisa datang sebelum relay menandai sent (producer async), sent_at
+// diisi dari waktu laporan jika masih kosong. Laporan untuk message_id yang tidak ada di riwayat diabaikan.
+func (n *notificationRepository) UpdateDeliveryStatus(ctx context.Context, req entity.NotificationDeliveryEntity) error {
+	updates := map[string]interface{}{
+		"status":      req.Status,
+		"last_error":  req.Reason,
+		"sent_at":     gorm.Expr("COALESCE(sent_at, ?)", req.OccurredAt),
+		"reported_at": req.OccurredAt,
+		"updated_at":  time.Now(),
+	}
+	if req.Status == entity.NotificationStatusDelivered {
+		updates["delivered_at"] = req.OccurredAt
+	}
+
+	if err := n.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("message_id = ? AND status <> ?", req.MessageID, entity.NotificationStatusSkipped).
+		Where("reported_at IS NULL OR reported_at <= ?", req.OccurredAt).
+		Updates(updates).Error; err != nil {
+		log.Errorf("[NotificationRepository-1] UpdateDeliveryStatus: %v", err)
+		return err
+	}
+	return nil
+}
+
+func notificationHistoryEntity(val model.Notification) entity.NotificationHistoryEntity {
+	result := entity.NotificationHistoryEntity{
+		ID:          val.ID,
+		MessageID:   val.MessageID,
+		ResendOfID:  val.ResendOfID,
+		Type:        val.Type,
+		QueueName:   val.QueueName,
+		Template:    val.Template,
+		Recipient:   val.Recipient,
+		Status:      val.Status,
+		Attempts:    val.Attempts,
+		LastError:   val.LastError,
+		CreatedAt:   val.CreatedAt,
+		SentAt:      val.SentAt,
+		DeliveredAt: val.DeliveredAt,
+		UpdatedAt:   val.UpdatedAt,
+	}
+	if val.UserID != nil {
+		result.UserID = *val.UserID
+	}
+	return result
+}
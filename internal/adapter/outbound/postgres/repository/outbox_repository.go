@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/traceparent"
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) outbound.OutboxRepositoryInterface {
+	return &outboxRepository{db: db}
+}
+
+func (o *outboxRepository) ClaimPending(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]entity.OutboxMessageEntity, error) {
+	var (
+		modelMessages []model.OutboxMessage
+		respEntities  []entity.OutboxMessageEntity
+	)
+
+	// SKIP LOCKED agar relay lain langsung mengambil baris berikutnya, bukan menunggu.
+	// Event user baru diambil setelah event sebelumnya milik user yang sama terkirim
+	// (atau gagal permanen) agar urutan event per user tidak tertukar saat retry
+	if err := o.db.WithContext(ctx).Raw(`
+		UPDATE outbox_messages
+		SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_messages o
+			WHERE o.status = ? AND o.next_attempt_at <= ?
+			AND (o.kind <> ? OR o.user_id IS NULL OR NOT EXISTS (
+				SELECT 1 FROM outbox_messages p
+				WHERE p.user_id = o.user_id AND p.kind = o.kind AND p.status = o.status AND p.id < o.id
+			))
+			ORDER BY o.id ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), entity.OutboxStatusPending, now, entity.OutboxKindEvent, limit).
+		Scan(&modelMessages).Error; err != nil {
+		log.Errorf("[OutboxRepository-1] ClaimPending: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelMessages {
+		respEntities = append(respEntities, entity.OutboxMessageEntity{
+			ID:            val.ID,
+			Kind:          val.Kind,
+			EventName:     val.EventName,
+			EventVersion:  val.EventVersion,
+			UserID:        val.UserID,
+			Payload:       []byte(val.Payload),
+			TraceParent:   val.TraceParent,
+			Status:        val.Status,
+			Attempts:      val.Attempts,
+			LastError:     val.LastError,
+			NextAttemptAt: val.NextAttemptAt,
+			CreatedAt:     val.CreatedAt,
+			SentAt:        val.SentAt,
+		})
+	}
+
+	return respEntities, nil
+}
+
+// redactedNotificationPayload isi template (password sementara, link reset/verifikasi, kode OTP) dan
+// pesan notifikasi kredensial dihapus dari payload setelah selesai, hanya data routing yang disimpan.
+var redactedNotificationPayload = gorm.Expr("CASE WHEN kind = ? AND payload->>'queue_name' IN ? THEN payload - 'data' - 'message' ELSE payload END",
+	entity.OutboxKindNotification, entity.CredentialNotificationQueues)
+
+func (o *outboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	if err := o.db.WithContext(ctx).Model(&model.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     entity.OutboxStatusSent,
+			"sent_at":    sentAt,
+			"last_error": "",
+			"payload":    redactedNotificationPayload,
+		}).Error; err != nil {
+		log.Errorf("[OutboxRepository-1] MarkSent: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (o *outboxRepository) MarkRetry(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	if err := o.db.WithContext(ctx).Model(&model.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error; err != nil {
+		log.Errorf("[OutboxRepository-1] MarkRetry: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (o *outboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	if err := o.db.WithContext(ctx).Model(&model.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     entity.OutboxStatusFailed,
+			"last_error": lastError,
+			"payload":    redactedNotificationPayload,
+		}).Error; err != nil {
+		log.Errorf("[OutboxRepository-1] MarkFailed: %v", err)
+		return err
+	}
+	return nil
+}
+
+func (o *outboxRepository) Release(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	if err := o.db.WithContext(ctx).Model(&model.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("GREATEST(attempts - 1, 0)"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error; err != nil {
+		log.Errorf("[OutboxRepository-1] Release: %v", err)
+		return err
+	}
+	return nil
+}
+
+// createNotificationOutbox menulis notifikasi ke outbox beserta riwayatnya di tabel notifications
+// memakai tx milik pemanggil. traceparent diambil dari context tx agar relay bisa meneruskannya ke header Kafka.
+// userID dipakai jika UserId pada pesan belum diisi (misalnya user baru dibuat di tx yang sama).
+func createNotificationOutbox(tx *gorm.DB, userID int64, messages []entity.PublishMessage) error {
+	_, err := createNotifications(tx, userID, messages, nil)
+	return err
+}
+
+// createNotifications message_id riwayat sama dengan yang dikirim relay: MessageId pada pesan, atau ID outbox.
+func createNotifications(tx *gorm.DB, userID int64, messages []entity.PublishMessage, resendOfID *int64) ([]model.Notification, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	// Salinan agar UserId yang diisi tidak mengubah slice milik pemanggil
+	messages = slices.Clone(messages)
+	modelMessages := make([]model.OutboxMessage, 0, len(messages))
+	for i := range messages {
+		if messages[i].UserId == 0 {
+			messages[i].UserId = userID
+		}
+
+		payload, err := json.Marshal(messages[i])
+		if err != nil {
+			return nil, err
+		}
+
+		modelMessages = append(modelMessages, model.OutboxMessage{
+			Kind:          entity.OutboxKindNotification,
+			UserID:        outboxUserID(messages[i].UserId),
+			Payload:       string(payload),
+			TraceParent:   traceparent.FromContext(tx.Statement.Context),
+			Status:        entity.OutboxStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	if err := tx.Create(&modelMessages).Error; err != nil {
+		return nil, err
+	}
+
+	modelNotifications := make([]model.Notification, 0, len(messages))
+	for i, val := range messages {
+		messageID := val.MessageId
+		if messageID == "" {
+			messageID = strconv.FormatInt(modelMessages[i].ID, 10)
+		}
+
+		notifType := entity.NotificationTypeForQueue(val.QueueName)
+		recipient := val.Email
+		if notifType == entity.NotificationTypeSMS {
+			recipient = val.Phone
+		}
+
+		modelNotifications = append(modelNotifications, model.Notification{
+			MessageID:       messageID,
+			OutboxMessageID: &modelMessages[i].ID,
+			ResendOfID:      resendOfID,
+			UserID:          outboxUserID(val.UserId),
+			Type:            notifType,
+			QueueName:       val.QueueName,
+			Template:        val.Template,
+			Recipient:       recipient,
+			Status:          entity.NotificationStatusPending,
+		})
+	}
+
+	if err := tx.Create(&modelNotifications).Error; err != nil {
+		return nil, err
+	}
+
+	return modelNotifications, nil
+}
+
+// createEventOutbox menulis domain event ke outbox memakai tx milik pemanggil.
+func createEventOutbox(tx *gorm.DB, events ...entity.DomainEventEntity) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	modelMessages := make([]model.OutboxMessage, 0, len(events))
+	for _, val := range events {
+		payload, err := json.Marshal(val.Data)
+		if err != nil {
+			return err
+		}
+
+		modelMessages = append(modelMessages, model.OutboxMessage{
+			Kind:          entity.OutboxKindEvent,
+			EventName:     val.Name,
+			EventVersion:  val.Version,
+			UserID:        outboxUserID(val.UserID),
+			Payload:       string(payload),
+			TraceParent:   traceparent.FromContext(tx.Statement.Context),
+			Status:        entity.OutboxStatusPending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	return tx.Create(&modelMessages).Error
+}
+
+func outboxUserID(userID int64) *int64 {
+	if userID == 0 {
+		return nil
+	}
+	return &userID
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepository(db *gorm.DB) outbound.PreferenceRepositoryInterface {
+	return &preferenceRepository{db: db}
+}
+
+func (p *preferenceRepository) GetAll(ctx context.Context, userID int64) ([]entity.StoredPreferenceEntity, error) {
+	var (
+		modelPreferences []model.UserPreference
+		respEntities     []entity.StoredPreferenceEntity
+	)
+
+	if err := p.db.WithContext(ctx).Where("user_id = ?", userID).Find(&modelPreferences).Error; err != nil {
+		log.Errorf("[PreferenceRepository-1] GetAll: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelPreferences {
+		respEntities = append(respEntities, entity.StoredPreferenceEntity{
+			Key:       val.Key,
+			Value:     val.Value,
+			UpdatedAt: val.UpdatedAt,
+		})
+	}
+
+	return respEntities, nil
+}
+
+func (p *preferenceRepository) GetByKey(ctx context.Context, userID int64, key string) (*entity.StoredPreferenceEntity, error) {
+	modelPreference := model.UserPreference{}
+
+	if err := p.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&modelPreference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("404")
+		}
+		log.Errorf("[PreferenceRepository-1] GetByKey: %v", err)
+		return nil, err
+	}
+
+	return &entity.StoredPreferenceEntity{
+		Key:       modelPreference.Key,
+		Value:     modelPreference.Value,
+		UpdatedAt: modelPreference.UpdatedAt,
+	}, nil
+}
+
+// Save upsert nilai preferensi dan menghapus key yang dikembalikan ke default dalam satu transaksi.
+func (p *preferenceRepository) Save(ctx context.Context, userID int64, values map[string]string, resetKeys []string) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(resetKeys) > 0 {
+			if err := tx.Where("user_id = ? AND key IN ?", userID, resetKeys).Delete(&model.UserPreference{}).Error; err != nil {
+				log.Errorf("[PreferenceRepository-1] Save: %v", err)
+				return err
+			}
+		}
+
+		if len(values) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		modelPreferences := make([]model.UserPreference, 0, len(values))
+		for key, value := range values {
+			modelPreferences = append(modelPreferences, model.UserPreference{
+				UserID:    userID,
+				Key:       key,
+				Value:     value,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+		}).Create(&modelPreferences).Error; err != nil {
+			log.Errorf("[PreferenceRepository-2] Save: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+const erasedUserName = "Deleted User"
+
+type privacyRepository struct {
+	db *gorm.DB
+}
+
+func NewPrivacyRepository(db *gorm.DB) outbound.PrivacyRepositoryInterface {
+	return &privacyRepository{db: db}
+}
+
+func (p *privacyRepository) GetUserRoles(ctx context.Context, userID int64) ([]entity.RoleEntity, error) {
+	var (
+		modelRoles   []model.Role
+		respEntities []entity.RoleEntity
+	)
+
+	if err := p.db.WithContext(ctx).
+		Joins("JOIN user_role ON user_role.role_id = roles.id").
+		Where("user_role.user_id = ?", userID).
+		Order("roles.id ASC").
+		Find(&modelRoles).Error; err != nil {
+		log.Errorf("[PrivacyRepository-1] GetUserRoles: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelRoles {
+		respEntities = append(respEntities, entity.RoleEntity{
+			ID:   val.ID,
+			Name: val.Name,
+		})
+	}
+
+	return respEntities, nil
+}
+
+func (p *privacyRepository) GetVerificationTokens(ctx context.Context, userID int64) ([]entity.VerificationTokenEntity, error) {
+	var (
+		modelTokens  []model.VerificationToken
+		respEntities []entity.VerificationTokenEntity
+	)
+
+	if err := p.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&modelTokens).Error; err != nil {
+		log.Errorf("[PrivacyRepository-1] GetVerificationTokens: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelTokens {
+		respEntities = append(respEntities, entity.VerificationTokenEntity{
+			ID:        val.ID,
+			UserID:    val.UserID,
+			Token:     val.Token,
+			TokenType: val.TokenType,
+			ExpiresAt: val.ExpiresAt,
+			CreatedAt: val.CreatedAt,
+		})
+	}
+
+	return respEntities, nil
+}
+
+func (p *privacyRepository) GetOtherUserPhotos(ctx context.Context, userID int64, folder string) ([]string, error) {
+	var photos []string
+
+	// Unscoped karena customer yang di soft delete masih bisa di restore bersama fotonya
+	if err := p.db.WithContext(ctx).Unscoped().Model(&model.User{}).
+		Where("id <> ? AND erased_at IS NULL AND strpos(photo, ?) > 0", userID, folder).
+		Pluck("photo", &photos).Error; err != nil {
+		log.Errorf("[PrivacyRepository-1] GetOtherUserPhotos: %v", err)
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+// CreateDeletionRequest mencatat permintaan hapus akun. "409" jika masih ada permintaan yang berjalan.
+func (p *privacyRepository) CreateDeletionRequest(ctx context.Context, userID int64, scheduledAt time.Time) (*entity.DeletionRequestEntity, error) {
+	modelRequest := model.UserDeletionRequest{
+		UserID:      userID,
+		RequestedAt: time.Now(),
+		ScheduledAt: scheduledAt,
+	}
+
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var countPending int64
+		if err := tx.Model(&model.UserDeletionRequest{}).
+			Where("user_id = ? AND cancelled_at IS NULL AND completed_at IS NULL", userID).
+			Count(&countPending).Error; err != nil {
+			log.Errorf("[PrivacyRepository-1] CreateDeletionRequest: %v", err)
+			return err
+		}
+		if countPending > 0 {
+			log.Infof("[PrivacyRepository-2] CreateDeletionRequest: Pending request already exists")
+			return errors.New("409")
+		}
+
+		if err := tx.Create(&modelRequest).Error; err != nil {
+			log.Errorf("[PrivacyRepository-3] CreateDeletionRequest: %v", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	respEntity := deletionRequestEntity(modelRequest)
+	return &respEntity, nil
+}
+
+func (p *privacyRepository) GetPendingDeletionRequest(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error) {
+	modelRequest := model.UserDeletionRequest{}
+
+	if err := p.db.WithContext(ctx).
+		Where("user_id = ? AND cancelled_at IS NULL AND completed_at IS NULL", userID).
+		First(&modelRequest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+			log.Infof("[PrivacyRepository-1] GetPendingDeletionRequest: Deletion request not found")
+			return nil, err
+		}
+		log.Errorf("[PrivacyRepository-2] GetPendingDeletionRequest: %v", err)
+		return nil, err
+	}
+
+	respEntity := deletionRequestEntity(modelRequest)
+	return &respEntity, nil
+}
+
+func (p *privacyRepository) CancelDeletionRequest(ctx context.Context, userID int64) error {
+	result := p.db.WithContext(ctx).
+		Model(&model.UserDeletionRequest{}).
+		Where("user_id = ? AND cancelled_at IS NULL AND completed_at IS NULL", userID).
+		Update("cancelled_at", time.Now())
+	if result.Error != nil {
+		log.Errorf("[PrivacyRepository-1] CancelDeletionRequest: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Infof("[PrivacyRepository-2] CancelDeletionRequest: Deletion request not found")
+		return errors.New("404")
+	}
+
+	return nil
+}
+
+func (p *privacyRepository) GetDueDeletionRequests(ctx context.Context, dueBefore time.Time, limit int) ([]entity.DeletionRequestEntity, error) {
+	var (
+		modelRequests []model.UserDeletionRequest
+		respEntities  []entity.DeletionRequestEntity
+	)
+
+	if err := p.db.WithContext(ctx).
+		Where("cancelled_at IS NULL AND completed_at IS NULL AND scheduled_at <= ?", dueBefore).
+		Order("scheduled_at ASC").
+		Limit(limit).
+		Find(&modelRequests).Error; err != nil {
+		log.Errorf("[PrivacyRepository-1] GetDueDeletionRequests: %v", err)
+		return nil, err
+	}
+
+	for _, val := range modelRequests {
+		respEntities = append(respEntities, deletionRequestEntity(val))
+	}
+
+	return respEntities, nil
+}
+
+// EraseUser menganonimkan PII user dan menutup permintaan hapus dalam satu transaksi.
+// Baris users dipertahankan (soft delete) agar foreign key & histori tetap valid,
+// sedangkan alamat dan token verifikasi dihapus permanen. "404" jika permintaan sudah dibatalkan.
+func (p *privacyRepository) EraseUser(ctx context.Context, req entity.DeletionRequestEntity, erasedAt time.Time, events []entity.DomainEventEntity) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.UserDeletionRequest{}).
+			Where("id = ? AND cancelled_at IS NULL AND completed_at IS NULL", req.ID).
+			Update("completed_at", erasedAt)
+		if result.Error != nil {
+			log.Errorf("[PrivacyRepository-1] EraseUser: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Infof("[PrivacyRepository-2] EraseUser: Deletion request no longer pending")
+			return errors.New("404")
+		}
+
+		updates := map[string]interface{}{
+			"name":              erasedUserName,
+			"email":             fmt.Sprintf("erased-%d@erased.invalid", req.UserID),
+			"password":          "",
+			"phone":             "",
+			"photo":             "",
+			"address":           "",
+			"lat":               nil,
+			"lng":               nil,
+			"is_verified":       false,
+			"phone_verified_at": nil,
+			"erased_at":         erasedAt,
+			"deleted_at":        gorm.Expr("COALESCE(deleted_at, ?)", erasedAt),
+			"version":           gorm.Expr("version + 1"),
+		}
+		if err := tx.Unscoped().Model(&model.User{}).Where("id = ?", req.UserID).Updates(updates).Error; err != nil {
+			log.Errorf("[PrivacyRepository-3] EraseUser: %v", err)
+			return err
+		}
+
+		if err := tx.Unscoped().Where("user_id = ?", req.UserID).Delete(&model.UserAddress{}).Error; err != nil {
+			log.Errorf("[PrivacyRepository-4] EraseUser: %v", err)
+			return err
+		}
+
+		if err := tx.Unscoped().Where("user_id = ?", req.UserID).Delete(&model.VerificationToken{}).Error; err != nil {
+			log.Errorf("[PrivacyRepository-5] EraseUser: %v", err)
+			return err
+		}
+
+		// Payload notifikasi berisi email/nomor telepon, ikut dihapus termasuk yang sudah terkirim
+		if err := tx.Where("user_id = ? AND kind = ?", req.UserID, entity.OutboxKindNotification).
+			Delete(&model.OutboxMessage{}).Error; err != nil {
+			log.Errorf("[PrivacyRepository-6] EraseUser: %v", err)
+			return err
+		}
+
+		// Riwayat notifikasi menyimpan alamat tujuan, ikut dihapus
+		if err := tx.Where("user_id = ?", req.UserID).Delete(&model.Notification{}).Error; err != nil {
+			log.Errorf("[PrivacyRepository-6] EraseUser: %v", err)
+			return err
+		}
+
+		if err := createEventOutbox(tx, events...); err != nil {
+			log.Errorf("[PrivacyRepository-7] EraseUser: %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func deletionRequestEntity(val model.UserDeletionRequest) entity.DeletionRequestEntity {
+	return entity.DeletionRequestEntity{
+		ID:          val.ID,
+		UserID:      val.UserID,
+		RequestedAt: val.RequestedAt,
+		ScheduledAt: val.ScheduledAt,
+		CancelledAt: val.CancelledAt,
+		CompletedAt: val.CompletedAt,
+	}
+}
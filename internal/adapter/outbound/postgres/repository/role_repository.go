@@ -6,6 +6,7 @@ import (
 	"clean-architecture/internal/port/outbound"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/labstack/gommon/log"
 	"gorm.io/gorm"
@@ -100,8 +101,9 @@ func (r *roleRepository) GetByID(ctx context.Context, id int64) (*entity.RoleEnt
 	}
 
 	return &entity.RoleEntity{
-		ID:   modelRole.ID,
-		Name: modelRole.Name,
+		ID:      modelRole.ID,
+		Name:    modelRole.Name,
+		Version: modelRole.Version,
 	}, nil
 }
 
@@ -125,16 +127,96 @@ func (r *roleRepository) Update(ctx context.Context, req entity.RoleEntity) erro
 		return err
 	}
 
+	// Version 0 berarti pemanggil tidak meminta pengecekan (If-Match: *)
+	if req.Version > 0 && req.Version != modelRole.Version {
+		log.Infof("[RoleRepository-4] Update: Version mismatch")
+		return errors.New("412")
+	}
+
 	if req.Name != "" {
 		updates["name"] = req.Name
 	}
 
 	if len(updates) > 0 {
-		if err := r.db.WithContext(ctx).Model(&modelRole).Updates(updates).Error; err != nil {
-			log.Errorf("[RoleRepository-3] Update: %v", err)
-			return err
+		// Filter version menolak update jika role diubah admin lain di antara SELECT & UPDATE
+		updates["version"] = gorm.Expr("version + 1")
+		result := r.db.WithContext(ctx).Model(&modelRole).Where("version = ?", modelRole.Version).Updates(updates)
+		if result.Error != nil {
+			log.Errorf("[RoleRepository-3] Update: %v", result.Error)
+			return result.Error
 		}
+		if result.RowsAffected == 0 {
+			log.Infof("[RoleRepository-4] Update: Version mismatch")
+			return errors.New("412")
+		}
+	}
+
+	return nil
+}
+
+func (r *roleRepository) GetDeletedAll(ctx context.Context, search string) ([]entity.RoleEntity, error) {
+	var (
+		modelRoles []model.Role
+		entityRole []entity.RoleEntity
+	)
+
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Where("name ILIKE ?", "%"+search+"%").
+		Find(&modelRoles).Error; err != nil {
+		log.Errorf("[RoleRepository-1] GetDeletedAll: %v", err)
+		return nil, err
+	}
+
+	if len(modelRoles) == 0 {
+		err := errors.New("404")
+		log.Infof("[RoleRepository-2] GetDeletedAll: No deleted role found")
+		return nil, err
+	}
+
+	for _, modelRole := range modelRoles {
+		deletedAt := modelRole.DeletedAt.Time
+		entityRole = append(entityRole, entity.RoleEntity{
+			ID:        modelRole.ID,
+			Name:      modelRole.Name,
+			DeletedAt: &deletedAt,
+		})
+	}
+
+	return entityRole, nil
+}
+
+func (r *roleRepository) Restore(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Unscoped().
+		Model(&model.Role{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		log.Errorf("[RoleRepository-1] Restore: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Infof("[RoleRepository-2] Restore: Deleted role not found")
+		return errors.New("404")
 	}
 
 	return nil
 }
+
+// PurgeDeleted hard delete role yang sudah di soft delete sebelum deletedBefore.
+// Role yang masih dipakai user aktif (misalnya user yang di restore setelah role dihapus) dilewati,
+// karena ON DELETE CASCADE akan ikut menghapus user_role mereka.
+func (r *roleRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Where("NOT EXISTS (SELECT 1 FROM user_role JOIN users ON users.id = user_role.user_id " +
+			"WHERE user_role.role_id = roles.id AND users.deleted_at IS NULL)").
+		Delete(&model.Role{})
+	if result.Error != nil {
+		log.Errorf("[RoleRepository-1] PurgeDeleted: %v", result.Error)
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
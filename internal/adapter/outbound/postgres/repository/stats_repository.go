@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"gorm.io/gorm"
+)
+
+// customerRoleExists user dengan role Customer yang masih aktif.
+const customerRoleExists = `EXISTS (SELECT 1 FROM user_role JOIN roles ON roles.id = user_role.role_id
+	WHERE user_role.user_id = users.id AND roles.deleted_at IS NULL AND roles.name = 'Customer')`
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) outbound.StatsRepositoryInterface {
+	return &statsRepository{db: db}
+}
+
+// GetDashboardStats seluruh agregasi dilakukan di database agar tidak perlu memuat data customer.
+// User yang sudah dihapus (soft delete, termasuk yang di-erase) tidak dihitung.
+func (s *statsRepository) GetDashboardStats(ctx context.Context, from, to time.Time) (*entity.DashboardStatsEntity, error) {
+	var (
+		counts struct {
+			TotalCustomers    int64
+			VerifiedCustomers int64
+		}
+		pendingVerifications int64
+		signups              []struct {
+			Date  time.Time
+			Count int64
+		}
+		roles []struct {
+			RoleID   int64
+			RoleName string
+			Count    int64
+		}
+	)
+
+	if err := s.db.WithContext(ctx).Model(&model.User{}).
+		Select("COUNT(*) AS total_customers, COUNT(*) FILTER (WHERE is_verified = true) AS verified_customers").
+		Where(customerRoleExists).
+		Scan(&counts).Error; err != nil {
+		log.Errorf("[StatsRepository-1] GetDashboardStats: %v", err)
+		return nil, err
+	}
+
+	// Verifikasi pending: customer belum terverifikasi yang token verifikasinya masih berlaku
+	if err := s.db.WithContext(ctx).Model(&model.User{}).
+		Where(customerRoleExists).
+		Where("is_verified = false").
+		Where(`EXISTS (SELECT 1 FROM verification_tokens vt WHERE vt.user_id = users.id
+			AND vt.token_type = 'email_verification' AND vt.expires_at > ? AND vt.deleted_at IS NULL)`, time.Now()).
+		Count(&pendingVerifications).Error; err != nil {
+		log.Errorf("[StatsRepository-2] GetDashboardStats: %v", err)
+		return nil, err
+	}
+
+	// generate_series agar hari tanpa pendaftaran tetap muncul dengan count 0
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT days.day::date AS date, COUNT(users.id) AS count
+		FROM generate_series(?::date, ?::date, interval '1 day') AS days(day)
+		LEFT JOIN users ON users.created_at >= days.day AND users.created_at < days.day + interval '1 day'
+			AND users.deleted_at IS NULL AND `+customerRoleExists+`
+		GROUP BY days.day
+		ORDER BY days.day ASC`, from.Format(time.DateOnly), to.Format(time.DateOnly)).
+		Scan(&signups).Error; err != nil {
+		log.Errorf("[StatsRepository-3] GetDashboardStats: %v", err)
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT roles.id AS role_id, roles.name AS role_name, COUNT(users.id) AS count
+		FROM roles
+		LEFT JOIN user_role ON user_role.role_id = roles.id
+		LEFT JOIN users ON users.id = user_role.user_id AND users.deleted_at IS NULL
+		WHERE roles.deleted_at IS NULL
+		GROUP BY roles.id, roles.name
+		ORDER BY roles.id ASC`).
+		Scan(&roles).Error; err != nil {
+		log.Errorf("[StatsRepository-4] GetDashboardStats: %v", err)
+		return nil, err
+	}
+
+	respEntity := entity.DashboardStatsEntity{
+		TotalCustomers:       counts.TotalCustomers,
+		VerifiedCustomers:    counts.VerifiedCustomers,
+		PendingVerifications: pendingVerifications,
+		SignupsPerDay:        []entity.DailyCountEntity{},
+		UsersPerRole:         []entity.RoleCountEntity{},
+		From:                 from,
+		To:                   to,
+	}
+
+	for _, val := range signups {
+		respEntity.SignupsPerDay = append(respEntity.SignupsPerDay, entity.DailyCountEntity{
+			Date:  val.Date,
+			Count: val.Count,
+		})
+	}
+
+	for _, val := range roles {
+		respEntity.UsersPerRole = append(respEntity.UsersPerRole, entity.RoleCountEntity{
+			RoleID:   val.RoleID,
+			RoleName: val.RoleName,
+			Count:    val.Count,
+		})
+	}
+
+	return &respEntity, nil
+}
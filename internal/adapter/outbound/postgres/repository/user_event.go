@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/internal/domain/entity"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// changedUserFields nama kolom yang nilainya benar-benar berubah untuk event user.updated.
+// Kolom internal seperti version dan password tidak ikut dilaporkan.
+func changedUserFields(updates map[string]interface{}, current model.User) []string {
+	currentValues := map[string]interface{}{
+		"name":              current.Name,
+		"email":             current.Email,
+		"phone":             current.Phone,
+		"address":           current.Address,
+		"photo":             current.Photo,
+		"lat":               current.Lat,
+		"lng":               current.Lng,
+		"phone_verified_at": current.PhoneVerifiedAt,
+	}
+
+	fields := []string{}
+	for column, value := range updates {
+		old, ok := currentValues[column]
+		if !ok || userFieldValue(old) == userFieldValue(value) {
+			continue
+		}
+		fields = append(fields, column)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// userFieldValue menyamakan bentuk nilai (pointer atau bukan) agar bisa dibandingkan.
+func userFieldValue(value interface{}) interface{} {
+	switch val := value.(type) {
+	case *float64:
+		if val == nil {
+			return nil
+		}
+		return *val
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return val.UnixNano()
+	case time.Time:
+		return val.UnixNano()
+	default:
+		return val
+	}
+}
+
+// createUserUpdateEvents menulis user.updated dan role.assigned (jika role berganti) setelah update berhasil.
+func createUserUpdateEvents(tx *gorm.DB, current model.User, updates map[string]interface{}, newRole *model.Role) error {
+	now := time.Now()
+	events := []entity.DomainEventEntity{}
+
+	if fields := changedUserFields(updates, current); len(fields) > 0 {
+		events = append(events, entity.NewUserUpdatedEvent(entity.UserUpdatedEventEntity{
+			UserID:        current.ID,
+			ChangedFields: fields,
+			UpdatedAt:     now,
+		}))
+	}
+
+	if newRole != nil && newRole.ID != 0 {
+		var previousRoleID int64
+		if len(current.Roles) > 0 {
+			previousRoleID = current.Roles[0].ID
+		}
+		if previousRoleID != newRole.ID {
+			events = append(events, entity.NewRoleAssignedEvent(entity.RoleAssignedEventEntity{
+				UserID:         current.ID,
+				RoleID:         newRole.ID,
+				RoleName:       newRole.Name,
+				PreviousRoleID: previousRoleID,
+				AssignedAt:     now,
+			}))
+		}
+	}
+
+	return createEventOutbox(tx, events...)
+}
+
+// createUserRegisteredEvents menulis user.registered dan role.assigned untuk user yang baru dibuat.
+func createUserRegisteredEvents(tx *gorm.DB, user model.User, role model.Role, source string) error {
+	return createEventOutbox(tx,
+		entity.NewUserRegisteredEvent(entity.UserRegisteredEventEntity{
+			UserID:       user.ID,
+			Name:         user.Name,
+			Email:        user.Email,
+			RoleName:     role.Name,
+			IsVerified:   user.IsVerified,
+			Source:       source,
+			RegisteredAt: user.CreatedAt,
+		}),
+		entity.NewRoleAssignedEvent(entity.RoleAssignedEventEntity{
+			UserID:     user.ID,
+			RoleID:     role.ID,
+			RoleName:   role.Name,
+			AssignedAt: user.CreatedAt,
+		}),
+	)
+}
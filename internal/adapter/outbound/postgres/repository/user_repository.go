@@ -4,12 +4,17 @@ import (
 	"clean-architecture/internal/adapter/outbound/postgres/model"
 	"clean-architecture/internal/domain/entity"
 	outboundport "clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/geo"
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/labstack/gommon/log"
 	"gorm.io/gorm"
 )
@@ -23,24 +28,154 @@ func NewUserRepository(db *gorm.DB) outboundport.UserRepositoryInterface {
 }
 
 func (u *userRepository) DeleteCustomer(ctx context.Context, customerID int64) error {
-	modelUser := model.User{}
-	if err := u.db.WithContext(ctx).Where("id =?", customerID).First(&modelUser).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err = errors.New("404")
-			log.Infof("[UserRepository-1] DeleteCustomer: User not found")
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelUser := model.User{}
+		if err := tx.Where("id =?", customerID).First(&modelUser).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				err = errors.New("404")
+				log.Infof("[UserRepository-1] DeleteCustomer: User not found")
+				return err
+			}
+			log.Errorf("[UserRepository-2] DeleteCustomer: %v", err)
 			return err
 		}
-		log.Errorf("[UserRepository-2] DeleteCustomer: %v", err)
-		return err
+
+		// Soft delete: hanya mengisi deleted_at, relasi user_role & verification_tokens tetap ada
+		if err := tx.Delete(&modelUser).Error; err != nil {
+			log.Errorf("[UserRepository-3] DeleteCustomer: %v", err)
+			return err
+		}
+
+		// Sesi customer dicabut service setelah transaksi, event session.revoked ikut dicatat di sini
+		now := time.Now()
+		events := []entity.DomainEventEntity{
+			entity.NewUserDeletedEvent(entity.UserDeletedEventEntity{
+				UserID:    modelUser.ID,
+				Reason:    entity.UserDeletedReasonSoftDeleted,
+				DeletedAt: now,
+			}),
+			entity.NewSessionRevokedEvent(entity.SessionRevokedEventEntity{
+				UserID:    modelUser.ID,
+				Reason:    entity.SessionRevokedReasonUserDeleted,
+				RevokedAt: now,
+			}),
+		}
+		if err := createEventOutbox(tx, events...); err != nil {
+			log.Errorf("[UserRepository-4] DeleteCustomer (outbox): %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (u *userRepository) GetDeletedCustomerAll(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error) {
+	var (
+		modelUsers   []model.User
+		respEntities []entity.UserEntity
+		countData    int64
+	)
+
+	order := customerOrder(query)
+	offset := (query.Page - 1) * query.Limit
+
+	filterScope, err := customerFilterScope(query.Filters)
+	if err != nil {
+		log.Infof("[UserRepository-1] GetDeletedCustomerAll: invalid filter")
+		return nil, 0, 0, err
 	}
 
-	if err := u.db.Delete(&modelUser).Error; err != nil {
-		log.Errorf("[UserRepository-3] DeleteCustomer: %v", err)
-		return err
+	// Unscoped agar default scope "deleted_at IS NULL" tidak dipakai. User yang sudah dihapus
+	// permanen (GDPR) tidak bisa di restore sehingga tidak ditampilkan
+	sqlMain := u.db.WithContext(ctx).Unscoped().Preload("Roles").
+		Where("users.deleted_at IS NOT NULL AND users.erased_at IS NULL").
+		Scopes(customerSearchScope(query.Search), filterScope)
+
+	if err := sqlMain.Model(&modelUsers).Count(&countData).Error; err != nil {
+		log.Errorf("[UserRepository-2] GetDeletedCustomerAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+
+	if err := sqlMain.Order(order).Limit(int(query.Limit)).Offset(int(offset)).Find(&modelUsers).Error; err != nil {
+		log.Errorf("[UserRepository-3] GetDeletedCustomerAll: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(modelUsers) < 1 {
+		err := errors.New("404")
+		log.Infof("[UserRepository-4] GetDeletedCustomerAll: No deleted customer found")
+		return nil, 0, 0, err
+	}
+
+	for _, val := range modelUsers {
+		roleName := ""
+		for _, role := range val.Roles {
+			roleName = role.Name
+			break
+		}
+
+		deletedAt := val.DeletedAt.Time
+		respEntities = append(respEntities, entity.UserEntity{
+			ID:        val.ID,
+			Name:      val.Name,
+			Email:     val.Email,
+			RoleName:  roleName,
+			Phone:     val.Phone,
+			Photo:     val.Photo,
+			DeletedAt: &deletedAt,
+		})
 	}
+
+	return respEntities, countData, int64(totalPage), nil
+}
+
+// RestoreCustomer "404" jika user tidak di soft delete, "409" jika datanya sudah dihapus permanen (GDPR).
+func (u *userRepository) RestoreCustomer(ctx context.Context, customerID int64) error {
+	result := u.db.WithContext(ctx).Unscoped().
+		Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL AND erased_at IS NULL", customerID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		log.Errorf("[UserRepository-1] RestoreCustomer: %v", result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var erased int64
+		if err := u.db.WithContext(ctx).Unscoped().Model(&model.User{}).
+			Where("id = ? AND erased_at IS NOT NULL", customerID).
+			Count(&erased).Error; err != nil {
+			log.Errorf("[UserRepository-2] RestoreCustomer: %v", err)
+			return err
+		}
+		if erased > 0 {
+			log.Infof("[UserRepository-3] RestoreCustomer: User data has been erased")
+			return errors.New("409")
+		}
+
+		log.Infof("[UserRepository-4] RestoreCustomer: Deleted user not found")
+		return errors.New("404")
+	}
+
 	return nil
 }
 
+// PurgeDeletedCustomers hard delete user yang sudah di soft delete sebelum deletedBefore.
+// Relasi user_role & verification_tokens ikut terhapus lewat ON DELETE CASCADE.
+func (u *userRepository) PurgeDeletedCustomers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result := u.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(&model.User{})
+	if result.Error != nil {
+		log.Errorf("[UserRepository-1] PurgeDeletedCustomers: %v", result.Error)
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
 func (u *userRepository) UpdateCustomer(ctx context.Context, req entity.UserEntity) error {
 	var (
 		modelRole = model.Role{}
@@ -57,8 +192,8 @@ func (u *userRepository) UpdateCustomer(ctx context.Context, req entity.UserEnti
 			return err
 		}
 
-		// 🔍 2. Cek user
-		if err := tx.Where("id = ?", req.ID).First(&modelUser).Error; err != nil {
+		// 🔍 2. Cek user, role lama dipakai untuk event role.assigned
+		if err := tx.Where("id = ?", req.ID).Preload("Roles").First(&modelUser).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				log.Infof("[UserRepository-2] UpdateCustomer: User not found")
 				return errors.New("404")
@@ -67,6 +202,16 @@ func (u *userRepository) UpdateCustomer(ctx context.Context, req entity.UserEnti
 			return err
 		}
 
+		// Version 0 berarti pemanggil tidak meminta pengecekan (If-Match: *)
+		if req.Version > 0 && req.Version != modelUser.Version {
+			log.Infof("[UserRepository-6] UpdateCustomer: Version mismatch")
+			return errors.New("412")
+		}
+
+		// Salinan data sebelum update untuk menghitung field yang berubah (event user.updated)
+		previous := modelUser
+		previous.Roles = slices.Clone(modelUser.Roles)
+
 		// 🧩 3. Siapkan field yang mau diupdate
 		if req.Name != "" {
 			updates["name"] = req.Name
@@ -76,15 +221,19 @@ func (u *userRepository) UpdateCustomer(ctx context.Context, req entity.UserEnti
 		}
 		if req.Phone != "" {
 			updates["phone"] = req.Phone
+			// Nomor berubah berarti harus diverifikasi ulang
+			if req.Phone != modelUser.Phone {
+				updates["phone_verified_at"] = nil
+			}
 		}
 		if req.Address != "" {
 			updates["address"] = req.Address
 		}
-		if req.Lat != "" {
-			updates["lat"] = req.Lat
+		if req.Lat != nil {
+			updates["lat"] = *req.Lat
 		}
-		if req.Lng != "" {
-			updates["lng"] = req.Lng
+		if req.Lng != nil {
+			updates["lng"] = *req.Lng
 		}
 		if req.Photo != "" {
 			updates["photo"] = req.Photo
@@ -93,12 +242,25 @@ func (u *userRepository) UpdateCustomer(ctx context.Context, req entity.UserEnti
 			updates["password"] = req.Password
 		}
 
-		// 🚀 4. Jalankan partial update (jika ada field)
-		if len(updates) > 0 {
-			if err := tx.Model(&modelUser).Where("id = ?", req.ID).Updates(updates).Error; err != nil {
-				log.Errorf("[UserRepository-4] UpdateCustomer: %v", err)
-				return err
-			}
+		// 🚀 4. Jalankan partial update, version selalu naik karena relasi role ikut diganti.
+		// Filter version memastikan tidak ada admin lain yang mengubah data sejak dibaca.
+		// Alamat disimpan ke alamat default, users.address/lat/lng hanya salinannya.
+		updates["version"] = gorm.Expr("version + 1")
+		userUpdates := maps.Clone(updates)
+		addressChanges := addressUpdates(userUpdates)
+		result := tx.Model(&modelUser).Where("version = ?", modelUser.Version).Updates(userUpdates)
+		if result.Error != nil {
+			log.Errorf("[UserRepository-4] UpdateCustomer: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Infof("[UserRepository-6] UpdateCustomer: Version mismatch")
+			return errors.New("412")
+		}
+
+		if err := saveDefaultAddress(tx, req.ID, addressChanges); err != nil {
+			log.Errorf("[UserRepository-4] UpdateCustomer (address): %v", err)
+			return err
 		}
 
 		// 🔗 5. Update relasi Role di pivo table user_role (many2many)
@@ -109,6 +271,27 @@ func (u *userRepository) UpdateCustomer(ctx context.Context, req entity.UserEnti
 			return err
 		}
 
+		if err := createNotificationOutbox(tx, req.ID, req.Notifications); err != nil {
+			log.Errorf("[UserRepository-7] UpdateCustomer (outbox): %v", err)
+			return err
+		}
+
+		if err := createUserUpdateEvents(tx, previous, updates, &modelRole); err != nil {
+			log.Errorf("[UserRepository-7] UpdateCustomer (outbox): %v", err)
+			return err
+		}
+		if req.Password != "" {
+			event := entity.NewPasswordChangedEvent(entity.PasswordChangedEventEntity{
+				UserID:    modelUser.ID,
+				Reason:    entity.PasswordChangedReasonAdmin,
+				ChangedAt: time.Now(),
+			})
+			if err := createEventOutbox(tx, event); err != nil {
+				log.Errorf("[UserRepository-7] UpdateCustomer (outbox): %v", err)
+				return err
+			}
+		}
+
 		// ✅ 6. Commit otomatis jika semua berhasil
 		log.Infof("[UserRepository] UpdateCustomer: User %d updated successfully", req.ID)
 		return nil
@@ -129,14 +312,11 @@ func (u *userRepository) CreateCustomer(ctx context.Context, req entity.UserEnti
 			return err
 		}
 
-		// Buat User baru
+		// Buat User baru, alamat disimpan sebagai alamat default lalu disalin ke users
 		modelUser = model.User{
 			Name:       req.Name,
 			Email:      req.Email,
 			Password:   req.Password,
-			Address:    req.Address,
-			Lat:        req.Lat,
-			Lng:        req.Lng,
 			Phone:      req.Phone,
 			Photo:      req.Photo,
 			Roles:      []model.Role{modelRole},
@@ -148,6 +328,31 @@ func (u *userRepository) CreateCustomer(ctx context.Context, req entity.UserEnti
 			return err
 		}
 
+		addressChanges := map[string]interface{}{}
+		if req.Address != "" {
+			addressChanges["address"] = req.Address
+		}
+		if req.Lat != nil {
+			addressChanges["lat"] = *req.Lat
+		}
+		if req.Lng != nil {
+			addressChanges["lng"] = *req.Lng
+		}
+		if err := saveDefaultAddress(tx, modelUser.ID, addressChanges); err != nil {
+			log.Errorf("[UserRepository-2] CreateCustomer (address): %v", err)
+			return err
+		}
+
+		if err := createNotificationOutbox(tx, modelUser.ID, req.Notifications); err != nil {
+			log.Errorf("[UserRepository-4] CreateCustomer (outbox): %v", err)
+			return err
+		}
+
+		if err := createUserRegisteredEvents(tx, modelUser, modelRole, entity.UserRegisteredSourceAdmin); err != nil {
+			log.Errorf("[UserRepository-4] CreateCustomer (outbox): %v", err)
+			return err
+		}
+
 		return nil
 	})
 
@@ -187,6 +392,9 @@ func (u *userRepository) GetCustomerByID(ctx context.Context, customerID int64)
 		Lng:     modelUser.Lng,
 		Phone:   modelUser.Phone,
 		Photo:   modelUser.Photo,
+		Version: modelUser.Version,
+
+		PhoneVerifiedAt: modelUser.PhoneVerifiedAt,
 	}, nil
 }
 
@@ -197,20 +405,36 @@ func (u *userRepository) GetCustomerAll(ctx context.Context, query entity.QueryS
 		countData    int64
 	)
 
-	order := fmt.Sprintf("%s %s", query.OrderBy, query.OrderType)
+	order := customerOrder(query)
 	offset := (query.Page - 1) * query.Limit
 
-	sqlMain := u.db.WithContext(ctx).Preload("Roles", "name = ?", "Customer").
-		Where("name ILIKE ? OR email ILIKE ? OR phone ILIKE ?", "%"+query.Search+"%", "%"+query.Search+"%", "%"+query.Search+"%")
+	filterScope, err := customerFilterScope(query.Filters)
+	if err != nil {
+		log.Infof("[UserRepository-1] GetCustomerAll: invalid filter")
+		return nil, 0, 0, err
+	}
 
-	if err := sqlMain.Model(&modelUsers).Count(&countData).Error; err != nil {
-		log.Errorf("[UserRepository-1] GetCustomerAll: %v", err)
+	// Session agar query count & find tidak saling mengubah statement
+	sqlMain := u.db.WithContext(ctx).Model(&model.User{}).
+		Scopes(customerSearchScope(query.Search), filterScope).
+		Session(&gorm.Session{})
+
+	if err := sqlMain.Count(&countData).Error; err != nil {
+		log.Errorf("[UserRepository-2] GetCustomerAll: %v", err)
 		return nil, 0, 0, err
 	}
 
 	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
 
-	if err := sqlMain.Order(order).Limit(int(query.Limit)).Offset(int(offset)).Find(&modelUsers).Error; err != nil {
+	sqlFind := sqlMain
+	if customerWantsRole(query.Fields) {
+		sqlFind = sqlFind.Preload("Roles", "name = ?", "Customer")
+	}
+	if columns := customerSelect(query.Fields); columns != nil {
+		sqlFind = sqlFind.Select(columns)
+	}
+
+	if err := sqlFind.Order(order).Limit(int(query.Limit)).Offset(int(offset)).Find(&modelUsers).Error; err != nil {
 		log.Errorf("[UserRepository-3] GetCustomerAll: %v", err)
 		return nil, 0, 0, err
 	}
@@ -222,80 +446,636 @@ func (u *userRepository) GetCustomerAll(ctx context.Context, query entity.QueryS
 	}
 
 	for _, val := range modelUsers {
-		roleName := ""
-		for _, role := range val.Roles {
-			roleName = role.Name
-			break // hanya butuh 1 role name
+		respEntities = append(respEntities, customerListEntity(val))
+	}
+
+	return respEntities, countData, int64(totalPage), nil
+}
+
+// customerListEntity mapping model ke entity untuk endpoint list customer.
+func customerListEntity(val model.User) entity.UserEntity {
+	roleName := ""
+	for _, role := range val.Roles {
+		roleName = role.Name
+		break // hanya butuh 1 role name
+	}
+
+	return entity.UserEntity{
+		ID:         val.ID,
+		Name:       val.Name,
+		Email:      val.Email,
+		RoleName:   roleName,
+		Address:    val.Address,
+		Lat:        val.Lat,
+		Lng:        val.Lng,
+		Phone:      val.Phone,
+		Photo:      val.Photo,
+		IsVerified: val.IsVerified,
+		CreatedAt:  val.CreatedAt,
+	}
+}
+
+// GetCustomerAllByCursor keyset pagination: tanpa COUNT & OFFSET, halaman diambil
+// berdasarkan (kolom order, id) dari cursor sebelumnya. "400" jika kolom order tidak
+// didukung keyset pagination atau cursor tidak valid.
+func (u *userRepository) GetCustomerAllByCursor(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, *entity.CursorPageEntity, error) {
+	var (
+		modelUsers   []model.User
+		respEntities []entity.UserEntity
+		page         = &entity.CursorPageEntity{}
+	)
+
+	orderBy := strings.ToLower(query.OrderBy)
+	column, ok := customerCursorColumns[orderBy]
+	if !ok {
+		log.Infof("[UserRepository-1] GetCustomerAllByCursor: unsupported order %q", query.OrderBy)
+		return nil, nil, errors.New("400")
+	}
+	desc := strings.ToLower(query.OrderType) != "asc"
+	backward := query.Cursor != nil && query.Cursor.Backward
+
+	// Arah scan dibalik saat mengambil halaman sebelumnya, hasilnya dibalik lagi di bawah
+	scanDesc := desc != backward
+	direction, comparator := "ASC", ">"
+	if scanDesc {
+		direction, comparator = "DESC", "<"
+	}
+
+	filterScope, err := customerFilterScope(query.Filters)
+	if err != nil {
+		log.Infof("[UserRepository-1] GetCustomerAllByCursor: invalid filter")
+		return nil, nil, err
+	}
+
+	sqlMain := u.db.WithContext(ctx).
+		Scopes(customerSearchScope(query.Search), filterScope)
+	if customerWantsRole(query.Fields) {
+		sqlMain = sqlMain.Preload("Roles", "name = ?", "Customer")
+	}
+
+	// Kolom order wajib ikut di-select karena dipakai untuk membentuk cursor
+	if columns := customerSelect(query.Fields); columns != nil {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
 		}
-		respEntities = append(respEntities, entity.UserEntity{
-			ID:       val.ID,
-			Name:     val.Name,
-			Email:    val.Email,
-			RoleName: roleName,
-			Phone:    val.Phone,
-			Photo:    val.Photo,
+		sqlMain = sqlMain.Select(columns)
+	}
+
+	if query.Cursor != nil {
+		value, err := parseCustomerCursorValue(orderBy, query.Cursor.Value)
+		if err != nil {
+			log.Infof("[UserRepository-1] GetCustomerAllByCursor: invalid cursor value: %v", err)
+			return nil, nil, errors.New("400")
+		}
+		sqlMain = sqlMain.Where(fmt.Sprintf("(%s, users.id) %s (?, ?)", column, comparator), value, query.Cursor.ID)
+	}
+
+	order := fmt.Sprintf("%s %s, users.id %s", column, direction, direction)
+	if err := sqlMain.Order(order).Limit(int(query.Limit) + 1).Find(&modelUsers).Error; err != nil {
+		log.Errorf("[UserRepository-2] GetCustomerAllByCursor: %v", err)
+		return nil, nil, err
+	}
+
+	hasMore := len(modelUsers) > int(query.Limit)
+	if hasMore {
+		modelUsers = modelUsers[:query.Limit]
+	}
+
+	if len(modelUsers) < 1 {
+		err := errors.New("404")
+		log.Infof("[UserRepository-3] GetCustomerAllByCursor: No Customer found")
+		return nil, nil, err
+	}
+
+	if backward {
+		for i, j := 0, len(modelUsers)-1; i < j; i, j = i+1, j-1 {
+			modelUsers[i], modelUsers[j] = modelUsers[j], modelUsers[i]
+		}
+	}
+
+	orderType := "desc"
+	if !desc {
+		orderType = "asc"
+	}
+
+	first, last := modelUsers[0], modelUsers[len(modelUsers)-1]
+	if hasMore || backward {
+		page.Next = &entity.CursorEntity{OrderBy: orderBy, OrderType: orderType, Value: customerCursorValue(orderBy, last), ID: last.ID}
+	}
+	if (!backward && query.Cursor != nil) || (backward && hasMore) {
+		page.Prev = &entity.CursorEntity{OrderBy: orderBy, OrderType: orderType, Value: customerCursorValue(orderBy, first), ID: first.ID, Backward: true}
+	}
+
+	for _, val := range modelUsers {
+		respEntities = append(respEntities, customerListEntity(val))
+	}
+
+	return respEntities, page, nil
+}
+
+// customerSearchRow hasil scan baris pencarian, Score skor relevansi (ts_rank + similarity)
+// dan *Highlight hasil ts_headline dengan term yang cocok ditandai.
+type customerSearchRow struct {
+	ID             int64
+	Name           string
+	Email          string
+	Phone          string
+	Photo          string
+	IsVerified     bool
+	CreatedAt      time.Time
+	Score          float64
+	NameHighlight  string
+	EmailHighlight string
+	PhoneHighlight string
+}
+
+// SearchCustomers pencarian full-text (prefix) + trigram (toleran typo) memakai index
+// search_vector & gin_trgm_ops. Hasil diurutkan berdasarkan skor relevansi.
+func (u *userRepository) SearchCustomers(ctx context.Context, query entity.QueryStringEntity) ([]entity.CustomerSearchEntity, int64, int64, error) {
+	var (
+		rows         []customerSearchRow
+		respEntities []entity.CustomerSearchEntity
+		countData    int64
+	)
+
+	tsQuery, err := customerTsQuery(query.Search)
+	if err != nil {
+		log.Infof("[UserRepository-1] SearchCustomers: invalid search term")
+		return nil, 0, 0, err
+	}
+
+	filterScope, err := customerFilterScope(query.Filters)
+	if err != nil {
+		log.Infof("[UserRepository-2] SearchCustomers: invalid filter")
+		return nil, 0, 0, err
+	}
+
+	args := map[string]any{
+		"tsquery": tsQuery,
+		"term":    query.Search,
+		"options": fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", searchHighlightStart, searchHighlightStop),
+	}
+
+	sqlMain := u.db.WithContext(ctx).Model(&model.User{}).
+		Where(`users.search_vector @@ to_tsquery('simple', @tsquery)
+			OR users.name % @term OR users.email % @term OR users.phone % @term`, args).
+		Scopes(filterScope).
+		Session(&gorm.Session{})
+
+	if err := sqlMain.Count(&countData).Error; err != nil {
+		log.Errorf("[UserRepository-3] SearchCustomers: %v", err)
+		return nil, 0, 0, err
+	}
+
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+	offset := (query.Page - 1) * query.Limit
+
+	if err := sqlMain.
+		Select(`users.id, users.name, users.email, users.phone, users.photo, users.is_verified, users.created_at,
+			ts_rank(users.search_vector, to_tsquery('simple', @tsquery))
+				+ GREATEST(similarity(users.name, @term), similarity(users.email, @term), similarity(coalesce(users.phone, ''), @term)) AS score,
+			ts_headline('simple', users.name, to_tsquery('simple', @tsquery), @options) AS name_highlight,
+			ts_headline('simple', users.email, to_tsquery('simple', @tsquery), @options) AS email_highlight,
+			ts_headline('simple', coalesce(users.phone, ''), to_tsquery('simple', @tsquery), @options) AS phone_highlight`, args).
+		Order("score DESC, users.id DESC").
+		Limit(int(query.Limit)).
+		Offset(int(offset)).
+		Scan(&rows).Error; err != nil {
+		log.Errorf("[UserRepository-4] SearchCustomers: %v", err)
+		return nil, 0, 0, err
+	}
+
+	if len(rows) < 1 {
+		err := errors.New("404")
+		log.Infof("[UserRepository-5] SearchCustomers: No Customer found")
+		return nil, 0, 0, err
+	}
+
+	for _, row := range rows {
+		respEntities = append(respEntities, entity.CustomerSearchEntity{
+			User: entity.UserEntity{
+				ID:         row.ID,
+				Name:       row.Name,
+				Email:      row.Email,
+				Phone:      row.Phone,
+				Photo:      row.Photo,
+				IsVerified: row.IsVerified,
+				CreatedAt:  row.CreatedAt,
+			},
+			Score: row.Score,
+			Highlights: customerSearchHighlights(map[string]string{
+				"name":  row.NameHighlight,
+				"email": row.EmailHighlight,
+				"phone": row.PhoneHighlight,
+			}),
 		})
 	}
 
 	return respEntities, countData, int64(totalPage), nil
 }
 
-func (u *userRepository) UpdateDataUser(ctx context.Context, req entity.UserEntity) error {
+type customerNearbyRow struct {
+	ID         int64
+	Name       string
+	Email      string
+	Phone      string
+	Photo      string
+	Address    string
+	Lat        *float64
+	Lng        *float64
+	DistanceKm float64
+}
+
+// GetCustomerNearby mencari customer dalam radius / box dan mengurutkan berdasarkan jarak
+// haversine. Box dipakai lebih dulu sebagai pre-filter agar index (lat, lng) terpakai.
+func (u *userRepository) GetCustomerNearby(ctx context.Context, query entity.NearbyQueryEntity) ([]entity.NearbyCustomerEntity, int64, int64, error) {
 	var (
-		modelUser model.User
-		updates   = map[string]interface{}{}
+		rows         []customerNearbyRow
+		respEntities []entity.NearbyCustomerEntity
+		countData    int64
 	)
 
-	// 🔍 Cek apakah user ditemukan dan sudah terverifikasi
-	if err := u.db.WithContext(ctx).
-		Where("id = ? AND is_verified = true", req.ID).
-		First(&modelUser).Error; err != nil {
+	filterScope, err := customerFilterScope(query.Filters)
+	if err != nil {
+		log.Infof("[UserRepository-1] GetCustomerNearby: invalid filter")
+		return nil, 0, 0, err
+	}
 
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Infof("[UserRepository-1] UpdateDataUser: User not found or not verified")
-			return errors.New("404")
-		}
+	args := map[string]any{
+		"earth_radius": geo.EarthRadiusKm,
+		"lat":          query.Lat,
+		"lng":          query.Lng,
+		"radius":       query.RadiusKm,
+	}
 
-		log.Errorf("[UserRepository-2] UpdateDataUser: %v", err)
-		return err
+	sqlMain := u.db.WithContext(ctx).Model(&model.User{}).Scopes(filterScope)
+	if query.Box != nil {
+		sqlMain = sqlMain.Scopes(customerBoxScope(geo.Box(*query.Box)))
+	} else {
+		sqlMain = sqlMain.
+			Scopes(customerBoxScope(geo.BoundingBox(query.Lat, query.Lng, query.RadiusKm))).
+			Where(customerDistanceSQL+" <= @radius", args)
+	}
+	sqlMain = sqlMain.Session(&gorm.Session{})
+
+	if err := sqlMain.Count(&countData).Error; err != nil {
+		log.Errorf("[UserRepository-2] GetCustomerNearby: %v", err)
+		return nil, 0, 0, err
 	}
 
-	if req.Name != "" {
-		updates["name"] = req.Name
+	totalPage := int(math.Ceil(float64(countData) / float64(query.Limit)))
+	offset := (query.Page - 1) * query.Limit
+
+	if err := sqlMain.
+		Select(`users.id, users.name, users.email, users.phone, users.photo, users.address, users.lat, users.lng, `+
+			customerDistanceSQL+` AS distance_km`, args).
+		Order("distance_km ASC, users.id ASC").
+		Limit(int(query.Limit)).
+		Offset(int(offset)).
+		Scan(&rows).Error; err != nil {
+		log.Errorf("[UserRepository-3] GetCustomerNearby: %v", err)
+		return nil, 0, 0, err
 	}
-	if req.Email != "" {
-		updates["email"] = req.Email
+
+	if len(rows) < 1 {
+		err := errors.New("404")
+		log.Infof("[UserRepository-4] GetCustomerNearby: No Customer found")
+		return nil, 0, 0, err
 	}
-	if req.Address != "" {
-		updates["address"] = req.Address
+
+	for _, row := range rows {
+		respEntities = append(respEntities, entity.NearbyCustomerEntity{
+			User: entity.UserEntity{
+				ID:      row.ID,
+				Name:    row.Name,
+				Email:   row.Email,
+				Phone:   row.Phone,
+				Photo:   row.Photo,
+				Address: row.Address,
+				Lat:     row.Lat,
+				Lng:     row.Lng,
+			},
+			DistanceKm: row.DistanceKm,
+		})
 	}
-	if req.Phone != "" {
-		updates["phone"] = req.Phone
+
+	return respEntities, countData, int64(totalPage), nil
+}
+
+// customerExportRow hasil scan baris export, role_name diambil lewat subquery
+// supaya bisa dibaca per baris tanpa Preload.
+type customerExportRow struct {
+	ID         int64
+	Name       string
+	Email      string
+	Phone      string
+	Photo      string
+	Address    string
+	Lat        *float64
+	Lng        *float64
+	IsVerified bool
+	CreatedAt  time.Time
+	RoleName   string
+}
+
+// ExportCustomers membaca customer baris per baris memakai cursor database (Rows),
+// sehingga data tidak dimuat sekaligus ke memory. handle dipanggil untuk setiap baris.
+func (u *userRepository) ExportCustomers(ctx context.Context, query entity.QueryStringEntity, handle func(entity.UserEntity) error) error {
+	filterScope, err := customerFilterScope(query.Filters)
+	if err != nil {
+		log.Infof("[UserRepository-1] ExportCustomers: invalid filter")
+		return err
 	}
-	if req.Photo != "" {
-		updates["photo"] = req.Photo
+
+	rows, err := u.db.WithContext(ctx).
+		Model(&model.User{}).
+		Select(`users.id, users.name, users.email, users.phone, users.photo, users.address,
+			users.lat, users.lng, users.is_verified, users.created_at,
+			(SELECT roles.name FROM user_role JOIN roles ON roles.id = user_role.role_id
+				WHERE user_role.user_id = users.id AND roles.deleted_at IS NULL LIMIT 1) AS role_name`).
+		Scopes(customerSearchScope(query.Search), filterScope).
+		Order(customerOrder(query)).
+		Rows()
+	if err != nil {
+		log.Errorf("[UserRepository-2] ExportCustomers: %v", err)
+		return err
 	}
-	if req.Lat != "" {
-		updates["lat"] = req.Lat
+	defer func() {
+		if cerr := rows.Close(); cerr != nil {
+			log.Errorf("[UserRepository-defer] ExportCustomers: failed to close rows: %v", cerr)
+		}
+	}()
+
+	for rows.Next() {
+		row := customerExportRow{}
+		if err := u.db.ScanRows(rows, &row); err != nil {
+			log.Errorf("[UserRepository-3] ExportCustomers: %v", err)
+			return err
+		}
+
+		if err := handle(entity.UserEntity{
+			ID:         row.ID,
+			Name:       row.Name,
+			Email:      row.Email,
+			RoleName:   row.RoleName,
+			Address:    row.Address,
+			Lat:        row.Lat,
+			Lng:        row.Lng,
+			Phone:      row.Phone,
+			Photo:      row.Photo,
+			IsVerified: row.IsVerified,
+			CreatedAt:  row.CreatedAt,
+		}); err != nil {
+			return err
+		}
 	}
-	if req.Lng != "" {
-		updates["lng"] = req.Lng
+
+	if err := rows.Err(); err != nil {
+		log.Errorf("[UserRepository-4] ExportCustomers: %v", err)
+		return err
 	}
 
-	// 🚀 Jalankan update hanya kalau ada field yang berubah
-	if len(updates) > 0 {
-		if err := u.db.WithContext(ctx).
+	return nil
+}
+
+func (u *userRepository) UpdateDataUser(ctx context.Context, req entity.UserEntity) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var (
+			modelUser model.User
+			updates   = map[string]interface{}{}
+		)
+
+		// 🔍 Cek apakah user ditemukan dan sudah terverifikasi
+		if err := tx.
+			Where("id = ? AND is_verified = true", req.ID).
+			First(&modelUser).Error; err != nil {
+
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[UserRepository-1] UpdateDataUser: User not found or not verified")
+				return errors.New("404")
+			}
+
+			log.Errorf("[UserRepository-2] UpdateDataUser: %v", err)
+			return err
+		}
+
+		// Version 0 berarti tanpa pengecekan (update profil sendiri atau If-Match: *)
+		if req.Version > 0 && req.Version != modelUser.Version {
+			log.Infof("[UserRepository-4] UpdateDataUser: Version mismatch")
+			return errors.New("412")
+		}
+
+		if req.Name != "" {
+			updates["name"] = req.Name
+		}
+		if req.Email != "" {
+			updates["email"] = req.Email
+		}
+		if req.Address != "" {
+			updates["address"] = req.Address
+		}
+		if req.Phone != "" {
+			updates["phone"] = req.Phone
+			// Nomor berubah berarti harus diverifikasi ulang
+			if req.Phone != modelUser.Phone {
+				updates["phone_verified_at"] = nil
+			}
+		}
+		if req.Photo != "" {
+			updates["photo"] = req.Photo
+		}
+		if req.Lat != nil {
+			updates["lat"] = *req.Lat
+		}
+		if req.Lng != nil {
+			updates["lng"] = *req.Lng
+		}
+
+		// 🚀 Jalankan update hanya kalau ada field yang berubah
+		if len(updates) == 0 {
+			log.Infof("[UserRepository] UpdateDataUser: No fields to update for user %d", req.ID)
+			return nil
+		}
+
+		previous := modelUser
+		updates["version"] = gorm.Expr("version + 1")
+		// Alamat disimpan ke alamat default, users.address/lat/lng hanya salinannya
+		userUpdates := maps.Clone(updates)
+		addressChanges := addressUpdates(userUpdates)
+		result := tx.
 			Model(&modelUser).
-			Updates(updates).Error; err != nil {
-			log.Errorf("[UserRepository-3] UpdateDataUser: %v", err)
+			Where("version = ?", modelUser.Version).
+			Updates(userUpdates)
+		if result.Error != nil {
+			log.Errorf("[UserRepository-3] UpdateDataUser: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Infof("[UserRepository-4] UpdateDataUser: Version mismatch")
+			return errors.New("412")
+		}
+
+		if err := saveDefaultAddress(tx, req.ID, addressChanges); err != nil {
+			log.Errorf("[UserRepository-3] UpdateDataUser (address): %v", err)
+			return err
+		}
+
+		if err := createUserUpdateEvents(tx, previous, updates, nil); err != nil {
+			log.Errorf("[UserRepository-5] UpdateDataUser (outbox): %v", err)
 			return err
 		}
+
 		log.Infof("[UserRepository] UpdateDataUser: User %d updated successfully", req.ID)
-	} else {
-		log.Infof("[UserRepository] UpdateDataUser: No fields to update for user %d", req.ID)
+		return nil
+	})
+}
+
+// PatchUser menerapkan JSON Merge Patch: hanya field yang ada di patch yang diubah dan field
+// bernilai null mengosongkan kolomnya. Error: "404" user tidak ada, "412" version tidak cocok,
+// "422" role tidak ditemukan.
+func (u *userRepository) PatchUser(ctx context.Context, patch entity.UserPatchEntity) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		modelUser := model.User{}
+		if err := tx.Where("id = ?", patch.ID).Preload("Roles").First(&modelUser).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[UserRepository-1] PatchUser: User not found")
+				return errors.New("404")
+			}
+			log.Errorf("[UserRepository-2] PatchUser: %v", err)
+			return err
+		}
+
+		// Version 0 berarti pemanggil tidak meminta pengecekan
+		if patch.Version > 0 && patch.Version != modelUser.Version {
+			log.Infof("[UserRepository-3] PatchUser: Version mismatch")
+			return errors.New("412")
+		}
+
+		// Email milik user lain (termasuk yang di soft delete, unique index tetap berlaku) "409"
+		if patch.Email.Set && patch.Email.Value != nil && *patch.Email.Value != "" && *patch.Email.Value != modelUser.Email {
+			var countUsed int64
+			if err := tx.Unscoped().Model(&model.User{}).
+				Where("email = ? AND id <> ?", *patch.Email.Value, patch.ID).
+				Count(&countUsed).Error; err != nil {
+				log.Errorf("[UserRepository-4] PatchUser: %v", err)
+				return err
+			}
+			if countUsed > 0 {
+				log.Infof("[UserRepository-5] PatchUser: Email already used by another user")
+				return errors.New("409")
+			}
+		}
+
+		modelRole := model.Role{}
+		if patch.RoleID.Set && patch.RoleID.Value != nil {
+			if err := tx.Where("id = ?", *patch.RoleID.Value).First(&modelRole).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Infof("[UserRepository-6] PatchUser: Role not found")
+					return errors.New("422")
+				}
+				log.Errorf("[UserRepository-7] PatchUser: %v", err)
+				return err
+			}
+		}
+
+		previous := modelUser
+		previous.Roles = slices.Clone(modelUser.Roles)
+
+		updates := userPatchUpdates(patch, modelUser)
+		if len(updates) == 0 && modelRole.ID == 0 {
+			log.Infof("[UserRepository] PatchUser: No fields to update for user %d", patch.ID)
+			return nil
+		}
+
+		// Alamat disimpan ke alamat default, users.address/lat/lng hanya salinannya
+		updates["version"] = gorm.Expr("version + 1")
+		userUpdates := maps.Clone(updates)
+		addressChanges := addressUpdates(userUpdates)
+		result := tx.Model(&modelUser).Where("version = ?", modelUser.Version).Updates(userUpdates)
+		if result.Error != nil {
+			// Email yang baru saja dipakai request lain lolos pengecekan di atas, unique index yang menolak
+			if isUniqueViolation(result.Error) {
+				log.Infof("[UserRepository-8] PatchUser: Email already used by another user")
+				return errors.New("409")
+			}
+			log.Errorf("[UserRepository-9] PatchUser: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Infof("[UserRepository-10] PatchUser: Version mismatch")
+			return errors.New("412")
+		}
+
+		if err := saveDefaultAddress(tx, patch.ID, addressChanges); err != nil {
+			log.Errorf("[UserRepository-11] PatchUser (address): %v", err)
+			return err
+		}
+
+		if modelRole.ID != 0 {
+			if err := tx.Model(&modelUser).Association("Roles").Replace(&modelRole); err != nil {
+				log.Errorf("[UserRepository-12] PatchUser (role): %v", err)
+				return err
+			}
+		}
+
+		if err := createUserUpdateEvents(tx, previous, updates, &modelRole); err != nil {
+			log.Errorf("[UserRepository-13] PatchUser (outbox): %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// isUniqueViolation true jika error dari Postgres adalah pelanggaran unique constraint (23505).
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// userPatchUpdates membangun map update dari field yang ada di patch, bukan dari nilai kosong.
+// Kolom teks yang di-null-kan diisi string kosong, lat & lng di-null-kan menjadi NULL.
+func userPatchUpdates(patch entity.UserPatchEntity, current model.User) map[string]interface{} {
+	updates := map[string]interface{}{}
+
+	textFields := []struct {
+		column string
+		field  entity.PatchField[string]
+	}{
+		{"name", patch.Name},
+		{"email", patch.Email},
+		{"phone", patch.Phone},
+		{"address", patch.Address},
+		{"photo", patch.Photo},
+	}
+	for _, val := range textFields {
+		if !val.field.Set {
+			continue
+		}
+		value := ""
+		if val.field.Value != nil {
+			value = *val.field.Value
+		}
+		// name & email NOT NULL, patch null untuk kolom ini diabaikan
+		if value == "" && (val.column == "name" || val.column == "email") {
+			continue
+		}
+		updates[val.column] = value
 	}
 
-	return nil
+	// Nomor berubah (termasuk dihapus) berarti harus diverifikasi ulang
+	if phone, ok := updates["phone"]; ok && phone != current.Phone {
+		updates["phone_verified_at"] = nil
+	}
+
+	if patch.Lat.Set {
+		updates["lat"] = patch.Lat.Value
+	}
+	if patch.Lng.Set {
+		updates["lng"] = patch.Lng.Value
+	}
+
+	return updates
 }
 
 func (u *userRepository) GetUserByID(ctx context.Context, userID int64) (*entity.UserEntity, error) {
@@ -326,32 +1106,57 @@ func (u *userRepository) GetUserByID(ctx context.Context, userID int64) (*entity
 		Address:  modelUser.Address,
 		Phone:    modelUser.Phone,
 		Photo:    modelUser.Photo,
+		Version:  modelUser.Version,
+
+		PhoneVerifiedAt: modelUser.PhoneVerifiedAt,
 	}, nil
 }
 
+// UpdatePasswordByID dipakai reset password. Event password.changed dan session.revoked ikut
+// ditulis karena seluruh sesi user dicabut oleh service setelah password diganti.
 func (u *userRepository) UpdatePasswordByID(ctx context.Context, req entity.UserEntity) error {
-	var modelUser model.User
-	if err := u.db.WithContext(ctx).Where("id = ?", req.ID).First(&modelUser).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			log.Infof("[UserRepository-1] UpdatePasswordByID: User not found")
-			return errors.New("404")
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var modelUser model.User
+		if err := tx.Where("id = ?", req.ID).First(&modelUser).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Infof("[UserRepository-1] UpdatePasswordByID: User not found")
+				return errors.New("404")
+			}
+			log.Errorf("[UserRepository-2] UpdatePasswordByID: %v", err)
+			return err
 		}
-		log.Errorf("[UserRepository-2] UpdatePasswordByID: %v", err)
-		return err
-	}
 
-	// 🚀 Update hanya kolom password
-	if err := u.db.WithContext(ctx).
-		Model(&modelUser).
-		Where("id = ?", req.ID).
-		Updates(map[string]interface{}{
-			"password": req.Password,
-		}).Error; err != nil {
-		log.Errorf("[UserRepository-3] UpdatePasswordByID: %v", err)
-		return err
-	}
+		// 🚀 Update hanya kolom password
+		if err := tx.
+			Model(&modelUser).
+			Where("id = ?", req.ID).
+			Updates(map[string]interface{}{
+				"password": req.Password,
+			}).Error; err != nil {
+			log.Errorf("[UserRepository-3] UpdatePasswordByID: %v", err)
+			return err
+		}
 
-	return nil
+		now := time.Now()
+		events := []entity.DomainEventEntity{
+			entity.NewPasswordChangedEvent(entity.PasswordChangedEventEntity{
+				UserID:    modelUser.ID,
+				Reason:    entity.PasswordChangedReasonReset,
+				ChangedAt: now,
+			}),
+			entity.NewSessionRevokedEvent(entity.SessionRevokedEventEntity{
+				UserID:    modelUser.ID,
+				Reason:    entity.SessionRevokedReasonPasswordChanged,
+				RevokedAt: now,
+			}),
+		}
+		if err := createEventOutbox(tx, events...); err != nil {
+			log.Errorf("[UserRepository-4] UpdatePasswordByID (outbox): %v", err)
+			return err
+		}
+
+		return nil
+	})
 }
 
 func (u *userRepository) UpdateUserVerified(ctx context.Context, userID int64) (*entity.UserEntity, error) {
@@ -372,12 +1177,22 @@ func (u *userRepository) UpdateUserVerified(ctx context.Context, userID int64) (
 		updateData["is_verified"] = true
 	}
 
-	// ⚙️ Jalankan update hanya jika ada kolom diupdate
+	// ⚙️ Jalankan update hanya jika ada kolom diupdate, user.verified hanya sekali saat pertama diverifikasi
 	if len(updateData) > 0 {
-		if err := u.db.WithContext(ctx).
-			Model(&model.User{}).
-			Where("id = ?", userID).
-			Updates(updateData).Error; err != nil {
+		err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.
+				Model(&model.User{}).
+				Where("id = ?", userID).
+				Updates(updateData).Error; err != nil {
+				return err
+			}
+
+			return createEventOutbox(tx, entity.NewUserVerifiedEvent(entity.UserVerifiedEventEntity{
+				UserID:     userID,
+				VerifiedAt: time.Now(),
+			}))
+		})
+		if err != nil {
 			log.Errorf("[UserRepository-3] UpdateUserVerified: %v", err)
 			return nil, err
 		}
@@ -451,6 +1266,30 @@ func (u *userRepository) CreateUserAccount(ctx context.Context, req entity.UserE
 			return err
 		}
 
+		// 4 Catat persetujuan syarat & ketentuan di transaksi yang sama
+		if len(req.Consents) > 0 {
+			consents := make([]entity.ConsentEntity, 0, len(req.Consents))
+			for _, val := range req.Consents {
+				val.UserID = modelUser.ID
+				consents = append(consents, val)
+			}
+			if err := tx.Create(userConsentModels(consents)).Error; err != nil {
+				log.Errorf("[UserRepository-3b] CreateUserAccount: failed to create consents: %v", err)
+				return err
+			}
+		}
+
+		// 5 Notifikasi verifikasi & event user.registered hanya terkirim jika user benar-benar tersimpan
+		if err := createNotificationOutbox(tx, modelUser.ID, req.Notifications); err != nil {
+			log.Errorf("[UserRepository-3c] CreateUserAccount: failed to create outbox message: %v", err)
+			return err
+		}
+
+		if err := createUserRegisteredEvents(tx, modelUser, model.Role{ID: roleID, Name: "Customer"}, entity.UserRegisteredSourceSignUp); err != nil {
+			log.Errorf("[UserRepository-3c] CreateUserAccount: failed to create outbox message: %v", err)
+			return err
+		}
+
 		// ✅ Semua sukses
 		log.Infof("[UserRepository-4] CreateUserAccount: user '%s' created successfully (ID=%d, RoleID=%d)", modelUser.Email, modelUser.ID, roleID)
 		return nil
@@ -495,5 +1334,89 @@ func (u *userRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 		Phone:      modelUser.Phone,
 		Photo:      modelUser.Photo,
 		IsVerified: modelUser.IsVerified,
+
+		PhoneVerifiedAt: modelUser.PhoneVerifiedAt,
+	}, nil
+}
+
+// GetUserByVerifiedPhone mencari user terverifikasi berdasarkan nomor E.164 yang sudah diverifikasi.
+func (u *userRepository) GetUserByVerifiedPhone(ctx context.Context, phone string) (*entity.UserEntity, error) {
+	modelUser := model.User{}
+
+	if err := u.db.WithContext(ctx).
+		Where("phone = ? AND phone_verified_at IS NOT NULL AND is_verified = ?", phone, true).
+		Preload("Roles").First(&modelUser).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errors.New("404")
+			log.Infof("[UserRepository-1] GetUserByVerifiedPhone: User not found")
+			return nil, err
+		}
+		log.Errorf("[UserRepository-2] GetUserByVerifiedPhone: %v", err)
+		return nil, err
+	}
+
+	var roleName string
+	if len(modelUser.Roles) > 0 {
+		roleName = modelUser.Roles[0].Name
+	}
+
+	return &entity.UserEntity{
+		ID:         modelUser.ID,
+		Name:       modelUser.Name,
+		Email:      modelUser.Email,
+		Password:   modelUser.Password,
+		RoleName:   roleName,
+		Address:    modelUser.Address,
+		Lat:        modelUser.Lat,
+		Lng:        modelUser.Lng,
+		Phone:      modelUser.Phone,
+		Photo:      modelUser.Photo,
+		IsVerified: modelUser.IsVerified,
+
+		PhoneVerifiedAt: modelUser.PhoneVerifiedAt,
 	}, nil
 }
+
+// UpdatePhoneVerified menyimpan nomor yang lolos verifikasi OTP. Nomor yang sudah
+// diverifikasi user lain menghasilkan error "409".
+func (u *userRepository) UpdatePhoneVerified(ctx context.Context, userID int64, phone string) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var countUsed int64
+		if err := tx.Model(&model.User{}).
+			Where("phone = ? AND phone_verified_at IS NOT NULL AND id <> ?", phone, userID).
+			Count(&countUsed).Error; err != nil {
+			log.Errorf("[UserRepository-1] UpdatePhoneVerified: %v", err)
+			return err
+		}
+		if countUsed > 0 {
+			log.Infof("[UserRepository-2] UpdatePhoneVerified: Phone already verified by another user")
+			return errors.New("409")
+		}
+
+		result := tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"phone":             phone,
+			"phone_verified_at": time.Now(),
+			"version":           gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			log.Errorf("[UserRepository-3] UpdatePhoneVerified: %v", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Infof("[UserRepository-4] UpdatePhoneVerified: User not found")
+			return errors.New("404")
+		}
+
+		event := entity.NewUserUpdatedEvent(entity.UserUpdatedEventEntity{
+			UserID:        userID,
+			ChangedFields: []string{"phone", "phone_verified_at"},
+			UpdatedAt:     time.Now(),
+		})
+		if err := createEventOutbox(tx, event); err != nil {
+			log.Errorf("[UserRepository-5] UpdatePhoneVerified (outbox): %v", err)
+			return err
+		}
+
+		return nil
+	})
+}
@@ -54,12 +54,20 @@ func (v *verificationTokenRepository) CreateVerificationToken(ctx context.Contex
 		UserID:    req.UserID,
 		Token:     req.Token,
 		TokenType: req.TokenType,
+		ExpiresAt: req.ExpiresAt,
 	}
 
-	if err := v.db.WithContext(ctx).Create(&modelVerificationToken).Error; err != nil {
-		log.Errorf("[VerificationTokenRepository-1] CreateVerificationToken: %v", err)
-		return err
-	}
+	return v.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&modelVerificationToken).Error; err != nil {
+			log.Errorf("[VerificationTokenRepository-1] CreateVerificationToken: %v", err)
+			return err
+		}
+
+		if err := createNotificationOutbox(tx, req.UserID, req.Notifications); err != nil {
+			log.Errorf("[VerificationTokenRepository-2] CreateVerificationToken: %v", err)
+			return err
+		}
 
-	return nil
+		return nil
+	})
 }
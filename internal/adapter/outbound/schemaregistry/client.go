@@ -0,0 +1,138 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const registryContentType = "application/vnd.schemaregistry.v1+json"
+
+type registrySchemaRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type registrySchemaResponse struct {
+	ID         int    `json:"id"`
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType"`
+}
+
+type registryErrorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// client REST API Confluent Schema Registry. Schema berdasarkan ID tidak pernah berubah
+// sehingga aman disimpan di cache tanpa batas waktu.
+type client struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+	cache    sync.Map
+}
+
+func NewClient(baseURL, username, password string, timeout time.Duration) outbound.SchemaRegistryInterface {
+	return &client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		http:     &http.Client{Timeout: timeout},
+	}
+}
+
+// Register schemaType tidak dikirim untuk Avro karena itu default registry.
+func (c *client) Register(ctx context.Context, subject string, schema entity.SchemaEntity) (int, error) {
+	body := registrySchemaRequest{Schema: schema.Schema}
+	if schema.Type != entity.SchemaTypeAvro {
+		body.SchemaType = schema.Type
+	}
+
+	result := registrySchemaResponse{}
+	path := "/subjects/" + url.PathEscape(subject) + "/versions"
+	if err := c.do(ctx, http.MethodPost, path, body, &result); err != nil {
+		log.Errorf("[SchemaRegistry-1] Register: %s: %v", subject, err)
+		return 0, err
+	}
+
+	return result.ID, nil
+}
+
+func (c *client) GetByID(ctx context.Context, id int) (*entity.SchemaEntity, error) {
+	if schema, ok := c.cache.Load(id); ok {
+		result := schema.(entity.SchemaEntity)
+		return &result, nil
+	}
+
+	result := registrySchemaResponse{}
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &result); err != nil {
+		log.Errorf("[SchemaRegistry-2] GetByID: %d: %v", id, err)
+		return nil, err
+	}
+
+	schema := entity.SchemaEntity{ID: id, Type: result.SchemaType, Schema: result.Schema}
+	if schema.Type == "" {
+		schema.Type = entity.SchemaTypeAvro
+	}
+	c.cache.Store(id, schema)
+	return &schema, nil
+}
+
+// do error dari registry dipetakan ke kode yang dipakai di repo: 404 subject/schema tidak ada,
+// 409 schema tidak kompatibel dan 422 schema tidak valid.
+func (c *client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", registryContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", registryContentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		errResp := registryErrorResponse{}
+		_ = json.NewDecoder(resp.Body).Decode(&errResp)
+		switch resp.StatusCode {
+		case http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity:
+			log.Errorf("[SchemaRegistry-3] %s %s: %d %s", method, path, errResp.ErrorCode, errResp.Message)
+			return errors.New(strconv.Itoa(resp.StatusCode))
+		}
+		return fmt.Errorf("schema registry responded %d: %s", resp.StatusCode, errResp.Message)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,170 @@
+package schemaregistry
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/utils/protoschema"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CheckCompatibility aturan BACKWARD (default Confluent): consumer dengan schema next
+// harus tetap bisa membaca data yang ditulis dengan schema previous.
+func CheckCompatibility(previous, next entity.SchemaEntity) error {
+	if previous.Type != next.Type {
+		return fmt.Errorf("schema type changed from %s to %s", previous.Type, next.Type)
+	}
+
+	switch next.Type {
+	case entity.SchemaTypeAvro:
+		return checkAvroCompatibility(previous.Schema, next.Schema)
+	case entity.SchemaTypeProtobuf:
+		return checkProtobufCompatibility(previous.Schema, next.Schema)
+	case entity.SchemaTypeJSON:
+		return checkJSONCompatibility(previous.Schema, next.Schema)
+	}
+	return fmt.Errorf("unknown schema type %q", next.Type)
+}
+
+// ValidateSchema memastikan schema bisa di-parse sesuai tipenya.
+func ValidateSchema(schema entity.SchemaEntity) error {
+	var err error
+	switch schema.Type {
+	case entity.SchemaTypeAvro:
+		_, err = avro.Parse(schema.Schema)
+	case entity.SchemaTypeProtobuf:
+		_, err = protoschema.Compile(schema.Schema)
+	case entity.SchemaTypeJSON:
+		_, err = parseJSONSchema(schema.Schema)
+	default:
+		err = fmt.Errorf("unknown schema type %q", schema.Type)
+	}
+	return err
+}
+
+func checkAvroCompatibility(previous, next string) error {
+	writer, err := avro.Parse(previous)
+	if err != nil {
+		return err
+	}
+	reader, err := avro.Parse(next)
+	if err != nil {
+		return err
+	}
+	return avro.NewSchemaCompatibility().Compatible(reader, writer)
+}
+
+// checkProtobufCompatibility message lama tidak boleh hilang, field lama harus tetap dengan
+// tipe yang sama atau nomornya di-reserved agar tidak dipakai ulang dengan arti lain.
+func checkProtobufCompatibility(previous, next string) error {
+	previousFile, err := protoschema.Compile(previous)
+	if err != nil {
+		return err
+	}
+	nextFile, err := protoschema.Compile(next)
+	if err != nil {
+		return err
+	}
+
+	messages := previousFile.Messages()
+	for i := 0; i < messages.Len(); i++ {
+		message := messages.Get(i)
+		nextMessage := nextFile.Messages().ByName(message.Name())
+		if nextMessage == nil {
+			return fmt.Errorf("message %s removed", message.Name())
+		}
+		if err := checkProtobufMessage(message, nextMessage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkProtobufMessage(previous, next protoreflect.MessageDescriptor) error {
+	fields := previous.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		nextField := next.Fields().ByNumber(field.Number())
+		if nextField == nil {
+			if !next.ReservedRanges().Has(field.Number()) {
+				return fmt.Errorf("field %s.%s removed without reserving number %d", previous.Name(), field.Name(), field.Number())
+			}
+			continue
+		}
+
+		if nextField.Kind() != field.Kind() || nextField.Cardinality() != field.Cardinality() {
+			return fmt.Errorf("field %s.%s changed type", previous.Name(), field.Name())
+		}
+		if field.Kind() == protoreflect.MessageKind && nextField.Message().FullName() != field.Message().FullName() {
+			return fmt.Errorf("field %s.%s changed message type", previous.Name(), field.Name())
+		}
+	}
+
+	nested := previous.Messages()
+	for i := 0; i < nested.Len(); i++ {
+		message := nested.Get(i)
+		nextMessage := next.Messages().ByName(message.Name())
+		if nextMessage == nil {
+			return fmt.Errorf("message %s.%s removed", previous.Name(), message.Name())
+		}
+		if err := checkProtobufMessage(message, nextMessage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonSchema struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+	Required             []string `json:"required"`
+	AdditionalProperties *bool    `json:"additionalProperties"`
+}
+
+func parseJSONSchema(source string) (*jsonSchema, error) {
+	schema := &jsonSchema{}
+	if err := json.Unmarshal([]byte(source), schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// checkJSONCompatibility field wajib baru dan perubahan tipe membuat data lama tidak valid.
+// Menghapus field hanya aman jika schema baru masih menerima additionalProperties.
+func checkJSONCompatibility(previous, next string) error {
+	previousSchema, err := parseJSONSchema(previous)
+	if err != nil {
+		return err
+	}
+	nextSchema, err := parseJSONSchema(next)
+	if err != nil {
+		return err
+	}
+
+	previousRequired := map[string]bool{}
+	for _, field := range previousSchema.Required {
+		previousRequired[field] = true
+	}
+	for _, field := range nextSchema.Required {
+		if !previousRequired[field] {
+			return fmt.Errorf("field %s became required", field)
+		}
+	}
+
+	for field, property := range previousSchema.Properties {
+		nextProperty, ok := nextSchema.Properties[field]
+		if !ok {
+			if nextSchema.AdditionalProperties != nil && !*nextSchema.AdditionalProperties {
+				return fmt.Errorf("field %s removed while additionalProperties is false", field)
+			}
+			continue
+		}
+		if nextProperty.Type != property.Type {
+			return fmt.Errorf("field %s changed type from %s to %s", field, property.Type, nextProperty.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package schemaregistry
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/labstack/gommon/log"
+)
+
+// memoryRegistry pengganti Schema Registry untuk test. Aturan ID dan
+// kompatibilitas mengikuti Confluent: schema yang sama mendapat ID yang sama di semua subject.
+type memoryRegistry struct {
+	mu       sync.Mutex
+	schemas  []entity.SchemaEntity
+	subjects map[string][]int
+}
+
+func NewMemoryRegistry() outbound.SchemaRegistryInterface {
+	return &memoryRegistry{subjects: map[string][]int{}}
+}
+
+func (m *memoryRegistry) Register(ctx context.Context, subject string, schema entity.SchemaEntity) (int, error) {
+	if err := ValidateSchema(schema); err != nil {
+		log.Errorf("[MemoryRegistry-1] Register: %s: %v", subject, err)
+		return 0, errors.New("422")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	versions := m.subjects[subject]
+	for _, id := range versions {
+		if sameSchema(m.schemas[id-1], schema) {
+			return id, nil
+		}
+	}
+
+	if len(versions) > 0 {
+		latest := m.schemas[versions[len(versions)-1]-1]
+		if err := CheckCompatibility(latest, schema); err != nil {
+			log.Errorf("[MemoryRegistry-2] Register: %s is incompatible: %v", subject, err)
+			return 0, errors.New("409")
+		}
+	}
+
+	id := 0
+	for _, val := range m.schemas {
+		if sameSchema(val, schema) {
+			id = val.ID
+			break
+		}
+	}
+	if id == 0 {
+		id = len(m.schemas) + 1
+		schema.ID = id
+		schema.Subject = subject
+		schema.Version = len(versions) + 1
+		m.schemas = append(m.schemas, schema)
+	}
+
+	m.subjects[subject] = append(versions, id)
+	return id, nil
+}
+
+func (m *memoryRegistry) GetByID(ctx context.Context, id int) (*entity.SchemaEntity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id <= 0 || id > len(m.schemas) {
+		return nil, errors.New("404")
+	}
+
+	schema := m.schemas[id-1]
+	return &schema, nil
+}
+
+func sameSchema(a, b entity.SchemaEntity) bool {
+	return a.Type == b.Type && strings.TrimSpace(a.Schema) == strings.TrimSpace(b.Schema)
+}
@@ -0,0 +1,205 @@
+package smtp
+
+import (
+	"bytes"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkadelivery"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	gosmtp "net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const defaultPort = "25"
+
+type sender struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+// NewSender transport smtp, email dikirim langsung ke server SMTP tanpa notification service.
+// STARTTLS dipakai jika didukung server, auth PLAIN hanya jika username diisi. port kosong berarti 25.
+func NewSender(host, port, username, password, from string, timeout time.Duration) outbound.NotificationSenderInterface {
+	if port == "" {
+		port = defaultPort
+	}
+	return &sender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+		timeout:  timeout,
+	}
+}
+
+// Send notifikasi PUSH dan SMS tidak bisa dikirim lewat SMTP, dilaporkan sebagai ErrSkipped agar
+// riwayatnya tercatat skipped (bukan sent) dan tidak dicoba ulang terus oleh relay outbox.
+func (s *sender) Send(ctx context.Context, notification entity.NotificationEntity) error {
+	if notification.Type != entity.NotificationTypeEmail || notification.Email == "" {
+		log.Warnf("[SMTP-1] Send: notification %s (%s) is not supported by smtp transport, skipped",
+			notification.ID, notification.Type)
+		kafkadelivery.Report(ctx, kafkadelivery.ErrSkipped)
+		return nil
+	}
+
+	to, err := mail.ParseAddress(notification.Email)
+	if err != nil {
+		log.Errorf("[SMTP-2] Send: invalid email %q: %v", notification.Email, err)
+		return err
+	}
+
+	body, err := s.buildMessage(to, notification)
+	if err != nil {
+		log.Errorf("[SMTP-3] Send: %v", err)
+		return err
+	}
+
+	if err := s.sendMail(ctx, to.Address, body); err != nil {
+		log.Errorf("[SMTP-4] Send: notification %s: %v", notification.ID, err)
+		return err
+	}
+
+	kafkadelivery.Report(ctx, nil)
+	return nil
+}
+
+func (s *sender) Close() error {
+	return nil
+}
+
+func (s *sender) sendMail(ctx context.Context, to string, body []byte) error {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.host, s.port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	client, err := gosmtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(gosmtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage email UTF-8, multipart/alternative jika ada body HTML. Subject di-encode agar aman
+// dari header injection.
+func (s *sender) buildMessage(to *mail.Address, notification entity.NotificationEntity) ([]byte, error) {
+	sentAt := notification.CreatedAt
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", notification.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", sentAt.Format(time.RFC1123Z))
+	if notification.ID != "" {
+		fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", notification.ID, s.host)
+	}
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	if notification.HTMLMessage == "" {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+		msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&msg, notification.Message); err != nil {
+			return nil, err
+		}
+		return msg.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+
+	// Urutan part dari yang paling sederhana, client email menampilkan part terakhir yang didukung
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", notification.Message},
+		{"text/html; charset=UTF-8", notification.HTMLMessage},
+	} {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(partWriter, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return msg.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\r\n")
+	return err
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"bytes"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkadelivery"
+	"clean-architecture/utils/traceparent"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+// SignatureHeader HMAC-SHA256 body dengan NOTIFICATION_WEBHOOK_SECRET dalam hex, dikirim jika secret diisi.
+const SignatureHeader = "X-Signature-256"
+
+type sender struct {
+	url    string
+	secret string
+	http   *http.Client
+}
+
+// NewSender transport webhook, setiap notifikasi dikirim sebagai POST JSON (entity.NotificationEntity).
+// Respons selain 2xx dianggap gagal sehingga dicoba ulang oleh relay outbox.
+func NewSender(url, secret string, timeout time.Duration) outbound.NotificationSenderInterface {
+	return &sender{
+		url:    url,
+		secret: secret,
+		http:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *sender) Send(ctx context.Context, notification entity.NotificationEntity) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		log.Errorf("[Webhook-1] Send: %v", err)
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		log.Errorf("[Webhook-2] Send: %v", err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", notification.ID)
+	if parent := traceparent.FromContext(ctx); parent != "" {
+		req.Header.Set(traceparent.Header, traceparent.Child(parent))
+	}
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		log.Errorf("[Webhook-3] Send: notification %s: %v", notification.ID, err)
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		log.Errorf("[Webhook-4] Send: notification %s: webhook responded %d", notification.ID, resp.StatusCode)
+		return fmt.Errorf("webhook responded %d", resp.StatusCode)
+	}
+
+	kafkadelivery.Report(ctx, nil)
+	return nil
+}
+
+func (s *sender) Close() error {
+	return nil
+}
@@ -3,16 +3,24 @@ package app
 import (
 	"clean-architecture/config"
 	inboundadapterecho "clean-architecture/internal/adapter/inbound/echo"
+	outboundadapterfilesink "clean-architecture/internal/adapter/outbound/filesink"
 	outboundadapterkafka "clean-architecture/internal/adapter/outbound/kafka"
 	outboundadapterminio "clean-architecture/internal/adapter/outbound/minio"
+	outboundadapternotificationtemplate "clean-architecture/internal/adapter/outbound/notificationtemplate"
 	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	outboundadapterschemaregistry "clean-architecture/internal/adapter/outbound/schemaregistry"
+	outboundadaptersmtp "clean-architecture/internal/adapter/outbound/smtp"
+	outboundadapterwebhook "clean-architecture/internal/adapter/outbound/webhook"
 	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/outbound"
 	"clean-architecture/utils/validator"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -23,10 +31,18 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 )
 
+// Transport notifikasi (NOTIFICATION_TRANSPORT)
+const (
+	notificationTransportKafka   = "kafka"
+	notificationTransportSMTP    = "smtp"
+	notificationTransportWebhook = "webhook"
+	notificationTransportStdout  = "stdout"
+	notificationTransportFile    = "file"
+)
+
 func RunServer() {
 	cfg := config.NewConfig()
 	redisConfig := cfg.RedisConfig()
-	kafkaConfig := cfg.NewKafkaConfig()
 
 	initMinio, err := cfg.InitMinio()
 	if err != nil {
@@ -45,27 +61,70 @@ func RunServer() {
 	}
 	appPort := ":" + cfg.App.AppPort
 
-	publisher, err := outboundadapterkafka.NewKafkaProducer(cfg.Kafka.Brokers, kafkaConfig)
+	// Producer Kafka dipakai domain event apa pun transport notifikasinya, hanya dilewati jika
+	// KAFKA_BROKERS kosong dan transport bukan kafka. Jika transport bukan kafka, gagal membuat
+	// producer tidak menghentikan server: tanpa producer event tetap pending di outbox.
+	var publisher outbound.KafkaProducerInterface
+	kafkaTransport := notificationTransport(cfg) == notificationTransportKafka
+	if kafkaBrokersConfigured(cfg) || kafkaTransport {
+		serializer, err := newKafkaSerializer(cfg)
+		if err == nil {
+			publisher, err = newKafkaPublisher(cfg, serializer)
+		}
+		if err != nil {
+			if kafkaTransport {
+				log.Fatalf("[RunServer-3] Failed to init Kafka: %v", err)
+			}
+			log.Warnf("[RunServer-3] Failed to init Kafka, domain events stay pending in outbox: %v", err)
+			publisher = nil
+		}
+	}
+
+	notificationSender, err := newNotificationSender(cfg, publisher)
 	if err != nil {
-		log.Fatalf("[RunServer-3] Failed to init Kafka: %v", err)
+		log.Fatalf("[RunServer-3] Failed to init notification transport: %v", err)
+	}
+
+	notificationTemplates, err := outboundadapternotificationtemplate.NewRenderer(cfg.Notification.TemplateDir, cfg.Notification.DefaultLocale)
+	if err != nil {
+		log.Fatalf("[RunServer-3] Failed to load notification templates: %v", err)
 	}
 
 	minioClient := outboundadapterminio.NewMinioStorage(initMinio, cfg.Minio.Bucket)
 	userRepo := outboundadapterpostgres.NewUserRepository(db.DB)
 	verificationTokenRepo := outboundadapterpostgres.NewVerificationTokenRepository(db.DB)
 	roleRepo := outboundadapterpostgres.NewRoleRepository(db.DB)
+	addressRepo := outboundadapterpostgres.NewAddressRepository(db.DB)
+	privacyRepo := outboundadapterpostgres.NewPrivacyRepository(db.DB)
+	preferenceRepo := outboundadapterpostgres.NewPreferenceRepository(db.DB)
+	consentRepo := outboundadapterpostgres.NewConsentRepository(db.DB)
+	statsRepo := outboundadapterpostgres.NewStatsRepository(db.DB)
+	outboxRepo := outboundadapterpostgres.NewOutboxRepository(db.DB)
+	notificationRepo := outboundadapterpostgres.NewNotificationRepository(db.DB)
 
 	jwtService := service.NewJwtService(cfg)
-	kafkaService := service.NewKafkaService(cfg, publisher)
-	userService := service.NewUserService(userRepo, cfg, jwtService, verificationTokenRepo, kafkaService, redisConfig)
+	preferenceService := service.NewPreferenceService(preferenceRepo, userRepo)
+	kafkaService := service.NewKafkaService(cfg, notificationSender, publisher, notificationTemplates, preferenceService)
+	consentService := service.NewConsentService(consentRepo, redisConfig)
+	statsService := service.NewStatsService(statsRepo, redisConfig)
+	outboxRelayService := service.NewOutboxRelayService(outboxRepo, notificationRepo, kafkaService, cfg)
+	userService := service.NewUserService(userRepo, cfg, jwtService, verificationTokenRepo, redisConfig, consentService, minioClient)
 	roleService := service.NewRoleService(roleRepo)
+	customerImportService := service.NewCustomerImportService(userService, redisConfig)
+	addressService := service.NewAddressService(addressRepo, userRepo, cfg)
+	phoneVerificationService := service.NewPhoneVerificationService(userRepo, cfg, notificationRepo, redisConfig)
+	privacyService := service.NewPrivacyService(privacyRepo, userRepo, addressRepo, minioClient, redisConfig, cfg)
+	notificationService := service.NewNotificationService(notificationRepo)
 
 	e := echo.New()
-	e.Use(middleware.CORS())
+	// ETag perlu di-expose agar client browser bisa mengirimnya kembali sebagai If-Match
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		ExposeHeaders: []string{"ETag"},
+	}))
 	e.HideBanner = true
 	e.Use(middleware.Recover())
 
-	customValidator := validator.NewValidator(db.DB)
+	customValidator := validator.NewValidator(db.DB, cfg.App.PhoneDefaultCountryCode)
 	if err := en.RegisterDefaultTranslations(customValidator.Validator, customValidator.Translator); err != nil {
 		log.Fatalf("[RunServer-4] %v", err)
 		return
@@ -74,12 +133,30 @@ func RunServer() {
 
 	mid := inboundadapterecho.NewMiddlewareAdapter(cfg, redisConfig, jwtService)
 
-	pingHandler := inboundadapterecho.NewPingHandler()
+	pingHandler := inboundadapterecho.NewPingHandler(publisher)
 	userHandler := inboundadapterecho.NewUserHandler(userService)
 	roleHandler := inboundadapterecho.NewRoleHandler(roleService)
 	uploadImageHandler := inboundadapterecho.NewUploadImageHandler(minioClient)
+	customerImportHandler := inboundadapterecho.NewCustomerImportHandler(customerImportService)
+	addressHandler := inboundadapterecho.NewAddressHandler(addressService)
+	phoneVerificationHandler := inboundadapterecho.NewPhoneVerificationHandler(phoneVerificationService)
+	privacyHandler := inboundadapterecho.NewPrivacyHandler(privacyService)
+	preferenceHandler := inboundadapterecho.NewPreferenceHandler(preferenceService)
+	consentHandler := inboundadapterecho.NewConsentHandler(consentService)
+	statsHandler := inboundadapterecho.NewStatsHandler(statsService)
+	notificationHandler := inboundadapterecho.NewNotificationHandler(notificationService)
 
-	inboundadapterecho.InitRoutes(e, mid, pingHandler, userHandler, roleHandler, uploadImageHandler)
+	inboundadapterecho.InitRoutes(e, mid, pingHandler, userHandler, roleHandler, uploadImageHandler, customerImportHandler,
+		addressHandler, phoneVerificationHandler, privacyHandler, preferenceHandler, consentHandler, statsHandler,
+		notificationHandler)
+
+	// Relay outbox berhenti setelah batch yang sedang berjalan selesai saat shutdown
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		outboxRelayService.Run(relayCtx)
+		close(relayDone)
+	}()
 
 	go func() {
 		log.Infof("[RunServer-5] Server starting at %s", appPort)
@@ -101,5 +178,104 @@ func RunServer() {
 		log.Fatalf("[RunServer-8] Server forced to shutdown: %v", err)
 	}
 
+	stopRelay()
+	<-relayDone
+
+	if err := notificationSender.Close(); err != nil {
+		log.Errorf("[RunServer-10] Failed to close notification transport: %v", err)
+	}
+
+	if publisher != nil {
+		// Pesan yang masih di antrean producer async dikirim dulu sebelum koneksi ditutup
+		flushCtx, cancelFlush := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelFlush()
+		if err := publisher.Flush(flushCtx); err != nil {
+			log.Errorf("[RunServer-10] Failed to flush Kafka producer: %v", err)
+		}
+		if err := publisher.Close(); err != nil {
+			log.Errorf("[RunServer-10] Failed to close Kafka producer: %v", err)
+		}
+	}
+
 	log.Infof("[RunServer-9] Server exited properly")
 }
+
+// newKafkaSerializer nil jika KAFKA_SERIALIZER kosong, data event tetap dikirim sebagai JSON biasa.
+func newKafkaSerializer(cfg *config.Config) (outbound.KafkaSerializerInterface, error) {
+	if cfg.Kafka.Serializer == "" {
+		return nil, nil
+	}
+	if cfg.Kafka.SchemaRegistryURL == "" {
+		return nil, errors.New("KAFKA_SCHEMA_REGISTRY_URL is required when KAFKA_SERIALIZER is set")
+	}
+
+	registry := outboundadapterschemaregistry.NewClient(cfg.Kafka.SchemaRegistryURL, cfg.Kafka.SchemaRegistryUsername,
+		cfg.Kafka.SchemaRegistryPassword, time.Duration(cfg.Kafka.TimeoutInMS)*time.Millisecond)
+	return outboundadapterkafka.NewSerializer(cfg.Kafka.Serializer, registry)
+}
+
+// newKafkaPublisher producer untuk notifikasi dan event sesuai KAFKA_PRODUCER_MODE.
+func newKafkaPublisher(cfg *config.Config, serializer outbound.KafkaSerializerInterface) (outbound.KafkaProducerInterface, error) {
+	kafkaConfig, err := cfg.NewKafkaProducerConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Kafka.ProducerMode {
+	case "", outboundadapterkafka.ProducerModeSync:
+		return outboundadapterkafka.NewKafkaProducer(cfg.Kafka.Brokers, kafkaConfig, cfg.Kafka.MessageFormat,
+			cfg.Kafka.CloudEventsSource, serializer)
+	case outboundadapterkafka.ProducerModeAsync:
+		return outboundadapterkafka.NewKafkaAsyncProducer(cfg.Kafka.Brokers, kafkaConfig, cfg.Kafka.MessageFormat,
+			cfg.Kafka.CloudEventsSource, serializer)
+	}
+	return nil, fmt.Errorf("unknown kafka producer mode %q", cfg.Kafka.ProducerMode)
+}
+
+// kafkaBrokersConfigured true jika KAFKA_BROKERS berisi minimal satu alamat broker.
+func kafkaBrokersConfigured(cfg *config.Config) bool {
+	for _, broker := range cfg.Kafka.Brokers {
+		if strings.TrimSpace(broker) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// notificationTransport kosong berarti kafka agar konfigurasi lama tetap berjalan.
+func notificationTransport(cfg *config.Config) string {
+	if cfg.Notification.Transport == "" {
+		return notificationTransportKafka
+	}
+	return cfg.Notification.Transport
+}
+
+// newNotificationSender transport notifikasi sesuai NOTIFICATION_TRANSPORT, publisher hanya dipakai transport kafka.
+func newNotificationSender(cfg *config.Config, publisher outbound.KafkaProducerInterface) (outbound.NotificationSenderInterface, error) {
+	timeout := time.Duration(cfg.Notification.TimeoutInMS) * time.Millisecond
+
+	switch transport := notificationTransport(cfg); transport {
+	case notificationTransportKafka:
+		return outboundadapterkafka.NewNotificationSender(publisher, cfg.Kafka.Topic), nil
+	case notificationTransportSMTP:
+		if cfg.Notification.SMTPHost == "" || cfg.Notification.SMTPFrom == "" {
+			return nil, errors.New("NOTIFICATION_SMTP_HOST and NOTIFICATION_SMTP_FROM are required for smtp transport")
+		}
+		return outboundadaptersmtp.NewSender(cfg.Notification.SMTPHost, cfg.Notification.SMTPPort, cfg.Notification.SMTPUsername,
+			cfg.Notification.SMTPPassword, cfg.Notification.SMTPFrom, timeout), nil
+	case notificationTransportWebhook:
+		if cfg.Notification.WebhookURL == "" {
+			return nil, errors.New("NOTIFICATION_WEBHOOK_URL is required for webhook transport")
+		}
+		return outboundadapterwebhook.NewSender(cfg.Notification.WebhookURL, cfg.Notification.WebhookSecret, timeout), nil
+	case notificationTransportStdout:
+		return outboundadapterfilesink.NewSender("")
+	case notificationTransportFile:
+		if cfg.Notification.FilePath == "" {
+			return nil, errors.New("NOTIFICATION_FILE_PATH is required for file transport")
+		}
+		return outboundadapterfilesink.NewSender(cfg.Notification.FilePath)
+	default:
+		return nil, fmt.Errorf("unknown notification transport %q", transport)
+	}
+}
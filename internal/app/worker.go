@@ -0,0 +1,83 @@
+package app
+
+import (
+	"clean-architecture/config"
+	inboundadapterkafka "clean-architecture/internal/adapter/inbound/kafka"
+	outboundadapterkafka "clean-architecture/internal/adapter/outbound/kafka"
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/internal/domain/service"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/labstack/gommon/log"
+)
+
+// RunWorker menjalankan consumer group Kafka sampai menerima SIGINT/SIGTERM. Pesan yang
+// sedang diproses dibiarkan selesai, offset yang belum di-commit akan dibaca ulang.
+func RunWorker() {
+	cfg := config.NewConfig()
+
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("[RunWorker-1] Failed to connect Postgres: %v", err)
+		return
+	}
+
+	// Producer dipakai untuk meneruskan pesan yang gagal ke retry topic / DLQ
+	producer, err := outboundadapterkafka.NewKafkaProducer(cfg.Kafka.Brokers, cfg.NewKafkaConfig(),
+		cfg.Kafka.MessageFormat, cfg.Kafka.CloudEventsSource, nil)
+	if err != nil {
+		log.Fatalf("[RunWorker-2] Failed to init Kafka producer: %v", err)
+		return
+	}
+
+	// Data event dibaca dengan serializer yang sama dengan producer di service utama
+	serializer, err := newKafkaSerializer(cfg)
+	if err != nil {
+		log.Fatalf("[RunWorker-2] Failed to init Kafka serializer: %v", err)
+		return
+	}
+
+	consumer, err := inboundadapterkafka.NewKafkaConsumer(cfg.Kafka.Brokers, cfg.Kafka.ConsumerGroup,
+		cfg.Kafka.ConsumerTopics, cfg.NewKafkaConsumerConfig(), producer, serializer, cfg.KafkaConsumerRetryDelays())
+	if err != nil {
+		log.Fatalf("[RunWorker-2] Failed to init Kafka consumer: %v", err)
+		return
+	}
+
+	notificationDeliveryRepo := outboundadapterpostgres.NewNotificationDeliveryRepository(db.DB)
+	notificationRepo := outboundadapterpostgres.NewNotificationRepository(db.DB)
+	notificationDeliveryService := service.NewNotificationDeliveryService(notificationDeliveryRepo, notificationRepo)
+	notificationEventHandler := inboundadapterkafka.NewNotificationEventHandler(notificationDeliveryService)
+
+	inboundadapterkafka.InitEventRoutes(consumer, notificationEventHandler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		if err := consumer.Run(ctx); err != nil {
+			log.Errorf("[RunWorker-3] Consumer stopped: %v", err)
+		}
+		close(done)
+	}()
+
+	// === GRACEFUL SHUTDOWN ===
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	log.Infof("[RunWorker-4] Shutting down worker...")
+	cancel()
+	<-done
+
+	if err := consumer.Close(); err != nil {
+		log.Errorf("[RunWorker-5] Failed to close Kafka consumer: %v", err)
+	}
+	if err := producer.Close(); err != nil {
+		log.Errorf("[RunWorker-7] Failed to close Kafka producer: %v", err)
+	}
+
+	log.Infof("[RunWorker-6] Worker exited properly")
+}
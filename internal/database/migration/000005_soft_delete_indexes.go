@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upSoftDeleteIndexes, downSoftDeleteIndexes)
+}
+
+func upSoftDeleteIndexes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
+	CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles(deleted_at);
+	CREATE INDEX IF NOT EXISTS idx_user_role_deleted_at ON user_role(deleted_at);
+	CREATE INDEX IF NOT EXISTS idx_verification_tokens_deleted_at ON verification_tokens(deleted_at);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downSoftDeleteIndexes(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	DROP INDEX IF EXISTS idx_users_deleted_at;
+	DROP INDEX IF EXISTS idx_roles_deleted_at;
+	DROP INDEX IF EXISTS idx_user_role_deleted_at;
+	DROP INDEX IF EXISTS idx_verification_tokens_deleted_at;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCustomerSearch, downCustomerSearch)
+}
+
+// search_vector: name (bobot A), email utuh + email yang dipecah per bagian (B), phone (C).
+// Index trigram dipakai untuk pencarian yang toleran typo (operator % / similarity).
+func upCustomerSearch(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(email, '') || ' ' || regexp_replace(coalesce(email, ''), '[@._+-]+', ' ', 'g')), 'B') ||
+		setweight(to_tsvector('simple', coalesce(phone, '')), 'C')
+	) STORED;
+
+	CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN (search_vector);
+	CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING GIN (name gin_trgm_ops);
+	CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
+	CREATE INDEX IF NOT EXISTS idx_users_phone_trgm ON users USING GIN (phone gin_trgm_ops);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Extension pg_trgm tidak di drop karena bisa dipakai objek lain di database.
+func downCustomerSearch(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	DROP INDEX IF EXISTS idx_users_phone_trgm;
+	DROP INDEX IF EXISTS idx_users_email_trgm;
+	DROP INDEX IF EXISTS idx_users_name_trgm;
+	DROP INDEX IF EXISTS idx_users_search_vector;
+	ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserCoordinates, downUserCoordinates)
+}
+
+// Nilai lat/lng lama (varchar) yang bukan angka atau di luar range dijadikan NULL.
+// Sebelumnya create customer tanpa koordinat menyimpan "0"/"0", jadi pasangan 0,0 juga dianggap kosong.
+func upUserCoordinates(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE users
+		ALTER COLUMN lat TYPE NUMERIC(9,6) USING (
+			CASE WHEN btrim(lat) ~ '^[-+]?[0-9]+(\.[0-9]+)?$' THEN
+				CASE WHEN btrim(lat)::numeric BETWEEN -90 AND 90 THEN round(btrim(lat)::numeric, 6) END
+			END
+		),
+		ALTER COLUMN lng TYPE NUMERIC(9,6) USING (
+			CASE WHEN btrim(lng) ~ '^[-+]?[0-9]+(\.[0-9]+)?$' THEN
+				CASE WHEN btrim(lng)::numeric BETWEEN -180 AND 180 THEN round(btrim(lng)::numeric, 6) END
+			END
+		);
+
+	UPDATE users SET lat = NULL, lng = NULL WHERE lat = 0 AND lng = 0;
+
+	ALTER TABLE users
+		ADD CONSTRAINT chk_users_lat CHECK (lat BETWEEN -90 AND 90),
+		ADD CONSTRAINT chk_users_lng CHECK (lng BETWEEN -180 AND 180);
+
+	CREATE INDEX IF NOT EXISTS idx_users_lat_lng ON users(lat, lng);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downUserCoordinates(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	DROP INDEX IF EXISTS idx_users_lat_lng;
+
+	ALTER TABLE users
+		DROP CONSTRAINT IF EXISTS chk_users_lat,
+		DROP CONSTRAINT IF EXISTS chk_users_lng;
+
+	ALTER TABLE users
+		ALTER COLUMN lat TYPE VARCHAR(50) USING lat::text,
+		ALTER COLUMN lng TYPE VARCHAR(50) USING lng::text;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
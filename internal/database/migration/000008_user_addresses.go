@@ -0,0 +1,65 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserAddresses, downUserAddresses)
+}
+
+// users.address lama dipindahkan sebagai alamat default ("Utama") setiap user.
+// Kolom users.address, lat & lng tetap ada dan selalu mengikuti alamat default.
+func upUserAddresses(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS user_addresses (
+		id BIGSERIAL PRIMARY KEY,
+		user_id BIGINT NOT NULL,
+		label VARCHAR(50) NOT NULL,
+		recipient_name VARCHAR(255) NOT NULL,
+		phone VARCHAR(17) NOT NULL DEFAULT '',
+		address_line TEXT NOT NULL,
+		district VARCHAR(100) NOT NULL DEFAULT '',
+		city VARCHAR(100) NOT NULL DEFAULT '',
+		province VARCHAR(100) NOT NULL DEFAULT '',
+		postal_code VARCHAR(10) NOT NULL DEFAULT '',
+		notes TEXT NOT NULL DEFAULT '',
+		lat NUMERIC(9,6),
+		lng NUMERIC(9,6),
+		is_default BOOLEAN NOT NULL DEFAULT FALSE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP,
+		deleted_at TIMESTAMP,
+
+		CONSTRAINT fk_user_addresses_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		CONSTRAINT chk_user_addresses_lat CHECK (lat BETWEEN -90 AND 90),
+		CONSTRAINT chk_user_addresses_lng CHECK (lng BETWEEN -180 AND 180)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_user_addresses_user_id ON user_addresses(user_id);
+	CREATE INDEX IF NOT EXISTS idx_user_addresses_deleted_at ON user_addresses(deleted_at);
+	-- Satu user hanya boleh punya satu alamat default yang aktif
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_user_addresses_default ON user_addresses(user_id)
+		WHERE is_default AND deleted_at IS NULL;
+
+	INSERT INTO user_addresses (user_id, label, recipient_name, phone, address_line, lat, lng, is_default)
+	SELECT id, 'Utama', name, coalesce(phone, ''), address, lat, lng, TRUE
+	FROM users
+	WHERE btrim(coalesce(address, '')) <> '';
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downUserAddresses(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS user_addresses;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upPhoneVerification, downPhoneVerification)
+}
+
+// Nomor yang sudah diverifikasi harus unik karena dipakai sebagai identitas login.
+func upPhoneVerification(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP;
+
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_users_verified_phone ON users(phone)
+		WHERE phone_verified_at IS NOT NULL AND deleted_at IS NULL;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downPhoneVerification(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	DROP INDEX IF EXISTS uq_users_verified_phone;
+	ALTER TABLE users DROP COLUMN IF EXISTS phone_verified_at;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserDeletionRequests, downUserDeletionRequests)
+}
+
+// Permintaan hapus akun (right to erasure) dieksekusi setelah masa tenggang oleh command erase.
+// users.erased_at menandai baris user yang PII-nya sudah dianonimkan.
+func upUserDeletionRequests(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS erased_at TIMESTAMP;
+
+	CREATE TABLE IF NOT EXISTS user_deletion_requests (
+		id BIGSERIAL PRIMARY KEY,
+		user_id BIGINT NOT NULL,
+		requested_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		scheduled_at TIMESTAMP NOT NULL,
+		cancelled_at TIMESTAMP,
+		completed_at TIMESTAMP,
+
+		CONSTRAINT fk_user_deletion_requests_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	-- Satu user hanya boleh punya satu permintaan yang masih berjalan
+	CREATE UNIQUE INDEX IF NOT EXISTS uq_user_deletion_requests_pending ON user_deletion_requests(user_id)
+		WHERE cancelled_at IS NULL AND completed_at IS NULL;
+	CREATE INDEX IF NOT EXISTS idx_user_deletion_requests_due ON user_deletion_requests(scheduled_at)
+		WHERE cancelled_at IS NULL AND completed_at IS NULL;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downUserDeletionRequests(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	DROP TABLE IF EXISTS user_deletion_requests;
+	ALTER TABLE users DROP COLUMN IF EXISTS erased_at;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upVersionColumns, downVersionColumns)
+}
+
+// Kolom version dipakai untuk optimistic locking (ETag / If-Match), naik setiap kali baris diubah.
+func upVersionColumns(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+	ALTER TABLE roles ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downVersionColumns(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE roles DROP COLUMN IF EXISTS version;
+	ALTER TABLE users DROP COLUMN IF EXISTS version;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upUserPreferences, downUserPreferences)
+}
+
+// Hanya preferensi yang diubah user yang disimpan, key lain memakai default dari schema di service.
+func upUserPreferences(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS user_preferences (
+		user_id BIGINT NOT NULL,
+		key VARCHAR(100) NOT NULL,
+		value JSONB NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+		PRIMARY KEY (user_id, key),
+		CONSTRAINT fk_user_preferences_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downUserPreferences(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS user_preferences;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upLegalConsents, downLegalConsents)
+}
+
+// Versi syarat & ketentuan dan kebijakan privasi. Versi yang berlaku adalah versi terakhir
+// per doc_type yang published_at-nya sudah lewat. user_consents mencatat versi yang disetujui tiap user.
+func upLegalConsents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS legal_documents (
+		id BIGSERIAL PRIMARY KEY,
+		doc_type VARCHAR(20) NOT NULL,
+		version VARCHAR(50) NOT NULL,
+		url TEXT NOT NULL,
+		published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+		CONSTRAINT uq_legal_documents_type_version UNIQUE (doc_type, version)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_legal_documents_current ON legal_documents(doc_type, published_at DESC);
+
+	CREATE TABLE IF NOT EXISTS user_consents (
+		id BIGSERIAL PRIMARY KEY,
+		user_id BIGINT NOT NULL,
+		document_id BIGINT NOT NULL,
+		accepted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		ip_address VARCHAR(45),
+
+		CONSTRAINT uq_user_consents_user_document UNIQUE (user_id, document_id),
+		CONSTRAINT fk_user_consents_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		CONSTRAINT fk_user_consents_document FOREIGN KEY (document_id) REFERENCES legal_documents(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_user_consents_document ON user_consents(document_id);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downLegalConsents(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	DROP TABLE IF EXISTS user_consents;
+	DROP TABLE IF EXISTS legal_documents;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upOutboxMessages, downOutboxMessages)
+}
+
+// Transactional outbox: pesan Kafka ditulis di transaksi yang sama dengan perubahan datanya,
+// lalu dikirim oleh relay. user_id hanya referensi (tanpa FK) agar event user yang sudah dihapus tetap terkirim.
+func upOutboxMessages(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS outbox_messages (
+		id BIGSERIAL PRIMARY KEY,
+		kind VARCHAR(20) NOT NULL,
+		event_name VARCHAR(100) NOT NULL DEFAULT '',
+		user_id BIGINT,
+		payload JSONB NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		sent_at TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_outbox_messages_pending ON outbox_messages(next_attempt_at, id)
+		WHERE status = 'pending';
+	CREATE INDEX IF NOT EXISTS idx_outbox_messages_user_id ON outbox_messages(user_id);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downOutboxMessages(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS outbox_messages;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNotificationDeliveries, downNotificationDeliveries)
+}
+
+// Status pengiriman terakhir per notifikasi, diisi worker dari event notification_delivered/failed.
+// message_id adalah message_id yang dikirim bersama notifikasi (ID outbox).
+func upNotificationDeliveries(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS notification_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		message_id VARCHAR(100) NOT NULL,
+		user_id BIGINT,
+		channel VARCHAR(20) NOT NULL DEFAULT '',
+		status VARCHAR(20) NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		occurred_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+		CONSTRAINT uq_notification_deliveries_message_id UNIQUE (message_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_notification_deliveries_user_id ON notification_deliveries(user_id);
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNotificationDeliveries(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS notification_deliveries;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upOutboxEventVersion, downOutboxEventVersion)
+}
+
+// Versi payload domain event disimpan per baris agar event lama di outbox tetap terkirim
+// dengan versi saat event dibuat walaupun versi terbaru sudah naik.
+func upOutboxEventVersion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE outbox_messages ADD COLUMN IF NOT EXISTS event_version INT NOT NULL DEFAULT 1;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downOutboxEventVersion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE outbox_messages DROP COLUMN IF EXISTS event_version;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
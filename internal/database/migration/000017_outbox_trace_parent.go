@@ -0,0 +1,31 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upOutboxTraceParent, downOutboxTraceParent)
+}
+
+// traceparent request yang menulis pesan, diteruskan relay sebagai header pesan Kafka.
+func upOutboxTraceParent(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	ALTER TABLE outbox_messages ADD COLUMN IF NOT EXISTS trace_parent VARCHAR(55) NOT NULL DEFAULT '';
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downOutboxTraceParent(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE outbox_messages DROP COLUMN IF EXISTS trace_parent;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNotifications, downNotifications)
+}
+
+// Riwayat setiap notifikasi yang dikirim ke user, ditulis bersama baris outbox-nya.
+// message_id sama dengan message_id yang dikirim relay (ID outbox) sehingga event
+// notification_delivered/failed bisa memperbarui status. Isi pesan tidak disimpan karena
+// bisa berisi password sementara. Notifikasi lama di outbox ikut diisi ke tabel ini.
+func upNotifications(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`
+	CREATE TABLE IF NOT EXISTS notifications (
+		id BIGSERIAL PRIMARY KEY,
+		message_id VARCHAR(100) NOT NULL,
+		outbox_message_id BIGINT,
+		resend_of_id BIGINT,
+		user_id BIGINT,
+		type VARCHAR(20) NOT NULL,
+		queue_name VARCHAR(50) NOT NULL DEFAULT '',
+		template VARCHAR(50) NOT NULL DEFAULT '',
+		recipient VARCHAR(255) NOT NULL DEFAULT '',
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		attempts INT NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		sent_at TIMESTAMP,
+		delivered_at TIMESTAMP,
+		reported_at TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+		CONSTRAINT uq_notifications_message_id UNIQUE (message_id),
+		CONSTRAINT fk_notifications_outbox FOREIGN KEY (outbox_message_id) REFERENCES outbox_messages(id) ON DELETE SET NULL,
+		CONSTRAINT fk_notifications_resend_of FOREIGN KEY (resend_of_id) REFERENCES notifications(id) ON DELETE SET NULL,
+		CONSTRAINT fk_notifications_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications(user_id, created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_notifications_outbox_message_id ON notifications(outbox_message_id);
+
+	INSERT INTO notifications (message_id, outbox_message_id, user_id, type, queue_name, template, recipient,
+		status, attempts, last_error, created_at, sent_at, delivered_at, reported_at)
+	SELECT
+		COALESCE(NULLIF(o.payload->>'message_id', ''), o.id::text),
+		o.id,
+		o.user_id,
+		CASE
+			WHEN o.payload->>'queue_name' IN ('push-notif', 'push_marketing') THEN 'PUSH'
+			WHEN o.payload->>'queue_name' IN ('sms_phone_otp', 'sms_marketing') THEN 'SMS'
+			ELSE 'EMAIL'
+		END,
+		COALESCE(o.payload->>'queue_name', ''),
+		COALESCE(o.payload->>'template', ''),
+		CASE
+			WHEN o.payload->>'queue_name' IN ('sms_phone_otp', 'sms_marketing') THEN COALESCE(o.payload->>'phone', '')
+			ELSE COALESCE(o.payload->>'email', '')
+		END,
+		COALESCE(d.status, o.status),
+		o.attempts,
+		COALESCE(NULLIF(d.reason, ''), o.last_error),
+		o.created_at,
+		o.sent_at,
+		CASE WHEN d.status = 'delivered' THEN d.occurred_at END,
+		d.occurred_at
+	FROM outbox_messages o
+	LEFT JOIN notification_deliveries d ON d.message_id = COALESCE(NULLIF(o.payload->>'message_id', ''), o.id::text)
+	WHERE o.kind = 'notification'
+	AND (o.user_id IS NULL OR EXISTS (SELECT 1 FROM users u WHERE u.id = o.user_id))
+	ON CONFLICT (message_id) DO NOTHING;
+	`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downNotifications(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec(`DROP TABLE IF EXISTS notifications;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+type AddressEntity struct {
+	ID            int64
+	UserID        int64
+	Label         string
+	RecipientName string
+	Phone         string
+	AddressLine   string
+	District      string
+	City          string
+	Province      string
+	PostalCode    string
+	Notes         string
+	Lat           *float64
+	Lng           *float64
+	IsDefault     bool
+	CreatedAt     time.Time
+	UpdatedAt     *time.Time
+}
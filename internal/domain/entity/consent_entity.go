@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+type LegalDocumentEntity struct {
+	ID          int64
+	DocType     string
+	Version     string
+	URL         string
+	PublishedAt time.Time
+	CreatedAt   time.Time
+}
+
+// ConsentEntity persetujuan user atas satu versi dokumen legal.
+type ConsentEntity struct {
+	UserID     int64
+	DocumentID int64
+	DocType    string
+	Version    string
+	IPAddress  string
+	AcceptedAt time.Time
+}
+
+type ConsentReportEntity struct {
+	Document       LegalDocumentEntity
+	IsCurrent      bool
+	AcceptedCount  int64
+	TotalUsers     int64
+	AcceptanceRate float64
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ConsumedMessageEntity pesan Kafka yang sudah dibongkar dari envelope (legacy atau CloudEvents).
+// Untuk pesan dari retry topic, Topic berisi topic asal dan Attempt jumlah percobaan sebelumnya.
+type ConsumedMessageEntity struct {
+	Topic       string
+	Partition   int32
+	Offset      int64
+	Key         string
+	ID          string
+	EventName   string
+	Metadata    KafkaMetaData
+	Data        json.RawMessage
+	TraceParent string
+	Timestamp   time.Time
+	Attempt     int
+}
+
+// KafkaConsumedEnvelope gabungan envelope legacy (event/metadata/body) dan CloudEvents
+// structured mode, Data belum di-decode. SpecVersion terisi berarti pesan CloudEvents.
+type KafkaConsumedEnvelope struct {
+	Event    KafkaEvent    `json:"event"`
+	Metadata KafkaMetaData `json:"metadata"`
+	Body     struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	} `json:"body"`
+
+	SpecVersion string          `json:"specversion"`
+	ID          string          `json:"id"`
+	Source      string          `json:"source"`
+	Type        string          `json:"type"`
+	Time        string          `json:"time"`
+	TraceParent string          `json:"traceparent"`
+	Data        json.RawMessage `json:"data"`
+}
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type CustomerImportRowEntity struct {
+	Row             int
+	User            UserEntity
+	ValidationError string
+}
+
+type CustomerImportErrorEntity struct {
+	Row     int    `json:"row"`
+	Email   string `json:"email"`
+	Message string `json:"message"`
+}
+
+type CustomerImportJobEntity struct {
+	JobID         string                      `json:"job_id"`
+	Status        string                      `json:"status"`
+	DryRun        bool                        `json:"dry_run"`
+	TotalRows     int                         `json:"total_rows"`
+	ProcessedRows int                         `json:"processed_rows"`
+	SuccessRows   int                         `json:"success_rows"`
+	FailedRows    int                         `json:"failed_rows"`
+	Errors        []CustomerImportErrorEntity `json:"errors"`
+	Error         string                      `json:"error,omitempty"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	FinishedAt    *time.Time                  `json:"finished_at"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+// NearbyQueryEntity parameter pencarian customer terdekat. Jika Box diisi, customer
+// dicari di dalam box; selain itu dalam radius RadiusKm dari (Lat, Lng).
+// Hasil selalu diurutkan berdasarkan jarak ke (Lat, Lng).
+type NearbyQueryEntity struct {
+	Lat      float64
+	Lng      float64
+	RadiusKm float64
+	Box      *GeoBoxEntity
+	Page     int64
+	Limit    int64
+	Filters  []FilterEntity
+}
+
+type GeoBoxEntity struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+type NearbyCustomerEntity struct {
+	User       UserEntity
+	DistanceKm float64
+}
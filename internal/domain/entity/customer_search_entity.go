@@ -0,0 +1,9 @@
+package entity
+
+// CustomerSearchEntity hasil pencarian customer beserta skor relevansi dan
+// field yang cocok dengan kata kunci (sudah diberi tanda <mark>...</mark>).
+type CustomerSearchEntity struct {
+	User       UserEntity
+	Score      float64
+	Highlights map[string]string
+}
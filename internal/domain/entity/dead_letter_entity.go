@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// KafkaRecordEntity pesan Kafka lengkap dengan key dan header.
+type KafkaRecordEntity struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// DeadLetterEntity pesan di topic DLQ beserta alasan gagal yang dicatat consumer.
+type DeadLetterEntity struct {
+	Topic         string            `json:"topic"`
+	Partition     int32             `json:"partition"`
+	Offset        int64             `json:"offset"`
+	Key           string            `json:"key,omitempty"`
+	Value         string            `json:"value"`
+	Headers       map[string]string `json:"headers"`
+	OriginalTopic string            `json:"original_topic"`
+	Error         string            `json:"error"`
+	Attempts      int               `json:"attempts"`
+	FailedAt      *time.Time        `json:"failed_at,omitempty"`
+	Timestamp     time.Time         `json:"timestamp"`
+}
@@ -1,15 +1,29 @@
 package entity
 
+import "time"
+
 type PublishMessage struct {
 	Email     string `json:"email"`
+	Phone     string `json:"phone,omitempty"`
 	Message   string `json:"message"`
 	UserId    int64  `json:"user_id"`
 	Subject   string `json:"subject"`
 	QueueName string `json:"queue_name"`
+	// MessageId dipakai notification service saat melaporkan status pengiriman
+	MessageId string `json:"message_id,omitempty"`
+
+	// Template nama template notifikasi yang dirender kafkaService sebelum dikirim, Data berisi
+	// variabelnya (name, link, expiry, ...). Locale kosong berarti preferensi locale user.
+	// Pesan lama di outbox tanpa Template dikirim memakai Message dan Subject apa adanya.
+	Template string            `json:"template,omitempty"`
+	Locale   string            `json:"locale,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
 }
 
 type KafkaEvent struct {
 	Name string `json:"name"`
+	// Version versi payload domain event, kosong untuk pesan notifikasi
+	Version int `json:"version,omitempty"`
 }
 
 type KafkaMetaData struct {
@@ -18,20 +32,49 @@ type KafkaMetaData struct {
 }
 
 type KafkaData struct {
-	ReceiverEmail    string `json:"receiver_email"`
-	Message          string `json:"message"`
-	ReceiverId       int64  `json:"receiver_id"`
-	Subject          string `json:"subject"`
-	NotificationType string `json:"notification_type"`
+	ReceiverEmail    string `json:"receiver_email" avro:"receiver_email"`
+	ReceiverPhone    string `json:"receiver_phone,omitempty" avro:"receiver_phone"`
+	Message          string `json:"message" avro:"message"`
+	ReceiverId       int64  `json:"receiver_id" avro:"receiver_id"`
+	Subject          string `json:"subject" avro:"subject"`
+	NotificationType string `json:"notification_type" avro:"notification_type"`
+	MessageId        string `json:"message_id,omitempty" avro:"message_id"`
+	HtmlMessage      string `json:"html_message,omitempty" avro:"html_message"`
+}
+
+// KafkaEventBody body envelope legacy, Data berisi KafkaData (notifikasi) atau data domain event.
+type KafkaEventBody struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// KafkaEventMessage envelope legacy (KAFKA_MESSAGE_FORMAT=legacy).
+type KafkaEventMessage struct {
+	Event    KafkaEvent     `json:"event"`
+	Metadata KafkaMetaData  `json:"metadata"`
+	Body     KafkaEventBody `json:"body"`
 }
 
-type KafkaBody struct {
-	Type string     `json:"type"`
-	Data *KafkaData `json:"data"`
+// KafkaOutgoingEventEntity pesan yang dikirim producer. Bentuk envelope (legacy atau
+// CloudEvents structured/binary) ditentukan adapter sesuai KAFKA_MESSAGE_FORMAT.
+type KafkaOutgoingEventEntity struct {
+	Topic       string
+	Key         string
+	ID          string
+	Type        string
+	Version     int
+	Subject     string
+	Time        time.Time
+	TraceParent string
+	Data        interface{}
 }
 
-type KafkaMessage struct {
-	Event    KafkaEvent    `json:"event"`
-	Metadata KafkaMetaData `json:"metadata"`
-	Body     KafkaBody     `json:"body"`
+// KafkaProducerMetricsEntity jumlah pesan producer sejak start. InFlight pesan yang sudah
+// masuk antrean producer async tapi belum dikonfirmasi broker.
+type KafkaProducerMetricsEntity struct {
+	Mode      string `json:"mode"`
+	Enqueued  int64  `json:"enqueued"`
+	Delivered int64  `json:"delivered"`
+	Failed    int64  `json:"failed"`
+	InFlight  int64  `json:"in_flight"`
 }
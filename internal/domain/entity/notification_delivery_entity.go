@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// NotificationDeliveryEventEntity data event notification_delivered/notification_failed dari notification service.
+type NotificationDeliveryEventEntity struct {
+	MessageID  string    `json:"message_id"`
+	UserID     int64     `json:"user_id"`
+	Channel    string    `json:"channel"`
+	Reason     string    `json:"reason"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type NotificationDeliveryEntity struct {
+	ID         int64
+	MessageID  string
+	UserID     int64
+	Channel    string
+	Status     string
+	Reason     string
+	OccurredAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"clean-architecture/utils"
+	"time"
+)
+
+const (
+	NotificationTypeEmail = "EMAIL"
+	NotificationTypePush  = "PUSH"
+	NotificationTypeSMS   = "SMS"
+)
+
+// NotificationTypeForQueue jenis notifikasi dari QueueName, selain push dan SMS dikirim sebagai email.
+func NotificationTypeForQueue(queueName string) string {
+	switch queueName {
+	case utils.PUSH_NOTIF, utils.NOTIF_PUSH_MARKETING:
+		return NotificationTypePush
+	case utils.NOTIF_SMS_PHONE_OTP, utils.NOTIF_SMS_MARKETING:
+		return NotificationTypeSMS
+	}
+	return NotificationTypeEmail
+}
+
+// NotificationEntity notifikasi yang dikirim lewat transport NOTIFICATION_TRANSPORT.
+// Tag json dipakai sebagai body transport webhook dan baris transport stdout/file.
+type NotificationEntity struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	QueueName string `json:"queue_name"`
+	UserID    int64  `json:"user_id"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Subject   string `json:"subject"`
+	Message   string `json:"message"`
+	// HTMLMessage versi HTML Message, kosong jika template tidak punya body HTML
+	HTMLMessage string    `json:"html_message,omitempty"`
+	Template    string    `json:"template,omitempty"`
+	Locale      string    `json:"locale,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RenderedNotificationEntity hasil render template, Locale adalah locale template yang
+// akhirnya dipakai setelah fallback.
+type RenderedNotificationEntity struct {
+	Locale  string
+	Subject string
+	Text    string
+	HTML    string
+}
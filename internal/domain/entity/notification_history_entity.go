@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// Status riwayat notifikasi. pending → sent/skipped/failed diisi relay outbox, sent → delivered/failed
+// diisi dari laporan pengiriman notification service.
+const (
+	NotificationStatusPending   = "pending"
+	NotificationStatusSent      = "sent"
+	NotificationStatusDelivered = "delivered"
+	NotificationStatusFailed    = "failed"
+	NotificationStatusSkipped   = "skipped"
+)
+
+// NotificationStatuses status yang boleh dipakai pada filter status riwayat notifikasi.
+var NotificationStatuses = []string{
+	NotificationStatusPending,
+	NotificationStatusSent,
+	NotificationStatusDelivered,
+	NotificationStatusFailed,
+	NotificationStatusSkipped,
+}
+
+// NotificationHistoryEntity satu notifikasi yang dikirim ke user. Recipient email atau nomor
+// telepon sesuai Type, isi pesan tidak disimpan.
+type NotificationHistoryEntity struct {
+	ID          int64
+	MessageID   string
+	ResendOfID  *int64
+	UserID      int64
+	Type        string
+	QueueName   string
+	Template    string
+	Recipient   string
+	Status      string
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+	SentAt      *time.Time
+	DeliveredAt *time.Time
+	UpdatedAt   time.Time
+}
+
+type NotificationHistoryQueryEntity struct {
+	UserID int64
+	// Status kosong berarti semua status
+	Status string
+	Page   int64
+	Limit  int64
+}
+
+// NotificationSendStatusEntity hasil percobaan kirim relay untuk satu pesan outbox.
+// Status pending berarti percobaan gagal dan pesan akan dicoba lagi.
+type NotificationSendStatusEntity struct {
+	OutboxMessageID int64
+	Status          string
+	Attempts        int
+	LastError       string
+	OccurredAt      time.Time
+}
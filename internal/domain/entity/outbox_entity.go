@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"clean-architecture/utils"
+	"time"
+)
+
+const (
+	OutboxKindNotification = "notification"
+	OutboxKindEvent        = "event"
+
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// CredentialNotificationQueues notifikasi berisi password sementara, link token atau kode OTP.
+// Isinya dihapus dari outbox setelah selesai dikirim dan notifikasinya tidak bisa dikirim ulang,
+// user harus meminta token atau kode baru.
+var CredentialNotificationQueues = []string{
+	utils.NOTIF_EMAIL_VERIFICATION,
+	utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+	utils.NOTIF_EMAIL_CREATE_CUSTOMER,
+	utils.NOTIF_EMAIL_UPDATE_CUSTOMER,
+	utils.NOTIF_SMS_PHONE_OTP,
+}
+
+// OutboxMessageEntity pesan yang menunggu dikirim relay ke Kafka. Payload berisi
+// PublishMessage (notification) atau data event (event) dalam bentuk JSON.
+type OutboxMessageEntity struct {
+	ID            int64
+	Kind          string
+	EventName     string
+	EventVersion  int
+	UserID        *int64
+	Payload       []byte
+	TraceParent   string
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+// DomainEventEntity event yang ikut ditulis ke outbox bersama perubahan datanya.
+// UserID dipakai sebagai key pesan Kafka agar urutan event per user terjaga.
+type DomainEventEntity struct {
+	// ID diisi relay dari ID outbox, dipakai consumer untuk deduplikasi
+	ID      string
+	Name    string
+	Version int
+	UserID  int64
+	Data    interface{}
+}
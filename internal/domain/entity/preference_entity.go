@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// PreferenceEntity nilai efektif satu preferensi user. Value berisi default dari schema
+// jika user belum pernah mengubahnya (IsDefault true).
+type PreferenceEntity struct {
+	Key       string
+	Type      string
+	Value     interface{}
+	Default   interface{}
+	IsDefault bool
+	UpdatedAt *time.Time
+}
+
+// StoredPreferenceEntity preferensi yang tersimpan di database, Value berupa JSON mentah.
+type StoredPreferenceEntity struct {
+	Key       string
+	Value     string
+	UpdatedAt time.Time
+}
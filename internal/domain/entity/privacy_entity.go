@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// UserDataExportEntity seluruh data pribadi user untuk export (GDPR data portability).
+// Files berisi object path di storage, isinya diambil terpisah saat ZIP dibuat.
+type UserDataExportEntity struct {
+	Profile     UserEntity
+	Roles       []RoleEntity
+	Addresses   []AddressEntity
+	Sessions    []SessionEntity
+	Tokens      []VerificationTokenEntity
+	Files       []string
+	GeneratedAt time.Time
+}
+
+type SessionEntity struct {
+	TokenHint string
+	CreatedAt string
+	ExpiresAt *time.Time
+}
+
+type DeletionRequestEntity struct {
+	ID          int64
+	UserID      int64
+	RequestedAt time.Time
+	ScheduledAt time.Time
+	CancelledAt *time.Time
+	CompletedAt *time.Time
+}
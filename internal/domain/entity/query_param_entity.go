@@ -1,9 +1,72 @@
 package entity
 
+const (
+	FilterOpEq   = "eq"
+	FilterOpNe   = "ne"
+	FilterOpGt   = "gt"
+	FilterOpGte  = "gte"
+	FilterOpLt   = "lt"
+	FilterOpLte  = "lte"
+	FilterOpLike = "like"
+	FilterOpIn   = "in"
+)
+
+// CustomerFilterFields field yang boleh dipakai pada filter[...] list customer beserta operatornya.
+var CustomerFilterFields = map[string][]string{
+	"id":          {FilterOpEq, FilterOpNe, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte, FilterOpIn},
+	"name":        {FilterOpEq, FilterOpNe, FilterOpLike, FilterOpIn},
+	"email":       {FilterOpEq, FilterOpNe, FilterOpLike, FilterOpIn},
+	"phone":       {FilterOpEq, FilterOpNe, FilterOpLike, FilterOpIn},
+	"is_verified": {FilterOpEq, FilterOpNe},
+	"created_at":  {FilterOpEq, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte},
+	"updated_at":  {FilterOpEq, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte},
+	"role":        {FilterOpEq, FilterOpNe, FilterOpIn},
+}
+
+// CustomerSortFields field yang boleh dipakai pada sort / order_by list customer.
+var CustomerSortFields = []string{"id", "name", "email", "phone", "is_verified", "created_at", "updated_at", "deleted_at"}
+
+// CustomerCursorSortFields field yang bisa dipakai untuk keyset pagination (pagination=cursor),
+// hanya kolom NOT NULL agar perbandingan (kolom, id) selalu konsisten.
+var CustomerCursorSortFields = []string{"id", "name", "email", "created_at"}
+
+// CustomerFields field yang boleh diminta lewat sparse fieldset (fields=...).
+var CustomerFields = []string{"id", "name", "email", "phone", "photo", "address", "lat", "lng", "is_verified", "created_at", "role"}
+
 type QueryStringEntity struct {
 	Search    string
 	Page      int64
 	Limit     int64
 	OrderBy   string
 	OrderType string
+	Cursor    *CursorEntity
+	Filters   []FilterEntity
+	Sorts     []SortEntity
+	Fields    []string
+}
+
+type FilterEntity struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+type SortEntity struct {
+	Field string
+	Desc  bool
+}
+
+// CursorEntity posisi terakhir pada keyset pagination: nilai kolom order & id.
+// Backward true berarti cursor dipakai untuk mengambil halaman sebelumnya.
+type CursorEntity struct {
+	OrderBy   string `json:"o"`
+	OrderType string `json:"t"`
+	Value     string `json:"v"`
+	ID        int64  `json:"i"`
+	Backward  bool   `json:"b,omitempty"`
+}
+
+type CursorPageEntity struct {
+	Next *CursorEntity
+	Prev *CursorEntity
 }
@@ -1,6 +1,10 @@
 package entity
 
+import "time"
+
 type RoleEntity struct {
-	ID   int64
-	Name string
+	ID        int64
+	Name      string
+	DeletedAt *time.Time
+	Version   int64
 }
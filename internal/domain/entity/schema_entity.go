@@ -0,0 +1,17 @@
+package entity
+
+// Tipe schema mengikuti nilai schemaType di Confluent Schema Registry.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+	SchemaTypeJSON     = "JSON"
+)
+
+// SchemaEntity schema yang terdaftar di registry. ID unik global, Version urutan per subject.
+type SchemaEntity struct {
+	ID      int
+	Subject string
+	Version int
+	Type    string
+	Schema  string
+}
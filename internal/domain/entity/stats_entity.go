@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type DashboardStatsEntity struct {
+	TotalCustomers       int64
+	VerifiedCustomers    int64
+	PendingVerifications int64
+	ActiveSessions       int64
+	SignupsPerDay        []DailyCountEntity
+	UsersPerRole         []RoleCountEntity
+	From                 time.Time
+	To                   time.Time
+	GeneratedAt          time.Time
+}
+
+type DailyCountEntity struct {
+	Date  time.Time
+	Count int64
+}
+
+type RoleCountEntity struct {
+	RoleID   int64
+	RoleName string
+	Count    int64
+}
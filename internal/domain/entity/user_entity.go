@@ -1,17 +1,28 @@
 package entity
 
+import "time"
+
 type UserEntity struct {
-	ID         int64
-	Name       string
-	Email      string
-	Password   string
-	RoleName   string
-	RoleID     int64
-	Address    string
-	Lat        string
-	Lng        string
-	Phone      string
-	Photo      string
-	IsVerified bool
-	Token      string
+	ID              int64
+	Name            string
+	Email           string
+	Password        string
+	RoleName        string
+	RoleID          int64
+	Address         string
+	Lat             *float64
+	Lng             *float64
+	Phone           string
+	Photo           string
+	IsVerified      bool
+	PhoneVerifiedAt *time.Time
+	Token           string
+	CreatedAt       time.Time
+	DeletedAt       *time.Time
+	Version         int64
+
+	// Consents versi dokumen legal yang disetujui saat sign up
+	Consents []ConsentEntity
+	// Notifications ditulis ke outbox di transaksi yang sama, UserId diisi repository
+	Notifications []PublishMessage
 }
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"clean-architecture/utils"
+	"time"
+)
+
+// Versi payload tiap domain event. Naikkan versi jika ada field yang dihapus atau berubah arti,
+// penambahan field baru tidak perlu menaikkan versi. Perubahan field wajib diikuti schema di
+// internal/adapter/outbound/kafka/schema.
+const (
+	UserRegisteredEventVersion  = 1
+	UserVerifiedEventVersion    = 1
+	UserUpdatedEventVersion     = 1
+	UserDeletedEventVersion     = 1
+	RoleAssignedEventVersion    = 1
+	PasswordChangedEventVersion = 1
+	SessionRevokedEventVersion  = 1
+)
+
+const (
+	UserRegisteredSourceSignUp = "signup"
+	UserRegisteredSourceAdmin  = "admin"
+
+	UserDeletedReasonSoftDeleted = "soft_deleted"
+	UserDeletedReasonErased      = "erased"
+
+	PasswordChangedReasonReset = "reset"
+	PasswordChangedReasonAdmin = "admin"
+
+	SessionRevokedReasonPasswordChanged = "password_changed"
+	SessionRevokedReasonUserErased      = "user_erased"
+	SessionRevokedReasonUserDeleted     = "user_deleted"
+)
+
+type UserRegisteredEventEntity struct {
+	UserID       int64     `json:"user_id" avro:"user_id"`
+	Name         string    `json:"name" avro:"name"`
+	Email        string    `json:"email" avro:"email"`
+	RoleName     string    `json:"role_name" avro:"role_name"`
+	IsVerified   bool      `json:"is_verified" avro:"is_verified"`
+	Source       string    `json:"source" avro:"source"`
+	RegisteredAt time.Time `json:"registered_at" avro:"registered_at"`
+}
+
+type UserVerifiedEventEntity struct {
+	UserID     int64     `json:"user_id" avro:"user_id"`
+	VerifiedAt time.Time `json:"verified_at" avro:"verified_at"`
+}
+
+// UserUpdatedEventEntity hanya berisi nama field yang berubah, consumer mengambil data terbaru sendiri.
+type UserUpdatedEventEntity struct {
+	UserID        int64     `json:"user_id" avro:"user_id"`
+	ChangedFields []string  `json:"changed_fields" avro:"changed_fields"`
+	UpdatedAt     time.Time `json:"updated_at" avro:"updated_at"`
+}
+
+type UserDeletedEventEntity struct {
+	UserID    int64     `json:"user_id" avro:"user_id"`
+	Reason    string    `json:"reason" avro:"reason"`
+	RequestID int64     `json:"request_id,omitempty" avro:"request_id"`
+	DeletedAt time.Time `json:"deleted_at" avro:"deleted_at"`
+}
+
+type RoleAssignedEventEntity struct {
+	UserID         int64     `json:"user_id" avro:"user_id"`
+	RoleID         int64     `json:"role_id" avro:"role_id"`
+	RoleName       string    `json:"role_name" avro:"role_name"`
+	PreviousRoleID int64     `json:"previous_role_id,omitempty" avro:"previous_role_id"`
+	AssignedAt     time.Time `json:"assigned_at" avro:"assigned_at"`
+}
+
+type PasswordChangedEventEntity struct {
+	UserID    int64     `json:"user_id" avro:"user_id"`
+	Reason    string    `json:"reason" avro:"reason"`
+	ChangedAt time.Time `json:"changed_at" avro:"changed_at"`
+}
+
+type SessionRevokedEventEntity struct {
+	UserID    int64     `json:"user_id" avro:"user_id"`
+	Reason    string    `json:"reason" avro:"reason"`
+	RevokedAt time.Time `json:"revoked_at" avro:"revoked_at"`
+}
+
+func NewUserRegisteredEvent(data UserRegisteredEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_USER_REGISTERED, Version: UserRegisteredEventVersion, UserID: data.UserID, Data: data}
+}
+
+func NewUserVerifiedEvent(data UserVerifiedEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_USER_VERIFIED, Version: UserVerifiedEventVersion, UserID: data.UserID, Data: data}
+}
+
+func NewUserUpdatedEvent(data UserUpdatedEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_USER_UPDATED, Version: UserUpdatedEventVersion, UserID: data.UserID, Data: data}
+}
+
+func NewUserDeletedEvent(data UserDeletedEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_USER_DELETED, Version: UserDeletedEventVersion, UserID: data.UserID, Data: data}
+}
+
+func NewRoleAssignedEvent(data RoleAssignedEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_ROLE_ASSIGNED, Version: RoleAssignedEventVersion, UserID: data.UserID, Data: data}
+}
+
+func NewPasswordChangedEvent(data PasswordChangedEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_PASSWORD_CHANGED, Version: PasswordChangedEventVersion, UserID: data.UserID, Data: data}
+}
+
+func NewSessionRevokedEvent(data SessionRevokedEventEntity) DomainEventEntity {
+	return DomainEventEntity{Name: utils.EVENT_SESSION_REVOKED, Version: SessionRevokedEventVersion, UserID: data.UserID, Data: data}
+}
@@ -0,0 +1,25 @@
+package entity
+
+// PatchField satu field dari dokumen JSON Merge Patch (RFC 7396).
+// Set false berarti field tidak dikirim (tidak diubah), Set true dengan Value nil
+// berarti field dikirim null (nilainya dikosongkan).
+type PatchField[T any] struct {
+	Set   bool
+	Value *T
+}
+
+type UserPatchEntity struct {
+	ID      int64
+	Version int64
+	Name    PatchField[string]
+	Email   PatchField[string]
+	Phone   PatchField[string]
+	Address PatchField[string]
+	Photo   PatchField[string]
+	Lat     PatchField[float64]
+	Lng     PatchField[float64]
+	RoleID  PatchField[int64]
+
+	// CallerID user yang mengirim patch, Photo dari folder upload miliknya disalin ke folder user ID
+	CallerID int64
+}
@@ -8,5 +8,9 @@ type VerificationTokenEntity struct {
 	Token     string
 	TokenType string
 	ExpiresAt time.Time
+	CreatedAt time.Time
 	User      UserEntity
+
+	// Notifications ditulis ke outbox di transaksi yang sama dengan token
+	Notifications []PublishMessage
 }
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/phone"
+	"context"
+	"errors"
+)
+
+type AddressServiceInterface interface {
+	GetAll(ctx context.Context, userID int64) ([]entity.AddressEntity, error)
+	GetByID(ctx context.Context, userID, addressID int64) (*entity.AddressEntity, error)
+	Create(ctx context.Context, req entity.AddressEntity) (*entity.AddressEntity, error)
+	Update(ctx context.Context, req entity.AddressEntity) (*entity.AddressEntity, error)
+	Delete(ctx context.Context, userID, addressID int64) error
+}
+
+type addressService struct {
+	repo     outbound.AddressRepositoryInterface
+	userRepo outbound.UserRepositoryInterface
+	cfg      *config.Config
+}
+
+func NewAddressService(repo outbound.AddressRepositoryInterface, userRepo outbound.UserRepositoryInterface,
+	cfg *config.Config) AddressServiceInterface {
+	return &addressService{repo: repo, userRepo: userRepo, cfg: cfg}
+}
+
+func (a *addressService) GetAll(ctx context.Context, userID int64) ([]entity.AddressEntity, error) {
+	return a.repo.GetAll(ctx, userID)
+}
+
+func (a *addressService) GetByID(ctx context.Context, userID, addressID int64) (*entity.AddressEntity, error) {
+	return a.repo.GetByID(ctx, userID, addressID)
+}
+
+func (a *addressService) Create(ctx context.Context, req entity.AddressEntity) (*entity.AddressEntity, error) {
+	// Pastikan user/customer masih ada (belum di soft delete), jika tidak repository mengembalikan "404"
+	if _, err := a.userRepo.GetCustomerByID(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := a.normalizePhone(&req); err != nil {
+		return nil, err
+	}
+
+	id, err := a.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.repo.GetByID(ctx, req.UserID, id)
+}
+
+func (a *addressService) Update(ctx context.Context, req entity.AddressEntity) (*entity.AddressEntity, error) {
+	if err := a.normalizePhone(&req); err != nil {
+		return nil, err
+	}
+
+	if err := a.repo.Update(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return a.repo.GetByID(ctx, req.UserID, req.ID)
+}
+
+func (a *addressService) Delete(ctx context.Context, userID, addressID int64) error {
+	return a.repo.Delete(ctx, userID, addressID)
+}
+
+func (a *addressService) normalizePhone(req *entity.AddressEntity) error {
+	normalized, err := phone.NormalizeE164(req.Phone, a.cfg.App.PhoneDefaultCountryCode)
+	if err != nil {
+		return errors.New("400")
+	}
+	req.Phone = normalized
+	return nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	LegalDocumentTerms   = "terms"
+	LegalDocumentPrivacy = "privacy"
+
+	consentChallengeTTL = time.Minute * 10
+)
+
+type ConsentServiceInterface interface {
+	PublishDocument(ctx context.Context, req entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error)
+	GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error)
+	GetPendingDocuments(ctx context.Context, userID int64) ([]entity.LegalDocumentEntity, error)
+	ResolveSignUpConsents(ctx context.Context, consents []entity.ConsentEntity) ([]entity.ConsentEntity, error)
+	AcceptDocuments(ctx context.Context, userID int64, documentIDs []int64, ipAddress string) error
+	CreateChallenge(ctx context.Context, userID int64) (string, error)
+	ResolveChallenge(ctx context.Context, challengeToken string) (int64, error)
+	ConsumeChallenge(ctx context.Context, challengeToken string) (int64, error)
+	GetAcceptanceReport(ctx context.Context) ([]entity.ConsentReportEntity, error)
+}
+
+type consentService struct {
+	repo  outbound.ConsentRepositoryInterface
+	redis *redis.Client
+}
+
+func NewConsentService(repo outbound.ConsentRepositoryInterface, redis *redis.Client) ConsentServiceInterface {
+	return &consentService{repo: repo, redis: redis}
+}
+
+// PublishDocument menerbitkan versi baru. Tanpa published_at versi langsung berlaku dan
+// setiap user yang belum menyetujuinya akan diminta menyetujui ulang saat login.
+func (c *consentService) PublishDocument(ctx context.Context, req entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error) {
+	if req.PublishedAt.IsZero() {
+		req.PublishedAt = time.Now()
+	}
+	return c.repo.CreateDocument(ctx, req)
+}
+
+func (c *consentService) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	return c.repo.GetCurrentDocuments(ctx)
+}
+
+// GetPendingDocuments versi yang berlaku tetapi belum disetujui user.
+func (c *consentService) GetPendingDocuments(ctx context.Context, userID int64) ([]entity.LegalDocumentEntity, error) {
+	documents, err := c.repo.GetCurrentDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	documentIDs := make([]int64, 0, len(documents))
+	for _, val := range documents {
+		documentIDs = append(documentIDs, val.ID)
+	}
+
+	acceptedIDs, err := c.repo.GetAcceptedDocumentIDs(ctx, userID, documentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []entity.LegalDocumentEntity{}
+	for _, val := range documents {
+		if !slices.Contains(acceptedIDs, val.ID) {
+			pending = append(pending, val)
+		}
+	}
+
+	return pending, nil
+}
+
+// ResolveSignUpConsents memastikan versi yang disetujui saat sign up adalah versi yang berlaku
+// untuk setiap doc_type, lalu mengisi DocumentID. "422" jika ada yang kurang atau versinya sudah usang.
+func (c *consentService) ResolveSignUpConsents(ctx context.Context, consents []entity.ConsentEntity) ([]entity.ConsentEntity, error) {
+	documents, err := c.repo.GetCurrentDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := []entity.ConsentEntity{}
+	for _, document := range documents {
+		index := slices.IndexFunc(consents, func(val entity.ConsentEntity) bool {
+			return val.DocType == document.DocType
+		})
+		if index < 0 || consents[index].Version != document.Version {
+			log.Infof("[ConsentService-1] ResolveSignUpConsents: %s version %s not accepted", document.DocType, document.Version)
+			return nil, errors.New("422")
+		}
+
+		consent := consents[index]
+		consent.DocumentID = document.ID
+		consent.AcceptedAt = time.Now()
+		resolved = append(resolved, consent)
+	}
+
+	return resolved, nil
+}
+
+// AcceptDocuments mencatat persetujuan user. Semua dokumen yang masih pending wajib ikut
+// disetujui dan ID di luar versi yang berlaku ditolak dengan "422".
+func (c *consentService) AcceptDocuments(ctx context.Context, userID int64, documentIDs []int64, ipAddress string) error {
+	pending, err := c.GetPendingDocuments(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.repo.GetCurrentDocuments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range documentIDs {
+		if !slices.ContainsFunc(current, func(val entity.LegalDocumentEntity) bool { return val.ID == id }) {
+			log.Infof("[ConsentService-1] AcceptDocuments: document %d is not a current version", id)
+			return errors.New("422")
+		}
+	}
+
+	consents := []entity.ConsentEntity{}
+	for _, val := range pending {
+		if !slices.Contains(documentIDs, val.ID) {
+			log.Infof("[ConsentService-2] AcceptDocuments: document %d not accepted", val.ID)
+			return errors.New("422")
+		}
+		consents = append(consents, entity.ConsentEntity{
+			UserID:     userID,
+			DocumentID: val.ID,
+			IPAddress:  ipAddress,
+			AcceptedAt: time.Now(),
+		})
+	}
+
+	return c.repo.CreateConsents(ctx, consents)
+}
+
+// CreateChallenge token sekali pakai yang menggantikan access token saat user harus
+// menyetujui versi terbaru sebelum boleh login.
+func (c *consentService) CreateChallenge(ctx context.Context, userID int64) (string, error) {
+	challengeToken := uuid.New().String()
+
+	if err := c.redis.Set(ctx, consentChallengeKey(challengeToken), userID, consentChallengeTTL).Err(); err != nil {
+		log.Errorf("[ConsentService-1] CreateChallenge: %v", err)
+		return "", err
+	}
+
+	return challengeToken, nil
+}
+
+// ResolveChallenge user ID pemilik challenge, "404" jika token tidak ada atau sudah kedaluwarsa.
+func (c *consentService) ResolveChallenge(ctx context.Context, challengeToken string) (int64, error) {
+	data, err := c.redis.Get(ctx, consentChallengeKey(challengeToken)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, errors.New("404")
+		}
+		log.Errorf("[ConsentService-1] ResolveChallenge: %v", err)
+		return 0, err
+	}
+
+	return strconv.ParseInt(data, 10, 64)
+}
+
+// ConsumeChallenge mengambil sekaligus menghapus challenge (GETDEL) sehingga hanya satu
+// pemanggil yang berhasil, "404" jika challenge sudah dipakai atau kedaluwarsa.
+func (c *consentService) ConsumeChallenge(ctx context.Context, challengeToken string) (int64, error) {
+	data, err := c.redis.GetDel(ctx, consentChallengeKey(challengeToken)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, errors.New("404")
+		}
+		log.Errorf("[ConsentService-1] ConsumeChallenge: %v", err)
+		return 0, err
+	}
+
+	return strconv.ParseInt(data, 10, 64)
+}
+
+func (c *consentService) GetAcceptanceReport(ctx context.Context) ([]entity.ConsentReportEntity, error) {
+	reports, err := c.repo.GetAcceptanceReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := c.repo.GetCurrentDocuments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, val := range reports {
+		reports[i].IsCurrent = slices.ContainsFunc(current, func(document entity.LegalDocumentEntity) bool {
+			return document.ID == val.Document.ID
+		})
+		if val.TotalUsers > 0 {
+			// Persentase dibulatkan 2 angka di belakang koma
+			reports[i].AcceptanceRate = math.Round(float64(val.AcceptedCount)/float64(val.TotalUsers)*10000) / 100
+		}
+	}
+
+	return reports, nil
+}
+
+func consentChallengeKey(challengeToken string) string {
+	return fmt.Sprintf("consent_challenge:%s", challengeToken)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	customerImportJobPrefix = "customer_import_job:"
+	customerImportJobTTL    = 24 * time.Hour
+
+	// progress job disimpan ke redis setiap N baris supaya tidak terlalu sering write
+	customerImportProgressEvery = 20
+
+	// customerImportJobFailedMessage detail error hanya ditulis ke log, bukan ke status job
+	customerImportJobFailedMessage = "import job failed unexpectedly"
+)
+
+type CustomerImportServiceInterface interface {
+	ImportCustomers(ctx context.Context, rows []entity.CustomerImportRowEntity, dryRun bool) (*entity.CustomerImportJobEntity, error)
+	CreateImportJob(ctx context.Context, totalRows int, dryRun bool) (*entity.CustomerImportJobEntity, error)
+	RunImportJob(ctx context.Context, jobID string, loadRows func() []entity.CustomerImportRowEntity, dryRun bool)
+	GetImportJob(ctx context.Context, jobID string) (*entity.CustomerImportJobEntity, error)
+}
+
+type customerImportService struct {
+	userService UserServiceInterface
+	redis       *redis.Client
+}
+
+func NewCustomerImportService(userService UserServiceInterface, redis *redis.Client) CustomerImportServiceInterface {
+	return &customerImportService{
+		userService: userService,
+		redis:       redis,
+	}
+}
+
+func (s *customerImportService) ImportCustomers(ctx context.Context, rows []entity.CustomerImportRowEntity, dryRun bool) (*entity.CustomerImportJobEntity, error) {
+	job := &entity.CustomerImportJobEntity{
+		Status:    utils.IMPORT_JOB_RUNNING,
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []entity.CustomerImportErrorEntity{},
+		CreatedAt: time.Now(),
+	}
+
+	s.processRows(ctx, job, rows, nil)
+
+	return job, nil
+}
+
+func (s *customerImportService) CreateImportJob(ctx context.Context, totalRows int, dryRun bool) (*entity.CustomerImportJobEntity, error) {
+	job := &entity.CustomerImportJobEntity{
+		JobID:     uuid.New().String(),
+		Status:    utils.IMPORT_JOB_PENDING,
+		DryRun:    dryRun,
+		TotalRows: totalRows,
+		Errors:    []entity.CustomerImportErrorEntity{},
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Errorf("[CustomerImportService-1] CreateImportJob: %v", err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// RunImportJob menjalankan import untuk job yang sudah dibuat lewat CreateImportJob.
+// Dipanggil dari goroutine, jadi ctx harus context yang tidak ikut selesai bersama request.
+// loadRows (parsing & validasi file) dijalankan di dalam job agar panic di sana ikut menandai job gagal.
+func (s *customerImportService) RunImportJob(ctx context.Context, jobID string, loadRows func() []entity.CustomerImportRowEntity, dryRun bool) {
+	var (
+		job = &entity.CustomerImportJobEntity{JobID: jobID, DryRun: dryRun, Errors: []entity.CustomerImportErrorEntity{}}
+		err error
+	)
+
+	// Tanpa ini job yang error atau panic tertinggal dengan status pending/running sampai key expired
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+		if err != nil {
+			log.Errorf("[CustomerImportService-1] RunImportJob: job %s failed: %v", jobID, err)
+			s.failJob(ctx, job)
+		}
+	}()
+
+	stored, err := s.GetImportJob(ctx, jobID)
+	if err != nil {
+		return
+	}
+	job = stored
+
+	job.Status = utils.IMPORT_JOB_RUNNING
+	job.DryRun = dryRun
+	if err = s.saveJob(ctx, job); err != nil {
+		return
+	}
+
+	rows := loadRows()
+	job.TotalRows = len(rows)
+
+	s.processRows(ctx, job, rows, func(job *entity.CustomerImportJobEntity) {
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Errorf("[CustomerImportService-2] RunImportJob: %v", err)
+		}
+	})
+
+	if err = s.saveJob(ctx, job); err != nil {
+		return
+	}
+
+	log.Infof("[CustomerImportService-3] RunImportJob: job %s finished (success=%d, failed=%d)", job.JobID, job.SuccessRows, job.FailedRows)
+}
+
+func (s *customerImportService) GetImportJob(ctx context.Context, jobID string) (*entity.CustomerImportJobEntity, error) {
+	data, err := s.redis.Get(ctx, customerImportJobPrefix+jobID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("404")
+		}
+		log.Errorf("[CustomerImportService-1] GetImportJob: %v", err)
+		return nil, err
+	}
+
+	job := entity.CustomerImportJobEntity{}
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		log.Errorf("[CustomerImportService-2] GetImportJob: %v", err)
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// processRows mencatat error validasi per baris, lalu membuat customer untuk baris yang valid
+// (kecuali dry run). onProgress dipanggil berkala agar status job bisa dipantau.
+func (s *customerImportService) processRows(ctx context.Context, job *entity.CustomerImportJobEntity, rows []entity.CustomerImportRowEntity,
+	onProgress func(job *entity.CustomerImportJobEntity)) {
+	for i, row := range rows {
+		switch {
+		case row.ValidationError != "":
+			job.FailedRows++
+			job.Errors = append(job.Errors, entity.CustomerImportErrorEntity{
+				Row:     row.Row,
+				Email:   row.User.Email,
+				Message: row.ValidationError,
+			})
+
+		case job.DryRun:
+			job.SuccessRows++
+
+		default:
+			if err := s.userService.CreateCustomer(ctx, row.User); err != nil {
+				job.FailedRows++
+				job.Errors = append(job.Errors, entity.CustomerImportErrorEntity{
+					Row:     row.Row,
+					Email:   row.User.Email,
+					Message: err.Error(),
+				})
+			} else {
+				job.SuccessRows++
+			}
+		}
+
+		job.ProcessedRows = i + 1
+		if onProgress != nil && job.ProcessedRows%customerImportProgressEvery == 0 {
+			onProgress(job)
+		}
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	job.Status = utils.IMPORT_JOB_COMPLETED
+}
+
+func (s *customerImportService) failJob(ctx context.Context, job *entity.CustomerImportJobEntity) {
+	finishedAt := time.Now()
+	job.Status = utils.IMPORT_JOB_FAILED
+	job.Error = customerImportJobFailedMessage
+	job.FinishedAt = &finishedAt
+
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Errorf("[CustomerImportService-1] failJob: %v", err)
+	}
+}
+
+func (s *customerImportService) saveJob(ctx context.Context, job *entity.CustomerImportJobEntity) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return s.redis.Set(ctx, customerImportJobPrefix+job.JobID, data, customerImportJobTTL).Err()
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkaretry"
+	"context"
+
+	"github.com/labstack/gommon/log"
+)
+
+type DeadLetterServiceInterface interface {
+	Inspect(ctx context.Context, topic string, limit int, all bool) ([]entity.DeadLetterEntity, error)
+	Replay(ctx context.Context, topic string, limit int) (int, error)
+}
+
+type deadLetterService struct {
+	reader   outbound.DeadLetterReaderInterface
+	producer outbound.KafkaProducerInterface
+}
+
+// NewDeadLetterService producer boleh nil jika hanya dipakai untuk Inspect.
+func NewDeadLetterService(reader outbound.DeadLetterReaderInterface, producer outbound.KafkaProducerInterface) DeadLetterServiceInterface {
+	return &deadLetterService{reader: reader, producer: producer}
+}
+
+// Inspect pesan di DLQ milik topic utama. Secara default hanya pesan yang belum di-replay,
+// all true untuk membaca seluruh isi DLQ.
+func (d *deadLetterService) Inspect(ctx context.Context, topic string, limit int, all bool) ([]entity.DeadLetterEntity, error) {
+	return d.reader.Fetch(ctx, kafkaretry.DeadLetterTopic(topic), limit, !all)
+}
+
+// Replay mengirim ulang pesan DLQ yang belum di-replay ke topic asalnya dengan header asli,
+// sehingga hitungan retry dimulai lagi dari awal. Berhenti di pesan pertama yang gagal dikirim.
+func (d *deadLetterService) Replay(ctx context.Context, topic string, limit int) (int, error) {
+	messages, err := d.reader.Fetch(ctx, kafkaretry.DeadLetterTopic(topic), limit, true)
+	if err != nil {
+		return 0, err
+	}
+
+	var replayed int
+	for _, msg := range messages {
+		target := msg.OriginalTopic
+		if target == "" {
+			target = topic
+		}
+
+		record := entity.KafkaRecordEntity{
+			Topic:   target,
+			Value:   []byte(msg.Value),
+			Headers: kafkaretry.OriginalHeaders(msg.Headers),
+		}
+		if msg.Key != "" {
+			record.Key = []byte(msg.Key)
+		}
+
+		if err := d.producer.ProduceRecord(record); err != nil {
+			log.Errorf("[DeadLetterService-1] Replay: offset %d: %v", msg.Offset, err)
+			return replayed, err
+		}
+
+		if err := d.reader.Commit(ctx, msg); err != nil {
+			log.Errorf("[DeadLetterService-2] Replay: offset %d: %v", msg.Offset, err)
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
@@ -4,73 +4,175 @@ import (
 	"clean-architecture/config"
 	"clean-architecture/internal/domain/entity"
 	"clean-architecture/internal/port/outbound"
-	"clean-architecture/utils"
+	"clean-architecture/utils/kafkadelivery"
+	"clean-architecture/utils/traceparent"
 	"context"
-	"encoding/json"
+	"errors"
+	"strconv"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
 )
 
 type KafkaServiceInterface interface {
 	PublishMessage(ctx context.Context, req entity.PublishMessage) error
+	PublishEvent(ctx context.Context, event entity.DomainEventEntity) error
 }
 
 type kafkaService struct {
-	cfg   *config.Config
-	kafka outbound.KafkaProducerInterface
+	cfg         *config.Config
+	sender      outbound.NotificationSenderInterface
+	kafka       outbound.KafkaProducerInterface
+	templates   outbound.NotificationTemplateInterface
+	preferences PreferenceServiceInterface
 }
 
-func NewKafkaService(cfg *config.Config, kafka outbound.KafkaProducerInterface) KafkaServiceInterface {
+// NewKafkaService notifikasi dikirim lewat sender (NOTIFICATION_TRANSPORT), domain event lewat kafka.
+// kafka boleh nil jika service berjalan tanpa broker, templates boleh nil jika semua notifikasi
+// sudah berisi Message, preferences boleh nil jika opt-out dan locale user tidak perlu dicek.
+func NewKafkaService(cfg *config.Config, sender outbound.NotificationSenderInterface, kafka outbound.KafkaProducerInterface,
+	templates outbound.NotificationTemplateInterface, preferences PreferenceServiceInterface) KafkaServiceInterface {
 	return &kafkaService{
-		cfg:   cfg,
-		kafka: kafka,
+		cfg:         cfg,
+		sender:      sender,
+		kafka:       kafka,
+		templates:   templates,
+		preferences: preferences,
 	}
 }
 
-func (s *kafkaService) produceToKafka(req entity.PublishMessage) error {
-	event := entity.KafkaEvent{
-		Name: "message_published",
+func (s *kafkaService) sendNotification(ctx context.Context, req entity.PublishMessage) error {
+	notification := entity.NotificationEntity{
+		ID:        req.MessageId,
+		Type:      entity.NotificationTypeForQueue(req.QueueName),
+		QueueName: req.QueueName,
+		UserID:    req.UserId,
+		Email:     req.Email,
+		Phone:     req.Phone,
+		Subject:   req.Subject,
+		Message:   req.Message,
+		CreatedAt: time.Now(),
+	}
+	if notification.ID == "" {
+		notification.ID = uuid.New().String()
 	}
 
-	metadata := entity.KafkaMetaData{
-		Sender:    "clean_architecture_service",
-		SendingAt: time.Now().Format(time.RFC3339),
+	if req.Template != "" {
+		rendered, err := s.renderTemplate(ctx, req)
+		if err != nil {
+			return err
+		}
+		notification.Template = req.Template
+		notification.Locale = rendered.Locale
+		notification.Message = rendered.Text
+		notification.HTMLMessage = rendered.HTML
+		if rendered.Subject != "" {
+			notification.Subject = rendered.Subject
+		}
 	}
 
-	notifType := "EMAIL"
-	if req.QueueName == utils.PUSH_NOTIF {
-		notifType = "PUSH"
+	return s.sender.Send(ctx, notification)
+}
+
+// renderTemplate variabel app_name selalu tersedia dari APP_NAME.
+func (s *kafkaService) renderTemplate(ctx context.Context, req entity.PublishMessage) (*entity.RenderedNotificationEntity, error) {
+	if s.templates == nil {
+		err := errors.New("notification template renderer is not configured")
+		log.Errorf("[KafkaService-4] renderTemplate: %v", err)
+		return nil, err
 	}
 
-	body := entity.KafkaBody{
-		Type: "JSON",
-		Data: &entity.KafkaData{
-			ReceiverEmail:    req.Email,
-			Message:          req.Message,
-			ReceiverId:       req.UserId,
-			Subject:          req.Subject,
-			NotificationType: notifType,
-		},
+	data := map[string]string{"app_name": s.cfg.App.AppName}
+	for key, val := range req.Data {
+		data[key] = val
 	}
 
-	kafkaMessage := entity.KafkaMessage{
-		Event:    event,
-		Metadata: metadata,
-		Body:     body,
+	rendered, err := s.templates.Render(req.Template, s.notificationLocale(ctx, req), data)
+	if err != nil {
+		log.Errorf("[KafkaService-5] renderTemplate: %s: %v", req.Template, err)
+		return nil, err
 	}
+	return rendered, nil
+}
 
-	topic := s.cfg.Kafka.Topic
-	kafkaMessageJSON, _ := json.Marshal(kafkaMessage)
-	err := s.kafka.ProduceMessage(topic, kafkaMessageJSON)
+// notificationLocale locale di pesan, lalu preferensi locale user. Kosong berarti locale default template.
+func (s *kafkaService) notificationLocale(ctx context.Context, req entity.PublishMessage) string {
+	if req.Locale != "" || s.preferences == nil || req.UserId == 0 {
+		return req.Locale
+	}
+
+	value, err := s.preferences.GetPreference(ctx, req.UserId, PreferenceLocale)
 	if err != nil {
-		return err
+		log.Errorf("[KafkaService-6] notificationLocale: %v", err)
+		return ""
 	}
-	return nil
+	locale, _ := value.(string)
+	return locale
 }
 
 func (s *kafkaService) PublishMessage(ctx context.Context, req entity.PublishMessage) error {
-	err := s.produceToKafka(req)
-	if err != nil {
-		return err
+	if s.preferences != nil {
+		// Preferensi hanya dibaca untuk notifikasi yang bisa dimatikan user, jika gagal dibaca
+		// notifikasi tidak dikirim (fail closed) dan dicoba ulang oleh relay outbox
+		enabled, err := s.preferences.IsNotificationEnabled(ctx, req.UserId, req.QueueName)
+		if err != nil {
+			log.Errorf("[KafkaService-1] PublishMessage: %v", err)
+			return err
+		}
+		if !enabled {
+			log.Infof("[KafkaService-2] PublishMessage: user %d opted out of %s, skipped", req.UserId, req.QueueName)
+			kafkadelivery.Report(ctx, kafkadelivery.ErrSkipped)
+			return nil
+		}
+	}
+
+	return s.sendNotification(ctx, req)
+}
+
+// PublishEvent mengirim domain event ke topic event (KAFKA_EVENT_TOPIC) agar service lain
+// bisa bereaksi. Key pesan adalah user ID sehingga event satu user selalu di partisi yang sama.
+// Tanpa producer Kafka dikembalikan kafkadelivery.ErrNoProducer agar event tetap pending di outbox.
+func (s *kafkaService) PublishEvent(ctx context.Context, event entity.DomainEventEntity) error {
+	if s.kafka == nil {
+		log.Errorf("[KafkaService-3] PublishEvent: no kafka producer, event %s v%d for user %d not published",
+			event.Name, event.Version, event.UserID)
+		return kafkadelivery.ErrNoProducer
 	}
-	return nil
+
+	topic := s.cfg.Kafka.EventTopic
+	if topic == "" {
+		topic = s.cfg.Kafka.Topic
+	}
+
+	outgoing := entity.KafkaOutgoingEventEntity{
+		Topic:       topic,
+		ID:          event.ID,
+		Type:        event.Name,
+		Version:     event.Version,
+		Subject:     userSubject(event.UserID),
+		Time:        time.Now(),
+		TraceParent: childTraceParent(ctx),
+		Data:        event.Data,
+	}
+	if event.UserID != 0 {
+		outgoing.Key = strconv.FormatInt(event.UserID, 10)
+	}
+	if outgoing.ID == "" {
+		outgoing.ID = uuid.New().String()
+	}
+
+	return s.kafka.ProduceEvent(ctx, outgoing)
+}
+
+func userSubject(userID int64) string {
+	if userID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(userID, 10)
+}
+
+// childTraceParent span baru di bawah traceparent pada ctx, atau trace baru jika tidak ada.
+func childTraceParent(ctx context.Context) string {
+	return traceparent.Child(traceparent.FromContext(ctx))
 }
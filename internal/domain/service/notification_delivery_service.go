@@ -0,0 +1,66 @@
+package service
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+type NotificationDeliveryServiceInterface interface {
+	RecordDelivered(ctx context.Context, event entity.NotificationDeliveryEventEntity) error
+	RecordFailed(ctx context.Context, event entity.NotificationDeliveryEventEntity) error
+}
+
+type notificationDeliveryService struct {
+	repo          outbound.NotificationDeliveryRepositoryInterface
+	notifications outbound.NotificationRepositoryInterface
+}
+
+func NewNotificationDeliveryService(repo outbound.NotificationDeliveryRepositoryInterface,
+	notifications outbound.NotificationRepositoryInterface) NotificationDeliveryServiceInterface {
+	return &notificationDeliveryService{
+		repo:          repo,
+		notifications: notifications,
+	}
+}
+
+func (n *notificationDeliveryService) RecordDelivered(ctx context.Context, event entity.NotificationDeliveryEventEntity) error {
+	return n.record(ctx, event, utils.DELIVERY_STATUS_DELIVERED)
+}
+
+func (n *notificationDeliveryService) RecordFailed(ctx context.Context, event entity.NotificationDeliveryEventEntity) error {
+	return n.record(ctx, event, utils.DELIVERY_STATUS_FAILED)
+}
+
+// record "422" jika event tidak punya message_id sehingga tidak bisa dikaitkan ke notifikasi mana pun.
+// Status juga diperbarui di riwayat notifikasi user.
+func (n *notificationDeliveryService) record(ctx context.Context, event entity.NotificationDeliveryEventEntity, status string) error {
+	if event.MessageID == "" {
+		log.Infof("[NotificationDeliveryService-1] record: event %s without message_id", status)
+		return errors.New("422")
+	}
+
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	delivery := entity.NotificationDeliveryEntity{
+		MessageID:  event.MessageID,
+		UserID:     event.UserID,
+		Channel:    event.Channel,
+		Status:     status,
+		Reason:     event.Reason,
+		OccurredAt: occurredAt,
+	}
+	if err := n.repo.UpsertStatus(ctx, delivery); err != nil {
+		return err
+	}
+
+	return n.notifications.UpdateDeliveryStatus(ctx, delivery)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/labstack/gommon/log"
+)
+
+type NotificationServiceInterface interface {
+	GetAll(ctx context.Context, query entity.NotificationHistoryQueryEntity) ([]entity.NotificationHistoryEntity, int64, int64, error)
+	Resend(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error)
+}
+
+type notificationService struct {
+	repo outbound.NotificationRepositoryInterface
+}
+
+func NewNotificationService(repo outbound.NotificationRepositoryInterface) NotificationServiceInterface {
+	return &notificationService{repo: repo}
+}
+
+// GetAll "400" jika filter status tidak dikenal.
+func (n *notificationService) GetAll(ctx context.Context, query entity.NotificationHistoryQueryEntity) ([]entity.NotificationHistoryEntity, int64, int64, error) {
+	if query.Status != "" && !slices.Contains(entity.NotificationStatuses, query.Status) {
+		log.Infof("[NotificationService-1] GetAll: unknown status %q", query.Status)
+		return nil, 0, 0, errors.New("400")
+	}
+	return n.repo.GetAll(ctx, query)
+}
+
+// Resend pesan dikirim ulang lewat outbox seperti notifikasi baru, riwayatnya menunjuk ke notifikasi asal.
+func (n *notificationService) Resend(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error) {
+	return n.repo.Resend(ctx, id)
+}
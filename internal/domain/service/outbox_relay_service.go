@@ -0,0 +1,264 @@
+package service
+
+import (
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkadelivery"
+	"clean-architecture/utils/traceparent"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	outboxDefaultBatchSize   = 100
+	outboxDefaultInterval    = time.Second
+	outboxDefaultMaxAttempts = 10
+	outboxClaimLease         = time.Minute
+	outboxRetryBaseDelay     = time.Second * 5
+	outboxRetryMaxDelay      = time.Minute * 10
+)
+
+type OutboxRelayServiceInterface interface {
+	// RelayPending mengirim satu batch pesan pending, mengembalikan jumlah yang berhasil terkirim.
+	RelayPending(ctx context.Context) (int, error)
+	// Run menjalankan RelayPending berkala sampai ctx dibatalkan.
+	Run(ctx context.Context)
+}
+
+type outboxRelayService struct {
+	repo          outbound.OutboxRepositoryInterface
+	notifications outbound.NotificationRepositoryInterface
+	publisher     KafkaServiceInterface
+	cfg           *config.Config
+}
+
+// NewOutboxRelayService notifications boleh nil jika status riwayat notifikasi tidak perlu diperbarui.
+func NewOutboxRelayService(repo outbound.OutboxRepositoryInterface, notifications outbound.NotificationRepositoryInterface,
+	publisher KafkaServiceInterface, cfg *config.Config) OutboxRelayServiceInterface {
+	return &outboxRelayService{
+		repo:          repo,
+		notifications: notifications,
+		publisher:     publisher,
+		cfg:           cfg,
+	}
+}
+
+func (o *outboxRelayService) Run(ctx context.Context) {
+	interval := time.Duration(o.cfg.Kafka.OutboxIntervalInMS) * time.Millisecond
+	if interval <= 0 {
+		interval = outboxDefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Infof("[OutboxRelayService-1] Run: relay started, interval %s", interval)
+	for {
+		// Batch penuh berarti masih ada antrean, langsung lanjut tanpa menunggu ticker
+		for {
+			sent, err := o.RelayPending(ctx)
+			if err != nil {
+				log.Errorf("[OutboxRelayService-2] Run: %v", err)
+				break
+			}
+			if sent < o.batchSize() || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Infof("[OutboxRelayService-3] Run: relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending pesan yang gagal dijadwalkan ulang dengan exponential backoff, setelah
+// KAFKA_OUTBOX_MAX_ATTEMPTS percobaan statusnya menjadi failed dan tidak diambil lagi. Event yang
+// belum bisa dikirim karena tidak ada producer Kafka tetap pending dan tidak dihitung sebagai percobaan.
+// Pesan ditandai sent setelah broker ack (kafkadelivery.Callback), sehingga pada producer async
+// satu batch dikirim bersamaan dan RelayPending menunggu semua hasilnya. Pesan yang di-skip
+// (kafkadelivery.ErrSkipped) juga ditandai sent di outbox, riwayat notifikasinya menjadi skipped.
+// Hasil ditunggu paling lama KAFKA_OUTBOX_DELIVERY_TIMEOUT_IN_MS, pesan yang belum dilaporkan
+// (sender tidak memanggil callback) diambil ulang setelah lease claim habis.
+func (o *outboxRelayService) RelayPending(ctx context.Context) (int, error) {
+	// Pesan yang sudah di-claim tetap diproses sampai selesai walaupun ctx dibatalkan saat shutdown
+	ctx = context.WithoutCancel(ctx)
+
+	messages, err := o.repo.ClaimPending(ctx, time.Now(), o.batchSize(), outboxClaimLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		sent    atomic.Int64
+		pending atomic.Int64
+		wg      sync.WaitGroup
+	)
+	for _, message := range messages {
+		wg.Add(1)
+		pending.Add(1)
+		delivered := func(err error) {
+			defer wg.Done()
+			defer pending.Add(-1)
+			skipped := errors.Is(err, kafkadelivery.ErrSkipped)
+			if err != nil && !skipped {
+				o.handleFailure(ctx, message, err)
+				return
+			}
+
+			sentAt := time.Now()
+			if err := o.repo.MarkSent(ctx, message.ID, sentAt); err != nil {
+				// Pesan akan dikirim ulang setelah lease habis, consumer harus idempotent
+				log.Errorf("[OutboxRelayService-1] RelayPending: message %d: %v", message.ID, err)
+				return
+			}
+			sent.Add(1)
+
+			status := entity.NotificationStatusSent
+			if skipped {
+				status = entity.NotificationStatusSkipped
+			}
+			o.updateNotificationStatus(ctx, message, status, "", sentAt)
+		}
+
+		// Callback tidak dipanggil jika publish langsung gagal
+		if err := o.publish(kafkadelivery.NewContext(ctx, delivered), message); err != nil {
+			delivered(err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(o.deliveryTimeout()):
+		log.Errorf("[OutboxRelayService-2] RelayPending: %d messages not reported after %s", pending.Load(), o.deliveryTimeout())
+	}
+
+	return int(sent.Load()), nil
+}
+
+func (o *outboxRelayService) publish(ctx context.Context, message entity.OutboxMessageEntity) error {
+	// Trace request yang menulis pesan diteruskan ke header Kafka
+	if message.TraceParent != "" {
+		ctx = traceparent.NewContext(ctx, message.TraceParent)
+	}
+
+	switch message.Kind {
+	case entity.OutboxKindNotification:
+		publishMessage := entity.PublishMessage{}
+		if err := json.Unmarshal(message.Payload, &publishMessage); err != nil {
+			return err
+		}
+		if publishMessage.MessageId == "" {
+			publishMessage.MessageId = strconv.FormatInt(message.ID, 10)
+		}
+		return o.publisher.PublishMessage(ctx, publishMessage)
+	case entity.OutboxKindEvent:
+		event := entity.DomainEventEntity{
+			ID:      strconv.FormatInt(message.ID, 10),
+			Name:    message.EventName,
+			Version: message.EventVersion,
+			Data:    json.RawMessage(message.Payload),
+		}
+		if message.UserID != nil {
+			event.UserID = *message.UserID
+		}
+		return o.publisher.PublishEvent(ctx, event)
+	default:
+		return fmt.Errorf("unknown outbox kind %q", message.Kind)
+	}
+}
+
+func (o *outboxRelayService) handleFailure(ctx context.Context, message entity.OutboxMessageEntity, cause error) {
+	// Tanpa producer pesan belum pernah dicoba dikirim, tetap pending sampai producer tersedia
+	if errors.Is(cause, kafkadelivery.ErrNoProducer) {
+		if err := o.repo.Release(ctx, message.ID, cause.Error(), time.Now().Add(outboxRetryMaxDelay)); err != nil {
+			log.Errorf("[OutboxRelayService-1] handleFailure: %v", err)
+		}
+		return
+	}
+
+	maxAttempts := o.cfg.Kafka.OutboxMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = outboxDefaultMaxAttempts
+	}
+
+	if message.Attempts >= maxAttempts {
+		log.Errorf("[OutboxRelayService-2] handleFailure: message %d failed after %d attempts: %v", message.ID, message.Attempts, cause)
+		if err := o.repo.MarkFailed(ctx, message.ID, cause.Error()); err != nil {
+			log.Errorf("[OutboxRelayService-3] handleFailure: %v", err)
+			return
+		}
+		o.updateNotificationStatus(ctx, message, entity.NotificationStatusFailed, cause.Error(), time.Now())
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(outboxRetryDelay(message.Attempts))
+	log.Infof("[OutboxRelayService-4] handleFailure: message %d attempt %d failed, retry at %s: %v",
+		message.ID, message.Attempts, nextAttemptAt.Format(time.RFC3339), cause)
+	if err := o.repo.MarkRetry(ctx, message.ID, cause.Error(), nextAttemptAt); err != nil {
+		log.Errorf("[OutboxRelayService-5] handleFailure: %v", err)
+		return
+	}
+	o.updateNotificationStatus(ctx, message, entity.NotificationStatusPending, cause.Error(), time.Now())
+}
+
+// updateNotificationStatus status pending berarti percobaan gagal dan akan dicoba lagi, hanya
+// jumlah percobaan dan error terakhir yang diperbarui. Gagal memperbarui riwayat tidak membuat
+// pesan dikirim ulang.
+func (o *outboxRelayService) updateNotificationStatus(ctx context.Context, message entity.OutboxMessageEntity,
+	status, lastError string, occurredAt time.Time) {
+	if o.notifications == nil || message.Kind != entity.OutboxKindNotification {
+		return
+	}
+
+	if err := o.notifications.UpdateSendStatus(ctx, entity.NotificationSendStatusEntity{
+		OutboxMessageID: message.ID,
+		Status:          status,
+		Attempts:        message.Attempts,
+		LastError:       lastError,
+		OccurredAt:      occurredAt,
+	}); err != nil {
+		log.Errorf("[OutboxRelayService-1] updateNotificationStatus: message %d: %v", message.ID, err)
+	}
+}
+
+func (o *outboxRelayService) deliveryTimeout() time.Duration {
+	if o.cfg.Kafka.OutboxDeliveryTimeoutInMS > 0 {
+		return time.Duration(o.cfg.Kafka.OutboxDeliveryTimeoutInMS) * time.Millisecond
+	}
+	return outboxClaimLease
+}
+
+func (o *outboxRelayService) batchSize() int {
+	if o.cfg.Kafka.OutboxBatchSize > 0 {
+		return o.cfg.Kafka.OutboxBatchSize
+	}
+	return outboxDefaultBatchSize
+}
+
+// outboxRetryDelay 5s, 10s, 20s, ... maksimal 10 menit.
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxRetryMaxDelay)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"clean-architecture/utils/phone"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	phoneOTPLength      = 6
+	phoneOTPTTL         = 5 * time.Minute
+	phoneOTPCooldown    = time.Minute
+	phoneOTPMaxAttempts = 5
+	phoneOTPMaxSends    = 5
+	// phoneOTPWindow batas percobaan dan pengiriman dihitung per nomor dalam window ini,
+	// tidak di-reset saat OTP dikirim ulang
+	phoneOTPWindow = time.Hour
+)
+
+type PhoneVerificationServiceInterface interface {
+	SendOTP(ctx context.Context, userID int64, rawPhone string) (string, error)
+	VerifyOTP(ctx context.Context, userID int64, otp string) error
+}
+
+type phoneVerificationService struct {
+	repo          outbound.UserRepositoryInterface
+	cfg           *config.Config
+	notifications outbound.NotificationRepositoryInterface
+	redis         *redis.Client
+}
+
+func NewPhoneVerificationService(repo outbound.UserRepositoryInterface, cfg *config.Config,
+	notifications outbound.NotificationRepositoryInterface, redis *redis.Client) PhoneVerificationServiceInterface {
+	return &phoneVerificationService{
+		repo:          repo,
+		cfg:           cfg,
+		notifications: notifications,
+		redis:         redis,
+	}
+}
+
+// SendOTP mengirim OTP lewat SMS ke nomor (format E.164) yang akan diverifikasi.
+// OTP disimpan di Redis dalam bentuk hash, bukan plain text. Error:
+// "400" nomor tidak valid, "409" nomor sudah diverifikasi user lain, "429" kirim ulang terlalu cepat
+// atau batas pengiriman/percobaan untuk nomor ini sudah habis.
+func (p *phoneVerificationService) SendOTP(ctx context.Context, userID int64, rawPhone string) (string, error) {
+	normalized, err := phone.NormalizeE164(rawPhone, p.cfg.App.PhoneDefaultCountryCode)
+	if err != nil {
+		log.Infof("[PhoneVerificationService-1] SendOTP: %v", err)
+		return "", errors.New("400")
+	}
+
+	owner, err := p.repo.GetUserByVerifiedPhone(ctx, normalized)
+	if err != nil && err.Error() != "404" {
+		log.Errorf("[PhoneVerificationService-2] SendOTP: %v", err)
+		return "", err
+	}
+	if owner != nil && owner.ID != userID {
+		log.Infof("[PhoneVerificationService-3] SendOTP: Phone already verified by another user")
+		return "", errors.New("409")
+	}
+
+	attempts, err := p.redis.Get(ctx, phoneOTPAttemptsKey(normalized)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Errorf("[PhoneVerificationService-4] SendOTP: %v", err)
+		return "", err
+	}
+	if attempts >= phoneOTPMaxAttempts {
+		log.Infof("[PhoneVerificationService-5] SendOTP: No attempts left for phone %s", normalized)
+		return "", errors.New("429")
+	}
+
+	allowed, err := p.redis.SetNX(ctx, phoneOTPCooldownKey(userID), 1, phoneOTPCooldown).Result()
+	if err != nil {
+		log.Errorf("[PhoneVerificationService-6] SendOTP: %v", err)
+		return "", err
+	}
+	if !allowed {
+		return "", errors.New("429")
+	}
+
+	sendsKey := phoneOTPSendsKey(normalized)
+	sends, err := p.countInWindow(ctx, sendsKey)
+	if err != nil {
+		log.Errorf("[PhoneVerificationService-7] SendOTP: %v", err)
+		return "", err
+	}
+	if sends > phoneOTPMaxSends {
+		log.Infof("[PhoneVerificationService-8] SendOTP: Send limit reached for phone %s", normalized)
+		return "", errors.New("429")
+	}
+
+	otp, err := generateOTP(phoneOTPLength)
+	if err != nil {
+		log.Errorf("[PhoneVerificationService-9] SendOTP: %v", err)
+		return "", err
+	}
+
+	key := phoneOTPKey(userID)
+	_, err = p.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"phone": normalized,
+			"hash":  p.hashOTP(userID, normalized, otp),
+		})
+		pipe.Expire(ctx, key, phoneOTPTTL)
+		return nil
+	})
+	if err != nil {
+		log.Errorf("[PhoneVerificationService-10] SendOTP: %v", err)
+		return "", err
+	}
+
+	publishMessage := entity.PublishMessage{
+		Phone:     normalized,
+		UserId:    userID,
+		QueueName: utils.NOTIF_SMS_PHONE_OTP,
+		Template:  utils.NOTIF_SMS_PHONE_OTP,
+		Data:      map[string]string{"code": otp, "expiry": strconv.Itoa(int(phoneOTPTTL.Minutes()))},
+	}
+
+	// Dikirim lewat outbox agar tercatat di riwayat notifikasi dan dicoba ulang jika transport gagal
+	if _, err := p.notifications.Create(ctx, publishMessage); err != nil {
+		log.Errorf("[PhoneVerificationService-11] SendOTP: %v", err)
+		p.redis.Del(ctx, key, phoneOTPCooldownKey(userID))
+		p.redis.IncrBy(ctx, sendsKey, -1)
+		return "", err
+	}
+
+	return normalized, nil
+}
+
+// VerifyOTP mencocokkan OTP dan menandai nomor sebagai terverifikasi. Error:
+// "404" tidak ada OTP aktif, "422" OTP salah, "429" percobaan untuk nomor ini melebihi batas
+// (OTP dihapus), "409" nomor sudah diverifikasi user lain.
+func (p *phoneVerificationService) VerifyOTP(ctx context.Context, userID int64, otp string) error {
+	key := phoneOTPKey(userID)
+
+	data, err := p.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		log.Errorf("[PhoneVerificationService-1] VerifyOTP: %v", err)
+		return err
+	}
+	if data["hash"] == "" {
+		return errors.New("404")
+	}
+
+	// Counter per nomor dinaikkan dulu (atomic) agar request paralel tetap terhitung
+	attempts, err := p.countInWindow(ctx, phoneOTPAttemptsKey(data["phone"]))
+	if err != nil {
+		log.Errorf("[PhoneVerificationService-2] VerifyOTP: %v", err)
+		return err
+	}
+	if attempts > phoneOTPMaxAttempts {
+		p.redis.Del(ctx, key)
+		log.Infof("[PhoneVerificationService-3] VerifyOTP: Too many attempts for user %d", userID)
+		return errors.New("429")
+	}
+
+	expected := p.hashOTP(userID, data["phone"], otp)
+	if !hmac.Equal([]byte(expected), []byte(data["hash"])) {
+		return errors.New("422")
+	}
+
+	if err := p.repo.UpdatePhoneVerified(ctx, userID, data["phone"]); err != nil {
+		return err
+	}
+
+	p.redis.Del(ctx, key, phoneOTPCooldownKey(userID), phoneOTPAttemptsKey(data["phone"]), phoneOTPSendsKey(data["phone"]))
+	return nil
+}
+
+// countInWindow menaikkan counter dan mengembalikan nilainya. TTL hanya dipasang saat counter
+// dibuat sehingga window tidak bergeser setiap kali counter dinaikkan.
+func (p *phoneVerificationService) countInWindow(ctx context.Context, key string) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := p.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetNX(ctx, key, 0, phoneOTPWindow)
+		incr = pipe.Incr(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// hashOTP HMAC-SHA256 dengan secret aplikasi, terikat ke user & nomor telepon.
+func (p *phoneVerificationService) hashOTP(userID int64, phoneNumber, otp string) string {
+	mac := hmac.New(sha256.New, []byte(p.cfg.App.JwtSecretKey))
+	mac.Write([]byte(strconv.FormatInt(userID, 10) + ":" + phoneNumber + ":" + otp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateOTP(length int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(length)), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", length, n), nil
+}
+
+func phoneOTPKey(userID int64) string {
+	return fmt.Sprintf("phone_otp:%d", userID)
+}
+
+func phoneOTPCooldownKey(userID int64) string {
+	return fmt.Sprintf("phone_otp_cooldown:%d", userID)
+}
+
+func phoneOTPAttemptsKey(phoneNumber string) string {
+	return "phone_otp_attempts:" + phoneNumber
+}
+
+func phoneOTPSendsKey(phoneNumber string) string {
+	return "phone_otp_sends:" + phoneNumber
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	// Database zona waktu ikut di-embed agar validasi timezone tidak bergantung pada OS
+	_ "time/tzdata"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	PreferenceTypeString = "string"
+	PreferenceTypeBool   = "bool"
+
+	PreferenceLocale           = "locale"
+	PreferenceTimezone         = "timezone"
+	PreferenceMarketingConsent = "marketing_consent"
+)
+
+type preferenceDefinition struct {
+	Type     string
+	Default  interface{}
+	Validate func(value interface{}) error
+}
+
+// preferenceSchema daftar key yang boleh disimpan beserta tipe, default dan validasinya.
+// Notifikasi keamanan (verifikasi email, reset password, OTP) tidak bisa dimatikan sehingga tidak ada di sini.
+var preferenceSchema = map[string]preferenceDefinition{
+	PreferenceLocale: {
+		Type:    PreferenceTypeString,
+		Default: "id",
+		Validate: func(value interface{}) error {
+			if !slices.Contains([]string{"id", "en"}, value.(string)) {
+				return errors.New("locale must be one of id, en")
+			}
+			return nil
+		},
+	},
+	PreferenceTimezone: {
+		Type:    PreferenceTypeString,
+		Default: "Asia/Jakarta",
+		Validate: func(value interface{}) error {
+			if _, err := time.LoadLocation(value.(string)); err != nil || value.(string) == "" {
+				return errors.New("timezone must be a valid IANA time zone")
+			}
+			return nil
+		},
+	},
+	PreferenceMarketingConsent:      {Type: PreferenceTypeBool, Default: false},
+	"notifications.email.marketing": {Type: PreferenceTypeBool, Default: false},
+	"notifications.sms.marketing":   {Type: PreferenceTypeBool, Default: false},
+	"notifications.push.general":    {Type: PreferenceTypeBool, Default: true},
+	"notifications.push.marketing":  {Type: PreferenceTypeBool, Default: false},
+}
+
+// notificationPreferenceKeys key preferensi yang menentukan apakah jenis notifikasi (QueueName) boleh dikirim.
+// Email akun (create/update customer) berisi password sementara sehingga tidak bisa dimatikan.
+var notificationPreferenceKeys = map[string]string{
+	utils.NOTIF_EMAIL_MARKETING: "notifications.email.marketing",
+	utils.NOTIF_SMS_MARKETING:   "notifications.sms.marketing",
+	utils.PUSH_NOTIF:            "notifications.push.general",
+	utils.NOTIF_PUSH_MARKETING:  "notifications.push.marketing",
+}
+
+// marketingQueues notifikasi marketing butuh persetujuan marketing_consent selain opt-in per channel.
+var marketingQueues = []string{utils.NOTIF_EMAIL_MARKETING, utils.NOTIF_SMS_MARKETING, utils.NOTIF_PUSH_MARKETING}
+
+type PreferenceServiceInterface interface {
+	GetPreferences(ctx context.Context, userID int64) ([]entity.PreferenceEntity, error)
+	GetCustomerPreferences(ctx context.Context, customerID int64) ([]entity.PreferenceEntity, error)
+	UpdatePreferences(ctx context.Context, userID int64, changes map[string]interface{}) ([]entity.PreferenceEntity, error)
+	GetPreference(ctx context.Context, userID int64, key string) (interface{}, error)
+	IsNotificationEnabled(ctx context.Context, userID int64, queueName string) (bool, error)
+}
+
+type preferenceService struct {
+	repo     outbound.PreferenceRepositoryInterface
+	userRepo outbound.UserRepositoryInterface
+}
+
+func NewPreferenceService(repo outbound.PreferenceRepositoryInterface, userRepo outbound.UserRepositoryInterface) PreferenceServiceInterface {
+	return &preferenceService{repo: repo, userRepo: userRepo}
+}
+
+func (p *preferenceService) GetPreferences(ctx context.Context, userID int64) ([]entity.PreferenceEntity, error) {
+	stored, err := p.repo.GetAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	storedByKey := map[string]entity.StoredPreferenceEntity{}
+	for _, val := range stored {
+		storedByKey[val.Key] = val
+	}
+
+	keys := make([]string, 0, len(preferenceSchema))
+	for key := range preferenceSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	preferences := make([]entity.PreferenceEntity, 0, len(keys))
+	for _, key := range keys {
+		definition := preferenceSchema[key]
+		preference := entity.PreferenceEntity{
+			Key:       key,
+			Type:      definition.Type,
+			Value:     definition.Default,
+			Default:   definition.Default,
+			IsDefault: true,
+		}
+
+		if val, ok := storedByKey[key]; ok {
+			value, err := decodePreference(definition, val.Value)
+			if err != nil {
+				// Nilai lama yang tidak sesuai schema lagi diabaikan dan kembali ke default
+				log.Errorf("[PreferenceService-1] GetPreferences: key %s: %v", key, err)
+			} else {
+				updatedAt := val.UpdatedAt
+				preference.Value = value
+				preference.IsDefault = false
+				preference.UpdatedAt = &updatedAt
+			}
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, nil
+}
+
+func (p *preferenceService) GetCustomerPreferences(ctx context.Context, customerID int64) ([]entity.PreferenceEntity, error) {
+	if _, err := p.userRepo.GetCustomerByID(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	return p.GetPreferences(ctx, customerID)
+}
+
+// UpdatePreferences menyimpan perubahan dengan semantik merge patch: key yang tidak dikirim
+// tidak berubah dan nilai nil mengembalikan key ke default. "422" jika key atau nilai tidak valid.
+func (p *preferenceService) UpdatePreferences(ctx context.Context, userID int64, changes map[string]interface{}) ([]entity.PreferenceEntity, error) {
+	var (
+		values    = map[string]string{}
+		resetKeys []string
+	)
+
+	for key, value := range changes {
+		definition, ok := preferenceSchema[key]
+		if !ok {
+			log.Infof("[PreferenceService-1] UpdatePreferences: unknown key %s", key)
+			return nil, errors.New("422")
+		}
+
+		if value == nil {
+			resetKeys = append(resetKeys, key)
+			continue
+		}
+
+		if err := validatePreference(definition, value); err != nil {
+			log.Infof("[PreferenceService-2] UpdatePreferences: key %s: %v", key, err)
+			return nil, errors.New("422")
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = string(encoded)
+	}
+
+	if err := p.repo.Save(ctx, userID, values, resetKeys); err != nil {
+		return nil, err
+	}
+
+	return p.GetPreferences(ctx, userID)
+}
+
+// GetPreference nilai efektif satu key, default dari schema jika belum pernah diubah.
+func (p *preferenceService) GetPreference(ctx context.Context, userID int64, key string) (interface{}, error) {
+	definition, ok := preferenceSchema[key]
+	if !ok {
+		return nil, fmt.Errorf("unknown preference key %s", key)
+	}
+
+	stored, err := p.repo.GetByKey(ctx, userID, key)
+	if err != nil {
+		if err.Error() == "404" {
+			return definition.Default, nil
+		}
+		return nil, err
+	}
+
+	value, err := decodePreference(definition, stored.Value)
+	if err != nil {
+		return definition.Default, nil
+	}
+	return value, nil
+}
+
+// IsNotificationEnabled dipakai kafkaService sebelum publish. Jenis notifikasi yang tidak
+// punya key preferensi (notifikasi keamanan) selalu dikirim.
+func (p *preferenceService) IsNotificationEnabled(ctx context.Context, userID int64, queueName string) (bool, error) {
+	key, ok := notificationPreferenceKeys[queueName]
+	if !ok || userID == 0 {
+		return true, nil
+	}
+
+	if slices.Contains(marketingQueues, queueName) {
+		consent, err := p.GetPreference(ctx, userID, PreferenceMarketingConsent)
+		if err != nil {
+			return false, err
+		}
+		if consent != true {
+			return false, nil
+		}
+	}
+
+	enabled, err := p.GetPreference(ctx, userID, key)
+	if err != nil {
+		return false, err
+	}
+	return enabled == true, nil
+}
+
+func validatePreference(definition preferenceDefinition, value interface{}) error {
+	switch definition.Type {
+	case PreferenceTypeString:
+		if _, ok := value.(string); !ok {
+			return errors.New("value must be a string")
+		}
+	case PreferenceTypeBool:
+		if _, ok := value.(bool); !ok {
+			return errors.New("value must be a boolean")
+		}
+	}
+
+	if definition.Validate != nil {
+		return definition.Validate(value)
+	}
+	return nil
+}
+
+func decodePreference(definition preferenceDefinition, raw string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, err
+	}
+
+	if err := validatePreference(definition, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/log"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	accountDeletionDefaultGraceDays = 30
+	userSessionTTL                  = time.Hour * 23
+	sessionTokenHintLength          = 8
+)
+
+type PrivacyServiceInterface interface {
+	GetUserDataExport(ctx context.Context, userID int64) (*entity.UserDataExportEntity, error)
+	OpenUserFile(ctx context.Context, path string) (io.ReadCloser, error)
+	RequestDeletion(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error)
+	GetDeletionRequest(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error)
+	CancelDeletion(ctx context.Context, userID int64) error
+	ProcessDueDeletions(ctx context.Context, now time.Time, limit int) (int, error)
+}
+
+type privacyService struct {
+	repo        outbound.PrivacyRepositoryInterface
+	userRepo    outbound.UserRepositoryInterface
+	addressRepo outbound.AddressRepositoryInterface
+	storage     outbound.MinioInterface
+	redis       *redis.Client
+	cfg         *config.Config
+}
+
+func NewPrivacyService(repo outbound.PrivacyRepositoryInterface, userRepo outbound.UserRepositoryInterface,
+	addressRepo outbound.AddressRepositoryInterface, storage outbound.MinioInterface, redis *redis.Client,
+	cfg *config.Config) PrivacyServiceInterface {
+	return &privacyService{
+		repo:        repo,
+		userRepo:    userRepo,
+		addressRepo: addressRepo,
+		storage:     storage,
+		redis:       redis,
+		cfg:         cfg,
+	}
+}
+
+// GetUserDataExport mengumpulkan seluruh data pribadi user. Isi file di storage tidak ikut
+// dimuat ke memory, handler membacanya satu per satu lewat OpenUserFile saat menulis ZIP.
+func (p *privacyService) GetUserDataExport(ctx context.Context, userID int64) (*entity.UserDataExportEntity, error) {
+	user, err := p.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := p.repo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := p.addressRepo.GetAll(ctx, userID)
+	if err != nil && err.Error() != "404" {
+		return nil, err
+	}
+
+	tokens, err := p.repo.GetVerificationTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	generatedAt := time.Now()
+
+	sessions, err := p.getSessions(ctx, userID)
+	if err != nil {
+		log.Errorf("[PrivacyService-1] GetUserDataExport: %v", err)
+		return nil, err
+	}
+
+	files, err := p.userObjectPaths(ctx, userID, user.Photo)
+	if err != nil {
+		log.Errorf("[PrivacyService-2] GetUserDataExport: %v", err)
+		return nil, err
+	}
+
+	return &entity.UserDataExportEntity{
+		Profile:     *user,
+		Roles:       roles,
+		Addresses:   addresses,
+		Sessions:    sessions,
+		Tokens:      expiredTokens(tokens, generatedAt),
+		Files:       files,
+		GeneratedAt: generatedAt,
+	}, nil
+}
+
+func (p *privacyService) OpenUserFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	return p.storage.GetObject(ctx, path)
+}
+
+// RequestDeletion menjadwalkan penghapusan akun setelah masa tenggang (ACCOUNT_DELETION_GRACE_DAYS).
+// Selama masa tenggang user masih bisa login dan membatalkan permintaan. "409" jika sudah ada permintaan.
+func (p *privacyService) RequestDeletion(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error) {
+	graceDays := p.cfg.App.AccountDeletionGraceDays
+	if graceDays <= 0 {
+		graceDays = accountDeletionDefaultGraceDays
+	}
+
+	return p.repo.CreateDeletionRequest(ctx, userID, time.Now().AddDate(0, 0, graceDays))
+}
+
+func (p *privacyService) GetDeletionRequest(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error) {
+	return p.repo.GetPendingDeletionRequest(ctx, userID)
+}
+
+func (p *privacyService) CancelDeletion(ctx context.Context, userID int64) error {
+	return p.repo.CancelDeletionRequest(ctx, userID)
+}
+
+// ProcessDueDeletions mengeksekusi permintaan hapus yang masa tenggangnya sudah lewat.
+// Kegagalan satu user tidak menghentikan user lain, permintaan yang gagal akan dicoba lagi di run berikutnya.
+func (p *privacyService) ProcessDueDeletions(ctx context.Context, now time.Time, limit int) (int, error) {
+	requests, err := p.repo.GetDueDeletionRequests(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		erased int
+		errs   []error
+	)
+	for _, req := range requests {
+		if err := p.eraseUser(ctx, req, now); err != nil {
+			log.Errorf("[PrivacyService-1] ProcessDueDeletions: user %d: %v", req.UserID, err)
+			errs = append(errs, fmt.Errorf("user %d: %w", req.UserID, err))
+			continue
+		}
+		erased++
+	}
+
+	return erased, errors.Join(errs...)
+}
+
+// eraseUser menghapus file di storage terlebih dahulu (idempotent, aman diulang), baru
+// menganonimkan data di database bersama event user.deleted (outbox), lalu mencabut semua sesi.
+func (p *privacyService) eraseUser(ctx context.Context, req entity.DeletionRequestEntity, erasedAt time.Time) error {
+	var photo string
+	user, err := p.userRepo.GetCustomerByID(ctx, req.UserID)
+	if err != nil && err.Error() != "404" {
+		return err
+	}
+	if user != nil {
+		photo = user.Photo
+	}
+
+	paths, err := p.userObjectPaths(ctx, req.UserID, photo)
+	if err != nil {
+		return err
+	}
+	paths, err = p.unreferencedObjectPaths(ctx, req.UserID, paths)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 0 {
+		if err := p.storage.RemoveObjects(ctx, paths); err != nil {
+			return err
+		}
+	}
+
+	// session.revoked ikut ditulis di transaksi yang sama, sesi di redis dicabut setelah commit
+	events := []entity.DomainEventEntity{
+		entity.NewUserDeletedEvent(entity.UserDeletedEventEntity{
+			UserID:    req.UserID,
+			Reason:    entity.UserDeletedReasonErased,
+			RequestID: req.ID,
+			DeletedAt: erasedAt,
+		}),
+		entity.NewSessionRevokedEvent(entity.SessionRevokedEventEntity{
+			UserID:    req.UserID,
+			Reason:    entity.SessionRevokedReasonUserErased,
+			RevokedAt: erasedAt,
+		}),
+	}
+	if err := p.repo.EraseUser(ctx, req, erasedAt, events); err != nil {
+		return err
+	}
+
+	if err := revokeUserSessions(ctx, p.redis, req.UserID); err != nil {
+		log.Errorf("[PrivacyService-1] eraseUser: %v", err)
+	}
+
+	return nil
+}
+
+// userObjectPaths seluruh object milik user: folder upload user ditambah foto profil
+// jika object-nya belum ikut terdaftar. Foto di luar folder user tidak pernah ikut di-export atau dihapus.
+func (p *privacyService) userObjectPaths(ctx context.Context, userID int64, photo string) ([]string, error) {
+	paths, err := p.storage.ListObjects(ctx, fmt.Sprintf(utils.USER_UPLOAD_PREFIX, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	photoPath := userPhotoObjectPath(photo, p.cfg.Minio.Bucket, userID)
+	if photoPath == "" {
+		return paths, nil
+	}
+	for _, val := range paths {
+		if val == photoPath {
+			return paths, nil
+		}
+	}
+
+	return append(paths, photoPath), nil
+}
+
+// unreferencedObjectPaths membuang object yang dipakai sebagai foto user lain, misalnya foto
+// yang diupload admin lalu dipasang ke customer, agar tidak ikut terhapus bersama uploader-nya.
+func (p *privacyService) unreferencedObjectPaths(ctx context.Context, userID int64, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return paths, nil
+	}
+
+	photos, err := p.repo.GetOtherUserPhotos(ctx, userID, fmt.Sprintf(utils.USER_UPLOAD_PREFIX, userID))
+	if err != nil {
+		return nil, err
+	}
+
+	referenced := map[string]bool{}
+	for _, val := range photos {
+		referenced[photoObjectPath(val, p.cfg.Minio.Bucket)] = true
+	}
+
+	result := []string{}
+	for _, val := range paths {
+		if referenced[val] {
+			log.Infof("[PrivacyService-1] unreferencedObjectPaths: %s is still used by another user, kept", val)
+			continue
+		}
+		result = append(result, val)
+	}
+	return result, nil
+}
+
+func (p *privacyService) getSessions(ctx context.Context, userID int64) ([]entity.SessionEntity, error) {
+	key := userSessionsKey(userID)
+
+	tokens, err := p.redis.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []entity.SessionEntity{}
+	for _, token := range tokens {
+		data, err := p.redis.Get(ctx, token).Result()
+		if errors.Is(err, redis.Nil) {
+			// Sesi sudah expired, bersihkan dari index
+			p.redis.SRem(ctx, key, token)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		sessionData := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(data), &sessionData); err != nil {
+			return nil, err
+		}
+
+		session := entity.SessionEntity{
+			TokenHint: tokenHint(token),
+		}
+		if createdAt, ok := sessionData["created_at"].(string); ok {
+			session.CreatedAt = createdAt
+		}
+		if ttl, err := p.redis.TTL(ctx, token).Result(); err == nil && ttl > 0 {
+			expiresAt := time.Now().Add(ttl)
+			session.ExpiresAt = &expiresAt
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// revokeUserSessions mencabut seluruh sesi user yang tercatat di index sesi.
+func revokeUserSessions(ctx context.Context, rdb *redis.Client, userID int64) error {
+	key := userSessionsKey(userID)
+
+	tokens, err := rdb.SMembers(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+
+	return rdb.Del(ctx, append(tokens, key)...).Err()
+}
+
+// trackUserSession mencatat token sesi per user agar sesi bisa di-export dan dicabut.
+// TTL index diperpanjang setiap login sehingga mengikuti sesi yang paling baru.
+func trackUserSession(ctx context.Context, rdb *redis.Client, userID int64, token string) error {
+	key := userSessionsKey(userID)
+	_, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, token)
+		pipe.Expire(ctx, key, userSessionTTL)
+		return nil
+	})
+	return err
+}
+
+func userSessionsKey(userID int64) string {
+	return fmt.Sprintf("user_sessions:%d", userID)
+}
+
+// tokenHint hanya menampilkan beberapa karakter terakhir, token utuh tidak pernah ikut di-export.
+func tokenHint(token string) string {
+	if len(token) <= sessionTokenHintLength {
+		return token
+	}
+	return "..." + token[len(token)-sessionTokenHintLength:]
+}
+
+// userPhotoObjectPath mengambil object path dari users.photo yang bisa berupa path atau presigned URL.
+// Hanya object di folder upload milik userID (USER_UPLOAD_PREFIX) yang dikembalikan, selain itu kosong
+// agar user tidak bisa mengarahkan foto ke file user lain.
+func userPhotoObjectPath(photo, bucket string, userID int64) string {
+	objectPath := photoObjectPath(photo, bucket)
+	if objectPath == "" {
+		return ""
+	}
+
+	if !strings.HasPrefix(objectPath, fmt.Sprintf(utils.USER_UPLOAD_PREFIX, userID)) {
+		log.Infof("[PrivacyService-1] userPhotoObjectPath: photo of user %d outside user folder ignored", userID)
+		return ""
+	}
+	return objectPath
+}
+
+// photoObjectPath object path dari users.photo (path atau presigned URL di bucket), kosong jika
+// bukan object di bucket. path.Clean agar "../" tidak bisa keluar dari folder user.
+func photoObjectPath(photo, bucket string) string {
+	if photo == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(photo)
+	if err != nil {
+		return ""
+	}
+
+	objectPath := strings.TrimPrefix(parsed.Path, "/")
+	if parsed.Host != "" {
+		if !strings.HasPrefix(objectPath, bucket+"/") {
+			return ""
+		}
+		objectPath = strings.TrimPrefix(objectPath, bucket+"/")
+	}
+
+	return path.Clean(objectPath)
+}
+
+// expiredTokens token verifikasi/reset yang masih berlaku tidak ikut di-export, nilai token juga dikosongkan.
+func expiredTokens(tokens []entity.VerificationTokenEntity, now time.Time) []entity.VerificationTokenEntity {
+	result := []entity.VerificationTokenEntity{}
+	for _, val := range tokens {
+		if val.ExpiresAt.After(now) {
+			continue
+		}
+		val.Token = ""
+		result = append(result, val)
+	}
+	return result
+}
@@ -12,6 +12,8 @@ type RoleServiceInterface interface {
 	Create(ctx context.Context, req entity.RoleEntity) error
 	Delete(ctx context.Context, id int64) error
 	Update(ctx context.Context, req entity.RoleEntity) error
+	GetDeletedAll(ctx context.Context, search string) ([]entity.RoleEntity, error)
+	Restore(ctx context.Context, id int64) error
 }
 
 type roleService struct {
@@ -41,3 +43,11 @@ func (r *roleService) GetByID(ctx context.Context, id int64) (*entity.RoleEntity
 func (r *roleService) Update(ctx context.Context, req entity.RoleEntity) error {
 	return r.repo.Update(ctx, req)
 }
+
+func (r *roleService) GetDeletedAll(ctx context.Context, search string) ([]entity.RoleEntity, error) {
+	return r.repo.GetDeletedAll(ctx, search)
+}
+
+func (r *roleService) Restore(ctx context.Context, id int64) error {
+	return r.repo.Restore(ctx, id)
+}
@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
+	"strconv"
 
 	"clean-architecture/config"
 	"clean-architecture/internal/domain/entity"
 	"clean-architecture/internal/port/outbound"
 	"clean-architecture/utils"
 	utilpassword "clean-architecture/utils/password"
+	"clean-architecture/utils/phone"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,21 +21,34 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// verificationTokenTTL masa berlaku token reset password, sama dengan token verifikasi akun yang dibuat
+// repository saat CreateUserAccount.
+const verificationTokenTTL = time.Hour
+
 type UserServiceInterface interface {
 	SignIn(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, string, error)
+	SignInWithConsent(ctx context.Context, challengeToken string, documentIDs []int64, ipAddress string) (*entity.UserEntity, string, error)
 	CreateUserAccount(ctx context.Context, req entity.UserEntity) error
 	ForgotPassword(ctx context.Context, req entity.UserEntity) error
 	VerifyToken(ctx context.Context, token string) (*entity.UserEntity, error)
 	UpdatePassword(ctx context.Context, req entity.UserEntity) error
 	GetProfileUser(ctx context.Context, userID int64) (*entity.UserEntity, error)
 	UpdateDataUser(ctx context.Context, req entity.UserEntity) error
+	PatchProfile(ctx context.Context, patch entity.UserPatchEntity) (*entity.UserEntity, error)
 
 	// Modul Customers Admin
 	GetCustomerAll(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error)
+	GetCustomerAllByCursor(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, *entity.CursorPageEntity, error)
+	SearchCustomers(ctx context.Context, query entity.QueryStringEntity) ([]entity.CustomerSearchEntity, int64, int64, error)
+	GetCustomerNearby(ctx context.Context, query entity.NearbyQueryEntity) ([]entity.NearbyCustomerEntity, int64, int64, error)
 	GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error)
 	CreateCustomer(ctx context.Context, req entity.UserEntity) error
 	UpdateCustomer(ctx context.Context, req entity.UserEntity) error
+	PatchCustomer(ctx context.Context, patch entity.UserPatchEntity) (*entity.UserEntity, error)
 	DeleteCustomer(ctx context.Context, customerID int64) error
+	ExportCustomers(ctx context.Context, query entity.QueryStringEntity, handle func(entity.UserEntity) error) error
+	GetDeletedCustomerAll(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error)
+	RestoreCustomer(ctx context.Context, customerID int64) error
 }
 
 type userService struct {
@@ -40,98 +56,111 @@ type userService struct {
 	cfg        *config.Config
 	jwtService JwtServiceInterface
 	repoToken  outbound.VerificationTokenRepositoryInterface
-	publisher  KafkaServiceInterface
 	redis      *redis.Client
+	consent    ConsentServiceInterface
+	storage    outbound.MinioInterface
 }
 
 func NewUserService(repo outbound.UserRepositoryInterface, cfg *config.Config, jwtService JwtServiceInterface,
-	repoToken outbound.VerificationTokenRepositoryInterface, publisher KafkaServiceInterface,
-	redis *redis.Client) UserServiceInterface {
+	repoToken outbound.VerificationTokenRepositoryInterface, redis *redis.Client,
+	consent ConsentServiceInterface, storage outbound.MinioInterface) UserServiceInterface {
 	return &userService{
 		repo:       repo,
 		cfg:        cfg,
 		jwtService: jwtService,
 		repoToken:  repoToken,
-		publisher:  publisher,
 		redis:      redis,
+		consent:    consent,
+		storage:    storage,
 	}
 }
 
 func (u *userService) DeleteCustomer(ctx context.Context, customerID int64) error {
-	return u.repo.DeleteCustomer(ctx, customerID)
+	if err := u.repo.DeleteCustomer(ctx, customerID); err != nil {
+		return err
+	}
+
+	// Customer yang dihapus tidak boleh tetap login dengan sesi lama
+	if err := revokeUserSessions(ctx, u.redis, customerID); err != nil {
+		log.Errorf("[UserService-1] DeleteCustomer: %v", err)
+	}
+
+	return nil
+}
+
+func (u *userService) ExportCustomers(ctx context.Context, query entity.QueryStringEntity, handle func(entity.UserEntity) error) error {
+	return u.repo.ExportCustomers(ctx, query, handle)
+}
+
+func (u *userService) GetDeletedCustomerAll(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error) {
+	return u.repo.GetDeletedCustomerAll(ctx, query)
+}
+
+func (u *userService) RestoreCustomer(ctx context.Context, customerID int64) error {
+	return u.repo.RestoreCustomer(ctx, customerID)
 }
 
 func (u *userService) UpdateCustomer(ctx context.Context, req entity.UserEntity) error {
+	if err := u.normalizePhone(&req); err != nil {
+		return err
+	}
+
 	passwordNoencrypt := ""
 	if req.Password != "" {
 		passwordNoencrypt = req.Password
 		password, err := utilpassword.HashPassword(req.Password)
 		if err != nil {
-			log.Fatalf("[UserService-1] UpdateCustomer: %v", err)
+			log.Errorf("[UserService-1] UpdateCustomer: %v", err)
 			return err
 		}
 
 		req.Password = password
 	}
 
-	err := u.repo.UpdateCustomer(ctx, req)
-	if err != nil {
-		log.Fatalf("[UserService-2] UpdateCustomer: %v", err)
-		return err
-	}
-
 	if passwordNoencrypt != "" {
-		messageparam := fmt.Sprintf("You're account has been updated. Please login use: \n Email: %s\nPassword: %s", req.Email, passwordNoencrypt)
-
-		publishMessage := entity.PublishMessage{
+		req.Notifications = append(req.Notifications, entity.PublishMessage{
 			Email:     req.Email,
-			Message:   messageparam,
 			UserId:    req.ID,
-			Subject:   "Update Data",
 			QueueName: utils.NOTIF_EMAIL_UPDATE_CUSTOMER,
-		}
+			Template:  utils.NOTIF_EMAIL_UPDATE_CUSTOMER,
+			Data:      map[string]string{"name": req.Name, "email": req.Email, "password": passwordNoencrypt},
+		})
+	}
 
-		go func() {
-			err := u.publisher.PublishMessage(ctx, publishMessage)
-			if err != nil {
-				log.Errorf("[UserService-3] PublishMessage error: %v", err)
-			}
-		}()
+	err := u.repo.UpdateCustomer(ctx, req)
+	if err != nil {
+		log.Errorf("[UserService-2] UpdateCustomer: %v", err)
+		return err
 	}
 
 	return nil
 }
 
 func (u *userService) CreateCustomer(ctx context.Context, req entity.UserEntity) error {
+	if err := u.normalizePhone(&req); err != nil {
+		return err
+	}
+
 	passwordNoEncrypt := req.Password
 	password, err := utilpassword.HashPassword(passwordNoEncrypt)
 	if err != nil {
-		log.Fatalf("[UserService-1] CreateCustomer: %v", err)
+		log.Errorf("[UserService-1] CreateCustomer: %v", err)
 		return err
 	}
 	req.Password = password
-	userID, err := u.repo.CreateCustomer(ctx, req)
-	if err != nil {
-		log.Fatalf("[UserService-4] CreateCustomer: %v", err)
-		return err
-	}
 
-	messageparam := fmt.Sprintf("You have been registered in Sayur Project. Please login use: \n Email: %s\nPassword: %s", req.Email, passwordNoEncrypt)
-
-	publishMessage := entity.PublishMessage{
+	// UserId diisi repository setelah user tersimpan
+	req.Notifications = append(req.Notifications, entity.PublishMessage{
 		Email:     req.Email,
-		Message:   messageparam,
-		UserId:    userID,
-		Subject:   "Account Exists",
 		QueueName: utils.NOTIF_EMAIL_CREATE_CUSTOMER,
-	}
+		Template:  utils.NOTIF_EMAIL_CREATE_CUSTOMER,
+		Data:      map[string]string{"name": req.Name, "email": req.Email, "password": passwordNoEncrypt},
+	})
 
-	go func() {
-		err := u.publisher.PublishMessage(ctx, publishMessage)
-		if err != nil {
-			log.Errorf("[UserService-5] PublishMessage error: %v", err)
-		}
-	}()
+	if _, err := u.repo.CreateCustomer(ctx, req); err != nil {
+		log.Errorf("[UserService-4] CreateCustomer: %v", err)
+		return err
+	}
 
 	return nil
 }
@@ -144,10 +173,118 @@ func (u *userService) GetCustomerAll(ctx context.Context, query entity.QueryStri
 	return u.repo.GetCustomerAll(ctx, query)
 }
 
+func (u *userService) GetCustomerAllByCursor(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, *entity.CursorPageEntity, error) {
+	return u.repo.GetCustomerAllByCursor(ctx, query)
+}
+
+func (u *userService) SearchCustomers(ctx context.Context, query entity.QueryStringEntity) ([]entity.CustomerSearchEntity, int64, int64, error) {
+	return u.repo.SearchCustomers(ctx, query)
+}
+
+func (u *userService) GetCustomerNearby(ctx context.Context, query entity.NearbyQueryEntity) ([]entity.NearbyCustomerEntity, int64, int64, error) {
+	return u.repo.GetCustomerNearby(ctx, query)
+}
+
 func (u *userService) UpdateDataUser(ctx context.Context, req entity.UserEntity) error {
+	if err := u.normalizePhone(&req); err != nil {
+		return err
+	}
+
 	return u.repo.UpdateDataUser(ctx, req)
 }
 
+// normalizePhone menyimpan nomor telepon dalam format E.164, nomor tidak valid menghasilkan "400".
+func (u *userService) normalizePhone(req *entity.UserEntity) error {
+	if req.Phone == "" {
+		return nil
+	}
+
+	normalized, err := phone.NormalizeE164(req.Phone, u.cfg.App.PhoneDefaultCountryCode)
+	if err != nil {
+		log.Infof("[UserService] normalizePhone: %v", err)
+		return errors.New("400")
+	}
+	req.Phone = normalized
+	return nil
+}
+
+// PatchProfile menerapkan merge patch ke profil sendiri dan mengembalikan data terbaru.
+func (u *userService) PatchProfile(ctx context.Context, patch entity.UserPatchEntity) (*entity.UserEntity, error) {
+	if err := u.normalizePatchPhone(&patch); err != nil {
+		return nil, err
+	}
+	if err := u.preparePatchPhoto(ctx, &patch); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.PatchUser(ctx, patch); err != nil {
+		return nil, err
+	}
+
+	return u.repo.GetUserByID(ctx, patch.ID)
+}
+
+// PatchCustomer menerapkan merge patch ke customer oleh admin dan mengembalikan data terbaru.
+func (u *userService) PatchCustomer(ctx context.Context, patch entity.UserPatchEntity) (*entity.UserEntity, error) {
+	if err := u.normalizePatchPhone(&patch); err != nil {
+		return nil, err
+	}
+	if err := u.preparePatchPhoto(ctx, &patch); err != nil {
+		return nil, err
+	}
+
+	if err := u.repo.PatchUser(ctx, patch); err != nil {
+		return nil, err
+	}
+
+	return u.repo.GetCustomerByID(ctx, patch.ID)
+}
+
+// normalizePatchPhone hanya menormalisasi phone yang dikirim dengan nilai, null berarti dihapus.
+func (u *userService) normalizePatchPhone(patch *entity.UserPatchEntity) error {
+	if !patch.Phone.Set || patch.Phone.Value == nil {
+		return nil
+	}
+
+	normalized, err := phone.NormalizeE164(*patch.Phone.Value, u.cfg.App.PhoneDefaultCountryCode)
+	if err != nil {
+		log.Infof("[UserService] normalizePatchPhone: %v", err)
+		return errors.New("400")
+	}
+	patch.Phone.Value = &normalized
+	return nil
+}
+
+// preparePatchPhoto photo harus berada di folder upload user yang di patch. Foto yang diupload admin
+// ke foldernya sendiri disalin ke folder customer agar ikut customer saat erase, selain itu "403".
+// Photo null atau kosong berarti foto dihapus dan selalu diizinkan.
+func (u *userService) preparePatchPhoto(ctx context.Context, patch *entity.UserPatchEntity) error {
+	if !patch.Photo.Set || patch.Photo.Value == nil || *patch.Photo.Value == "" {
+		return nil
+	}
+
+	if userPhotoObjectPath(*patch.Photo.Value, u.cfg.Minio.Bucket, patch.ID) != "" {
+		return nil
+	}
+
+	srcPath := ""
+	if patch.CallerID != patch.ID {
+		srcPath = userPhotoObjectPath(*patch.Photo.Value, u.cfg.Minio.Bucket, patch.CallerID)
+	}
+	if srcPath == "" {
+		log.Infof("[UserService] preparePatchPhoto: photo is not uploaded for user %d", patch.ID)
+		return errors.New("403")
+	}
+
+	dstPath := fmt.Sprintf(utils.USER_UPLOAD_PREFIX, patch.ID) + path.Base(srcPath)
+	if err := u.storage.CopyObject(ctx, srcPath, dstPath); err != nil {
+		log.Errorf("[UserService-1] preparePatchPhoto: %v", err)
+		return err
+	}
+	patch.Photo.Value = &dstPath
+	return nil
+}
+
 func (u *userService) GetProfileUser(ctx context.Context, userID int64) (*entity.UserEntity, error) {
 	return u.repo.GetUserByID(ctx, userID)
 }
@@ -179,6 +316,11 @@ func (u *userService) UpdatePassword(ctx context.Context, req entity.UserEntity)
 		return err
 	}
 
+	// Sesi lama tidak boleh tetap aktif setelah password di-reset
+	if err := revokeUserSessions(ctx, u.redis, req.ID); err != nil {
+		log.Errorf("[UserService-5] UpdatePassword: %v", err)
+	}
+
 	return nil
 }
 
@@ -223,6 +365,11 @@ func (u *userService) VerifyToken(ctx context.Context, token string) (*entity.Us
 		return nil, err
 	}
 
+	if err := trackUserSession(ctx, u.redis, user.ID, token); err != nil {
+		log.Errorf("[UserService-5] VerifyToken: %v", err)
+		return nil, err
+	}
+
 	user.Token = accessToken
 
 	return user, nil
@@ -236,10 +383,20 @@ func (u *userService) ForgotPassword(ctx context.Context, req entity.UserEntity)
 	}
 
 	token := uuid.New().String()
+	urlForgot := fmt.Sprintf("%s/auth/update-password?token=%s", u.cfg.App.UrlFrontFE, token)
+
 	reqEntity := entity.VerificationTokenEntity{
 		UserID:    user.ID,
 		Token:     token,
 		TokenType: utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		ExpiresAt: time.Now().Add(verificationTokenTTL),
+		Notifications: []entity.PublishMessage{{
+			Email:     req.Email,
+			UserId:    user.ID,
+			QueueName: utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+			Template:  utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+			Data:      verificationTemplateData(user.Name, urlForgot),
+		}},
 	}
 
 	err = u.repoToken.CreateVerificationToken(ctx, reqEntity)
@@ -248,28 +405,17 @@ func (u *userService) ForgotPassword(ctx context.Context, req entity.UserEntity)
 		return err
 	}
 
-	urlForgot := fmt.Sprintf("%s/auth/update-password?token=%s", u.cfg.App.UrlFrontFE, token)
-	messageparam := fmt.Sprintf("Please click link below for reset password: %v", urlForgot)
-
-	publishMessage := entity.PublishMessage{
-		Email:     req.Email,
-		Message:   messageparam,
-		UserId:    user.ID,
-		Subject:   "Reset Password",
-		QueueName: utils.NOTIF_EMAIL_FORGOT_PASSWORD,
-	}
-
-	go func() {
-		err := u.publisher.PublishMessage(ctx, publishMessage)
-		if err != nil {
-			log.Errorf("[UserService-3] PublishMessage error: %v", err)
-		}
-	}()
-
 	return nil
 }
 
+// CreateUserAccount "422" jika versi syarat & ketentuan atau kebijakan privasi yang disetujui bukan versi yang berlaku.
 func (u *userService) CreateUserAccount(ctx context.Context, req entity.UserEntity) error {
+	consents, err := u.consent.ResolveSignUpConsents(ctx, req.Consents)
+	if err != nil {
+		return err
+	}
+	req.Consents = consents
+
 	password, err := utilpassword.HashPassword(req.Password)
 	if err != nil {
 		log.Errorf("[UserService-1] CreateUserAccount: %v", err)
@@ -279,35 +425,43 @@ func (u *userService) CreateUserAccount(ctx context.Context, req entity.UserEnti
 	req.Password = password
 	req.Token = uuid.New().String()
 
-	userID, err := u.repo.CreateUserAccount(ctx, req)
-	if err != nil {
-		log.Errorf("[UserService-2] CreateUserAccount: %v", err)
-		return err
-	}
-
 	verifyURL := fmt.Sprintf("%s/auth/verify-account?token=%s", u.cfg.App.UrlFrontFE, req.Token)
-	verifyMsg := fmt.Sprintf("Please verify your account by clicking the link: %s", verifyURL)
 
-	publishMessage := entity.PublishMessage{
+	// UserId diisi repository setelah user tersimpan
+	req.Notifications = append(req.Notifications, entity.PublishMessage{
 		Email:     req.Email,
-		Message:   verifyMsg,
-		UserId:    userID,
-		Subject:   "Verify Your Account",
 		QueueName: utils.NOTIF_EMAIL_VERIFICATION,
-	}
+		Template:  utils.NOTIF_EMAIL_VERIFICATION,
+		Data:      verificationTemplateData(req.Name, verifyURL),
+	})
 
-	go func() {
-		err := u.publisher.PublishMessage(ctx, publishMessage)
-		if err != nil {
-			log.Errorf("[UserService-3] PublishMessage error: %v", err)
-		}
-	}()
+	if _, err := u.repo.CreateUserAccount(ctx, req); err != nil {
+		log.Errorf("[UserService-2] CreateUserAccount: %v", err)
+		return err
+	}
 
 	return nil
 }
 
+// SignIn login memakai email, atau nomor telepon (req.Phone) yang sudah diverifikasi.
+// Jika ada versi dokumen legal yang belum disetujui, access token tidak diterbitkan:
+// error "428" dan string yang dikembalikan adalah challenge token untuk SignInWithConsent.
 func (u *userService) SignIn(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, string, error) {
-	user, err := u.repo.GetUserByEmail(ctx, req.Email)
+	var (
+		user *entity.UserEntity
+		err  error
+	)
+
+	if req.Phone != "" {
+		phoneNumber, errPhone := phone.NormalizeE164(req.Phone, u.cfg.App.PhoneDefaultCountryCode)
+		if errPhone != nil {
+			log.Infof("[UserService-1] SignIn: %v", errPhone)
+			return nil, "", errors.New("404")
+		}
+		user, err = u.repo.GetUserByVerifiedPhone(ctx, phoneNumber)
+	} else {
+		user, err = u.repo.GetUserByEmail(ctx, req.Email)
+	}
 	if err != nil {
 		log.Errorf("[UserService-1] SignIn: %v", err)
 		return nil, "", err
@@ -319,11 +473,65 @@ func (u *userService) SignIn(ctx context.Context, req entity.UserEntity) (*entit
 		return nil, "", err
 	}
 
-	token, err := u.jwtService.GenerateToken(user.ID)
+	pending, err := u.consent.GetPendingDocuments(ctx, user.ID)
 	if err != nil {
 		log.Errorf("[UserService-3] SignIn: %v", err)
 		return nil, "", err
 	}
+	if len(pending) > 0 {
+		challengeToken, err := u.consent.CreateChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, "", err
+		}
+		log.Infof("[UserService-4] SignIn: user %d must accept %d new legal document(s)", user.ID, len(pending))
+		return nil, challengeToken, errors.New("428")
+	}
+
+	token, err := u.createSession(ctx, *user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+// SignInWithConsent menyelesaikan challenge dari SignIn: mencatat persetujuan lalu menerbitkan access token.
+// "404" jika challenge tidak valid, "422" jika masih ada dokumen yang belum disetujui.
+func (u *userService) SignInWithConsent(ctx context.Context, challengeToken string, documentIDs []int64, ipAddress string) (*entity.UserEntity, string, error) {
+	userID, err := u.consent.ResolveChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := u.consent.AcceptDocuments(ctx, userID, documentIDs, ipAddress); err != nil {
+		return nil, "", err
+	}
+
+	user, err := u.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Persetujuan boleh tercatat lebih dari sekali, tetapi challenge hanya bisa ditukar
+	// dengan satu sesi walaupun request yang sama dikirim bersamaan
+	if _, err := u.consent.ConsumeChallenge(ctx, challengeToken); err != nil {
+		return nil, "", err
+	}
+
+	token, err := u.createSession(ctx, *user)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+func (u *userService) createSession(ctx context.Context, user entity.UserEntity) (string, error) {
+	token, err := u.jwtService.GenerateToken(user.ID)
+	if err != nil {
+		log.Errorf("[UserService-1] createSession: %v", err)
+		return "", err
+	}
 
 	sessionData := map[string]interface{}{
 		"user_id":    user.ID,
@@ -338,14 +546,28 @@ func (u *userService) SignIn(ctx context.Context, req entity.UserEntity) (*entit
 	jsonData, err := json.Marshal(sessionData)
 	if err != nil {
 		fmt.Println("Error encoding JSON:", err)
-		return nil, "", err
+		return "", err
 	}
 
 	err = u.redis.Set(ctx, token, jsonData, time.Hour*23).Err()
 	if err != nil {
-		log.Errorf("[UserService-4] SignIn: %v", err)
-		return nil, "", err
+		log.Errorf("[UserService-2] createSession: %v", err)
+		return "", err
 	}
 
-	return user, token, nil
+	if err := trackUserSession(ctx, u.redis, user.ID, token); err != nil {
+		log.Errorf("[UserService-3] createSession: %v", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// verificationTemplateData variabel template email berisi link token verifikasi.
+func verificationTemplateData(name, link string) map[string]string {
+	return map[string]string{
+		"name":   name,
+		"link":   link,
+		"expiry": strconv.Itoa(int(verificationTokenTTL.Minutes())),
+	}
 }
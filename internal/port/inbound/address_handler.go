@@ -0,0 +1,19 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type AddressHandlerInterface interface {
+	// Alamat milik user yang login (/auth/addresses)
+	GetAll(c echo.Context) error
+	GetByID(c echo.Context) error
+	Create(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+
+	// Alamat customer oleh admin (/admin/customers/:id/addresses)
+	GetCustomerAddressAll(c echo.Context) error
+	GetCustomerAddressByID(c echo.Context) error
+	CreateCustomerAddress(c echo.Context) error
+	UpdateCustomerAddress(c echo.Context) error
+	DeleteCustomerAddress(c echo.Context) error
+}
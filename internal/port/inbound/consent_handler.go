@@ -0,0 +1,9 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type ConsentHandlerInterface interface {
+	GetCurrentDocuments(c echo.Context) error
+	PublishDocument(c echo.Context) error
+	GetAcceptanceReport(c echo.Context) error
+}
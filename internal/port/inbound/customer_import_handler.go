@@ -0,0 +1,8 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type CustomerImportHandlerInterface interface {
+	ImportCustomers(c echo.Context) error
+	GetImportJob(c echo.Context) error
+}
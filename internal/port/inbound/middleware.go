@@ -4,4 +4,5 @@ import "github.com/labstack/echo/v4"
 
 type MiddlewareAdapterInterface interface {
 	CheckToken() echo.MiddlewareFunc
+	TraceParent() echo.MiddlewareFunc
 }
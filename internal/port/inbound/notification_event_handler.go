@@ -0,0 +1,11 @@
+package inbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type NotificationEventHandlerInterface interface {
+	HandleDelivered(ctx context.Context, msg entity.ConsumedMessageEntity) error
+	HandleFailed(ctx context.Context, msg entity.ConsumedMessageEntity) error
+}
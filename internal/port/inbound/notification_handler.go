@@ -0,0 +1,12 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type NotificationHandlerInterface interface {
+	// Riwayat notifikasi user yang login (/auth/notifications)
+	GetAll(c echo.Context) error
+
+	// Riwayat notifikasi customer dan kirim ulang oleh admin
+	GetCustomerNotificationAll(c echo.Context) error
+	Resend(c echo.Context) error
+}
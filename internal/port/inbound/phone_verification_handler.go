@@ -0,0 +1,8 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type PhoneVerificationHandlerInterface interface {
+	SendOTP(c echo.Context) error
+	VerifyOTP(c echo.Context) error
+}
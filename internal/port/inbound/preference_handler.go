@@ -0,0 +1,9 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type PreferenceHandlerInterface interface {
+	GetPreferences(c echo.Context) error
+	UpdatePreferences(c echo.Context) error
+	GetCustomerPreferences(c echo.Context) error
+}
@@ -0,0 +1,10 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type PrivacyHandlerInterface interface {
+	ExportMyData(c echo.Context) error
+	RequestDeletion(c echo.Context) error
+	GetDeletionRequest(c echo.Context) error
+	CancelDeletion(c echo.Context) error
+}
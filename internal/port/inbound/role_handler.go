@@ -8,4 +8,6 @@ type RoleHandlerInterface interface {
 	Create(c echo.Context) error
 	Delete(c echo.Context) error
 	Update(c echo.Context) error
+	GetDeletedAll(c echo.Context) error
+	Restore(c echo.Context) error
 }
@@ -0,0 +1,7 @@
+package inbound
+
+import "github.com/labstack/echo/v4"
+
+type StatsHandlerInterface interface {
+	GetDashboardStats(c echo.Context) error
+}
@@ -0,0 +1,14 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type AddressRepositoryInterface interface {
+	GetAll(ctx context.Context, userID int64) ([]entity.AddressEntity, error)
+	GetByID(ctx context.Context, userID, addressID int64) (*entity.AddressEntity, error)
+	Create(ctx context.Context, req entity.AddressEntity) (int64, error)
+	Update(ctx context.Context, req entity.AddressEntity) error
+	Delete(ctx context.Context, userID, addressID int64) error
+}
@@ -0,0 +1,14 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type ConsentRepositoryInterface interface {
+	CreateDocument(ctx context.Context, req entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error)
+	GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error)
+	GetAcceptedDocumentIDs(ctx context.Context, userID int64, documentIDs []int64) ([]int64, error)
+	CreateConsents(ctx context.Context, consents []entity.ConsentEntity) error
+	GetAcceptanceReport(ctx context.Context) ([]entity.ConsentReportEntity, error)
+}
@@ -0,0 +1,15 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type DeadLetterReaderInterface interface {
+	// Fetch membaca pesan DLQ sampai high watermark saat dipanggil. Jika fromCommitted true
+	// pembacaan dimulai dari offset replay terakhir yang di-commit, selain itu dari offset paling awal.
+	Fetch(ctx context.Context, topic string, limit int, fromCommitted bool) ([]entity.DeadLetterEntity, error)
+	// Commit menandai pesan DLQ sudah di-replay.
+	Commit(ctx context.Context, msg entity.DeadLetterEntity) error
+	Close() error
+}
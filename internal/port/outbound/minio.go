@@ -2,10 +2,16 @@ package outbound
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"time"
 )
 
 type MinioInterface interface {
 	UploadFile(path string, file *bytes.Buffer) (string, error)
 	GetPresignedURL(path string, expiry time.Duration) (string, error)
+	ListObjects(ctx context.Context, prefix string) ([]string, error)
+	GetObject(ctx context.Context, path string) (io.ReadCloser, error)
+	RemoveObjects(ctx context.Context, paths []string) error
+	CopyObject(ctx context.Context, srcPath, dstPath string) error
 }
@@ -0,0 +1,11 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type NotificationDeliveryRepositoryInterface interface {
+	// UpsertStatus menyimpan status terbaru, event yang lebih lama dari status tersimpan diabaikan.
+	UpsertStatus(ctx context.Context, req entity.NotificationDeliveryEntity) error
+}
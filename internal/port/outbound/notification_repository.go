@@ -0,0 +1,22 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type NotificationRepositoryInterface interface {
+	GetAll(ctx context.Context, query entity.NotificationHistoryQueryEntity) ([]entity.NotificationHistoryEntity, int64, int64, error)
+	GetByID(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error)
+	// Create menulis notifikasi ke outbox beserta riwayatnya.
+	Create(ctx context.Context, message entity.PublishMessage) (*entity.NotificationHistoryEntity, error)
+	// Resend menyalin payload outbox notifikasi ke pesan outbox baru beserta riwayatnya, dikirim ke
+	// email/nomor user saat ini. "404" jika notifikasi tidak ada, "422" jika payload-nya sudah tidak
+	// tersimpan, notifikasi berisi kredensial atau user-nya sudah tidak ada.
+	Resend(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error)
+	// UpdateSendStatus hanya mengubah notifikasi yang masih pending.
+	UpdateSendStatus(ctx context.Context, req entity.NotificationSendStatusEntity) error
+	// UpdateDeliveryStatus menyimpan laporan pengiriman, laporan yang lebih lama dari laporan
+	// tersimpan dan notifikasi yang di-skip diabaikan.
+	UpdateDeliveryStatus(ctx context.Context, req entity.NotificationDeliveryEntity) error
+}
@@ -0,0 +1,15 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type NotificationSenderInterface interface {
+	// Send mengirim notifikasi lewat transport yang dikonfigurasi. Jika Send kembali tanpa error,
+	// hasil pengiriman selalu dilaporkan lewat kafkadelivery.Callback di ctx (langsung untuk
+	// transport sinkron, setelah broker ack untuk Kafka async).
+	Send(ctx context.Context, notification entity.NotificationEntity) error
+	// Close melepas resource milik transport, producer Kafka ditutup oleh pemiliknya.
+	Close() error
+}
@@ -0,0 +1,9 @@
+package outbound
+
+import "clean-architecture/internal/domain/entity"
+
+type NotificationTemplateInterface interface {
+	// Render template name untuk locale, fallback ke bahasa dasar (en-US → en) lalu locale default.
+	// Error "404" jika template tidak ada, error lain jika ada variabel di template yang tidak ada di data.
+	Render(name, locale string, data map[string]string) (*entity.RenderedNotificationEntity, error)
+}
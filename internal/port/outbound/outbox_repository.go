@@ -0,0 +1,21 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+	"time"
+)
+
+type OutboxRepositoryInterface interface {
+	// ClaimPending mengambil pesan pending yang sudah waktunya dikirim dan menunda next_attempt_at
+	// selama lease, sehingga beberapa relay bisa berjalan bersamaan tanpa mengirim pesan yang sama.
+	ClaimPending(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]entity.OutboxMessageEntity, error)
+	// MarkSent dan MarkFailed menghapus isi template dan pesan dari payload notifikasi
+	// entity.CredentialNotificationQueues karena berisi password sementara, link token atau kode OTP.
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id int64, lastError string) error
+	// Release mengembalikan pesan yang sudah di-claim tanpa dihitung sebagai percobaan, dipakai jika
+	// pesan belum bisa dikirim sama sekali (misalnya producer Kafka tidak dikonfigurasi).
+	Release(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+}
@@ -0,0 +1,12 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type PreferenceRepositoryInterface interface {
+	GetAll(ctx context.Context, userID int64) ([]entity.StoredPreferenceEntity, error)
+	GetByKey(ctx context.Context, userID int64, key string) (*entity.StoredPreferenceEntity, error)
+	Save(ctx context.Context, userID int64, values map[string]string, resetKeys []string) error
+}
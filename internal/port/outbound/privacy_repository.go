@@ -0,0 +1,20 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+	"time"
+)
+
+type PrivacyRepositoryInterface interface {
+	GetUserRoles(ctx context.Context, userID int64) ([]entity.RoleEntity, error)
+	GetVerificationTokens(ctx context.Context, userID int64) ([]entity.VerificationTokenEntity, error)
+	// GetOtherUserPhotos users.photo user lain (termasuk yang di soft delete) yang mengarah ke folder.
+	GetOtherUserPhotos(ctx context.Context, userID int64, folder string) ([]string, error)
+
+	CreateDeletionRequest(ctx context.Context, userID int64, scheduledAt time.Time) (*entity.DeletionRequestEntity, error)
+	GetPendingDeletionRequest(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error)
+	CancelDeletionRequest(ctx context.Context, userID int64) error
+	GetDueDeletionRequests(ctx context.Context, dueBefore time.Time, limit int) ([]entity.DeletionRequestEntity, error)
+	EraseUser(ctx context.Context, req entity.DeletionRequestEntity, erasedAt time.Time, events []entity.DomainEventEntity) error
+}
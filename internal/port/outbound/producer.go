@@ -1,6 +1,31 @@
 package outbound
 
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
 type KafkaProducerInterface interface {
-	ProduceMessage(topic string, data []byte) error
+	// ProduceEvent mengirim event dengan envelope sesuai format yang dikonfigurasi. Pada mode async
+	// method ini kembali setelah pesan masuk antrean, hasil pengiriman dilaporkan lewat
+	// kafkadelivery.Callback di ctx.
+	ProduceEvent(ctx context.Context, event entity.KafkaOutgoingEventEntity) error
+	// ProduceRecord mengirim pesan apa adanya, dipakai untuk retry topic, DLQ dan replay.
+	ProduceRecord(record entity.KafkaRecordEntity) error
+	// Flush menunggu semua pesan di antrean dikonfirmasi broker atau ctx habis.
+	Flush(ctx context.Context) error
+	Metrics() entity.KafkaProducerMetricsEntity
+	// Close mengirim sisa antrean lalu menutup koneksi.
 	Close() error
 }
+
+// KafkaSerializerInterface serializer data event berbasis schema (JSON, Protobuf atau Avro)
+// dengan framing wire format Confluent: magic byte 0 lalu schema ID 4 byte big-endian.
+type KafkaSerializerInterface interface {
+	// Serialize mengubah data event ke bytes sesuai schema untuk event.Type di topic tersebut.
+	Serialize(ctx context.Context, topic string, event entity.KafkaOutgoingEventEntity) ([]byte, error)
+	// Deserialize kebalikan Serialize, hasilnya pointer ke struct data event sesuai eventType.
+	Deserialize(ctx context.Context, eventType string, value []byte) (interface{}, error)
+	// ContentType dipakai sebagai header content-type pesan.
+	ContentType() string
+}
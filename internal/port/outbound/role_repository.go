@@ -3,6 +3,7 @@ package outbound
 import (
 	"clean-architecture/internal/domain/entity"
 	"context"
+	"time"
 )
 
 type RoleRepositoryInterface interface {
@@ -11,4 +12,7 @@ type RoleRepositoryInterface interface {
 	Create(ctx context.Context, req entity.RoleEntity) error
 	Delete(ctx context.Context, id int64) error
 	Update(ctx context.Context, req entity.RoleEntity) error
+	GetDeletedAll(ctx context.Context, search string) ([]entity.RoleEntity, error)
+	Restore(ctx context.Context, id int64) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
@@ -0,0 +1,13 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+)
+
+type SchemaRegistryInterface interface {
+	// Register mendaftarkan schema ke subject dan mengembalikan ID-nya. Schema yang sama
+	// mengembalikan ID lama, schema yang tidak kompatibel dengan versi terakhir ditolak dengan "409".
+	Register(ctx context.Context, subject string, schema entity.SchemaEntity) (int, error)
+	GetByID(ctx context.Context, id int) (*entity.SchemaEntity, error)
+}
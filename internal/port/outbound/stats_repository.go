@@ -0,0 +1,12 @@
+package outbound
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+	"time"
+)
+
+type StatsRepositoryInterface interface {
+	// GetDashboardStats mengisi semua metrik yang berasal dari database, sesi aktif dihitung di service.
+	GetDashboardStats(ctx context.Context, from, to time.Time) (*entity.DashboardStatsEntity, error)
+}
@@ -3,6 +3,7 @@ package outbound
 import (
 	"clean-architecture/internal/domain/entity"
 	"context"
+	"time"
 )
 
 type UserRepositoryInterface interface {
@@ -12,11 +13,21 @@ type UserRepositoryInterface interface {
 	UpdatePasswordByID(ctx context.Context, req entity.UserEntity) error
 	GetUserByID(ctx context.Context, userID int64) (*entity.UserEntity, error)
 	UpdateDataUser(ctx context.Context, req entity.UserEntity) error
+	GetUserByVerifiedPhone(ctx context.Context, phone string) (*entity.UserEntity, error)
+	UpdatePhoneVerified(ctx context.Context, userID int64, phone string) error
+	PatchUser(ctx context.Context, patch entity.UserPatchEntity) error
 
 	// Modul Customers Admin
 	GetCustomerAll(ctx context.Context, queryString entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error)
+	GetCustomerAllByCursor(ctx context.Context, queryString entity.QueryStringEntity) ([]entity.UserEntity, *entity.CursorPageEntity, error)
+	SearchCustomers(ctx context.Context, queryString entity.QueryStringEntity) ([]entity.CustomerSearchEntity, int64, int64, error)
+	GetCustomerNearby(ctx context.Context, query entity.NearbyQueryEntity) ([]entity.NearbyCustomerEntity, int64, int64, error)
 	GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error)
 	CreateCustomer(ctx context.Context, req entity.UserEntity) (int64, error)
 	UpdateCustomer(ctx context.Context, req entity.UserEntity) error
 	DeleteCustomer(ctx context.Context, customerID int64) error
+	ExportCustomers(ctx context.Context, queryString entity.QueryStringEntity, handle func(entity.UserEntity) error) error
+	GetDeletedCustomerAll(ctx context.Context, queryString entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error)
+	RestoreCustomer(ctx context.Context, customerID int64) error
+	PurgeDeletedCustomers(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
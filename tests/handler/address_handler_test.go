@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestGetAllAddresses_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/auth/addresses", nil)
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockAddressService)
+	mockService.On("GetAll", testifymock.Anything, int64(7)).Return([]entity.AddressEntity{
+		{ID: 1, UserID: 7, Label: "Rumah", IsDefault: true},
+	}, nil)
+
+	addressHandler := echoinboundadapter.NewAddressHandler(mockService)
+
+	err := addressHandler.GetAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"label":"Rumah"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCustomerAddress_Success(t *testing.T) {
+	body := `{"label":"Kantor","recipient_name":"Budi","phone":"08123456789","address_line":"Jl. Sudirman 1","lat":-6.2,"lng":106.8,"is_default":true}`
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/customers/3/addresses", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", "test-user")
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+
+	mockService := new(mock.MockAddressService)
+	mockService.On("Create", testifymock.Anything, testifymock.MatchedBy(func(req entity.AddressEntity) bool {
+		return req.UserID == 3 && req.Label == "Kantor" && req.IsDefault && req.Lat != nil && *req.Lat == -6.2
+	})).Return(&entity.AddressEntity{ID: 10, UserID: 3, Label: "Kantor", IsDefault: true}, nil)
+
+	addressHandler := echoinboundadapter.NewAddressHandler(mockService)
+
+	err := addressHandler.CreateCustomerAddress(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateAddress_InvalidLatitude(t *testing.T) {
+	body := `{"label":"Rumah","recipient_name":"Budi","phone":"08123456789","address_line":"Jl. Merdeka 2","lat":120}`
+	c, rec := tests.NewEchoContext(http.MethodPost, "/auth/addresses", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockAddressService)
+	addressHandler := echoinboundadapter.NewAddressHandler(mockService)
+
+	err := addressHandler.Create(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "Create", testifymock.Anything, testifymock.Anything)
+}
+
+func TestDeleteCustomerAddress_NotFound(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodDelete, "/admin/customers/3/addresses/99", nil)
+	c.Set("user", "test-user")
+	c.SetParamNames("id", "address_id")
+	c.SetParamValues("3", "99")
+
+	mockService := new(mock.MockAddressService)
+	mockService.On("Delete", testifymock.Anything, int64(3), int64(99)).Return(errors.New("404"))
+
+	addressHandler := echoinboundadapter.NewAddressHandler(mockService)
+
+	err := addressHandler.DeleteCustomerAddress(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
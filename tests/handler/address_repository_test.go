@@ -0,0 +1,40 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressRepository_DeleteDefaultPromotesOldestAddress(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	for _, row := range [][]driver.Value{
+		{int64(11), int64(7), "Jl. Rumah", true},
+		{int64(12), int64(7), "Jl. Kantor", false},
+		{int64(12), int64(7), "Jl. Kantor", true},
+	} {
+		recorder.AddRows(tests.SQLRows{
+			Match:   `FROM "user_addresses"`,
+			Columns: []string{"id", "user_id", "address_line", "is_default"},
+			Rows:    [][]driver.Value{row},
+		})
+	}
+
+	repo := outboundadapterpostgres.NewAddressRepository(db)
+	require.NoError(t, repo.Delete(context.Background(), 7, 11))
+
+	assert.Len(t, queriesContaining(recorder, `UPDATE "user_addresses" SET "deleted_at"=`), 1)
+	defaults := queriesContaining(recorder, `UPDATE "user_addresses" SET "is_default"=`)
+	require.Len(t, defaults, 2)
+	assert.Equal(t, []any{true, int64(12)}, []any{defaults[1].Args[0], defaults[1].Args[2]})
+
+	userUpdates := queriesContaining(recorder, `UPDATE "users"`)
+	require.Len(t, userUpdates, 1)
+	assert.Contains(t, userUpdates[0].Args, "Jl. Kantor")
+}
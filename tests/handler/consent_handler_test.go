@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestPublishDocument_VersionExists(t *testing.T) {
+	body := `{"doc_type":"terms","version":"2.0","url":"https://example.com/terms/2.0"}`
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/legal-documents", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockConsentService)
+	mockService.On("PublishDocument", testifymock.Anything, testifymock.MatchedBy(func(req entity.LegalDocumentEntity) bool {
+		return req.DocType == "terms" && req.Version == "2.0" && req.PublishedAt.IsZero()
+	})).Return(nil, errors.New("409"))
+
+	handler := echoinboundadapter.NewConsentHandler(mockService)
+
+	err := handler.PublishDocument(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPublishDocument_NotSuperAdmin(t *testing.T) {
+	body := `{"doc_type":"privacy","version":"2.0","url":"https://example.com/privacy/2.0"}`
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/legal-documents", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Set("user", `{"user_id": 2, "role_name": "Customer"}`)
+
+	mockService := new(mock.MockConsentService)
+	handler := echoinboundadapter.NewConsentHandler(mockService)
+
+	err := handler.PublishDocument(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	mockService.AssertNotCalled(t, "PublishDocument", testifymock.Anything, testifymock.Anything)
+}
@@ -0,0 +1,134 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils/cursor"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	in := entity.CursorEntity{OrderBy: "created_at", OrderType: "desc", Value: "2025-01-01T10:00:00.123456Z", ID: 42, Backward: true}
+
+	encoded, err := cursor.Encode(in)
+	require.NoError(t, err)
+	assert.NotContains(t, encoded, "=")
+
+	out := entity.CursorEntity{}
+	require.NoError(t, cursor.Decode(encoded, &out))
+	assert.Equal(t, in, out)
+
+	assert.EqualError(t, cursor.Decode("not a cursor!", &out), "invalid cursor")
+	assert.EqualError(t, cursor.Decode("bm90IGpzb24", &out), "invalid cursor")
+}
+
+func TestGetCustomerAllByCursor_UnsupportedSort(t *testing.T) {
+	phoneCursor, err := cursor.Encode(entity.CursorEntity{OrderBy: "phone", OrderType: "asc", Value: "+62812", ID: 1})
+	require.NoError(t, err)
+
+	paths := []string{
+		"/admin/customers?pagination=cursor&sort=phone",
+		"/admin/customers?pagination=cursor&order_by=updated_at",
+		"/admin/customers?pagination=cursor&sort=name,-id",
+		"/admin/customers?cursor=" + phoneCursor,
+	}
+	for _, path := range paths {
+		c, rec := tests.NewEchoContext(http.MethodGet, path, nil)
+		c.Set("user", "test-user")
+
+		mockService := new(mock.MockUserService)
+		userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+		require.NoError(t, userHandler.GetCustomerAll(c), path)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), "cursor pagination only supports sorting by one of id, name, email, created_at", path)
+		mockService.AssertNotCalled(t, "GetCustomerAllByCursor", testifymock.Anything, testifymock.Anything)
+	}
+}
+
+func TestGetCustomerAllByCursor_NextCursor(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers?pagination=cursor&sort=-name&limit=1", nil)
+	c.Set("user", "test-user")
+
+	next := &entity.CursorEntity{OrderBy: "name", OrderType: "desc", Value: "Budi", ID: 7}
+	mockService := new(mock.MockUserService)
+	mockService.On("GetCustomerAllByCursor", testifymock.Anything, testifymock.MatchedBy(func(query entity.QueryStringEntity) bool {
+		return query.OrderBy == "name" && query.OrderType == "desc" && query.Limit == 1 && query.Cursor == nil
+	})).Return([]entity.UserEntity{{ID: 7, Name: "Budi"}}, &entity.CursorPageEntity{Next: next}, nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	require.NoError(t, userHandler.GetCustomerAll(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Pagination struct {
+			NextCursor string `json:"next_cursor"`
+			PrevCursor string `json:"prev_cursor"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Empty(t, body.Pagination.PrevCursor)
+
+	decoded := entity.CursorEntity{}
+	require.NoError(t, cursor.Decode(body.Pagination.NextCursor, &decoded))
+	assert.Equal(t, *next, decoded)
+	mockService.AssertExpectations(t)
+}
+
+// Cursor created_at selalu ditulis dalam UTC dan nilai dari cursor dikirim ke database sebagai UTC.
+func TestUserRepository_CursorTimestampIsUTC(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	createdAt := time.Date(2025, 1, 2, 8, 30, 0, 123456000, jakarta)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "email", "created_at"},
+		Rows: [][]driver.Value{
+			{int64(9), "Budi", "budi@example.com", createdAt},
+			{int64(8), "Siti", "siti@example.com", createdAt.Add(-time.Hour)},
+		},
+	})
+
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	users, page, err := repo.GetCustomerAllByCursor(context.Background(), entity.QueryStringEntity{
+		Limit:     1,
+		OrderBy:   "created_at",
+		OrderType: "desc",
+		Fields:    []string{"id", "name"},
+		Cursor:    &entity.CursorEntity{OrderBy: "created_at", OrderType: "desc", Value: "2025-01-03T07:00:00+07:00", ID: 10},
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+
+	require.NotNil(t, page.Next)
+	assert.Equal(t, "2025-01-02T01:30:00.123456Z", page.Next.Value)
+
+	queries := recorder.Queries()
+	require.NotEmpty(t, queries)
+	assert.Contains(t, queries[0].SQL, "(users.created_at, users.id) < ($1, $2)")
+	assert.Equal(t, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC), queries[0].Args[0])
+}
+
+func TestUserRepository_CursorUnsupportedOrder(t *testing.T) {
+	db, _ := tests.NewGormDB(t)
+
+	_, _, err := outboundadapterpostgres.NewUserRepository(db).GetCustomerAllByCursor(context.Background(), entity.QueryStringEntity{
+		Limit:   10,
+		OrderBy: "phone",
+	})
+	assert.EqualError(t, err, "400")
+}
@@ -0,0 +1,120 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"net/http"
+	"testing"
+	"time"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/tests"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// fakeExportUserService hanya ExportCustomers yang dipakai export
+type fakeExportUserService struct {
+	service.UserServiceInterface
+	testifymock.Mock
+}
+
+func (f *fakeExportUserService) ExportCustomers(ctx context.Context, query entity.QueryStringEntity, handle func(entity.UserEntity) error) error {
+	args := f.Called(ctx, query, handle)
+	return args.Error(0)
+}
+
+func exportingUserService(users ...entity.UserEntity) *fakeExportUserService {
+	userService := new(fakeExportUserService)
+	userService.On("ExportCustomers", testifymock.Anything, testifymock.Anything, testifymock.Anything).
+		Run(func(args testifymock.Arguments) {
+			handle := args.Get(2).(func(entity.UserEntity) error)
+			for _, val := range users {
+				if err := handle(val); err != nil {
+					panic(err)
+				}
+			}
+		}).Return(nil)
+	return userService
+}
+
+func formulaCustomer() entity.UserEntity {
+	lat, lng := -6.2, 106.8
+	return entity.UserEntity{
+		ID:        1,
+		Name:      `=HYPERLINK("http://evil.example","klik")`,
+		Email:     "@budi@example.com",
+		Phone:     "+6281234567890",
+		RoleName:  "-Customer",
+		Address:   "+1+1",
+		Lat:       &lat,
+		Lng:       &lng,
+		CreatedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestExportCustomers_CSVEscapesFormulas(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/export?format=csv", nil)
+	c.Set("user", "test-user")
+
+	userHandler := echoinboundadapter.NewUserHandler(exportingUserService(formulaCustomer()))
+
+	require.NoError(t, userHandler.ExportCustomers(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	row := records[1]
+	assert.Equal(t, `'=HYPERLINK("http://evil.example","klik")`, row[1])
+	assert.Equal(t, "'@budi@example.com", row[2])
+	// Angka tidak diubah
+	assert.Equal(t, "+6281234567890", row[3])
+	assert.Equal(t, "'-Customer", row[4])
+	assert.Equal(t, "'+1+1", row[5])
+	assert.Equal(t, "-6.2", row[6])
+}
+
+func TestExportCustomers_XLSXEscapesFormulas(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/export?format=xlsx", nil)
+	c.Set("user", "test-user")
+
+	userHandler := echoinboundadapter.NewUserHandler(exportingUserService(formulaCustomer()))
+
+	require.NoError(t, userHandler.ExportCustomers(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	file, err := excelize.OpenReader(bytes.NewReader(rec.Body.Bytes()))
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("Sheet1")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, `'=HYPERLINK("http://evil.example","klik")`, rows[1][1])
+	assert.Equal(t, "'@budi@example.com", rows[1][2])
+	assert.Equal(t, "+6281234567890", rows[1][3])
+
+	formula, err := file.GetCellFormula("Sheet1", "B2")
+	require.NoError(t, err)
+	assert.Empty(t, formula)
+}
+
+// NDJSON bukan format spreadsheet, nilai dikirim apa adanya.
+func TestExportCustomers_NDJSONKeepsValues(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/export?format=ndjson", nil)
+	c.Set("user", "test-user")
+
+	userHandler := echoinboundadapter.NewUserHandler(exportingUserService(formulaCustomer()))
+
+	require.NoError(t, userHandler.ExportCustomers(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"=HYPERLINK(\"http://evil.example\",\"klik\")"`)
+}
@@ -0,0 +1,221 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/tests"
+	"clean-architecture/utils"
+	"clean-architecture/utils/spreadsheet"
+	"clean-architecture/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func newImportRequest(t *testing.T, fileName, content string, fields map[string]string) (echo.Context, *bytes.Buffer) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for key, val := range fields {
+		require.NoError(t, writer.WriteField(key, val))
+	}
+	part, err := writer.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/customers/import", body)
+	c.Request().Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	db, _ := tests.NewGormDB(t)
+	c.Echo().Validator = validator.NewValidator(db, "62")
+	return c, rec.Body
+}
+
+// fakeImportUserService hanya CreateCustomer yang dipakai import
+type fakeImportUserService struct {
+	service.UserServiceInterface
+	testifymock.Mock
+}
+
+func (f *fakeImportUserService) CreateCustomer(ctx context.Context, req entity.UserEntity) error {
+	args := f.Called(ctx, req)
+	return args.Error(0)
+}
+
+func TestImportCustomers_DryRunReportsRowErrors(t *testing.T) {
+	csv := strings.Join([]string{
+		"name,email,password,phone,lat,role_id",
+		"Budi,budi@example.com,password123,081234567890,-6.2,2",
+		"Siti,not-an-email,password123,081234567891,,2",
+		"Budi Lagi,BUDI@example.com,password123,081234567892,,2",
+		"Andi,andi@example.com,password123,081234567893,abc,2",
+		"Rina,rina@example.com,short,081234567894,,2",
+	}, "\n")
+	c, body := newImportRequest(t, "customers.csv", csv, map[string]string{"dry_run": "true"})
+
+	userService := new(fakeImportUserService)
+	importService := service.NewCustomerImportService(userService, tests.NewRedisServer(t).Client())
+	handler := echoinboundadapter.NewCustomerImportHandler(importService)
+
+	require.NoError(t, handler.ImportCustomers(c))
+	assert.Equal(t, http.StatusOK, c.Response().Status)
+
+	var resp struct {
+		Message string `json:"message"`
+		Data    struct {
+			Status      string `json:"status"`
+			TotalRows   int    `json:"total_rows"`
+			SuccessRows int    `json:"success_rows"`
+			FailedRows  int    `json:"failed_rows"`
+			Errors      []struct {
+				Row     int    `json:"row"`
+				Message string `json:"message"`
+			} `json:"errors"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body.Bytes(), &resp))
+
+	assert.Equal(t, "Dry run finished", resp.Message)
+	assert.Equal(t, utils.IMPORT_JOB_COMPLETED, resp.Data.Status)
+	assert.Equal(t, 5, resp.Data.TotalRows)
+	assert.Equal(t, 1, resp.Data.SuccessRows)
+	assert.Equal(t, 4, resp.Data.FailedRows)
+	require.Len(t, resp.Data.Errors, 4)
+	assert.Equal(t, 3, resp.Data.Errors[0].Row)
+	assert.Equal(t, "email is duplicated with row 2", resp.Data.Errors[1].Message)
+	assert.Equal(t, "lat must be a number", resp.Data.Errors[2].Message)
+	assert.Equal(t, 6, resp.Data.Errors[3].Row)
+
+	// Dry run tidak membuat customer
+	userService.AssertNotCalled(t, "CreateCustomer", testifymock.Anything, testifymock.Anything)
+}
+
+func TestImportCustomers_TooManyRows(t *testing.T) {
+	var csv strings.Builder
+	csv.WriteString("name,email,password,phone,role_id\n")
+	for range 10001 {
+		csv.WriteString("Budi,budi@example.com,password123,081234567890,2\n")
+	}
+	c, _ := newImportRequest(t, "customers.csv", csv.String(), nil)
+
+	importService := service.NewCustomerImportService(new(fakeImportUserService), tests.NewRedisServer(t).Client())
+	handler := echoinboundadapter.NewCustomerImportHandler(importService)
+
+	require.NoError(t, handler.ImportCustomers(c))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, c.Response().Status)
+}
+
+func TestSpreadsheetReadRows_StopsAtLimit(t *testing.T) {
+	csv := "name\nBudi\nSiti\nAndi\n"
+	records, err := spreadsheet.ReadRows(spreadsheet.FormatCSV, strings.NewReader(csv), 3)
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+
+	_, err = spreadsheet.ReadRows(spreadsheet.FormatCSV, strings.NewReader(csv), 2)
+	assert.ErrorIs(t, err, spreadsheet.ErrTooManyRows)
+
+	file := excelize.NewFile()
+	for i, val := range []string{"name", "Budi", "Siti", "Andi"} {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		require.NoError(t, file.SetCellValue("Sheet1", cell, val))
+	}
+	xlsx, err := file.WriteToBuffer()
+	require.NoError(t, err)
+
+	records, err = spreadsheet.ReadRows(spreadsheet.FormatXLSX, bytes.NewReader(xlsx.Bytes()), 3)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name"}, {"Budi"}, {"Siti"}, {"Andi"}}, records)
+
+	_, err = spreadsheet.ReadRows(spreadsheet.FormatXLSX, bytes.NewReader(xlsx.Bytes()), 2)
+	assert.ErrorIs(t, err, spreadsheet.ErrTooManyRows)
+}
+
+func importRows(emails ...string) func() []entity.CustomerImportRowEntity {
+	return func() []entity.CustomerImportRowEntity {
+		rows := []entity.CustomerImportRowEntity{}
+		for i, email := range emails {
+			rows = append(rows, entity.CustomerImportRowEntity{Row: i + 2, User: entity.UserEntity{Name: "Budi", Email: email}})
+		}
+		return rows
+	}
+}
+
+func TestCustomerImportJob_Completes(t *testing.T) {
+	userService := new(fakeImportUserService)
+	userService.On("CreateCustomer", testifymock.Anything, entity.UserEntity{Name: "Budi", Email: "budi@example.com"}).Return(nil)
+	userService.On("CreateCustomer", testifymock.Anything, entity.UserEntity{Name: "Budi", Email: "siti@example.com"}).Return(errors.New("role not found"))
+
+	importService := service.NewCustomerImportService(userService, tests.NewRedisServer(t).Client())
+	ctx := context.Background()
+
+	job, err := importService.CreateImportJob(ctx, 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, utils.IMPORT_JOB_PENDING, job.Status)
+
+	importService.RunImportJob(ctx, job.JobID, importRows("budi@example.com", "siti@example.com"), false)
+
+	stored, err := importService.GetImportJob(ctx, job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, utils.IMPORT_JOB_COMPLETED, stored.Status)
+	assert.Equal(t, 2, stored.ProcessedRows)
+	assert.Equal(t, 1, stored.SuccessRows)
+	assert.Equal(t, 1, stored.FailedRows)
+	assert.Equal(t, []entity.CustomerImportErrorEntity{{Row: 3, Email: "siti@example.com", Message: "role not found"}}, stored.Errors)
+	assert.NotNil(t, stored.FinishedAt)
+	assert.Empty(t, stored.Error)
+	userService.AssertExpectations(t)
+}
+
+func TestCustomerImportJob_PanicMarksFailed(t *testing.T) {
+	userService := new(fakeImportUserService)
+	userService.On("CreateCustomer", testifymock.Anything, testifymock.Anything).Run(func(args testifymock.Arguments) {
+		panic("nil pointer dereference")
+	})
+
+	importService := service.NewCustomerImportService(userService, tests.NewRedisServer(t).Client())
+	ctx := context.Background()
+
+	for name, loadRows := range map[string]func() []entity.CustomerImportRowEntity{
+		"load rows":       func() []entity.CustomerImportRowEntity { panic("validator crashed") },
+		"create customer": importRows("budi@example.com"),
+	} {
+		job, err := importService.CreateImportJob(ctx, 1, false)
+		require.NoError(t, err, name)
+
+		assert.NotPanics(t, func() { importService.RunImportJob(ctx, job.JobID, loadRows, false) }, name)
+
+		stored, err := importService.GetImportJob(ctx, job.JobID)
+		require.NoError(t, err, name)
+		assert.Equal(t, utils.IMPORT_JOB_FAILED, stored.Status, name)
+		assert.Equal(t, "import job failed unexpectedly", stored.Error, name)
+		assert.NotNil(t, stored.FinishedAt, name)
+	}
+}
+
+func TestCustomerImportJob_MissingJobIsMarkedFailed(t *testing.T) {
+	importService := service.NewCustomerImportService(new(fakeImportUserService), tests.NewRedisServer(t).Client())
+	ctx := context.Background()
+
+	importService.RunImportJob(ctx, "unknown", importRows("budi@example.com"), false)
+
+	stored, err := importService.GetImportJob(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Equal(t, utils.IMPORT_JOB_FAILED, stored.Status)
+}
@@ -0,0 +1,215 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	outboundkafka "clean-architecture/internal/adapter/outbound/kafka"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils/kafkaretry"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deadLetterTestGroup = "dlq-replay"
+
+// newDeadLetterBroker broker berisi dua pesan DLQ di offset 0 dan 1, offset replay yang
+// sudah di-commit adalah committed (-1 jika belum pernah).
+func newDeadLetterBroker(t *testing.T, committed int64) *sarama.MockBroker {
+	t.Helper()
+
+	topic := kafkaretry.DeadLetterTopic(consumerTestTopic)
+	fetch := &sarama.FetchResponse{Version: 10}
+	fetch.AddRecord(topic, 0, sarama.StringEncoder("user-7"), sarama.StringEncoder(`{"message":"satu"}`), 0)
+	fetch.AddRecord(topic, 0, nil, sarama.StringEncoder(`{"message":"dua"}`), 1)
+	block := fetch.GetBlock(topic, 0)
+	block.HighWaterMarkOffset = 2
+	block.LastStableOffset = 2
+
+	// MockFetchResponse tidak mendukung header sehingga header ditambahkan langsung ke record
+	records := block.RecordsSet[0].RecordBatch.Records
+	records[0].Headers = []*sarama.RecordHeader{
+		{Key: []byte("traceparent"), Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+		{Key: []byte(kafkaretry.HeaderOriginalTopic), Value: []byte(consumerTestTopic)},
+		{Key: []byte(kafkaretry.HeaderAttempt), Value: []byte("3")},
+		{Key: []byte(kafkaretry.HeaderError), Value: []byte("connection refused")},
+		{Key: []byte(kafkaretry.HeaderFailedAt), Value: []byte("2025-01-01T10:00:00Z")},
+	}
+	records[1].Headers = []*sarama.RecordHeader{
+		{Key: []byte(kafkaretry.HeaderError), Value: []byte("422")},
+	}
+
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(topic, 0, sarama.OffsetOldest, 0).
+			SetOffset(topic, 0, sarama.OffsetNewest, 2),
+		"FetchRequest": sarama.NewMockWrapper(fetch),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, deadLetterTestGroup, broker),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(deadLetterTestGroup, topic, 0, committed, "", sarama.ErrNoError),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+	})
+	t.Cleanup(broker.Close)
+	return broker
+}
+
+func newTestDeadLetterReader(t *testing.T, broker *sarama.MockBroker) outbound.DeadLetterReaderInterface {
+	t.Helper()
+
+	reader, err := outboundkafka.NewDeadLetterReader([]string{broker.Addr()}, deadLetterTestGroup, sarama.NewConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() { reader.Close() })
+	return reader
+}
+
+func TestDeadLetterReader_FetchParsesHeaders(t *testing.T) {
+	reader := newTestDeadLetterReader(t, newDeadLetterBroker(t, -1))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	messages, err := reader.Fetch(ctx, "orders.dlq", 0, true)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	first := messages[0]
+	assert.Equal(t, "orders.dlq", first.Topic)
+	assert.Equal(t, int64(0), first.Offset)
+	assert.Equal(t, "user-7", first.Key)
+	assert.Equal(t, `{"message":"satu"}`, first.Value)
+	assert.Equal(t, consumerTestTopic, first.OriginalTopic)
+	assert.Equal(t, "connection refused", first.Error)
+	assert.Equal(t, 3, first.Attempts)
+	require.NotNil(t, first.FailedAt)
+	assert.True(t, first.FailedAt.Equal(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", first.Headers["traceparent"])
+
+	second := messages[1]
+	assert.Equal(t, int64(1), second.Offset)
+	assert.Empty(t, second.Key)
+	assert.Empty(t, second.OriginalTopic)
+	assert.Equal(t, "422", second.Error)
+	assert.Zero(t, second.Attempts)
+	assert.Nil(t, second.FailedAt)
+}
+
+func TestDeadLetterReader_FetchFromCommittedAndLimit(t *testing.T) {
+	reader := newTestDeadLetterReader(t, newDeadLetterBroker(t, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pending, err := reader.Fetch(ctx, "orders.dlq", 0, true)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, int64(1), pending[0].Offset)
+
+	all, err := reader.Fetch(ctx, "orders.dlq", 1, false)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, int64(0), all[0].Offset)
+}
+
+func TestDeadLetterReader_CommitStoresNextOffset(t *testing.T) {
+	broker := newDeadLetterBroker(t, -1)
+	reader := newTestDeadLetterReader(t, broker)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, reader.Commit(ctx, entity.DeadLetterEntity{Topic: "orders.dlq", Partition: 0, Offset: 1}))
+
+	var commits int
+	for _, val := range broker.History() {
+		if _, ok := val.Request.(*sarama.OffsetCommitRequest); ok {
+			commits++
+		}
+	}
+	assert.Equal(t, 1, commits)
+
+	// Semua pesan sudah di-replay sehingga tidak ada lagi yang tertunda
+	pending, err := reader.Fetch(ctx, "orders.dlq", 0, true)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+// fakeDeadLetterReader mengembalikan messages dan mencatat pesan yang di-commit.
+type fakeDeadLetterReader struct {
+	outbound.DeadLetterReaderInterface
+	messages  []entity.DeadLetterEntity
+	fetched   []string
+	committed []int64
+}
+
+func (f *fakeDeadLetterReader) Fetch(ctx context.Context, topic string, limit int, fromCommitted bool) ([]entity.DeadLetterEntity, error) {
+	f.fetched = append(f.fetched, topic)
+	if !fromCommitted {
+		return nil, errors.New("replay must start from the committed offset")
+	}
+	return f.messages, nil
+}
+
+func (f *fakeDeadLetterReader) Commit(ctx context.Context, msg entity.DeadLetterEntity) error {
+	f.committed = append(f.committed, msg.Offset)
+	return nil
+}
+
+func TestDeadLetterService_Replay(t *testing.T) {
+	reader := &fakeDeadLetterReader{messages: []entity.DeadLetterEntity{
+		{
+			Topic:         "orders.dlq",
+			Offset:        0,
+			Key:           "user-7",
+			Value:         `{"message":"satu"}`,
+			OriginalTopic: "orders",
+			Headers: map[string]string{
+				"traceparent":                  "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				kafkaretry.HeaderOriginalTopic: "orders",
+				kafkaretry.HeaderAttempt:       "3",
+				kafkaretry.HeaderError:         "connection refused",
+			},
+		},
+		{Topic: "orders.dlq", Offset: 1, Value: `{"message":"dua"}`, Headers: map[string]string{}},
+	}}
+	producer := &recordingProducer{}
+
+	replayed, err := service.NewDeadLetterService(reader, producer).Replay(context.Background(), "orders", 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, replayed)
+	assert.Equal(t, []string{"orders.dlq"}, reader.fetched)
+	assert.Equal(t, []int64{0, 1}, reader.committed)
+	assert.Equal(t, []entity.KafkaRecordEntity{
+		{
+			Topic:   "orders",
+			Key:     []byte("user-7"),
+			Value:   []byte(`{"message":"satu"}`),
+			Headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		},
+		// Tanpa header topic asal, dikirim ke topic utama
+		{Topic: "orders", Value: []byte(`{"message":"dua"}`), Headers: map[string]string{}},
+	}, producer.records)
+}
+
+func TestDeadLetterService_ReplayStopsAtProduceError(t *testing.T) {
+	reader := &fakeDeadLetterReader{messages: []entity.DeadLetterEntity{
+		{Topic: "orders.dlq", Offset: 0, Value: `{}`},
+		{Topic: "orders.dlq", Offset: 1, Value: `{}`},
+	}}
+	producer := &recordingProducer{err: errors.New("broker not available")}
+
+	replayed, err := service.NewDeadLetterService(reader, producer).Replay(context.Background(), "orders", 10)
+
+	assert.EqualError(t, err, "broker not available")
+	assert.Zero(t, replayed)
+	assert.Empty(t, reader.committed)
+}
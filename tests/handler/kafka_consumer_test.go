@@ -0,0 +1,329 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	kafkainboundadapter "clean-architecture/internal/adapter/inbound/kafka"
+	outboundkafka "clean-architecture/internal/adapter/outbound/kafka"
+	"clean-architecture/internal/adapter/outbound/schemaregistry"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"clean-architecture/utils/kafkaretry"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const consumerTestTopic = "orders"
+
+// recordingProducer mencatat record yang diteruskan consumer, err dikembalikan jika diisi.
+type recordingProducer struct {
+	outbound.KafkaProducerInterface
+	records []entity.KafkaRecordEntity
+	err     error
+	onError func()
+}
+
+func (r *recordingProducer) ProduceRecord(record entity.KafkaRecordEntity) error {
+	if r.err != nil {
+		if r.onError != nil {
+			r.onError()
+		}
+		return r.err
+	}
+	r.records = append(r.records, record)
+	return nil
+}
+
+type fakeConsumerSession struct {
+	sarama.ConsumerGroupSession
+	ctx     context.Context
+	marked  []int64
+	commits int
+}
+
+func (f *fakeConsumerSession) Context() context.Context { return f.ctx }
+
+func (f *fakeConsumerSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	f.marked = append(f.marked, msg.Offset)
+}
+
+func (f *fakeConsumerSession) Commit() { f.commits++ }
+
+type fakeConsumerClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (f *fakeConsumerClaim) Messages() <-chan *sarama.ConsumerMessage { return f.messages }
+
+func newTestConsumer(t *testing.T, producer outbound.KafkaProducerInterface, handler kafkainboundadapter.HandlerFunc) *kafkainboundadapter.Consumer {
+	t.Helper()
+	return newSerializingTestConsumer(t, producer, nil, handler)
+}
+
+func newSerializingTestConsumer(t *testing.T, producer outbound.KafkaProducerInterface,
+	serializer outbound.KafkaSerializerInterface, handler kafkainboundadapter.HandlerFunc) *kafkainboundadapter.Consumer {
+	t.Helper()
+
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(consumerTestTopic, 0, broker.BrokerID()),
+	})
+	t.Cleanup(broker.Close)
+
+	consumer, err := kafkainboundadapter.NewKafkaConsumer([]string{broker.Addr()}, "test-group", []string{consumerTestTopic},
+		sarama.NewConfig(), producer, serializer, []time.Duration{time.Minute, time.Hour})
+	require.NoError(t, err)
+	t.Cleanup(func() { consumer.Close() })
+
+	consumer.Register("message_published", handler)
+	return consumer
+}
+
+// consumeMessages menjalankan ConsumeClaim sampai semua pesan habis dan mengembalikan session-nya.
+func consumeMessages(t *testing.T, ctx context.Context, consumer *kafkainboundadapter.Consumer, messages ...*sarama.ConsumerMessage) *fakeConsumerSession {
+	t.Helper()
+
+	claim := &fakeConsumerClaim{messages: make(chan *sarama.ConsumerMessage, len(messages))}
+	for _, msg := range messages {
+		claim.messages <- msg
+	}
+	close(claim.messages)
+
+	session := &fakeConsumerSession{ctx: ctx}
+	require.NoError(t, consumer.ConsumeClaim(session, claim))
+	return session
+}
+
+func legacyMessage(topic string, offset int64, headers map[string]string) *sarama.ConsumerMessage {
+	msg := &sarama.ConsumerMessage{
+		Topic:  topic,
+		Offset: offset,
+		Key:    []byte("user-7"),
+		Value:  []byte(`{"event":{"name":"message_published"},"metadata":{"sender":"test"},"body":{"type":"email","data":{"message":"halo"}}}`),
+	}
+	for key, val := range headers {
+		msg.Headers = append(msg.Headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(val)})
+	}
+	return msg
+}
+
+func TestKafkaConsumer_SuccessMarksMessage(t *testing.T) {
+	producer := &recordingProducer{}
+	var received []entity.ConsumedMessageEntity
+	consumer := newTestConsumer(t, producer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+		received = append(received, msg)
+		return nil
+	})
+
+	session := consumeMessages(t, context.Background(), consumer, legacyMessage(consumerTestTopic, 5, nil))
+
+	assert.Equal(t, []int64{5}, session.marked)
+	assert.Equal(t, 1, session.commits)
+	assert.Empty(t, producer.records)
+	require.Len(t, received, 1)
+	assert.Equal(t, consumerTestTopic, received[0].Topic)
+	assert.Equal(t, "user-7", received[0].Key)
+	assert.Equal(t, "test", received[0].Metadata.Sender)
+	assert.JSONEq(t, `{"message":"halo"}`, string(received[0].Data))
+	assert.Zero(t, received[0].Attempt)
+}
+
+func TestKafkaConsumer_TransientErrorGoesToRetryTopic(t *testing.T) {
+	producer := &recordingProducer{}
+	consumer := newTestConsumer(t, producer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+		return errors.New("connection refused")
+	})
+
+	before := time.Now()
+	session := consumeMessages(t, context.Background(), consumer,
+		legacyMessage(consumerTestTopic, 5, map[string]string{"x-request-id": "req-1"}))
+
+	assert.Equal(t, []int64{5}, session.marked)
+	require.Len(t, producer.records, 1)
+
+	record := producer.records[0]
+	assert.Equal(t, "orders.retry.1", record.Topic)
+	assert.Equal(t, []byte("user-7"), record.Key)
+	assert.Equal(t, "req-1", record.Headers["x-request-id"])
+	assert.Equal(t, consumerTestTopic, record.Headers[kafkaretry.HeaderOriginalTopic])
+	assert.Equal(t, "0", record.Headers[kafkaretry.HeaderOriginalPartition])
+	assert.Equal(t, "5", record.Headers[kafkaretry.HeaderOriginalOffset])
+	assert.Equal(t, "1", record.Headers[kafkaretry.HeaderAttempt])
+	assert.Equal(t, "connection refused", record.Headers[kafkaretry.HeaderError])
+
+	notBefore, err := time.Parse(time.RFC3339Nano, record.Headers[kafkaretry.HeaderNotBefore])
+	require.NoError(t, err)
+	assert.WithinDuration(t, before.Add(time.Minute), notBefore, 5*time.Second)
+}
+
+// Pesan dari retry topic terakhir yang gagal lagi dikirim ke DLQ topic asal dengan posisi asal yang sama.
+func TestKafkaConsumer_MaxAttemptsGoesToDeadLetter(t *testing.T) {
+	producer := &recordingProducer{}
+	var attempts []int
+	consumer := newTestConsumer(t, producer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+		attempts = append(attempts, msg.Attempt)
+		return errors.New("connection refused")
+	})
+
+	session := consumeMessages(t, context.Background(), consumer, legacyMessage("orders.retry.2", 3, map[string]string{
+		kafkaretry.HeaderOriginalTopic:     consumerTestTopic,
+		kafkaretry.HeaderOriginalPartition: "1",
+		kafkaretry.HeaderOriginalOffset:    "42",
+		kafkaretry.HeaderAttempt:           "2",
+		kafkaretry.HeaderNotBefore:         time.Now().Add(-time.Second).Format(time.RFC3339Nano),
+	}))
+
+	assert.Equal(t, []int64{3}, session.marked)
+	assert.Equal(t, []int{2}, attempts)
+	require.Len(t, producer.records, 1)
+
+	record := producer.records[0]
+	assert.Equal(t, "orders.dlq", record.Topic)
+	assert.Equal(t, consumerTestTopic, record.Headers[kafkaretry.HeaderOriginalTopic])
+	assert.Equal(t, "1", record.Headers[kafkaretry.HeaderOriginalPartition])
+	assert.Equal(t, "42", record.Headers[kafkaretry.HeaderOriginalOffset])
+	assert.Equal(t, "3", record.Headers[kafkaretry.HeaderAttempt])
+	assert.NotContains(t, record.Headers, kafkaretry.HeaderNotBefore)
+}
+
+func TestKafkaConsumer_InvalidMessagesGoStraightToDeadLetter(t *testing.T) {
+	producer := &recordingProducer{}
+	var calls int
+	consumer := newTestConsumer(t, producer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+		calls++
+		return errors.New("422")
+	})
+
+	malformed := legacyMessage(consumerTestTopic, 6, nil)
+	malformed.Value = []byte(`{"event":`)
+
+	session := consumeMessages(t, context.Background(), consumer, legacyMessage(consumerTestTopic, 5, nil), malformed)
+
+	assert.Equal(t, []int64{5, 6}, session.marked)
+	assert.Equal(t, 1, calls)
+	require.Len(t, producer.records, 2)
+	for _, record := range producer.records {
+		assert.Equal(t, "orders.dlq", record.Topic)
+	}
+	assert.Equal(t, "422", producer.records[0].Headers[kafkaretry.HeaderError])
+	assert.Equal(t, "1", producer.records[0].Headers[kafkaretry.HeaderAttempt])
+	assert.Equal(t, "6", producer.records[1].Headers[kafkaretry.HeaderOriginalOffset])
+	assert.Equal(t, "0", producer.records[1].Headers[kafkaretry.HeaderAttempt])
+}
+
+func TestKafkaConsumer_UnknownEventIsSkipped(t *testing.T) {
+	producer := &recordingProducer{}
+	consumer := newTestConsumer(t, producer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+		t.Fatal("handler must not be called")
+		return nil
+	})
+
+	msg := legacyMessage(consumerTestTopic, 5, nil)
+	msg.Value = []byte(`{"event":{"name":"user_deleted"},"body":{"data":{}}}`)
+
+	session := consumeMessages(t, context.Background(), consumer, msg)
+
+	assert.Equal(t, []int64{5}, session.marked)
+	assert.Empty(t, producer.records)
+}
+
+// Producer gagal sampai session berakhir, offset tidak ditandai agar pesan dibaca ulang.
+func TestKafkaConsumer_ForwardFailureDoesNotMarkMessage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	producer := &recordingProducer{err: errors.New("broker not available"), onError: cancel}
+	consumer := newTestConsumer(t, producer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+		return errors.New("connection refused")
+	})
+
+	session := consumeMessages(t, ctx, consumer, legacyMessage(consumerTestTopic, 5, nil))
+
+	assert.Empty(t, session.marked)
+	assert.Zero(t, session.commits)
+}
+
+// Data CloudEvents binary dibaca lewat serializer yang dikonfigurasi, handler tetap menerima JSON.
+func TestKafkaConsumer_DeserializesBinaryData(t *testing.T) {
+	for _, format := range serializerFormats {
+		serializer, err := outboundkafka.NewSerializer(format, schemaregistry.NewMemoryRegistry())
+		require.NoError(t, err, format)
+
+		data := &entity.KafkaData{ReceiverEmail: "budi@example.com", Message: "Halo Budi", ReceiverId: 10, MessageId: "msg-1"}
+		value, err := serializer.Serialize(context.Background(), consumerTestTopic, entity.KafkaOutgoingEventEntity{
+			Type: utils.EVENT_MESSAGE_PUBLISHED,
+			Data: data,
+		})
+		require.NoError(t, err, format)
+
+		producer := &recordingProducer{}
+		var received []entity.ConsumedMessageEntity
+		consumer := newSerializingTestConsumer(t, producer, serializer, func(ctx context.Context, msg entity.ConsumedMessageEntity) error {
+			received = append(received, msg)
+			return nil
+		})
+
+		binaryMessage := func(offset int64, value []byte) *sarama.ConsumerMessage {
+			return &sarama.ConsumerMessage{Topic: consumerTestTopic, Offset: offset, Value: value, Headers: []*sarama.RecordHeader{
+				{Key: []byte("ce_type"), Value: []byte(utils.EVENT_MESSAGE_PUBLISHED)},
+				{Key: []byte("ce_id"), Value: []byte("event-1")},
+				{Key: []byte("content-type"), Value: []byte(serializer.ContentType())},
+			}}
+		}
+
+		// Data JSON biasa tidak sesuai wire format serializer sehingga masuk DLQ
+		session := consumeMessages(t, context.Background(), consumer,
+			binaryMessage(5, value), binaryMessage(6, []byte(`{"message":"halo"}`)))
+
+		assert.Equal(t, []int64{5, 6}, session.marked, format)
+		require.Len(t, received, 1, format)
+		assert.Equal(t, "event-1", received[0].ID, format)
+
+		decoded := &entity.KafkaData{}
+		require.NoError(t, json.Unmarshal(received[0].Data, decoded), format)
+		assert.Equal(t, data, decoded, format)
+
+		require.Len(t, producer.records, 1, format)
+		assert.Equal(t, "orders.dlq", producer.records[0].Topic, format)
+		assert.Equal(t, "6", producer.records[0].Headers[kafkaretry.HeaderOriginalOffset], format)
+	}
+}
+
+func TestKafkaRetry_Topics(t *testing.T) {
+	assert.Equal(t, "notification-events.retry.1", kafkaretry.RetryTopic("notification-events", 1))
+	assert.Equal(t, "notification-events.retry.3", kafkaretry.RetryTopic("notification-events", 3))
+	assert.Equal(t, "notification-events.dlq", kafkaretry.DeadLetterTopic("notification-events"))
+}
+
+func TestKafkaRetry_OriginalHeadersStripsInternalHeaders(t *testing.T) {
+	headers := map[string]string{
+		"traceparent":                      "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"ce_type":                          "message_published",
+		kafkaretry.HeaderOriginalTopic:     "orders",
+		kafkaretry.HeaderOriginalPartition: "0",
+		kafkaretry.HeaderOriginalOffset:    "5",
+		kafkaretry.HeaderAttempt:           "2",
+		kafkaretry.HeaderNotBefore:         "2025-01-01T10:00:00Z",
+		kafkaretry.HeaderError:             "connection refused",
+		kafkaretry.HeaderFailedAt:          "2025-01-01T09:59:00Z",
+	}
+
+	assert.Equal(t, map[string]string{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"ce_type":     "message_published",
+	}, kafkaretry.OriginalHeaders(headers))
+
+	// Map asli tidak ikut berubah
+	assert.Len(t, headers, 9)
+}
@@ -0,0 +1,175 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	outboundkafka "clean-architecture/internal/adapter/outbound/kafka"
+	"clean-architecture/internal/adapter/outbound/schemaregistry"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+	"clean-architecture/utils/traceparent"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envelopeTestTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// sentMessageRecorder interceptor producer yang menyimpan pesan persis seperti dikirim ke broker.
+type sentMessageRecorder struct {
+	mu       sync.Mutex
+	messages []*sarama.ProducerMessage
+}
+
+func (s *sentMessageRecorder) OnSend(msg *sarama.ProducerMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, msg)
+}
+
+// produceEnvelope mengirim event lewat producer sync dengan format tertentu dan mengembalikan
+// value serta header pesan yang sampai ke broker.
+func produceEnvelope(t *testing.T, format string, serializer outbound.KafkaSerializerInterface,
+	event entity.KafkaOutgoingEventEntity) ([]byte, map[string]string) {
+	t.Helper()
+
+	broker := newMockKafkaBroker(t)
+	recorder := &sentMessageRecorder{}
+
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Interceptors = []sarama.ProducerInterceptor{recorder}
+
+	producer, err := outboundkafka.NewKafkaProducer([]string{broker.Addr()}, config, format, "/clean-architecture", serializer)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	require.NoError(t, producer.ProduceEvent(context.Background(), event))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Len(t, recorder.messages, 1)
+
+	msg := recorder.messages[0]
+	assert.Equal(t, producerTestTopic, msg.Topic)
+	key, err := msg.Key.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("user-10"), key)
+
+	value, err := msg.Value.Encode()
+	require.NoError(t, err)
+	headers := map[string]string{}
+	for _, header := range msg.Headers {
+		headers[string(header.Key)] = string(header.Value)
+	}
+	return value, headers
+}
+
+func envelopeTestEvent() entity.KafkaOutgoingEventEntity {
+	return entity.KafkaOutgoingEventEntity{
+		Topic:       producerTestTopic,
+		Key:         "user-10",
+		ID:          "event-1",
+		Type:        utils.EVENT_MESSAGE_PUBLISHED,
+		Version:     2,
+		Subject:     "users/10",
+		Time:        time.Date(2026, 10, 19, 15, 30, 15, 123000000, time.FixedZone("WIB", 7*60*60)),
+		TraceParent: envelopeTestTraceParent,
+		Data:        map[string]string{"message": "halo"},
+	}
+}
+
+func TestEncodeEvent_CloudEventsStructured(t *testing.T) {
+	value, headers := produceEnvelope(t, outboundkafka.FormatCloudEventsStructured, nil, envelopeTestEvent())
+
+	assert.Equal(t, map[string]string{
+		"content-type":     "application/cloudevents+json; charset=UTF-8",
+		traceparent.Header: envelopeTestTraceParent,
+	}, headers)
+	assert.JSONEq(t, `{
+		"specversion": "1.0",
+		"id": "event-1",
+		"source": "/clean-architecture",
+		"type": "message_published",
+		"subject": "users/10",
+		"time": "2026-10-19T08:30:15.123Z",
+		"datacontenttype": "application/json",
+		"eventversion": 2,
+		"traceparent": "`+envelopeTestTraceParent+`",
+		"data": {"message": "halo"}
+	}`, string(value))
+}
+
+func TestEncodeEvent_CloudEventsBinary(t *testing.T) {
+	value, headers := produceEnvelope(t, outboundkafka.FormatCloudEventsBinary, nil, envelopeTestEvent())
+
+	assert.Equal(t, map[string]string{
+		"content-type":     "application/json",
+		"ce_specversion":   "1.0",
+		"ce_id":            "event-1",
+		"ce_source":        "/clean-architecture",
+		"ce_type":          "message_published",
+		"ce_subject":       "users/10",
+		"ce_time":          "2026-10-19T08:30:15.123Z",
+		"ce_eventversion":  "2",
+		"ce_traceparent":   envelopeTestTraceParent,
+		traceparent.Header: envelopeTestTraceParent,
+	}, headers)
+	// Value hanya data event tanpa envelope
+	assert.JSONEq(t, `{"message": "halo"}`, string(value))
+}
+
+// Subject dan versi kosong tidak dikirim sebagai header.
+func TestEncodeEvent_CloudEventsBinaryOptionalAttributes(t *testing.T) {
+	event := envelopeTestEvent()
+	event.Subject = ""
+	event.Version = 0
+
+	_, headers := produceEnvelope(t, outboundkafka.FormatCloudEventsBinary, nil, event)
+
+	assert.NotContains(t, headers, "ce_subject")
+	assert.NotContains(t, headers, "ce_eventversion")
+	assert.Equal(t, envelopeTestTraceParent, headers[traceparent.Header])
+}
+
+func TestEncodeEvent_CloudEventsBinaryWithSerializer(t *testing.T) {
+	serializer, err := outboundkafka.NewSerializer(outboundkafka.SerializerJSON, schemaregistry.NewMemoryRegistry())
+	require.NoError(t, err)
+
+	event := envelopeTestEvent()
+	event.Version = 0
+	event.Data = &entity.KafkaData{ReceiverEmail: "budi@example.com", Message: "Halo Budi", ReceiverId: 10, MessageId: "msg-1"}
+
+	value, headers := produceEnvelope(t, outboundkafka.FormatCloudEventsBinary, serializer, event)
+
+	assert.Equal(t, serializer.ContentType(), headers["content-type"])
+	assert.Equal(t, "message_published", headers["ce_type"])
+	assert.Equal(t, envelopeTestTraceParent, headers["ce_traceparent"])
+	assert.Equal(t, envelopeTestTraceParent, headers[traceparent.Header])
+
+	decoded, err := serializer.Deserialize(context.Background(), event.Type, value)
+	require.NoError(t, err)
+	assert.Equal(t, event.Data, decoded)
+}
+
+// Format legacy tetap membawa header traceparent walaupun envelope-nya tidak punya atribut trace.
+func TestEncodeEvent_LegacyKeepsTraceParentHeader(t *testing.T) {
+	value, headers := produceEnvelope(t, outboundkafka.FormatLegacy, nil, envelopeTestEvent())
+
+	assert.Equal(t, map[string]string{
+		"content-type":     "application/json",
+		traceparent.Header: envelopeTestTraceParent,
+	}, headers)
+
+	var envelope entity.KafkaConsumedEnvelope
+	require.NoError(t, json.Unmarshal(value, &envelope))
+	assert.Equal(t, "message_published", envelope.Event.Name)
+	assert.Equal(t, 2, envelope.Event.Version)
+	assert.JSONEq(t, `{"message": "halo"}`, string(envelope.Body.Data))
+}
@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	outboundkafka "clean-architecture/internal/adapter/outbound/kafka"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/utils/kafkadelivery"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const producerTestTopic = "clean-architecture"
+
+func newMockKafkaBroker(t *testing.T) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(producerTestTopic, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+	t.Cleanup(broker.Close)
+	return broker
+}
+
+func newAsyncProducerConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+	config.Producer.Return.Errors = true
+	config.Producer.Flush.Messages = 10
+	config.Producer.Flush.Frequency = 20 * time.Millisecond
+	config.Producer.Compression = sarama.CompressionSnappy
+	return config
+}
+
+func TestAsyncKafkaProducer_ReportsDeliveryThroughCallback(t *testing.T) {
+	broker := newMockKafkaBroker(t)
+
+	producer, err := outboundkafka.NewKafkaAsyncProducer([]string{broker.Addr()}, newAsyncProducerConfig(),
+		outboundkafka.FormatCloudEventsStructured, "/clean-architecture", nil)
+	require.NoError(t, err)
+
+	results := make(chan error, 3)
+	ctx := kafkadelivery.NewContext(context.Background(), func(err error) { results <- err })
+	for i := 0; i < 3; i++ {
+		err := producer.ProduceEvent(ctx, entity.KafkaOutgoingEventEntity{
+			Topic: producerTestTopic,
+			ID:    "event-1",
+			Type:  "message_published",
+			Time:  time.Now(),
+			Data:  map[string]string{"message": "halo"},
+		})
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case err := <-results:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("delivery callback was not called")
+		}
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, producer.Flush(flushCtx))
+
+	assert.Equal(t, entity.KafkaProducerMetricsEntity{
+		Mode:      outboundkafka.ProducerModeAsync,
+		Enqueued:  3,
+		Delivered: 3,
+	}, producer.Metrics())
+
+	require.NoError(t, producer.Close())
+	assert.Error(t, producer.ProduceRecord(entity.KafkaRecordEntity{Topic: producerTestTopic, Value: []byte("{}")}))
+}
+
+// Close menunggu batch yang belum penuh terkirim setelah linger sebelum menutup koneksi.
+func TestAsyncKafkaProducer_CloseFlushesPendingMessages(t *testing.T) {
+	broker := newMockKafkaBroker(t)
+
+	config := newAsyncProducerConfig()
+	config.Producer.Flush.Messages = 1000
+	config.Producer.Flush.Frequency = 200 * time.Millisecond
+
+	producer, err := outboundkafka.NewKafkaAsyncProducer([]string{broker.Addr()}, config,
+		outboundkafka.FormatLegacy, "", nil)
+	require.NoError(t, err)
+
+	var delivered []error
+	ctx := kafkadelivery.NewContext(context.Background(), func(err error) { delivered = append(delivered, err) })
+	require.NoError(t, producer.ProduceEvent(ctx, entity.KafkaOutgoingEventEntity{
+		Topic: producerTestTopic,
+		Type:  "message_published",
+		Time:  time.Now(),
+		Data:  map[string]string{"message": "halo"},
+	}))
+
+	require.NoError(t, producer.Close())
+	assert.Equal(t, []error{nil}, delivered)
+	assert.Equal(t, int64(1), producer.Metrics().Delivered)
+}
+
+func TestAsyncKafkaProducer_RequiresSuccessChannel(t *testing.T) {
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = false
+
+	_, err := outboundkafka.NewKafkaAsyncProducer([]string{"localhost:0"}, config, outboundkafka.FormatLegacy, "", nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,315 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	outboundkafka "clean-architecture/internal/adapter/outbound/kafka"
+	"clean-architecture/internal/adapter/outbound/schemaregistry"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/utils"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const serializerTestTopic = "clean-architecture-events"
+
+var serializerFormats = []string{outboundkafka.SerializerJSON, outboundkafka.SerializerProtobuf, outboundkafka.SerializerAvro}
+
+// Avro menyimpan timestamp dalam milidetik, waktu contoh dibuat tanpa sisa di bawah milidetik
+var serializerTestTime = time.Date(2026, 10, 19, 8, 30, 15, 123000000, time.UTC)
+
+func serializerTestEvents() []entity.KafkaOutgoingEventEntity {
+	events := []entity.KafkaOutgoingEventEntity{{
+		Type: utils.EVENT_MESSAGE_PUBLISHED,
+		Data: &entity.KafkaData{
+			ReceiverEmail:    "budi@example.com",
+			Message:          "Halo Budi",
+			ReceiverId:       10,
+			Subject:          "Selamat datang",
+			NotificationType: "EMAIL",
+			MessageId:        "msg-1",
+		},
+	}}
+
+	domainEvents := []entity.DomainEventEntity{
+		entity.NewUserRegisteredEvent(entity.UserRegisteredEventEntity{UserID: 10, Name: "Budi", Email: "budi@example.com",
+			RoleName: "Customer", Source: entity.UserRegisteredSourceSignUp, RegisteredAt: serializerTestTime}),
+		entity.NewUserVerifiedEvent(entity.UserVerifiedEventEntity{UserID: 10, VerifiedAt: serializerTestTime}),
+		entity.NewUserUpdatedEvent(entity.UserUpdatedEventEntity{UserID: 10, ChangedFields: []string{"name", "phone"}, UpdatedAt: serializerTestTime}),
+		entity.NewUserDeletedEvent(entity.UserDeletedEventEntity{UserID: 10, Reason: entity.UserDeletedReasonErased, RequestID: 3, DeletedAt: serializerTestTime}),
+		entity.NewRoleAssignedEvent(entity.RoleAssignedEventEntity{UserID: 10, RoleID: 2, RoleName: "Customer", PreviousRoleID: 1, AssignedAt: serializerTestTime}),
+		entity.NewPasswordChangedEvent(entity.PasswordChangedEventEntity{UserID: 10, Reason: entity.PasswordChangedReasonReset, ChangedAt: serializerTestTime}),
+		entity.NewSessionRevokedEvent(entity.SessionRevokedEventEntity{UserID: 10, Reason: entity.SessionRevokedReasonPasswordChanged, RevokedAt: serializerTestTime}),
+	}
+	for _, val := range domainEvents {
+		data := val.Data
+		events = append(events, entity.KafkaOutgoingEventEntity{Type: val.Name, Version: val.Version, Data: &data})
+	}
+	return events
+}
+
+// expectedData data event sebagai pointer struct, sama dengan hasil Deserialize.
+func expectedData(t *testing.T, event entity.KafkaOutgoingEventEntity) interface{} {
+	switch data := event.Data.(type) {
+	case *entity.KafkaData:
+		return data
+	case *interface{}:
+		switch val := (*data).(type) {
+		case entity.UserRegisteredEventEntity:
+			return &val
+		case entity.UserVerifiedEventEntity:
+			return &val
+		case entity.UserUpdatedEventEntity:
+			return &val
+		case entity.UserDeletedEventEntity:
+			return &val
+		case entity.RoleAssignedEventEntity:
+			return &val
+		case entity.PasswordChangedEventEntity:
+			return &val
+		case entity.SessionRevokedEventEntity:
+			return &val
+		}
+	}
+	t.Fatalf("unexpected event data %T", event.Data)
+	return nil
+}
+
+func TestKafkaSerializer_RoundTrip(t *testing.T) {
+	for _, format := range serializerFormats {
+		t.Run(format, func(t *testing.T) {
+			serializer, err := outboundkafka.NewSerializer(format, schemaregistry.NewMemoryRegistry())
+			require.NoError(t, err)
+
+			for _, event := range serializerTestEvents() {
+				value, err := serializer.Serialize(context.Background(), serializerTestTopic, event)
+				require.NoError(t, err, event.Type)
+				assert.Equal(t, byte(0), value[0], event.Type)
+				assert.NotZero(t, binary.BigEndian.Uint32(value[1:5]), event.Type)
+
+				decoded, err := serializer.Deserialize(context.Background(), event.Type, value)
+				require.NoError(t, err, event.Type)
+				assert.Equal(t, expectedData(t, event), decoded, event.Type)
+			}
+		})
+	}
+}
+
+// Event dari outbox relay berupa json.RawMessage, hasilnya harus sama dengan data struct.
+func TestKafkaSerializer_OutboxPayload(t *testing.T) {
+	for _, format := range serializerFormats {
+		t.Run(format, func(t *testing.T) {
+			serializer, err := outboundkafka.NewSerializer(format, schemaregistry.NewMemoryRegistry())
+			require.NoError(t, err)
+
+			event := entity.KafkaOutgoingEventEntity{
+				Type: utils.EVENT_USER_UPDATED,
+				Data: entity.UserUpdatedEventEntity{UserID: 10, ChangedFields: []string{"email"}, UpdatedAt: serializerTestTime},
+			}
+			fromStruct, err := serializer.Serialize(context.Background(), serializerTestTopic, event)
+			require.NoError(t, err)
+
+			raw, err := json.Marshal(event.Data)
+			require.NoError(t, err)
+			event.Data = json.RawMessage(raw)
+			fromOutbox, err := serializer.Serialize(context.Background(), serializerTestTopic, event)
+			require.NoError(t, err)
+
+			assert.Equal(t, fromStruct, fromOutbox)
+		})
+	}
+}
+
+func TestKafkaSerializer_RejectsDataOutsideSchema(t *testing.T) {
+	for _, format := range serializerFormats {
+		t.Run(format, func(t *testing.T) {
+			serializer, err := outboundkafka.NewSerializer(format, schemaregistry.NewMemoryRegistry())
+			require.NoError(t, err)
+
+			_, err = serializer.Serialize(context.Background(), serializerTestTopic, entity.KafkaOutgoingEventEntity{
+				Type: utils.EVENT_USER_VERIFIED,
+				Data: json.RawMessage(`{"user_id":10,"verified_at":"2026-10-19T08:30:15Z","verified_by":"admin"}`),
+			})
+			assert.Error(t, err)
+
+			_, err = serializer.Serialize(context.Background(), serializerTestTopic, entity.KafkaOutgoingEventEntity{
+				Type: "user.unknown",
+				Data: json.RawMessage(`{}`),
+			})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKafkaSerializer_ProtobufMessageIndex(t *testing.T) {
+	serializer, err := outboundkafka.NewSerializer(outboundkafka.SerializerProtobuf, schemaregistry.NewMemoryRegistry())
+	require.NoError(t, err)
+
+	events := serializerTestEvents()
+
+	// NotificationMessage message pertama di events.proto: index [0] ditulis sebagai satu byte 0
+	value, err := serializer.Serialize(context.Background(), serializerTestTopic, events[0])
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), value[5])
+
+	// UserRegistered message kedua: jumlah index 1 lalu index 1, keduanya zigzag varint
+	value, err = serializer.Serialize(context.Background(), serializerTestTopic, events[1])
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2, 2}, value[5:7])
+}
+
+// testdata/schema berisi schema yang sudah dipublikasikan ke consumer. Schema di
+// internal/adapter/outbound/kafka/schema harus tetap kompatibel (BACKWARD) dengan schema tersebut.
+func TestKafkaSerializer_CompatibleWithPublishedSchemas(t *testing.T) {
+	published := map[string]func(file string) (entity.SchemaEntity, string){
+		outboundkafka.SerializerJSON: func(file string) (entity.SchemaEntity, string) {
+			return entity.SchemaEntity{Type: entity.SchemaTypeJSON}, filepath.Join("json", file+".json")
+		},
+		outboundkafka.SerializerProtobuf: func(file string) (entity.SchemaEntity, string) {
+			return entity.SchemaEntity{Type: entity.SchemaTypeProtobuf}, "events.proto"
+		},
+		outboundkafka.SerializerAvro: func(file string) (entity.SchemaEntity, string) {
+			return entity.SchemaEntity{Type: entity.SchemaTypeAvro}, filepath.Join("avro", file+".avsc")
+		},
+	}
+	files := map[string]string{
+		utils.EVENT_MESSAGE_PUBLISHED: "notification_message",
+		utils.EVENT_USER_REGISTERED:   "user_registered",
+		utils.EVENT_USER_VERIFIED:     "user_verified",
+		utils.EVENT_USER_UPDATED:      "user_updated",
+		utils.EVENT_USER_DELETED:      "user_deleted",
+		utils.EVENT_ROLE_ASSIGNED:     "role_assigned",
+		utils.EVENT_PASSWORD_CHANGED:  "password_changed",
+		utils.EVENT_SESSION_REVOKED:   "session_revoked",
+	}
+
+	for _, format := range serializerFormats {
+		t.Run(format, func(t *testing.T) {
+			registry := schemaregistry.NewMemoryRegistry()
+			for eventType, file := range files {
+				schema, path := published[format](file)
+				raw, err := os.ReadFile(filepath.Join("testdata", "schema", path))
+				require.NoError(t, err)
+				schema.Schema = string(raw)
+
+				subject, err := outboundkafka.SchemaSubject(serializerTestTopic, eventType)
+				require.NoError(t, err)
+				_, err = registry.Register(context.Background(), subject, schema)
+				require.NoError(t, err)
+			}
+
+			serializer, err := outboundkafka.NewSerializer(format, registry)
+			require.NoError(t, err)
+			for _, event := range serializerTestEvents() {
+				_, err := serializer.Serialize(context.Background(), serializerTestTopic, event)
+				assert.NoError(t, err, event.Type)
+			}
+		})
+	}
+}
+
+// Consumer dengan schema baru tetap bisa membaca pesan yang ditulis dengan schema lama.
+func TestKafkaSerializer_ReadsOlderAvroWriterSchema(t *testing.T) {
+	registry := schemaregistry.NewMemoryRegistry()
+	subject, err := outboundkafka.SchemaSubject(serializerTestTopic, utils.EVENT_USER_DELETED)
+	require.NoError(t, err)
+
+	oldSchema := `{"type":"record","name":"UserDeleted","namespace":"clean_architecture.events","fields":[
+		{"name":"user_id","type":"long"},
+		{"name":"reason","type":"string"},
+		{"name":"deleted_at","type":{"type":"long","logicalType":"timestamp-millis"}}]}`
+	id, err := registry.Register(context.Background(), subject, entity.SchemaEntity{Type: entity.SchemaTypeAvro, Schema: oldSchema})
+	require.NoError(t, err)
+
+	payload, err := avro.Marshal(avro.MustParse(oldSchema), map[string]interface{}{
+		"user_id":    int64(10),
+		"reason":     entity.UserDeletedReasonSoftDeleted,
+		"deleted_at": serializerTestTime,
+	})
+	require.NoError(t, err)
+	value := append([]byte{0, 0, 0, 0, byte(id)}, payload...)
+
+	serializer, err := outboundkafka.NewSerializer(outboundkafka.SerializerAvro, registry)
+	require.NoError(t, err)
+
+	decoded, err := serializer.Deserialize(context.Background(), utils.EVENT_USER_DELETED, value)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.UserDeletedEventEntity{
+		UserID:    10,
+		Reason:    entity.UserDeletedReasonSoftDeleted,
+		DeletedAt: serializerTestTime,
+	}, decoded)
+}
+
+func TestSchemaRegistry_RejectsIncompatibleChanges(t *testing.T) {
+	cases := []struct {
+		name       string
+		schemaType string
+		previous   string
+		compatible string
+		breaking   string
+	}{
+		{
+			name:       "avro field without default",
+			schemaType: entity.SchemaTypeAvro,
+			previous:   `{"type":"record","name":"UserVerified","fields":[{"name":"user_id","type":"long"}]}`,
+			compatible: `{"type":"record","name":"UserVerified","fields":[{"name":"user_id","type":"long"},{"name":"channel","type":"string","default":""}]}`,
+			breaking:   `{"type":"record","name":"UserVerified","fields":[{"name":"user_id","type":"long"},{"name":"channel","type":"string"}]}`,
+		},
+		{
+			name:       "protobuf field type changed",
+			schemaType: entity.SchemaTypeProtobuf,
+			previous:   `syntax = "proto3"; message UserVerified { int64 user_id = 1; string channel = 2; }`,
+			compatible: `syntax = "proto3"; message UserVerified { int64 user_id = 1; reserved 2; string source = 3; }`,
+			breaking:   `syntax = "proto3"; message UserVerified { string user_id = 1; string channel = 2; }`,
+		},
+		{
+			name:       "protobuf field removed without reserved",
+			schemaType: entity.SchemaTypeProtobuf,
+			previous:   `syntax = "proto3"; message UserVerified { int64 user_id = 1; string channel = 2; }`,
+			compatible: `syntax = "proto3"; message UserVerified { int64 user_id = 1; string channel = 2; string source = 3; }`,
+			breaking:   `syntax = "proto3"; message UserVerified { int64 user_id = 1; }`,
+		},
+		{
+			name:       "json new required field",
+			schemaType: entity.SchemaTypeJSON,
+			previous:   `{"type":"object","properties":{"user_id":{"type":"integer"}},"required":["user_id"]}`,
+			compatible: `{"type":"object","properties":{"user_id":{"type":"integer"},"channel":{"type":"string"}},"required":["user_id"]}`,
+			breaking:   `{"type":"object","properties":{"user_id":{"type":"integer"},"channel":{"type":"string"}},"required":["user_id","channel"]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var registry outbound.SchemaRegistryInterface = schemaregistry.NewMemoryRegistry()
+			ctx := context.Background()
+
+			previousID, err := registry.Register(ctx, "subject", entity.SchemaEntity{Type: tc.schemaType, Schema: tc.previous})
+			require.NoError(t, err)
+
+			sameID, err := registry.Register(ctx, "subject", entity.SchemaEntity{Type: tc.schemaType, Schema: tc.previous})
+			require.NoError(t, err)
+			assert.Equal(t, previousID, sameID)
+
+			_, err = registry.Register(ctx, "subject", entity.SchemaEntity{Type: tc.schemaType, Schema: tc.breaking})
+			assert.EqualError(t, err, "409")
+
+			compatibleID, err := registry.Register(ctx, "subject", entity.SchemaEntity{Type: tc.schemaType, Schema: tc.compatible})
+			require.NoError(t, err)
+			assert.NotEqual(t, previousID, compatibleID)
+
+			schema, err := registry.GetByID(ctx, compatibleID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.compatible, schema.Schema)
+		})
+	}
+}
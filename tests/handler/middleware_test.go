@@ -0,0 +1,49 @@
+package handler_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/tests"
+	"clean-architecture/utils/traceparent"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceParent_ContinuesIncomingTrace(t *testing.T) {
+	incoming := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	c, rec := tests.NewEchoContext(http.MethodGet, "/ping", nil)
+	c.Request().Header.Set(traceparent.Header, incoming)
+
+	var fromContext string
+	mid := echoinboundadapter.NewMiddlewareAdapter(nil, nil, nil)
+	err := mid.TraceParent()(func(c echo.Context) error {
+		fromContext = traceparent.FromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})(c)
+
+	assert.NoError(t, err)
+	assert.True(t, traceparent.Valid(fromContext))
+	assert.True(t, strings.HasPrefix(fromContext, "00-4bf92f3577b34da6a3ce929d0e0e4736-"))
+	assert.NotEqual(t, incoming, fromContext)
+	assert.Equal(t, fromContext, rec.Header().Get(traceparent.Header))
+}
+
+func TestTraceParent_StartsNewTrace(t *testing.T) {
+	c, _ := tests.NewEchoContext(http.MethodGet, "/ping", nil)
+	c.Request().Header.Set(traceparent.Header, "invalid")
+
+	var fromContext string
+	mid := echoinboundadapter.NewMiddlewareAdapter(nil, nil, nil)
+	err := mid.TraceParent()(func(c echo.Context) error {
+		fromContext = traceparent.FromContext(c.Request().Context())
+		return nil
+	})(c)
+
+	assert.NoError(t, err)
+	assert.True(t, traceparent.Valid(fromContext))
+}
@@ -0,0 +1,55 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	kafkainboundadapter "clean-architecture/internal/adapter/inbound/kafka"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestHandleDelivered_Success(t *testing.T) {
+	occurredAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	msg := entity.ConsumedMessageEntity{
+		Topic:     "notification-events",
+		EventName: utils.EVENT_NOTIFICATION_DELIVERED,
+		Data:      json.RawMessage(`{"message_id":"42","user_id":7,"channel":"email","occurred_at":"2025-01-01T10:00:00Z"}`),
+	}
+
+	mockService := new(mock.MockNotificationDeliveryService)
+	mockService.On("RecordDelivered", testifymock.Anything, entity.NotificationDeliveryEventEntity{
+		MessageID:  "42",
+		UserID:     7,
+		Channel:    "email",
+		OccurredAt: occurredAt,
+	}).Return(nil)
+
+	handler := kafkainboundadapter.NewNotificationEventHandler(mockService)
+
+	err := handler.HandleDelivered(context.Background(), msg)
+	assert.NoError(t, err)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleFailed_MalformedData(t *testing.T) {
+	msg := entity.ConsumedMessageEntity{
+		EventName: utils.EVENT_NOTIFICATION_FAILED,
+		Data:      json.RawMessage(`"not an object"`),
+	}
+
+	mockService := new(mock.MockNotificationDeliveryService)
+	handler := kafkainboundadapter.NewNotificationEventHandler(mockService)
+
+	err := handler.HandleFailed(context.Background(), msg)
+	assert.EqualError(t, err, "422")
+
+	mockService.AssertNotCalled(t, "RecordFailed", testifymock.Anything, testifymock.Anything)
+}
@@ -0,0 +1,176 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/adapter/inbound/echo/response"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func failedResetNotification() entity.NotificationHistoryEntity {
+	sentAt := time.Date(2025, 1, 1, 10, 0, 5, 0, time.UTC)
+	return entity.NotificationHistoryEntity{
+		ID:        15,
+		MessageID: "42",
+		UserID:    7,
+		Type:      entity.NotificationTypeEmail,
+		QueueName: utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		Template:  utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		Recipient: "budi@example.com",
+		Status:    entity.NotificationStatusFailed,
+		Attempts:  1,
+		LastError: "mailbox unavailable",
+		CreatedAt: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC),
+		SentAt:    &sentAt,
+	}
+}
+
+func TestGetNotifications_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/auth/notifications?page=2&limit=5&status=failed", nil)
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockNotificationService)
+	mockService.On("GetAll", testifymock.Anything, entity.NotificationHistoryQueryEntity{
+		UserID: 7,
+		Status: entity.NotificationStatusFailed,
+		Page:   2,
+		Limit:  5,
+	}).Return([]entity.NotificationHistoryEntity{failedResetNotification()}, int64(6), int64(2), nil)
+
+	handler := echoinboundadapter.NewNotificationHandler(mockService)
+
+	err := handler.GetAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	resp := struct {
+		Data       []response.NotificationResponse `json:"data"`
+		Pagination response.Pagination             `json:"pagination"`
+	}{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "reset_password", resp.Data[0].Template)
+	assert.Equal(t, "failed", resp.Data[0].Status)
+	assert.Equal(t, "budi@example.com", resp.Data[0].Recipient)
+	// Detail error transport tidak ditampilkan ke user
+	assert.NotContains(t, rec.Body.String(), "mailbox unavailable")
+	assert.Equal(t, int64(6), resp.Pagination.TotalCount)
+	assert.Equal(t, int64(2), resp.Pagination.TotalPage)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetNotifications_InvalidStatus(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/auth/notifications?status=unknown", nil)
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockNotificationService)
+	mockService.On("GetAll", testifymock.Anything, testifymock.Anything).Return(nil, int64(0), int64(0), errors.New("400"))
+
+	handler := echoinboundadapter.NewNotificationHandler(mockService)
+
+	err := handler.GetAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCustomerNotifications_ShowsLastError(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/7/notifications", nil)
+	c.Set("user", "test-user")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+
+	mockService := new(mock.MockNotificationService)
+	mockService.On("GetAll", testifymock.Anything, entity.NotificationHistoryQueryEntity{UserID: 7, Page: 1, Limit: 10}).
+		Return([]entity.NotificationHistoryEntity{failedResetNotification()}, int64(1), int64(1), nil)
+
+	handler := echoinboundadapter.NewNotificationHandler(mockService)
+
+	err := handler.GetCustomerNotificationAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"last_error":"mailbox unavailable"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestResendNotification_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/notifications/15/resend", nil)
+	c.Set("user", "test-user")
+	c.SetParamNames("id")
+	c.SetParamValues("15")
+
+	resendOfID := int64(15)
+	mockService := new(mock.MockNotificationService)
+	mockService.On("Resend", testifymock.Anything, int64(15)).Return(&entity.NotificationHistoryEntity{
+		ID:         16,
+		MessageID:  "43",
+		ResendOfID: &resendOfID,
+		Type:       entity.NotificationTypeEmail,
+		Template:   utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		Status:     entity.NotificationStatusPending,
+	}, nil)
+
+	handler := echoinboundadapter.NewNotificationHandler(mockService)
+
+	err := handler.Resend(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"resend_of_id":15`)
+	assert.Contains(t, rec.Body.String(), `"status":"pending"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestResendNotification_Errors(t *testing.T) {
+	cases := map[string]int{
+		"404": http.StatusNotFound,
+		"409": http.StatusConflict,
+		"422": http.StatusUnprocessableEntity,
+	}
+	for code, status := range cases {
+		c, rec := tests.NewEchoContext(http.MethodPost, "/admin/notifications/15/resend", nil)
+		c.Set("user", "test-user")
+		c.SetParamNames("id")
+		c.SetParamValues("15")
+
+		mockService := new(mock.MockNotificationService)
+		mockService.On("Resend", testifymock.Anything, int64(15)).Return(nil, errors.New(code))
+
+		handler := echoinboundadapter.NewNotificationHandler(mockService)
+
+		err := handler.Resend(c)
+		assert.NoError(t, err)
+		assert.Equal(t, status, rec.Code, code)
+	}
+}
+
+func TestResendNotification_InvalidID(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/notifications/abc/resend", nil)
+	c.Set("user", "test-user")
+	c.SetParamNames("id")
+	c.SetParamValues("abc")
+
+	mockService := new(mock.MockNotificationService)
+	handler := echoinboundadapter.NewNotificationHandler(mockService)
+
+	err := handler.Resend(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "Resend", testifymock.Anything, testifymock.Anything)
+}
@@ -0,0 +1,219 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	mockService "clean-architecture/tests/mock"
+	"clean-architecture/utils"
+	"clean-architecture/utils/kafkadelivery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutboxRepository mengembalikan pesan sekali lalu mencatat hasil relay.
+type fakeOutboxRepository struct {
+	mu       sync.Mutex
+	messages []entity.OutboxMessageEntity
+	sent     []int64
+	retried  []int64
+	failed   []int64
+	released []int64
+}
+
+func (f *fakeOutboxRepository) ClaimPending(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]entity.OutboxMessageEntity, error) {
+	messages := f.messages
+	f.messages = nil
+	return messages, nil
+}
+
+func (f *fakeOutboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, id)
+	return nil
+}
+
+func (f *fakeOutboxRepository) MarkRetry(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retried = append(f.retried, id)
+	return nil
+}
+
+func (f *fakeOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = append(f.failed, id)
+	return nil
+}
+
+func (f *fakeOutboxRepository) Release(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = append(f.released, id)
+	return nil
+}
+
+// fakeNotificationRepository mencatat perubahan status riwayat notifikasi.
+type fakeNotificationRepository struct {
+	mu           sync.Mutex
+	sendStatuses map[int64]entity.NotificationSendStatusEntity
+	deliveries   []entity.NotificationDeliveryEntity
+}
+
+func (f *fakeNotificationRepository) GetAll(ctx context.Context, query entity.NotificationHistoryQueryEntity) ([]entity.NotificationHistoryEntity, int64, int64, error) {
+	return nil, 0, 0, errors.New("404")
+}
+
+func (f *fakeNotificationRepository) GetByID(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error) {
+	return nil, errors.New("404")
+}
+
+func (f *fakeNotificationRepository) Create(ctx context.Context, message entity.PublishMessage) (*entity.NotificationHistoryEntity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeNotificationRepository) Resend(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error) {
+	return nil, errors.New("404")
+}
+
+func (f *fakeNotificationRepository) UpdateSendStatus(ctx context.Context, req entity.NotificationSendStatusEntity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sendStatuses == nil {
+		f.sendStatuses = map[int64]entity.NotificationSendStatusEntity{}
+	}
+	f.sendStatuses[req.OutboxMessageID] = req
+	return nil
+}
+
+func (f *fakeNotificationRepository) UpdateDeliveryStatus(ctx context.Context, req entity.NotificationDeliveryEntity) error {
+	f.deliveries = append(f.deliveries, req)
+	return nil
+}
+
+type fakeDeliveryRepository struct {
+	upserted []entity.NotificationDeliveryEntity
+}
+
+func (f *fakeDeliveryRepository) UpsertStatus(ctx context.Context, req entity.NotificationDeliveryEntity) error {
+	f.upserted = append(f.upserted, req)
+	return nil
+}
+
+// scriptedPublisher hasil kirim per message_id, ErrSkipped dilaporkan seperti user opt-out.
+type scriptedPublisher struct {
+	results map[string]error
+}
+
+func (s *scriptedPublisher) PublishMessage(ctx context.Context, req entity.PublishMessage) error {
+	err := s.results[req.MessageId]
+	if err != nil && !errors.Is(err, kafkadelivery.ErrSkipped) {
+		return err
+	}
+	kafkadelivery.Report(ctx, err)
+	return nil
+}
+
+func (s *scriptedPublisher) PublishEvent(ctx context.Context, event entity.DomainEventEntity) error {
+	kafkadelivery.Report(ctx, nil)
+	return nil
+}
+
+func notificationOutboxMessage(t *testing.T, id int64, attempts int) entity.OutboxMessageEntity {
+	payload, err := json.Marshal(entity.PublishMessage{
+		Email:     "budi@example.com",
+		UserId:    7,
+		QueueName: utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		Template:  utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+	})
+	require.NoError(t, err)
+	return entity.OutboxMessageEntity{ID: id, Kind: entity.OutboxKindNotification, Payload: payload, Attempts: attempts}
+}
+
+func TestOutboxRelay_UpdatesNotificationStatus(t *testing.T) {
+	outboxRepo := &fakeOutboxRepository{messages: []entity.OutboxMessageEntity{
+		notificationOutboxMessage(t, 1, 1),
+		notificationOutboxMessage(t, 2, 1),
+		notificationOutboxMessage(t, 3, 1),
+		notificationOutboxMessage(t, 4, 3),
+		{ID: 5, Kind: entity.OutboxKindEvent, EventName: utils.EVENT_USER_REGISTERED, Payload: []byte(`{}`), Attempts: 1},
+	}}
+	notificationRepo := &fakeNotificationRepository{}
+	publisher := &scriptedPublisher{results: map[string]error{
+		"2": kafkadelivery.ErrSkipped,
+		"3": errors.New("smtp unavailable"),
+		"4": errors.New("smtp unavailable"),
+	}}
+	cfg := &config.Config{Kafka: config.Kafka{OutboxMaxAttempts: 3}}
+
+	relay := service.NewOutboxRelayService(outboxRepo, notificationRepo, publisher, cfg)
+	sent, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, sent)
+	assert.ElementsMatch(t, []int64{1, 2, 5}, outboxRepo.sent)
+	assert.Equal(t, []int64{3}, outboxRepo.retried)
+	assert.Equal(t, []int64{4}, outboxRepo.failed)
+
+	statuses := notificationRepo.sendStatuses
+	require.Len(t, statuses, 4)
+	assert.Equal(t, entity.NotificationStatusSent, statuses[1].Status)
+	assert.Equal(t, entity.NotificationStatusSkipped, statuses[2].Status)
+	assert.Equal(t, entity.NotificationStatusPending, statuses[3].Status)
+	assert.Equal(t, "smtp unavailable", statuses[3].LastError)
+	assert.Equal(t, entity.NotificationStatusFailed, statuses[4].Status)
+	assert.Equal(t, 3, statuses[4].Attempts)
+}
+
+func TestKafkaService_OptOutReportsSkipped(t *testing.T) {
+	preferences := new(mockService.MockPreferenceService)
+	preferences.On("IsNotificationEnabled", mock.Anything, int64(7), utils.NOTIF_EMAIL_MARKETING).Return(false, nil)
+
+	sender := &recordingSender{}
+	kafkaService := service.NewKafkaService(&config.Config{}, sender, nil, nil, preferences)
+
+	ctx, delivered := deliveryRecorder()
+	require.NoError(t, kafkaService.PublishMessage(ctx, entity.PublishMessage{
+		Email:     "budi@example.com",
+		Message:   "Promo",
+		UserId:    7,
+		QueueName: utils.NOTIF_EMAIL_MARKETING,
+	}))
+
+	assert.Empty(t, sender.sent)
+	assert.Equal(t, []error{kafkadelivery.ErrSkipped}, *delivered)
+	preferences.AssertExpectations(t)
+}
+
+func TestNotificationDelivery_UpdatesNotificationHistory(t *testing.T) {
+	deliveryRepo := &fakeDeliveryRepository{}
+	notificationRepo := &fakeNotificationRepository{}
+	deliveryService := service.NewNotificationDeliveryService(deliveryRepo, notificationRepo)
+
+	occurredAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, deliveryService.RecordFailed(context.Background(), entity.NotificationDeliveryEventEntity{
+		MessageID:  "42",
+		UserID:     7,
+		Channel:    "email",
+		Reason:     "mailbox unavailable",
+		OccurredAt: occurredAt,
+	}))
+
+	require.Len(t, notificationRepo.deliveries, 1)
+	assert.Equal(t, deliveryRepo.upserted, notificationRepo.deliveries)
+	assert.Equal(t, "42", notificationRepo.deliveries[0].MessageID)
+	assert.Equal(t, entity.NotificationStatusFailed, notificationRepo.deliveries[0].Status)
+	assert.Equal(t, "mailbox unavailable", notificationRepo.deliveries[0].Reason)
+	assert.Equal(t, occurredAt, notificationRepo.deliveries[0].OccurredAt)
+}
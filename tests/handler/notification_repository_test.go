@@ -0,0 +1,90 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func addNotificationRow(recorder *tests.SQLRecorder, queueName string) {
+	recorder.AddRows(tests.SQLRows{
+		Columns: []string{"id", "message_id", "outbox_message_id", "user_id", "type", "queue_name", "template", "recipient", "status"},
+		Rows: [][]driver.Value{{int64(15), "42", int64(42), int64(7), entity.NotificationTypeForQueue(queueName), queueName,
+			queueName, "old@example.com", entity.NotificationStatusFailed}},
+	})
+}
+
+func TestNotificationRepository_ResendRefusesCredentialQueues(t *testing.T) {
+	for _, queue := range entity.CredentialNotificationQueues {
+		db, recorder := tests.NewGormDB(t)
+		addNotificationRow(recorder, queue)
+
+		repo := outboundadapterpostgres.NewNotificationRepository(db)
+		_, err := repo.Resend(context.Background(), 15)
+		require.Error(t, err, queue)
+		assert.Equal(t, "422", err.Error(), queue)
+
+		// Payload lama tidak dibaca dan tidak ada pesan outbox baru
+		assert.Len(t, recorder.Queries(), 1, queue)
+	}
+}
+
+func TestNotificationRepository_ResendUsesCurrentRecipient(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	addNotificationRow(recorder, utils.NOTIF_EMAIL_MARKETING)
+	payload, err := json.Marshal(entity.PublishMessage{
+		Email:     "old@example.com",
+		UserId:    7,
+		QueueName: utils.NOTIF_EMAIL_MARKETING,
+		MessageId: "42",
+		Message:   "Promo",
+	})
+	require.NoError(t, err)
+	recorder.AddRows(tests.SQLRows{
+		Columns: []string{"id", "kind", "payload", "status"},
+		Rows:    [][]driver.Value{{int64(42), entity.OutboxKindNotification, string(payload), entity.OutboxStatusSent}},
+	})
+	recorder.AddRows(tests.SQLRows{
+		Columns: []string{"id", "email", "phone"},
+		Rows:    [][]driver.Value{{int64(7), "new@example.com", "+6281234567890"}},
+	})
+	recorder.AddRows(tests.SQLRows{Columns: []string{"id"}, Rows: [][]driver.Value{{int64(43)}}})
+	recorder.AddRows(tests.SQLRows{
+		Columns: []string{"id", "created_at", "updated_at"},
+		Rows:    [][]driver.Value{{int64(16), time.Now(), time.Now()}},
+	})
+
+	repo := outboundadapterpostgres.NewNotificationRepository(db)
+	result, err := repo.Resend(context.Background(), 15)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(16), result.ID)
+	assert.Equal(t, "43", result.MessageID)
+	assert.Equal(t, "new@example.com", result.Recipient)
+	require.NotNil(t, result.ResendOfID)
+	assert.Equal(t, int64(15), *result.ResendOfID)
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 5)
+	assert.Contains(t, queries[2].SQL, "erased_at IS NULL")
+	assert.Contains(t, queries[3].SQL, `INSERT INTO "outbox_messages"`)
+	resent := entity.PublishMessage{}
+	for _, arg := range queries[3].Args {
+		if val, ok := arg.(string); ok && json.Valid([]byte(val)) {
+			require.NoError(t, json.Unmarshal([]byte(val), &resent))
+		}
+	}
+	assert.Equal(t, "new@example.com", resent.Email)
+	assert.Equal(t, "Promo", resent.Message)
+	assert.Empty(t, resent.MessageId)
+}
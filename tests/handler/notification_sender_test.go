@@ -0,0 +1,265 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/adapter/outbound/filesink"
+	"clean-architecture/internal/adapter/outbound/smtp"
+	"clean-architecture/internal/adapter/outbound/webhook"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/utils"
+	"clean-architecture/utils/kafkadelivery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNotification() entity.NotificationEntity {
+	return entity.NotificationEntity{
+		ID:        "42",
+		Type:      entity.NotificationTypeEmail,
+		QueueName: utils.NOTIF_EMAIL_VERIFICATION,
+		UserID:    7,
+		Email:     "budi@example.com",
+		Subject:   "Verifikasi Akun",
+		Message:   "Kode verifikasi kamu 123456",
+		CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+// deliveryRecorder mencatat callback kafkadelivery yang dipanggil transport.
+func deliveryRecorder() (context.Context, *[]error) {
+	var results []error
+	ctx := kafkadelivery.NewContext(context.Background(), func(err error) { results = append(results, err) })
+	return ctx, &results
+}
+
+func TestWebhookSender_PostsSignedNotification(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		headers = r.Header.Clone()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := webhook.NewSender(server.URL, "rahasia", time.Second)
+	ctx, delivered := deliveryRecorder()
+	require.NoError(t, sender.Send(ctx, newTestNotification()))
+
+	got := entity.NotificationEntity{}
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, newTestNotification(), got)
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, "42", headers.Get("Idempotency-Key"))
+
+	mac := hmac.New(sha256.New, []byte("rahasia"))
+	mac.Write(body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), headers.Get(webhook.SignatureHeader))
+	assert.Equal(t, []error{nil}, *delivered)
+}
+
+func TestWebhookSender_ErrorResponseIsNotDelivered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := webhook.NewSender(server.URL, "", time.Second)
+	ctx, delivered := deliveryRecorder()
+	err := sender.Send(ctx, newTestNotification())
+
+	assert.EqualError(t, err, "webhook responded 503")
+	assert.Empty(t, *delivered)
+}
+
+func TestFileSinkSender_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	sender, err := filesink.NewSender(path)
+	require.NoError(t, err)
+
+	ctx, delivered := deliveryRecorder()
+	first := newTestNotification()
+	second := newTestNotification()
+	second.ID = "43"
+	require.NoError(t, sender.Send(ctx, first))
+	require.NoError(t, sender.Send(ctx, second))
+	require.NoError(t, sender.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	got := entity.NotificationEntity{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, second, got)
+	assert.Equal(t, []error{nil, nil}, *delivered)
+}
+
+// fakeSMTPServer server SMTP minimal (tanpa STARTTLS dan AUTH) yang menyimpan isi DATA.
+func fakeSMTPServer(t *testing.T) (host, port string, received <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "DATA"):
+				reply("354 end with .")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				messages <- data.String()
+				reply("250 queued")
+			case strings.HasPrefix(command, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, err = net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return host, port, messages
+}
+
+func TestSMTPSender_SendsEmail(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	sender := smtp.NewSender(host, port, "", "", "no-reply@example.com", 2*time.Second)
+	ctx, delivered := deliveryRecorder()
+	notification := newTestNotification()
+	notification.Subject = "Verifikasi Akun ✓"
+	require.NoError(t, sender.Send(ctx, notification))
+
+	select {
+	case message := <-received:
+		assert.Contains(t, message, "From: no-reply@example.com\r\n")
+		assert.Contains(t, message, "To: <budi@example.com>\r\n")
+		assert.Contains(t, message, "Subject: =?utf-8?q?Verifikasi_Akun_=E2=9C=93?=\r\n")
+		assert.Contains(t, message, "Kode verifikasi kamu 123456")
+	case <-time.After(2 * time.Second):
+		t.Fatal("email was not received")
+	}
+	assert.Equal(t, []error{nil}, *delivered)
+}
+
+func TestSMTPSender_SendsMultipartWhenHTMLPresent(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	sender := smtp.NewSender(host, port, "", "", "no-reply@example.com", 2*time.Second)
+	notification := newTestNotification()
+	notification.HTMLMessage = `<p>Kode verifikasi kamu <b>123456</b></p>`
+	require.NoError(t, sender.Send(context.Background(), notification))
+
+	select {
+	case message := <-received:
+		assert.Contains(t, message, "Content-Type: multipart/alternative; boundary=")
+		assert.Contains(t, message, "Content-Type: text/plain; charset=UTF-8")
+		assert.Contains(t, message, "Content-Type: text/html; charset=UTF-8")
+		assert.Contains(t, message, "<b>123456</b>")
+	case <-time.After(2 * time.Second):
+		t.Fatal("email was not received")
+	}
+}
+
+func TestSMTPSender_SkipsNonEmailNotification(t *testing.T) {
+	// Tidak ada server, transport tidak boleh mencoba terhubung
+	sender := smtp.NewSender("127.0.0.1", "1", "", "", "no-reply@example.com", time.Second)
+	ctx, delivered := deliveryRecorder()
+	notification := newTestNotification()
+	notification.Type = entity.NotificationTypeSMS
+
+	require.NoError(t, sender.Send(ctx, notification))
+	assert.Equal(t, []error{kafkadelivery.ErrSkipped}, *delivered)
+}
+
+func TestSMTPSender_ConnectionErrorIsNotDelivered(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	sender := smtp.NewSender(host, port, "", "", "no-reply@example.com", time.Second)
+	ctx, delivered := deliveryRecorder()
+
+	assert.Error(t, sender.Send(ctx, newTestNotification()))
+	assert.Empty(t, *delivered)
+}
+
+// Tanpa broker: notifikasi lewat transport file, domain event gagal agar dicoba ulang oleh relay outbox.
+func TestKafkaService_RunsWithoutKafka(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	sender, err := filesink.NewSender(path)
+	require.NoError(t, err)
+	defer sender.Close()
+
+	kafkaService := service.NewKafkaService(&config.Config{}, sender, nil, nil, nil)
+
+	ctx, delivered := deliveryRecorder()
+	require.NoError(t, kafkaService.PublishMessage(ctx, entity.PublishMessage{
+		Phone:     "+6281234567890",
+		Message:   "Kode OTP 123456",
+		UserId:    7,
+		QueueName: utils.NOTIF_SMS_PHONE_OTP,
+	}))
+	assert.Error(t, kafkaService.PublishEvent(ctx, entity.DomainEventEntity{
+		Name:    utils.EVENT_USER_REGISTERED,
+		Version: 1,
+		UserID:  7,
+	}))
+	assert.Equal(t, []error{nil}, *delivered)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	got := entity.NotificationEntity{}
+	require.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, entity.NotificationTypeSMS, got.Type)
+	assert.Equal(t, "+6281234567890", got.Phone)
+	assert.NotEmpty(t, got.ID)
+}
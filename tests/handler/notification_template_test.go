@@ -0,0 +1,196 @@
+package handler_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/adapter/outbound/notificationtemplate"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	mockService "clean-architecture/tests/mock"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSender transport notifikasi untuk test, menyimpan notifikasi yang dikirim.
+type recordingSender struct {
+	sent []entity.NotificationEntity
+}
+
+func (r *recordingSender) Send(ctx context.Context, notification entity.NotificationEntity) error {
+	r.sent = append(r.sent, notification)
+	return nil
+}
+
+func (r *recordingSender) Close() error {
+	return nil
+}
+
+func verificationData() map[string]string {
+	return map[string]string{
+		"app_name": "Toko Kita",
+		"name":     "Budi",
+		"link":     "https://example.com/auth/verify-account?token=abc&x=1",
+		"expiry":   "60",
+	}
+}
+
+func TestNotificationTemplate_RendersAllParts(t *testing.T) {
+	renderer, err := notificationtemplate.NewRenderer("", "")
+	require.NoError(t, err)
+
+	result, err := renderer.Render(utils.NOTIF_EMAIL_VERIFICATION, "id", verificationData())
+	require.NoError(t, err)
+
+	assert.Equal(t, "id", result.Locale)
+	assert.Equal(t, "Verifikasi Akun Toko Kita", result.Subject)
+	assert.Contains(t, result.Text, "Halo Budi,")
+	assert.Contains(t, result.Text, "https://example.com/auth/verify-account?token=abc&x=1")
+	assert.Contains(t, result.Text, "Tautan berlaku 60 menit")
+	assert.Contains(t, result.HTML, `<a href="https://example.com/auth/verify-account?token=abc&amp;x=1">`)
+}
+
+func TestNotificationTemplate_EscapesHTMLVariables(t *testing.T) {
+	renderer, err := notificationtemplate.NewRenderer("", "")
+	require.NoError(t, err)
+
+	data := verificationData()
+	data["name"] = "<script>alert(1)</script>"
+	result, err := renderer.Render(utils.NOTIF_EMAIL_VERIFICATION, "en", data)
+	require.NoError(t, err)
+
+	assert.NotContains(t, result.HTML, "<script>")
+	assert.Contains(t, result.HTML, "&lt;script&gt;")
+}
+
+func TestNotificationTemplate_LocaleFallback(t *testing.T) {
+	renderer, err := notificationtemplate.NewRenderer("", "id")
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"en":    "en",
+		"en-US": "en",
+		"EN_gb": "en",
+		"fr":    "id",
+		"":      "id",
+	}
+	for locale, expected := range tests {
+		result, err := renderer.Render(utils.NOTIF_EMAIL_FORGOT_PASSWORD, locale, verificationData())
+		require.NoError(t, err, locale)
+		assert.Equal(t, expected, result.Locale, locale)
+	}
+}
+
+func TestNotificationTemplate_MissingVariableFails(t *testing.T) {
+	renderer, err := notificationtemplate.NewRenderer("", "")
+	require.NoError(t, err)
+
+	data := verificationData()
+	delete(data, "link")
+	_, err = renderer.Render(utils.NOTIF_EMAIL_VERIFICATION, "en", data)
+	assert.Error(t, err)
+}
+
+func TestNotificationTemplate_UnknownTemplate(t *testing.T) {
+	renderer, err := notificationtemplate.NewRenderer("", "")
+	require.NoError(t, err)
+
+	_, err = renderer.Render("unknown", "en", nil)
+	assert.EqualError(t, err, "404")
+}
+
+func TestNotificationTemplate_OverrideDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "en"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ms"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en", "email_verification.html.tmpl"),
+		[]byte(`<h1>{{.app_name}}</h1><a href="{{.link}}">Verify</a>`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ms", "sms_phone_otp.text.tmpl"),
+		[]byte(`Kod pengesahan {{.app_name}} anda: {{.code}}`), 0o644))
+
+	renderer, err := notificationtemplate.NewRenderer(dir, "")
+	require.NoError(t, err)
+
+	// Hanya body HTML yang ditimpa, subject dan text tetap dari template bawaan
+	result, err := renderer.Render(utils.NOTIF_EMAIL_VERIFICATION, "en", verificationData())
+	require.NoError(t, err)
+	assert.Equal(t, "Verify Your Toko Kita Account", result.Subject)
+	assert.Contains(t, result.Text, "Hi Budi,")
+	assert.Equal(t, `<h1>Toko Kita</h1><a href="https://example.com/auth/verify-account?token=abc&amp;x=1">Verify</a>`, result.HTML)
+
+	result, err = renderer.Render(utils.NOTIF_SMS_PHONE_OTP, "ms", map[string]string{"app_name": "Toko Kita", "code": "123456"})
+	require.NoError(t, err)
+	assert.Equal(t, "ms", result.Locale)
+	assert.Equal(t, "Kod pengesahan Toko Kita anda: 123456", result.Text)
+	assert.Empty(t, result.Subject)
+}
+
+func TestNotificationTemplate_InvalidOverride(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "en"), 0o755))
+
+	// Template baru tanpa body text
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en", "welcome.subject.tmpl"), []byte(`Welcome`), 0o644))
+	_, err := notificationtemplate.NewRenderer(dir, "")
+	assert.Error(t, err)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "en", "welcome.subject.tmpl")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "en", "welcome.text.tmpl"), []byte(`Hi {{.name`), 0o644))
+	_, err = notificationtemplate.NewRenderer(dir, "")
+	assert.Error(t, err)
+}
+
+func TestKafkaService_RendersTemplateWithUserLocale(t *testing.T) {
+	renderer, err := notificationtemplate.NewRenderer("", "")
+	require.NoError(t, err)
+
+	preferences := new(mockService.MockPreferenceService)
+	preferences.On("IsNotificationEnabled", mock.Anything, int64(7), utils.NOTIF_EMAIL_FORGOT_PASSWORD).Return(true, nil)
+	preferences.On("GetPreference", mock.Anything, int64(7), service.PreferenceLocale).Return("en", nil)
+
+	sender := &recordingSender{}
+	cfg := &config.Config{App: config.App{AppName: "Toko Kita"}}
+	kafkaService := service.NewKafkaService(cfg, sender, nil, renderer, preferences)
+
+	require.NoError(t, kafkaService.PublishMessage(context.Background(), entity.PublishMessage{
+		Email:     "budi@example.com",
+		UserId:    7,
+		QueueName: utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		Template:  utils.NOTIF_EMAIL_FORGOT_PASSWORD,
+		Data:      map[string]string{"name": "Budi", "link": "https://example.com/reset", "expiry": "60"},
+	}))
+
+	require.Len(t, sender.sent, 1)
+	notification := sender.sent[0]
+	assert.Equal(t, "en", notification.Locale)
+	assert.Equal(t, utils.NOTIF_EMAIL_FORGOT_PASSWORD, notification.Template)
+	assert.Equal(t, "Reset Your Toko Kita Password", notification.Subject)
+	assert.Contains(t, notification.Message, "https://example.com/reset")
+	assert.Contains(t, notification.HTMLMessage, `<a href="https://example.com/reset">`)
+	assert.Equal(t, entity.NotificationTypeEmail, notification.Type)
+	preferences.AssertExpectations(t)
+}
+
+// Pesan di outbox yang ditulis sebelum ada template tetap dikirim apa adanya.
+func TestKafkaService_SendsLegacyMessageWithoutTemplate(t *testing.T) {
+	sender := &recordingSender{}
+	kafkaService := service.NewKafkaService(&config.Config{}, sender, nil, nil, nil)
+
+	require.NoError(t, kafkaService.PublishMessage(context.Background(), entity.PublishMessage{
+		Email:     "budi@example.com",
+		Message:   "Please verify your account by clicking the link: https://example.com",
+		Subject:   "Verify Your Account",
+		QueueName: utils.NOTIF_EMAIL_VERIFICATION,
+	}))
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "Verify Your Account", sender.sent[0].Subject)
+	assert.Equal(t, "Please verify your account by clicking the link: https://example.com", sender.sent[0].Message)
+	assert.Empty(t, sender.sent[0].HTMLMessage)
+}
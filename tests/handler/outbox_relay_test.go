@@ -0,0 +1,188 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"clean-architecture/config"
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/tests"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository_ClaimPending(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	recorder.AddRows(tests.SQLRows{
+		Columns: []string{"id", "kind", "event_name", "event_version", "user_id", "payload", "trace_parent",
+			"status", "attempts", "last_error", "next_attempt_at", "created_at", "sent_at"},
+		Rows: [][]driver.Value{
+			{int64(1), entity.OutboxKindEvent, utils.EVENT_USER_REGISTERED, int64(1), int64(7), `{"user_id":7}`, "",
+				entity.OutboxStatusPending, int64(2), "broker down", now.Add(time.Minute), now, nil},
+			{int64(2), entity.OutboxKindNotification, "", int64(1), nil, `{"email":"budi@example.com"}`, "",
+				entity.OutboxStatusPending, int64(1), "", now.Add(time.Minute), now, nil},
+		},
+	})
+
+	repo := outboundadapterpostgres.NewOutboxRepository(db)
+	messages, err := repo.ClaimPending(context.Background(), now, 50, time.Minute)
+	require.NoError(t, err)
+
+	require.Len(t, messages, 2)
+	assert.Equal(t, int64(1), messages[0].ID)
+	assert.Equal(t, utils.EVENT_USER_REGISTERED, messages[0].EventName)
+	require.NotNil(t, messages[0].UserID)
+	assert.Equal(t, int64(7), *messages[0].UserID)
+	assert.Equal(t, 2, messages[0].Attempts)
+	assert.JSONEq(t, `{"user_id":7}`, string(messages[0].Payload))
+	assert.Nil(t, messages[1].UserID)
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0].SQL, "SET attempts = attempts + 1")
+	assert.Contains(t, queries[0].SQL, "FOR UPDATE SKIP LOCKED")
+	assert.Equal(t, []any{now.Add(time.Minute), entity.OutboxStatusPending, now, entity.OutboxKindEvent, 50}, queries[0].Args)
+}
+
+func TestOutboxRepository_RedactsFinishedNotificationPayload(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	repo := outboundadapterpostgres.NewOutboxRepository(db)
+
+	require.NoError(t, repo.MarkSent(context.Background(), 1, time.Now()))
+	require.NoError(t, repo.MarkFailed(context.Background(), 2, "smtp unavailable"))
+	require.NoError(t, repo.MarkRetry(context.Background(), 3, "smtp unavailable", time.Now()))
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 3)
+	for _, query := range queries[:2] {
+		assert.Contains(t, query.SQL, `THEN payload - 'data' - 'message' ELSE payload END`)
+		assert.Contains(t, query.Args, entity.OutboxKindNotification)
+		for _, queue := range entity.CredentialNotificationQueues {
+			assert.Contains(t, query.Args, queue)
+		}
+	}
+	// Pesan yang masih akan dicoba ulang butuh payload lengkap
+	assert.NotContains(t, queries[2].SQL, "payload")
+}
+
+func TestOutboxRelay_RetriesThenFails(t *testing.T) {
+	outboxRepo := &fakeOutboxRepository{messages: []entity.OutboxMessageEntity{
+		notificationOutboxMessage(t, 1, 1),
+		notificationOutboxMessage(t, 2, 5),
+	}}
+	publisher := &scriptedPublisher{results: map[string]error{
+		"1": errors.New("broker down"),
+		"2": errors.New("broker down"),
+	}}
+	cfg := &config.Config{Kafka: config.Kafka{OutboxMaxAttempts: 5}}
+
+	relay := service.NewOutboxRelayService(outboxRepo, nil, publisher, cfg)
+	sent, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, outboxRepo.sent)
+	assert.Equal(t, []int64{1}, outboxRepo.retried)
+	assert.Equal(t, []int64{2}, outboxRepo.failed)
+}
+
+func TestOutboxRelay_ClaimError(t *testing.T) {
+	relay := service.NewOutboxRelayService(&failingOutboxRepository{}, nil, &scriptedPublisher{}, &config.Config{})
+
+	sent, err := relay.RelayPending(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+// silentPublisher mengembalikan nil tanpa memanggil callback untuk message_id di silent.
+type silentPublisher struct {
+	scriptedPublisher
+	silent map[string]bool
+}
+
+func (s *silentPublisher) PublishMessage(ctx context.Context, req entity.PublishMessage) error {
+	if s.silent[req.MessageId] {
+		return nil
+	}
+	return s.scriptedPublisher.PublishMessage(ctx, req)
+}
+
+// Sender yang tidak melaporkan hasil tidak boleh menahan relay, pesannya dibiarkan ter-claim
+// sampai lease habis lalu diambil ulang.
+func TestOutboxRelay_UnreportedDeliveryDoesNotBlock(t *testing.T) {
+	outboxRepo := &fakeOutboxRepository{messages: []entity.OutboxMessageEntity{
+		notificationOutboxMessage(t, 1, 1),
+		notificationOutboxMessage(t, 2, 1),
+	}}
+	publisher := &silentPublisher{silent: map[string]bool{"2": true}}
+	cfg := &config.Config{Kafka: config.Kafka{OutboxDeliveryTimeoutInMS: 50}}
+
+	relay := service.NewOutboxRelayService(outboxRepo, nil, publisher, cfg)
+
+	result := make(chan int, 1)
+	go func() {
+		sent, err := relay.RelayPending(context.Background())
+		assert.NoError(t, err)
+		result <- sent
+	}()
+
+	select {
+	case sent := <-result:
+		assert.Equal(t, 1, sent)
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay is blocked by a delivery that was never reported")
+	}
+
+	assert.Equal(t, []int64{1}, outboxRepo.sent)
+	assert.Empty(t, outboxRepo.retried)
+	assert.Empty(t, outboxRepo.failed)
+}
+
+// Tanpa producer Kafka (transport notifikasi bukan kafka) event tetap pending walaupun
+// sudah mencapai batas percobaan.
+func TestOutboxRelay_NoProducerKeepsEventsPending(t *testing.T) {
+	outboxRepo := &fakeOutboxRepository{messages: []entity.OutboxMessageEntity{
+		{ID: 1, Kind: entity.OutboxKindEvent, EventName: utils.EVENT_USER_REGISTERED, Payload: []byte(`{}`), Attempts: 3},
+	}}
+	cfg := &config.Config{Kafka: config.Kafka{OutboxMaxAttempts: 3}}
+	kafkaService := service.NewKafkaService(cfg, nil, nil, nil, nil)
+
+	relay := service.NewOutboxRelayService(outboxRepo, nil, kafkaService, cfg)
+	sent, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, sent)
+	assert.Equal(t, []int64{1}, outboxRepo.released)
+	assert.Empty(t, outboxRepo.retried)
+	assert.Empty(t, outboxRepo.failed)
+}
+
+func TestOutboxRepository_ReleaseDoesNotCountAttempt(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+
+	nextAttemptAt := time.Date(2025, 1, 1, 10, 10, 0, 0, time.UTC)
+	err := outboundadapterpostgres.NewOutboxRepository(db).Release(context.Background(), 1, "kafka producer is not configured", nextAttemptAt)
+	require.NoError(t, err)
+
+	queries := recorder.Queries()
+	require.Len(t, queries, 1)
+	assert.Contains(t, queries[0].SQL, `"attempts"=GREATEST(attempts - 1, 0)`)
+	assert.NotContains(t, queries[0].SQL, `"status"`)
+	assert.Contains(t, queries[0].Args, nextAttemptAt)
+}
+
+type failingOutboxRepository struct {
+	fakeOutboxRepository
+}
+
+func (f *failingOutboxRepository) ClaimPending(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]entity.OutboxMessageEntity, error) {
+	return nil, errors.New("connection refused")
+}
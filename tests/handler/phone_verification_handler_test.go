@@ -0,0 +1,82 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestSendPhoneOTP_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/auth/phone/otp", strings.NewReader(`{"phone":"0812-3456-7890"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockPhoneVerificationService)
+	mockService.On("SendOTP", testifymock.Anything, int64(7), "0812-3456-7890").Return("+6281234567890", nil)
+
+	handler := echoinboundadapter.NewPhoneVerificationHandler(mockService)
+
+	err := handler.SendOTP(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"phone":"+6281234567890"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSendPhoneOTP_InvalidPhone(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/auth/phone/otp", strings.NewReader(`{"phone":"12ab"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockPhoneVerificationService)
+	handler := echoinboundadapter.NewPhoneVerificationHandler(mockService)
+
+	err := handler.SendOTP(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "SendOTP", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
+
+func TestVerifyPhoneOTP_TooManyAttempts(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/auth/phone/verify", strings.NewReader(`{"otp":"123456"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockPhoneVerificationService)
+	mockService.On("VerifyOTP", testifymock.Anything, int64(7), "123456").Return(errors.New("429"))
+
+	handler := echoinboundadapter.NewPhoneVerificationHandler(mockService)
+
+	err := handler.VerifyOTP(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// Validator memakai PHONE_DEFAULT_COUNTRY_CODE yang sama dengan service untuk nomor lokal.
+func TestPhoneValidator_UsesConfiguredCountryCode(t *testing.T) {
+	type request struct {
+		Phone string `validate:"phone"`
+	}
+	local := request{Phone: "08123456789012"}
+
+	assert.NoError(t, validator.NewValidator(nil, "62").Validate(&local))
+	// +358 diikuti 13 digit melebihi panjang maksimal E.164
+	assert.Error(t, validator.NewValidator(nil, "358").Validate(&local))
+}
@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/tests"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *fakeUserRepository) GetUserByVerifiedPhone(ctx context.Context, phone string) (*entity.UserEntity, error) {
+	return nil, errors.New("404")
+}
+
+// queueingNotificationRepository mencatat notifikasi yang ditulis ke outbox.
+type queueingNotificationRepository struct {
+	fakeNotificationRepository
+	created []entity.PublishMessage
+	err     error
+}
+
+func (q *queueingNotificationRepository) Create(ctx context.Context, message entity.PublishMessage) (*entity.NotificationHistoryEntity, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	q.created = append(q.created, message)
+	return &entity.NotificationHistoryEntity{ID: int64(len(q.created)), QueueName: message.QueueName}, nil
+}
+
+func newTestPhoneVerificationService(t *testing.T, notifications *queueingNotificationRepository) (service.PhoneVerificationServiceInterface, *tests.RedisServer) {
+	server := tests.NewRedisServer(t)
+	cfg := &config.Config{App: config.App{JwtSecretKey: "secret", PhoneDefaultCountryCode: "62"}}
+	return service.NewPhoneVerificationService(&fakeUserRepository{}, cfg, notifications, server.Client()), server
+}
+
+func TestPhoneVerification_SendOTPQueuesNotification(t *testing.T) {
+	notifications := &queueingNotificationRepository{}
+	phoneService, _ := newTestPhoneVerificationService(t, notifications)
+
+	normalized, err := phoneService.SendOTP(context.Background(), 7, "0812-3456-7890")
+	require.NoError(t, err)
+	assert.Equal(t, "+6281234567890", normalized)
+
+	require.Len(t, notifications.created, 1)
+	assert.Equal(t, utils.NOTIF_SMS_PHONE_OTP, notifications.created[0].QueueName)
+	assert.Equal(t, "+6281234567890", notifications.created[0].Phone)
+	assert.Equal(t, int64(7), notifications.created[0].UserId)
+	assert.Len(t, notifications.created[0].Data["code"], 6)
+}
+
+func TestPhoneVerification_SendOTPQueueErrorAllowsRetry(t *testing.T) {
+	notifications := &queueingNotificationRepository{err: errors.New("connection refused")}
+	phoneService, server := newTestPhoneVerificationService(t, notifications)
+
+	_, err := phoneService.SendOTP(context.Background(), 7, "081234567890")
+	assert.Error(t, err)
+	// OTP dan cooldown dihapus agar user bisa langsung meminta ulang
+	assert.False(t, server.Exists("phone_otp:7"))
+	assert.False(t, server.Exists("phone_otp_cooldown:7"))
+}
+
+// Kirim ulang OTP tidak mengembalikan jatah percobaan, batas dihitung per nomor.
+func TestPhoneVerification_AttemptBudgetSurvivesResend(t *testing.T) {
+	notifications := &queueingNotificationRepository{}
+	phoneService, server := newTestPhoneVerificationService(t, notifications)
+	ctx := context.Background()
+
+	_, err := phoneService.SendOTP(ctx, 7, "081234567890")
+	require.NoError(t, err)
+	for range 3 {
+		assert.EqualError(t, phoneService.VerifyOTP(ctx, 7, "wrong"), "422")
+	}
+
+	server.FastForward(time.Minute)
+	_, err = phoneService.SendOTP(ctx, 7, "081234567890")
+	require.NoError(t, err)
+	for range 2 {
+		assert.EqualError(t, phoneService.VerifyOTP(ctx, 7, "wrong"), "422")
+	}
+	assert.EqualError(t, phoneService.VerifyOTP(ctx, 7, notifications.created[1].Data["code"]), "429")
+	assert.False(t, server.Exists("phone_otp:7"))
+
+	// Jatah habis, OTP baru juga tidak dikirim sampai window berakhir
+	server.FastForward(time.Minute)
+	_, err = phoneService.SendOTP(ctx, 7, "081234567890")
+	assert.EqualError(t, err, "429")
+	assert.Len(t, notifications.created, 2)
+}
+
+func TestPhoneVerification_SendCapPerPhone(t *testing.T) {
+	notifications := &queueingNotificationRepository{}
+	phoneService, server := newTestPhoneVerificationService(t, notifications)
+	ctx := context.Background()
+
+	for range 5 {
+		_, err := phoneService.SendOTP(ctx, 7, "081234567890")
+		require.NoError(t, err)
+		server.FastForward(time.Minute)
+	}
+	_, err := phoneService.SendOTP(ctx, 7, "081234567890")
+	assert.EqualError(t, err, "429")
+	assert.Len(t, notifications.created, 5)
+
+	server.FastForward(time.Hour)
+	_, err = phoneService.SendOTP(ctx, 7, "081234567890")
+	assert.NoError(t, err)
+}
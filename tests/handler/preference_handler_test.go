@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestUpdatePreferences_Success(t *testing.T) {
+	body := `{"locale": "en", "notifications.push.general": null}`
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/auth/preferences", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	c.Set("user", `{"user_id": 7}`)
+
+	changes := map[string]interface{}{"locale": "en", "notifications.push.general": nil}
+	mockService := new(mock.MockPreferenceService)
+	mockService.On("UpdatePreferences", testifymock.Anything, int64(7), changes).Return([]entity.PreferenceEntity{
+		{Key: "locale", Type: "string", Value: "en", Default: "id"},
+		{Key: "notifications.push.general", Type: "bool", Value: true, Default: true, IsDefault: true},
+	}, nil)
+
+	handler := echoinboundadapter.NewPreferenceHandler(mockService)
+
+	err := handler.UpdatePreferences(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"value":"en"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_InvalidValue(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/auth/preferences", strings.NewReader(`{"timezone": "Mars/Olympus"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockPreferenceService)
+	mockService.On("UpdatePreferences", testifymock.Anything, int64(7), testifymock.Anything).Return(nil, errors.New("422"))
+
+	handler := echoinboundadapter.NewPreferenceHandler(mockService)
+
+	err := handler.UpdatePreferences(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCustomerPreferences_NotFound(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/9/preferences", nil)
+	c.Set("user", `{"user_id": 1}`)
+	c.SetParamNames("id")
+	c.SetParamValues("9")
+
+	mockService := new(mock.MockPreferenceService)
+	mockService.On("GetCustomerPreferences", testifymock.Anything, int64(9)).Return(nil, errors.New("404"))
+
+	handler := echoinboundadapter.NewPreferenceHandler(mockService)
+
+	err := handler.GetCustomerPreferences(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
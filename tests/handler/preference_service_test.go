@@ -0,0 +1,205 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/outbound"
+	mockService "clean-architecture/tests/mock"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePreferenceRepository menyimpan preferensi di map, err dikembalikan oleh semua method jika diisi.
+type fakePreferenceRepository struct {
+	outbound.PreferenceRepositoryInterface
+	values map[string]string
+	resets []string
+	err    error
+}
+
+func (f *fakePreferenceRepository) GetAll(ctx context.Context, userID int64) ([]entity.StoredPreferenceEntity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	stored := []entity.StoredPreferenceEntity{}
+	for key, val := range f.values {
+		stored = append(stored, entity.StoredPreferenceEntity{Key: key, Value: val, UpdatedAt: time.Now()})
+	}
+	return stored, nil
+}
+
+func (f *fakePreferenceRepository) GetByKey(ctx context.Context, userID int64, key string) (*entity.StoredPreferenceEntity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	val, ok := f.values[key]
+	if !ok {
+		return nil, errors.New("404")
+	}
+	return &entity.StoredPreferenceEntity{Key: key, Value: val, UpdatedAt: time.Now()}, nil
+}
+
+func (f *fakePreferenceRepository) Save(ctx context.Context, userID int64, values map[string]string, resetKeys []string) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	for key, val := range values {
+		f.values[key] = val
+	}
+	for _, key := range resetKeys {
+		delete(f.values, key)
+	}
+	f.resets = append(f.resets, resetKeys...)
+	return nil
+}
+
+func preferenceValue(t *testing.T, preferences []entity.PreferenceEntity, key string) entity.PreferenceEntity {
+	t.Helper()
+	for _, val := range preferences {
+		if val.Key == key {
+			return val
+		}
+	}
+	t.Fatalf("preference %s not found", key)
+	return entity.PreferenceEntity{}
+}
+
+func TestPreferenceService_UpdateRejectsInvalidValues(t *testing.T) {
+	cases := map[string]map[string]interface{}{
+		"unknown key":      {"notifications.email.account": false},
+		"wrong type":       {service.PreferenceMarketingConsent: "yes"},
+		"invalid locale":   {service.PreferenceLocale: "fr"},
+		"invalid timezone": {service.PreferenceTimezone: "Mars/Olympus"},
+		"empty timezone":   {service.PreferenceTimezone: ""},
+	}
+	for name, changes := range cases {
+		repo := &fakePreferenceRepository{}
+		preferenceService := service.NewPreferenceService(repo, nil)
+
+		_, err := preferenceService.UpdatePreferences(context.Background(), 7, changes)
+		require.Error(t, err, name)
+		assert.Equal(t, "422", err.Error(), name)
+		assert.Empty(t, repo.values, name)
+	}
+}
+
+func TestPreferenceService_UpdateMergesAndResets(t *testing.T) {
+	repo := &fakePreferenceRepository{values: map[string]string{
+		service.PreferenceLocale:   `"en"`,
+		service.PreferenceTimezone: `"Asia/Makassar"`,
+	}}
+	preferenceService := service.NewPreferenceService(repo, nil)
+
+	preferences, err := preferenceService.UpdatePreferences(context.Background(), 7, map[string]interface{}{
+		service.PreferenceMarketingConsent: true,
+		service.PreferenceLocale:           nil,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{service.PreferenceLocale}, repo.resets)
+	locale := preferenceValue(t, preferences, service.PreferenceLocale)
+	assert.Equal(t, "id", locale.Value)
+	assert.True(t, locale.IsDefault)
+	assert.Equal(t, "Asia/Makassar", preferenceValue(t, preferences, service.PreferenceTimezone).Value)
+	consent := preferenceValue(t, preferences, service.PreferenceMarketingConsent)
+	assert.Equal(t, true, consent.Value)
+	assert.False(t, consent.IsDefault)
+}
+
+// Nilai tersimpan yang tidak lagi sesuai schema kembali ke default.
+func TestPreferenceService_InvalidStoredValueFallsBackToDefault(t *testing.T) {
+	repo := &fakePreferenceRepository{values: map[string]string{
+		service.PreferenceLocale:        `"fr"`,
+		"notifications.push.general":    `"off"`,
+		"notifications.email.marketing": `true`,
+	}}
+	preferenceService := service.NewPreferenceService(repo, nil)
+
+	preferences, err := preferenceService.GetPreferences(context.Background(), 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, "id", preferenceValue(t, preferences, service.PreferenceLocale).Value)
+	assert.True(t, preferenceValue(t, preferences, service.PreferenceLocale).IsDefault)
+	assert.Equal(t, true, preferenceValue(t, preferences, "notifications.push.general").Value)
+	assert.Equal(t, true, preferenceValue(t, preferences, "notifications.email.marketing").Value)
+
+	value, err := preferenceService.GetPreference(context.Background(), 7, "notifications.push.general")
+	require.NoError(t, err)
+	assert.Equal(t, true, value)
+}
+
+func TestPreferenceService_IsNotificationEnabled(t *testing.T) {
+	cases := []struct {
+		name    string
+		values  map[string]string
+		queue   string
+		enabled bool
+	}{
+		{"marketing without consent", map[string]string{"notifications.email.marketing": `true`}, utils.NOTIF_EMAIL_MARKETING, false},
+		{"consent without channel opt-in", map[string]string{service.PreferenceMarketingConsent: `true`}, utils.NOTIF_EMAIL_MARKETING, false},
+		{"consent and channel opt-in", map[string]string{service.PreferenceMarketingConsent: `true`, "notifications.sms.marketing": `true`}, utils.NOTIF_SMS_MARKETING, true},
+		{"general push default", nil, utils.PUSH_NOTIF, true},
+		{"general push opted out", map[string]string{"notifications.push.general": `false`}, utils.PUSH_NOTIF, false},
+		{"security notification", nil, utils.NOTIF_EMAIL_FORGOT_PASSWORD, true},
+		{"create customer", nil, utils.NOTIF_EMAIL_CREATE_CUSTOMER, true},
+		{"update customer", nil, utils.NOTIF_EMAIL_UPDATE_CUSTOMER, true},
+	}
+	for _, val := range cases {
+		preferenceService := service.NewPreferenceService(&fakePreferenceRepository{values: val.values}, nil)
+
+		enabled, err := preferenceService.IsNotificationEnabled(context.Background(), 7, val.queue)
+		require.NoError(t, err, val.name)
+		assert.Equal(t, val.enabled, enabled, val.name)
+	}
+}
+
+// Email akun berisi password sementara, preferensi tidak dibaca sama sekali sehingga tetap terkirim
+// walaupun database preferensi tidak bisa diakses.
+func TestPreferenceService_AccountEmailsCannotBeOptedOut(t *testing.T) {
+	repo := &fakePreferenceRepository{err: errors.New("connection refused")}
+	preferenceService := service.NewPreferenceService(repo, nil)
+
+	for _, queue := range []string{utils.NOTIF_EMAIL_CREATE_CUSTOMER, utils.NOTIF_EMAIL_UPDATE_CUSTOMER} {
+		enabled, err := preferenceService.IsNotificationEnabled(context.Background(), 7, queue)
+		require.NoError(t, err, queue)
+		assert.True(t, enabled, queue)
+	}
+
+	_, err := preferenceService.IsNotificationEnabled(context.Background(), 7, utils.NOTIF_EMAIL_MARKETING)
+	assert.Error(t, err)
+}
+
+func TestKafkaService_PreferenceErrorFailsClosed(t *testing.T) {
+	preferences := new(mockService.MockPreferenceService)
+	preferences.On("IsNotificationEnabled", mock.Anything, int64(7), utils.NOTIF_EMAIL_MARKETING).
+		Return(false, errors.New("connection refused"))
+
+	sender := &recordingSender{}
+	kafkaService := service.NewKafkaService(&config.Config{}, sender, nil, nil, preferences)
+
+	ctx, delivered := deliveryRecorder()
+	err := kafkaService.PublishMessage(ctx, entity.PublishMessage{
+		Email:     "budi@example.com",
+		Message:   "Promo",
+		UserId:    7,
+		QueueName: utils.NOTIF_EMAIL_MARKETING,
+	})
+
+	assert.Error(t, err)
+	assert.Empty(t, sender.sent)
+	// Error sinkron, callback tidak dipanggil sehingga outbox mencoba ulang
+	assert.Empty(t, *delivered)
+	preferences.AssertExpectations(t)
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestExportMyData_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/auth/me/export", nil)
+	c.Set("user", `{"user_id": 7}`)
+
+	photoPath := "public/uploads/users/7/photo.jpg"
+	mockService := new(mock.MockPrivacyService)
+	mockService.On("GetUserDataExport", testifymock.Anything, int64(7)).Return(&entity.UserDataExportEntity{
+		Profile:     entity.UserEntity{ID: 7, Name: "Budi", Email: "budi@mail.com"},
+		Roles:       []entity.RoleEntity{{ID: 2, Name: "Customer"}},
+		Tokens:      []entity.VerificationTokenEntity{{ID: 1, Token: "secret-token", TokenType: "email_verification"}},
+		Files:       []string{photoPath},
+		GeneratedAt: time.Now(),
+	}, nil)
+	mockService.On("OpenUserFile", testifymock.Anything, photoPath).Return(io.NopCloser(strings.NewReader("jpeg-bytes")), nil)
+
+	handler := echoinboundadapter.NewPrivacyHandler(mockService)
+
+	err := handler.ExportMyData(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/zip", rec.Header().Get("Content-Type"))
+
+	archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	assert.NoError(t, err)
+
+	contents := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		contents[file.Name] = string(data)
+	}
+
+	assert.Contains(t, contents["profile.json"], `"email": "budi@mail.com"`)
+	assert.Contains(t, contents["roles.json"], `"Customer"`)
+	assert.Equal(t, "jpeg-bytes", contents["files/users/7/photo.jpg"])
+	assert.NotContains(t, contents["tokens.json"], "secret-token")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRequestDeletion_AlreadyRequested(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/auth/me/deletion-request", nil)
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockPrivacyService)
+	mockService.On("RequestDeletion", testifymock.Anything, int64(7)).Return(nil, errors.New("409"))
+
+	handler := echoinboundadapter.NewPrivacyHandler(mockService)
+
+	err := handler.RequestDeletion(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCancelDeletion_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodDelete, "/auth/me/deletion-request", nil)
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockPrivacyService)
+	mockService.On("CancelDeletion", testifymock.Anything, int64(7)).Return(nil)
+
+	handler := echoinboundadapter.NewPrivacyHandler(mockService)
+
+	err := handler.CancelDeletion(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
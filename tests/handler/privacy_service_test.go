@@ -0,0 +1,177 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Fake repository hanya mengimplementasikan method yang dipakai test, method lain panic.
+type fakePrivacyRepository struct {
+	outbound.PrivacyRepositoryInterface
+	due    []entity.DeletionRequestEntity
+	tokens []entity.VerificationTokenEntity
+	photos []string
+	erased []int64
+}
+
+func (f *fakePrivacyRepository) GetUserRoles(ctx context.Context, userID int64) ([]entity.RoleEntity, error) {
+	return nil, nil
+}
+
+func (f *fakePrivacyRepository) GetVerificationTokens(ctx context.Context, userID int64) ([]entity.VerificationTokenEntity, error) {
+	return f.tokens, nil
+}
+
+func (f *fakePrivacyRepository) GetOtherUserPhotos(ctx context.Context, userID int64, folder string) ([]string, error) {
+	return f.photos, nil
+}
+
+func (f *fakePrivacyRepository) GetDueDeletionRequests(ctx context.Context, dueBefore time.Time, limit int) ([]entity.DeletionRequestEntity, error) {
+	return f.due, nil
+}
+
+func (f *fakePrivacyRepository) EraseUser(ctx context.Context, req entity.DeletionRequestEntity, erasedAt time.Time, events []entity.DomainEventEntity) error {
+	f.erased = append(f.erased, req.UserID)
+	return nil
+}
+
+type fakeUserRepository struct {
+	outbound.UserRepositoryInterface
+	user *entity.UserEntity
+}
+
+func (f *fakeUserRepository) GetCustomerByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
+	if f.user == nil || f.user.ID != id {
+		return nil, errors.New("404")
+	}
+	return f.user, nil
+}
+
+func (f *fakeUserRepository) GetUserByID(ctx context.Context, id int64) (*entity.UserEntity, error) {
+	return f.GetCustomerByID(ctx, id)
+}
+
+type fakeAddressRepository struct {
+	outbound.AddressRepositoryInterface
+}
+
+func (f *fakeAddressRepository) GetAll(ctx context.Context, userID int64) ([]entity.AddressEntity, error) {
+	return nil, errors.New("404")
+}
+
+type fakeStorage struct {
+	outbound.MinioInterface
+	objects []string
+	removed []string
+	copied  map[string]string
+}
+
+func (f *fakeStorage) ListObjects(ctx context.Context, prefix string) ([]string, error) {
+	paths := []string{}
+	for _, val := range f.objects {
+		if strings.HasPrefix(val, prefix) {
+			paths = append(paths, val)
+		}
+	}
+	return paths, nil
+}
+
+func (f *fakeStorage) GetObject(ctx context.Context, path string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(path)), nil
+}
+
+func (f *fakeStorage) RemoveObjects(ctx context.Context, paths []string) error {
+	f.removed = append(f.removed, paths...)
+	return nil
+}
+
+func (f *fakeStorage) CopyObject(ctx context.Context, srcPath, dstPath string) error {
+	if f.copied == nil {
+		f.copied = map[string]string{}
+	}
+	f.copied[srcPath] = dstPath
+	return nil
+}
+
+func newTestPrivacyService(t *testing.T, photo string, repo *fakePrivacyRepository, storage *fakeStorage) service.PrivacyServiceInterface {
+	userRepo := &fakeUserRepository{user: &entity.UserEntity{ID: 7, Name: "Budi", Photo: photo}}
+	cfg := &config.Config{Minio: config.Minio{Bucket: "sayur"}}
+	return service.NewPrivacyService(repo, userRepo, &fakeAddressRepository{}, storage, tests.NewRedisServer(t).Client(), cfg)
+}
+
+func TestEraseUser_RefusesForeignPhotoPath(t *testing.T) {
+	photos := []string{
+		"public/uploads/users/8/photo.jpg",
+		"http://minio:9000/sayur/public/uploads/users/8/photo.jpg",
+		"public/uploads/users/7/../8/photo.jpg",
+		"public/uploads/shared/banner.jpg",
+	}
+	for _, photo := range photos {
+		storage := &fakeStorage{objects: []string{
+			"public/uploads/users/7/photo.jpg",
+			"public/uploads/users/8/photo.jpg",
+		}}
+		repo := &fakePrivacyRepository{due: []entity.DeletionRequestEntity{{ID: 1, UserID: 7}}}
+		privacyService := newTestPrivacyService(t, photo, repo, storage)
+
+		erased, err := privacyService.ProcessDueDeletions(context.Background(), time.Now(), 10)
+		require.NoError(t, err, photo)
+		assert.Equal(t, 1, erased, photo)
+		assert.Equal(t, []string{"public/uploads/users/7/photo.jpg"}, storage.removed, photo)
+	}
+}
+
+func TestEraseUser_KeepsPhotosUsedByOtherUsers(t *testing.T) {
+	storage := &fakeStorage{objects: []string{
+		"public/uploads/users/7/photo.jpg",
+		"public/uploads/users/7/customer.jpg",
+		"public/uploads/users/7/customer-2.jpg",
+	}}
+	repo := &fakePrivacyRepository{
+		due: []entity.DeletionRequestEntity{{ID: 1, UserID: 7}},
+		photos: []string{
+			"public/uploads/users/7/customer.jpg",
+			"http://minio:9000/sayur/public/uploads/users/7/customer-2.jpg?X-Amz-Signature=abc",
+		},
+	}
+	privacyService := newTestPrivacyService(t, "public/uploads/users/7/photo.jpg", repo, storage)
+
+	erased, err := privacyService.ProcessDueDeletions(context.Background(), time.Now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, erased)
+	assert.Equal(t, []string{"public/uploads/users/7/photo.jpg"}, storage.removed)
+}
+
+func TestUserDataExport_OnlyOwnFilesAndExpiredTokens(t *testing.T) {
+	now := time.Now()
+	storage := &fakeStorage{objects: []string{
+		"public/uploads/users/7/photo.jpg",
+		"public/uploads/users/8/photo.jpg",
+	}}
+	repo := &fakePrivacyRepository{tokens: []entity.VerificationTokenEntity{
+		{ID: 1, Token: "expired-token", TokenType: "email_verification", ExpiresAt: now.Add(-time.Hour)},
+		{ID: 2, Token: "live-token", TokenType: "reset_password", ExpiresAt: now.Add(time.Hour)},
+	}}
+	privacyService := newTestPrivacyService(t, "public/uploads/users/8/photo.jpg", repo, storage)
+
+	data, err := privacyService.GetUserDataExport(context.Background(), 7)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"public/uploads/users/7/photo.jpg"}, data.Files)
+	require.Len(t, data.Tokens, 1)
+	assert.Equal(t, int64(1), data.Tokens[0].ID)
+	assert.Empty(t, data.Tokens[0].Token)
+}
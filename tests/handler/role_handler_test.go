@@ -1,15 +1,22 @@
 package handler_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
 	"clean-architecture/internal/domain/entity"
 	"clean-architecture/tests"
 	"clean-architecture/tests/mock"
+	"clean-architecture/utils/validator"
 
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	testifymock "github.com/stretchr/testify/mock"
 )
@@ -48,3 +55,127 @@ func TestGetAllRoles_Success(t *testing.T) {
 	// Verifikasi mock dipanggil
 	mockService.AssertCalled(t, "GetAll", testifymock.Anything, "")
 }
+
+func TestUpdateRole_StaleVersion(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPut, "/admin/roles/2", strings.NewReader(`{"name":"Staff"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Request().Header.Set("If-Match", `"4"`)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockRoleService)
+	mockService.On("Update", testifymock.Anything, entity.RoleEntity{ID: 2, Name: "Staff", Version: 4}).Return(errors.New("412"))
+
+	roleHandler := echoinboundadapter.NewRoleHandler(mockService)
+
+	err := roleHandler.Update(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetDeletedRoles_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/roles/deleted?search=staff", nil)
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	deletedAt := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	mockService := new(mock.MockRoleService)
+	mockService.On("GetDeletedAll", testifymock.Anything, "staff").Return([]entity.RoleEntity{
+		{ID: 3, Name: "Staff", DeletedAt: &deletedAt},
+	}, nil)
+
+	roleHandler := echoinboundadapter.NewRoleHandler(mockService)
+
+	err := roleHandler.GetDeletedAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"Staff"`)
+	assert.Contains(t, rec.Body.String(), `"deleted_at":"2025-01-01T10:00:00Z"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetDeletedRoles_NotFound(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/roles/deleted", nil)
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockRoleService)
+	mockService.On("GetDeletedAll", testifymock.Anything, "").Return([]entity.RoleEntity(nil), errors.New("404"))
+
+	roleHandler := echoinboundadapter.NewRoleHandler(mockService)
+
+	err := roleHandler.GetDeletedAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetDeletedRoles_NotSuperAdmin(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/roles/deleted", nil)
+	c.Set("user", `{"user_id": 2, "role_name": "Admin"}`)
+
+	mockService := new(mock.MockRoleService)
+	roleHandler := echoinboundadapter.NewRoleHandler(mockService)
+
+	err := roleHandler.GetDeletedAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	mockService.AssertNotCalled(t, "GetDeletedAll", testifymock.Anything, testifymock.Anything)
+}
+
+func TestRestoreRole_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/roles/3/restore", nil)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockRoleService)
+	mockService.On("Restore", testifymock.Anything, int64(3)).Return(nil)
+
+	roleHandler := echoinboundadapter.NewRoleHandler(mockService)
+
+	err := roleHandler.Restore(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreRole_NotFound(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/roles/3/restore", nil)
+	c.SetParamNames("id")
+	c.SetParamValues("3")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockRoleService)
+	mockService.On("Restore", testifymock.Anything, int64(3)).Return(errors.New("404"))
+
+	roleHandler := echoinboundadapter.NewRoleHandler(mockService)
+
+	err := roleHandler.Restore(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRoleRepository_PurgeSkipsRolesOfActiveUsers(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	deletedBefore := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := outboundadapterpostgres.NewRoleRepository(db).PurgeDeleted(context.Background(), deletedBefore)
+	assert.NoError(t, err)
+
+	queries := recorder.Queries()
+	if assert.Len(t, queries, 1) {
+		assert.Contains(t, queries[0].SQL, `DELETE FROM "roles"`)
+		assert.Contains(t, queries[0].SQL, "NOT EXISTS (SELECT 1 FROM user_role JOIN users ON users.id = user_role.user_id")
+		assert.Contains(t, queries[0].SQL, "users.deleted_at IS NULL")
+		assert.Equal(t, []any{deletedBefore}, queries[0].Args)
+	}
+}
@@ -0,0 +1,61 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestGetDashboardStats_WithRange(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/stats?from=2025-01-01&to=2025-01-02", nil)
+	c.Set("user", `{"user_id": 1}`)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mockService := new(mock.MockStatsService)
+	mockService.On("GetDashboardStats", testifymock.Anything, from, to).Return(&entity.DashboardStatsEntity{
+		TotalCustomers:    10,
+		VerifiedCustomers: 7,
+		ActiveSessions:    3,
+		SignupsPerDay: []entity.DailyCountEntity{
+			{Date: from, Count: 2},
+			{Date: to, Count: 0},
+		},
+		UsersPerRole: []entity.RoleCountEntity{{RoleID: 2, RoleName: "Customer", Count: 10}},
+		From:         from,
+		To:           to,
+	}, nil)
+
+	handler := echoinboundadapter.NewStatsHandler(mockService)
+
+	err := handler.GetDashboardStats(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total_customers":10`)
+	assert.Contains(t, rec.Body.String(), `{"date":"2025-01-02","count":0}`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetDashboardStats_InvalidDate(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/stats?from=01-01-2025", nil)
+	c.Set("user", `{"user_id": 1}`)
+
+	mockService := new(mock.MockStatsService)
+	handler := echoinboundadapter.NewStatsHandler(mockService)
+
+	err := handler.GetDashboardStats(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "GetDashboardStats", testifymock.Anything, testifymock.Anything, testifymock.Anything)
+}
@@ -0,0 +1,505 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	echoinboundadapter "clean-architecture/internal/adapter/inbound/echo"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/tests/mock"
+	"clean-architecture/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	testifymock "github.com/stretchr/testify/mock"
+)
+
+func TestGetCustomerAll_WithFilterSortAndFields(t *testing.T) {
+	path := "/admin/customers?filter[is_verified]=true&filter[created_at][gte]=2025-01-01&sort=-created_at,name&fields=id,email"
+	c, rec := tests.NewEchoContext(http.MethodGet, path, nil)
+	c.Set("user", "test-user")
+
+	mockService := new(mock.MockUserService)
+	mockService.On("GetCustomerAll", testifymock.Anything, testifymock.MatchedBy(func(query entity.QueryStringEntity) bool {
+		return assert.ObjectsAreEqual([]entity.FilterEntity{
+			{Field: "created_at", Operator: entity.FilterOpGte, Value: "2025-01-01"},
+			{Field: "is_verified", Operator: entity.FilterOpEq, Value: "true"},
+		}, query.Filters) &&
+			assert.ObjectsAreEqual([]entity.SortEntity{
+				{Field: "created_at", Desc: true},
+				{Field: "name", Desc: false},
+			}, query.Sorts) &&
+			assert.ObjectsAreEqual([]string{"id", "email"}, query.Fields)
+	})).Return([]entity.UserEntity{
+		{ID: 1, Name: "Budi", Email: "budi@mail.com"},
+	}, int64(1), int64(1), nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.GetCustomerAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []map[string]any `json:"data"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": float64(1), "email": "budi@mail.com"}}, body.Data)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCustomerAll_UnsupportedFilter(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers?filter[password]=secret", nil)
+	c.Set("user", "test-user")
+
+	mockService := new(mock.MockUserService)
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.GetCustomerAll(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "GetCustomerAll", testifymock.Anything, testifymock.Anything)
+}
+
+func TestSearchCustomers_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/search?q=budi&limit=1", nil)
+	c.Set("user", "test-user")
+
+	mockService := new(mock.MockUserService)
+	mockService.On("SearchCustomers", testifymock.Anything, testifymock.MatchedBy(func(query entity.QueryStringEntity) bool {
+		return query.Search == "budi" && query.Page == 1 && query.Limit == 1
+	})).Return([]entity.CustomerSearchEntity{
+		{
+			User:       entity.UserEntity{ID: 1, Name: "Budi Santoso", Email: "budi@mail.com"},
+			Score:      0.9,
+			Highlights: map[string]string{"name": "<mark>Budi</mark> Santoso"},
+		},
+	}, int64(2), int64(2), nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.SearchCustomers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []struct {
+			ID         int64             `json:"id"`
+			Score      float64           `json:"score"`
+			Highlights map[string]string `json:"highlights"`
+		} `json:"data"`
+		Pagination struct {
+			TotalCount int64  `json:"total_count"`
+			Next       string `json:"next"`
+		} `json:"pagination"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Len(t, body.Data, 1)
+	assert.Equal(t, "<mark>Budi</mark> Santoso", body.Data[0].Highlights["name"])
+	assert.Equal(t, int64(2), body.Pagination.TotalCount)
+	assert.Contains(t, body.Pagination.Next, "page=2")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchCustomers_MissingKeyword(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/search?q=%20", nil)
+	c.Set("user", "test-user")
+
+	mockService := new(mock.MockUserService)
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.SearchCustomers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "SearchCustomers", testifymock.Anything, testifymock.Anything)
+}
+
+func TestGetCustomerNearby_Radius(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/nearby?lat=-6.2&lng=106.816666&radius_km=10", nil)
+	c.Set("user", "test-user")
+
+	lat, lng := -6.21, 106.82
+	mockService := new(mock.MockUserService)
+	mockService.On("GetCustomerNearby", testifymock.Anything, testifymock.MatchedBy(func(query entity.NearbyQueryEntity) bool {
+		return query.Lat == -6.2 && query.Lng == 106.816666 && query.RadiusKm == 10 && query.Box == nil &&
+			query.Page == 1 && query.Limit == 10
+	})).Return([]entity.NearbyCustomerEntity{
+		{User: entity.UserEntity{ID: 1, Name: "Budi", Lat: &lat, Lng: &lng}, DistanceKm: 1.23456},
+	}, int64(1), int64(1), nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.GetCustomerNearby(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []struct {
+			ID         int64    `json:"id"`
+			Lat        *float64 `json:"lat"`
+			DistanceKm float64  `json:"distance_km"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Len(t, body.Data, 1)
+	assert.Equal(t, -6.21, *body.Data[0].Lat)
+	assert.Equal(t, 1.235, body.Data[0].DistanceKm)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCustomerNearby_BoundingBox(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/nearby?min_lat=-7&min_lng=106&max_lat=-6&max_lng=107", nil)
+	c.Set("user", "test-user")
+
+	mockService := new(mock.MockUserService)
+	mockService.On("GetCustomerNearby", testifymock.Anything, testifymock.MatchedBy(func(query entity.NearbyQueryEntity) bool {
+		return query.Box != nil && *query.Box == entity.GeoBoxEntity{MinLat: -7, MinLng: 106, MaxLat: -6, MaxLng: 107} &&
+			query.Lat == -6.5 && query.Lng == 106.5
+	})).Return([]entity.NearbyCustomerEntity{{User: entity.UserEntity{ID: 1}}}, int64(1), int64(1), nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.GetCustomerNearby(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetCustomerNearby_InvalidCoordinate(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/nearby?lat=91&lng=106", nil)
+	c.Set("user", "test-user")
+
+	mockService := new(mock.MockUserService)
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.GetCustomerNearby(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "GetCustomerNearby", testifymock.Anything, testifymock.Anything)
+}
+
+func TestSignIn_WithPhone(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/signin", strings.NewReader(`{"phone":"+6281234567890","password":"password123"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+
+	mockService := new(mock.MockUserService)
+	mockService.On("SignIn", testifymock.Anything, testifymock.MatchedBy(func(req entity.UserEntity) bool {
+		return req.Phone == "+6281234567890" && req.Email == "" && req.Password == "password123"
+	})).Return(&entity.UserEntity{ID: 1, Name: "Budi", Phone: "+6281234567890"}, "token", nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.SignIn(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSignIn_WithoutEmailAndPhone(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/signin", strings.NewReader(`{"password":"password123"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+
+	mockService := new(mock.MockUserService)
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.SignIn(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	mockService.AssertNotCalled(t, "SignIn", testifymock.Anything, testifymock.Anything)
+}
+
+func TestSignIn_ConsentRequired(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/signin", strings.NewReader(`{"email":"budi@mail.com","password":"password123"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+
+	mockService := new(mock.MockUserService)
+	mockService.On("SignIn", testifymock.Anything, testifymock.Anything).Return(nil, "challenge-token", errors.New("428"))
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.SignIn(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"challenge_token":"challenge-token"`)
+	assert.NotContains(t, rec.Body.String(), "access_token")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSignInWithConsent_Success(t *testing.T) {
+	body := `{"challenge_token":"challenge-token","document_ids":[3,4]}`
+	c, rec := tests.NewEchoContext(http.MethodPost, "/signin/consent", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Request().Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+	c.Echo().Validator = validator.NewValidator(nil, "")
+
+	mockService := new(mock.MockUserService)
+	mockService.On("SignInWithConsent", testifymock.Anything, "challenge-token", []int64{3, 4}, "10.0.0.1").
+		Return(&entity.UserEntity{ID: 1, Name: "Budi"}, "token", nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.SignInWithConsent(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"access_token":"token"`)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateUserAccount_WithoutTermsAcceptance(t *testing.T) {
+	body := `{"name":"Budi","email":"budi@mail.com","password":"password123","password_confirmation":"password123"}`
+	c, rec := tests.NewEchoContext(http.MethodPost, "/signup", strings.NewReader(body))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+
+	mockService := new(mock.MockUserService)
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.CreateUserAccount(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	mockService.AssertNotCalled(t, "CreateUserAccount", testifymock.Anything, testifymock.Anything)
+}
+
+func TestGetCustomerByID_SetsETag(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodGet, "/admin/customers/5", nil)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("GetCustomerByID", testifymock.Anything, int64(5)).Return(&entity.UserEntity{ID: 5, Name: "Budi", Version: 3}, nil)
+
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.GetCustomerByID(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateCustomer_MissingIfMatch(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPut, "/admin/customers/5", strings.NewReader(`{"name":"Budi"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.UpdateCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+	mockService.AssertNotCalled(t, "UpdateDataUser", testifymock.Anything, testifymock.Anything)
+}
+
+func TestUpdateCustomer_StaleVersion(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPut, "/admin/customers/5", strings.NewReader(`{"name":"Budi"}`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Request().Header.Set("If-Match", `"2"`)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("UpdateDataUser", testifymock.Anything, entity.UserEntity{ID: 5, Name: "Budi", Version: 2}).Return(errors.New("412"))
+
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.UpdateCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchProfile_NullClearsField(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/auth/profile", strings.NewReader(`{"phone":null,"lat":null,"name":"Budi"}`))
+	c.Request().Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 7}`)
+
+	name := "Budi"
+	mockService := new(mock.MockUserService)
+	mockService.On("PatchProfile", testifymock.Anything, testifymock.MatchedBy(func(patch entity.UserPatchEntity) bool {
+		return patch.ID == 7 &&
+			patch.Phone.Set && patch.Phone.Value == nil &&
+			patch.Lat.Set && patch.Lat.Value == nil &&
+			patch.Name.Set && *patch.Name.Value == name &&
+			!patch.Address.Set && !patch.Lng.Set
+	})).Return(&entity.UserEntity{ID: 7, Name: name, Version: 5}, nil)
+
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.PatchProfile(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchCustomer_PhotoOfAnotherUser(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/admin/customers/5", strings.NewReader(`{"photo":"public/uploads/users/9/photo.jpg"}`))
+	c.Request().Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	c.Request().Header.Set("If-Match", `"1"`)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("PatchCustomer", testifymock.Anything, testifymock.MatchedBy(func(patch entity.UserPatchEntity) bool {
+		return patch.ID == 5 && patch.CallerID == 1 && patch.Photo.Set
+	})).Return(nil, errors.New("403"))
+
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.PatchCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchProfile_EmailUsedByAnotherUser(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/auth/profile", strings.NewReader(`{"email":"siti@example.com"}`))
+	c.Request().Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.Set("user", `{"user_id": 7}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("PatchProfile", testifymock.Anything, testifymock.MatchedBy(func(patch entity.UserPatchEntity) bool {
+		return patch.ID == 7 && patch.Email.Set && *patch.Email.Value == "siti@example.com"
+	})).Return(nil, errors.New("409"))
+
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.PatchProfile(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchCustomer_EmailUsedByAnotherUser(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/admin/customers/5", strings.NewReader(`{"email":"siti@example.com"}`))
+	c.Request().Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	c.Request().Header.Set("If-Match", `"1"`)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("PatchCustomer", testifymock.Anything, testifymock.MatchedBy(func(patch entity.UserPatchEntity) bool {
+		return patch.ID == 5 && patch.Email.Set && *patch.Email.Value == "siti@example.com"
+	})).Return(nil, errors.New("409"))
+
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.PatchCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchCustomer_RejectsNullName(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/admin/customers/5", strings.NewReader(`{"name":null}`))
+	c.Request().Header.Set(echo.HeaderContentType, "application/merge-patch+json")
+	c.Request().Header.Set("If-Match", `"1"`)
+	c.Echo().Validator = validator.NewValidator(nil, "")
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.PatchCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	mockService.AssertNotCalled(t, "PatchCustomer", testifymock.Anything, testifymock.Anything)
+}
+
+func TestPatchCustomer_UnsupportedMediaType(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPatch, "/admin/customers/5", strings.NewReader(`name=Budi`))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	c.Request().Header.Set("If-Match", `"1"`)
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	handler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := handler.PatchCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestRestoreCustomer_Success(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/customers/7/restore", nil)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("RestoreCustomer", testifymock.Anything, int64(7)).Return(nil)
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.RestoreCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreCustomer_Erased(t *testing.T) {
+	c, rec := tests.NewEchoContext(http.MethodPost, "/admin/customers/7/restore", nil)
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	c.Set("user", `{"user_id": 1, "role_name": "Super Admin"}`)
+
+	mockService := new(mock.MockUserService)
+	mockService.On("RestoreCustomer", testifymock.Anything, int64(7)).Return(errors.New("409"))
+
+	userHandler := echoinboundadapter.NewUserHandler(mockService)
+
+	err := userHandler.RestoreCustomer(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "customer data has been erased and cannot be restored")
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,217 @@
+package handler_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	outboundadapterpostgres "clean-architecture/internal/adapter/outbound/postgres/repository"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/tests"
+	"clean-architecture/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func queriesContaining(recorder *tests.SQLRecorder, match string) []tests.RecordedQuery {
+	result := []tests.RecordedQuery{}
+	for _, val := range recorder.Queries() {
+		if strings.Contains(val.SQL, match) {
+			result = append(result, val)
+		}
+	}
+	return result
+}
+
+func TestUserRepository_UpdateDataUserWritesDefaultAddress(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "email", "address", "version"},
+		Rows:    [][]driver.Value{{int64(7), "Budi", "budi@example.com", "Jl. Lama", int64(3)}},
+	})
+	for _, addressLine := range []string{"Jl. Lama", "Jl. Baru"} {
+		recorder.AddRows(tests.SQLRows{
+			Match:   `FROM "user_addresses"`,
+			Columns: []string{"id", "user_id", "address_line", "is_default"},
+			Rows:    [][]driver.Value{{int64(11), int64(7), addressLine, true}},
+		})
+	}
+
+	lat := -6.2
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	require.NoError(t, repo.UpdateDataUser(context.Background(), entity.UserEntity{ID: 7, Address: "Jl. Baru", Lat: &lat}))
+
+	userUpdates := queriesContaining(recorder, `UPDATE "users"`)
+	require.Len(t, userUpdates, 2)
+	// Update profil tidak menulis alamat langsung, hanya salinan dari alamat default
+	assert.NotContains(t, userUpdates[0].SQL, `"address"`)
+	assert.Contains(t, userUpdates[1].SQL, `"address"=`)
+	assert.Contains(t, userUpdates[1].Args, "Jl. Baru")
+
+	addressUpdates := queriesContaining(recorder, `UPDATE "user_addresses"`)
+	require.Len(t, addressUpdates, 1)
+	assert.Contains(t, addressUpdates[0].SQL, `"address_line"=`)
+	assert.Contains(t, addressUpdates[0].Args, "Jl. Baru")
+	assert.Contains(t, addressUpdates[0].Args, lat)
+	assert.Empty(t, queriesContaining(recorder, `INSERT INTO "user_addresses"`))
+}
+
+func TestUserRepository_CreateCustomerCreatesDefaultAddress(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "roles"`,
+		Columns: []string{"id", "name"},
+		Rows:    [][]driver.Value{{int64(2), "Customer"}},
+	})
+	recorder.AddRows(tests.SQLRows{Match: `INSERT INTO "users"`, Columns: []string{"id"}, Rows: [][]driver.Value{{int64(7)}}})
+	// Customer baru belum punya alamat default
+	recorder.AddRows(tests.SQLRows{Match: `FROM "user_addresses"`})
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "phone"},
+		Rows:    [][]driver.Value{{int64(7), "Budi", "+6281234567890"}},
+	})
+	recorder.AddRows(tests.SQLRows{Match: `INSERT INTO "user_addresses"`, Columns: []string{"id"}, Rows: [][]driver.Value{{int64(11)}}})
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "user_addresses"`,
+		Columns: []string{"id", "user_id", "address_line", "is_default"},
+		Rows:    [][]driver.Value{{int64(11), int64(7), "Jl. Baru", true}},
+	})
+
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	id, err := repo.CreateCustomer(context.Background(), entity.UserEntity{
+		Name:    "Budi",
+		Email:   "budi@example.com",
+		Phone:   "+6281234567890",
+		Address: "Jl. Baru",
+		RoleID:  2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+
+	userInserts := queriesContaining(recorder, `INSERT INTO "users"`)
+	require.Len(t, userInserts, 1)
+	assert.NotContains(t, userInserts[0].Args, "Jl. Baru")
+
+	addressInserts := queriesContaining(recorder, `INSERT INTO "user_addresses"`)
+	require.Len(t, addressInserts, 1)
+	assert.Contains(t, addressInserts[0].Args, "Utama")
+	assert.Contains(t, addressInserts[0].Args, "Budi")
+	assert.Contains(t, addressInserts[0].Args, "Jl. Baru")
+	assert.Contains(t, addressInserts[0].Args, true)
+
+	userUpdates := queriesContaining(recorder, `UPDATE "users"`)
+	require.Len(t, userUpdates, 1)
+	assert.Contains(t, userUpdates[0].Args, "Jl. Baru")
+}
+
+func TestUserRepository_PatchUserNullAddressRemovesDefaultAddress(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "email", "address", "version"},
+		Rows:    [][]driver.Value{{int64(7), "Budi", "budi@example.com", "Jl. Lama", int64(3)}},
+	})
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "user_addresses"`,
+		Columns: []string{"id", "user_id", "address_line", "is_default"},
+		Rows:    [][]driver.Value{{int64(11), int64(7), "Jl. Lama", true}},
+	})
+	// Alamat kedua menjadi default setelah alamat default dihapus
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "user_addresses"`,
+		Columns: []string{"id", "user_id", "address_line", "is_default"},
+		Rows:    [][]driver.Value{{int64(12), int64(7), "Jl. Kantor", false}},
+	})
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "user_addresses"`,
+		Columns: []string{"id", "user_id", "address_line", "is_default"},
+		Rows:    [][]driver.Value{{int64(12), int64(7), "Jl. Kantor", true}},
+	})
+
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	require.NoError(t, repo.PatchUser(context.Background(), entity.UserPatchEntity{
+		ID:      7,
+		Address: entity.PatchField[string]{Set: true},
+	}))
+
+	userUpdates := queriesContaining(recorder, `UPDATE "users"`)
+	require.Len(t, userUpdates, 2)
+	assert.NotContains(t, userUpdates[0].SQL, `"address"`)
+	assert.Contains(t, userUpdates[1].Args, "Jl. Kantor")
+
+	// Alamat lama di-soft delete dan alamat tertua yang tersisa menjadi default
+	assert.Len(t, queriesContaining(recorder, `UPDATE "user_addresses" SET "deleted_at"=`), 1)
+	promoted := queriesContaining(recorder, `UPDATE "user_addresses" SET "is_default"=`)
+	require.Len(t, promoted, 2)
+	assert.Equal(t, []any{true, int64(12)}, []any{promoted[1].Args[0], promoted[1].Args[2]})
+}
+
+func TestUserRepository_PatchUserEmailUsedByAnotherUser(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "email", "version"},
+		Rows:    [][]driver.Value{{int64(7), "Budi", "budi@example.com", int64(3)}},
+	})
+	recorder.AddRows(tests.SQLRows{
+		Match:   `SELECT count(*) FROM "users"`,
+		Columns: []string{"count"},
+		Rows:    [][]driver.Value{{int64(1)}},
+	})
+
+	email := "siti@example.com"
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	err := repo.PatchUser(context.Background(), entity.UserPatchEntity{
+		ID:    7,
+		Email: entity.PatchField[string]{Set: true, Value: &email},
+	})
+	require.Error(t, err)
+	assert.Equal(t, "409", err.Error())
+
+	// Pengecekan mengecualikan user itu sendiri dan tidak ada update
+	counts := queriesContaining(recorder, `SELECT count(*) FROM "users"`)
+	require.Len(t, counts, 1)
+	assert.Contains(t, counts[0].SQL, "id <>")
+	assert.Equal(t, []any{email, int64(7)}, counts[0].Args)
+	assert.Empty(t, queriesContaining(recorder, `UPDATE "users"`))
+}
+
+func TestUserRepository_PatchUserKeepsOwnEmail(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "email", "version"},
+		Rows:    [][]driver.Value{{int64(7), "Budi", "budi@example.com", int64(3)}},
+	})
+
+	email := "budi@example.com"
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	require.NoError(t, repo.PatchUser(context.Background(), entity.UserPatchEntity{
+		ID:    7,
+		Email: entity.PatchField[string]{Set: true, Value: &email},
+	}))
+	assert.Empty(t, queriesContaining(recorder, `SELECT count(*) FROM "users"`))
+}
+
+func TestUserRepository_DeleteCustomerRecordsSessionRevoked(t *testing.T) {
+	db, recorder := tests.NewGormDB(t)
+	recorder.AddRows(tests.SQLRows{
+		Match:   `FROM "users"`,
+		Columns: []string{"id", "name", "email"},
+		Rows:    [][]driver.Value{{int64(7), "Budi", "budi@example.com"}},
+	})
+
+	repo := outboundadapterpostgres.NewUserRepository(db)
+	require.NoError(t, repo.DeleteCustomer(context.Background(), 7))
+
+	// user.deleted dan session.revoked ditulis ke outbox dalam transaksi yang sama
+	inserts := queriesContaining(recorder, `INSERT INTO "outbox_messages"`)
+	require.Len(t, inserts, 1)
+	assert.Contains(t, inserts[0].Args, utils.EVENT_USER_DELETED)
+	assert.Contains(t, inserts[0].Args, utils.EVENT_SESSION_REVOKED)
+	assert.Contains(t, inserts[0].SQL, "RETURNING")
+}
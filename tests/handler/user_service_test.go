@@ -0,0 +1,223 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"clean-architecture/config"
+	"clean-architecture/internal/domain/entity"
+	"clean-architecture/internal/domain/service"
+	"clean-architecture/internal/port/outbound"
+	"clean-architecture/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// patchingUserRepository mencatat patch yang diteruskan ke repository.
+type patchingUserRepository struct {
+	fakeUserRepository
+	patches []entity.UserPatchEntity
+}
+
+func (p *patchingUserRepository) PatchUser(ctx context.Context, patch entity.UserPatchEntity) error {
+	p.patches = append(p.patches, patch)
+	return nil
+}
+
+func photoPatch(callerID int64, photo string) entity.UserPatchEntity {
+	return entity.UserPatchEntity{ID: 7, CallerID: callerID, Photo: entity.PatchField[string]{Set: true, Value: &photo}}
+}
+
+func TestUserService_PatchProfileRejectsForeignPhoto(t *testing.T) {
+	photos := []string{
+		"public/uploads/users/8/photo.jpg",
+		"public/uploads/users/7/../8/photo.jpg",
+		"http://minio:9000/other-bucket/public/uploads/users/7/photo.jpg",
+		"https://example.com/photo.jpg",
+	}
+	for _, photo := range photos {
+		repo := &patchingUserRepository{fakeUserRepository: fakeUserRepository{user: &entity.UserEntity{ID: 7}}}
+		cfg := &config.Config{Minio: config.Minio{Bucket: "sayur"}}
+		userService := service.NewUserService(repo, cfg, nil, nil, nil, nil, nil)
+
+		_, err := userService.PatchProfile(context.Background(), photoPatch(7, photo))
+		require.Error(t, err, photo)
+		assert.Equal(t, "403", err.Error(), photo)
+		assert.Empty(t, repo.patches, photo)
+	}
+}
+
+func TestUserService_PatchAcceptsCallerPhoto(t *testing.T) {
+	repo := &patchingUserRepository{fakeUserRepository: fakeUserRepository{user: &entity.UserEntity{ID: 7}}}
+	cfg := &config.Config{Minio: config.Minio{Bucket: "sayur"}}
+	userService := service.NewUserService(repo, cfg, nil, nil, nil, nil, nil)
+
+	_, err := userService.PatchProfile(context.Background(),
+		photoPatch(7, "http://minio:9000/sayur/public/uploads/users/7/photo.jpg?X-Amz-Signature=abc"))
+	require.NoError(t, err)
+
+	cleared := entity.UserPatchEntity{ID: 7, CallerID: 7, Photo: entity.PatchField[string]{Set: true}}
+	_, err = userService.PatchProfile(context.Background(), cleared)
+	require.NoError(t, err)
+
+	assert.Len(t, repo.patches, 2)
+}
+
+func TestUserService_PatchCustomerCopiesAdminPhotoToCustomerFolder(t *testing.T) {
+	repo := &patchingUserRepository{fakeUserRepository: fakeUserRepository{user: &entity.UserEntity{ID: 7}}}
+	cfg := &config.Config{Minio: config.Minio{Bucket: "sayur"}}
+	storage := &fakeStorage{}
+	userService := service.NewUserService(repo, cfg, nil, nil, nil, nil, storage)
+
+	// Admin memakai foto yang diupload ke folder miliknya sendiri, foto disalin ke folder customer
+	_, err := userService.PatchCustomer(context.Background(),
+		photoPatch(1, "http://minio:9000/sayur/public/uploads/users/1/photo.jpg?X-Amz-Signature=abc"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"public/uploads/users/1/photo.jpg": "public/uploads/users/7/photo.jpg"}, storage.copied)
+
+	// Foto yang sudah berada di folder customer dipakai apa adanya
+	_, err = userService.PatchCustomer(context.Background(), photoPatch(1, "public/uploads/users/7/other.jpg"))
+	require.NoError(t, err)
+	assert.Len(t, storage.copied, 1)
+
+	require.Len(t, repo.patches, 2)
+	assert.Equal(t, "public/uploads/users/7/photo.jpg", *repo.patches[0].Photo.Value)
+	assert.Equal(t, "public/uploads/users/7/other.jpg", *repo.patches[1].Photo.Value)
+
+	_, err = userService.PatchCustomer(context.Background(), photoPatch(1, "public/uploads/users/8/photo.jpg"))
+	require.Error(t, err)
+	assert.Equal(t, "403", err.Error())
+	assert.Len(t, repo.patches, 2)
+}
+
+// deletingUserRepository mencatat customer yang di soft delete.
+type deletingUserRepository struct {
+	fakeUserRepository
+	deleted []int64
+}
+
+func (d *deletingUserRepository) DeleteCustomer(ctx context.Context, customerID int64) error {
+	d.deleted = append(d.deleted, customerID)
+	return nil
+}
+
+func TestUserService_DeleteCustomerRevokesSessions(t *testing.T) {
+	server := tests.NewRedisServer(t)
+	redis := server.Client()
+	require.NoError(t, redis.Set(context.Background(), "session-token", "7", 0).Err())
+	require.NoError(t, redis.SAdd(context.Background(), "user_sessions:7", "session-token").Err())
+
+	repo := &deletingUserRepository{}
+	userService := service.NewUserService(repo, &config.Config{}, nil, nil, redis, nil, nil)
+
+	require.NoError(t, userService.DeleteCustomer(context.Background(), 7))
+	assert.Equal(t, []int64{7}, repo.deleted)
+	assert.False(t, server.Exists("session-token"))
+	assert.False(t, server.Exists("user_sessions:7"))
+}
+
+// fakeConsentRepository satu dokumen terms yang berlaku, persetujuan dicatat per user.
+type fakeConsentRepository struct {
+	outbound.ConsentRepositoryInterface
+	mu       sync.Mutex
+	accepted map[int64][]int64
+}
+
+func (f *fakeConsentRepository) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	return []entity.LegalDocumentEntity{{ID: 3, DocType: service.LegalDocumentTerms, Version: "2"}}, nil
+}
+
+func (f *fakeConsentRepository) GetAcceptedDocumentIDs(ctx context.Context, userID int64, documentIDs []int64) ([]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.accepted[userID], nil
+}
+
+func (f *fakeConsentRepository) CreateConsents(ctx context.Context, consents []entity.ConsentEntity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.accepted == nil {
+		f.accepted = map[int64][]int64{}
+	}
+	for _, val := range consents {
+		f.accepted[val.UserID] = append(f.accepted[val.UserID], val.DocumentID)
+	}
+	return nil
+}
+
+type fakeJwtService struct {
+	service.JwtServiceInterface
+	mu     sync.Mutex
+	issued int
+}
+
+func (f *fakeJwtService) GenerateToken(userID int64) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issued++
+	return fmt.Sprintf("token-%d-%d", userID, f.issued), nil
+}
+
+// Challenge yang dikirim bersamaan hanya menghasilkan satu sesi, sisanya "404".
+func TestUserService_SignInWithConsentChallengeIsSingleUse(t *testing.T) {
+	redis := tests.NewRedisServer(t).Client()
+	consentService := service.NewConsentService(&fakeConsentRepository{}, redis)
+	jwt := &fakeJwtService{}
+	repo := &fakeUserRepository{user: &entity.UserEntity{ID: 7, Name: "Budi"}}
+	userService := service.NewUserService(repo, &config.Config{}, jwt, nil, redis, consentService, nil)
+
+	challengeToken, err := consentService.CreateChallenge(context.Background(), 7)
+	require.NoError(t, err)
+
+	const attempts = 10
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, attempts)
+	)
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := userService.SignInWithConsent(context.Background(), challengeToken, []int64{3}, "10.0.0.1")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var succeeded int
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.EqualError(t, err, "404")
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, 1, jwt.issued)
+
+	_, err = consentService.ResolveChallenge(context.Background(), challengeToken)
+	assert.EqualError(t, err, "404")
+}
+
+// Persetujuan yang belum lengkap tidak menghabiskan challenge sehingga user bisa mengulang.
+func TestUserService_SignInWithConsentKeepsChallengeOnInvalidConsent(t *testing.T) {
+	redis := tests.NewRedisServer(t).Client()
+	consentService := service.NewConsentService(&fakeConsentRepository{}, redis)
+	repo := &fakeUserRepository{user: &entity.UserEntity{ID: 7, Name: "Budi"}}
+	userService := service.NewUserService(repo, &config.Config{}, &fakeJwtService{}, nil, redis, consentService, nil)
+
+	challengeToken, err := consentService.CreateChallenge(context.Background(), 7)
+	require.NoError(t, err)
+
+	_, _, err = userService.SignInWithConsent(context.Background(), challengeToken, nil, "10.0.0.1")
+	assert.EqualError(t, err, "422")
+
+	user, token, err := userService.SignInWithConsent(context.Background(), challengeToken, []int64{3}, "10.0.0.1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), user.ID)
+	assert.NotEmpty(t, token)
+}
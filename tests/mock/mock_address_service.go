@@ -0,0 +1,43 @@
+package mock
+
+import (
+	"context"
+
+	"clean-architecture/internal/domain/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAddressService adalah mock implementasi dari service.AddressServiceInterface
+type MockAddressService struct {
+	mock.Mock
+}
+
+func (m *MockAddressService) GetAll(ctx context.Context, userID int64) ([]entity.AddressEntity, error) {
+	args := m.Called(ctx, userID)
+	addresses, _ := args.Get(0).([]entity.AddressEntity)
+	return addresses, args.Error(1)
+}
+
+func (m *MockAddressService) GetByID(ctx context.Context, userID, addressID int64) (*entity.AddressEntity, error) {
+	args := m.Called(ctx, userID, addressID)
+	address, _ := args.Get(0).(*entity.AddressEntity)
+	return address, args.Error(1)
+}
+
+func (m *MockAddressService) Create(ctx context.Context, req entity.AddressEntity) (*entity.AddressEntity, error) {
+	args := m.Called(ctx, req)
+	address, _ := args.Get(0).(*entity.AddressEntity)
+	return address, args.Error(1)
+}
+
+func (m *MockAddressService) Update(ctx context.Context, req entity.AddressEntity) (*entity.AddressEntity, error) {
+	args := m.Called(ctx, req)
+	address, _ := args.Get(0).(*entity.AddressEntity)
+	return address, args.Error(1)
+}
+
+func (m *MockAddressService) Delete(ctx context.Context, userID, addressID int64) error {
+	args := m.Called(ctx, userID, addressID)
+	return args.Error(0)
+}
@@ -0,0 +1,73 @@
+package mock
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockConsentService adalah mock implementasi dari service.ConsentServiceInterface
+type MockConsentService struct {
+	mock.Mock
+}
+
+func (m *MockConsentService) PublishDocument(ctx context.Context, req entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error) {
+	args := m.Called(ctx, req)
+	if data, ok := args.Get(0).(*entity.LegalDocumentEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockConsentService) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	args := m.Called(ctx)
+	if data, ok := args.Get(0).([]entity.LegalDocumentEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockConsentService) GetPendingDocuments(ctx context.Context, userID int64) ([]entity.LegalDocumentEntity, error) {
+	args := m.Called(ctx, userID)
+	if data, ok := args.Get(0).([]entity.LegalDocumentEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockConsentService) ResolveSignUpConsents(ctx context.Context, consents []entity.ConsentEntity) ([]entity.ConsentEntity, error) {
+	args := m.Called(ctx, consents)
+	if data, ok := args.Get(0).([]entity.ConsentEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockConsentService) AcceptDocuments(ctx context.Context, userID int64, documentIDs []int64, ipAddress string) error {
+	args := m.Called(ctx, userID, documentIDs, ipAddress)
+	return args.Error(0)
+}
+
+func (m *MockConsentService) CreateChallenge(ctx context.Context, userID int64) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockConsentService) ResolveChallenge(ctx context.Context, challengeToken string) (int64, error) {
+	args := m.Called(ctx, challengeToken)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockConsentService) ConsumeChallenge(ctx context.Context, challengeToken string) (int64, error) {
+	args := m.Called(ctx, challengeToken)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockConsentService) GetAcceptanceReport(ctx context.Context) ([]entity.ConsentReportEntity, error) {
+	args := m.Called(ctx)
+	if data, ok := args.Get(0).([]entity.ConsentReportEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
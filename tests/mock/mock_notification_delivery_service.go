@@ -0,0 +1,23 @@
+package mock
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationDeliveryService adalah mock implementasi dari service.NotificationDeliveryServiceInterface
+type MockNotificationDeliveryService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationDeliveryService) RecordDelivered(ctx context.Context, event entity.NotificationDeliveryEventEntity) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockNotificationDeliveryService) RecordFailed(ctx context.Context, event entity.NotificationDeliveryEventEntity) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
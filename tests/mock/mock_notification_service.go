@@ -0,0 +1,29 @@
+package mock
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationService adalah mock implementasi dari service.NotificationServiceInterface
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) GetAll(ctx context.Context, query entity.NotificationHistoryQueryEntity) ([]entity.NotificationHistoryEntity, int64, int64, error) {
+	args := m.Called(ctx, query)
+	if data, ok := args.Get(0).([]entity.NotificationHistoryEntity); ok {
+		return data, args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+	}
+	return nil, 0, 0, args.Error(3)
+}
+
+func (m *MockNotificationService) Resend(ctx context.Context, id int64) (*entity.NotificationHistoryEntity, error) {
+	args := m.Called(ctx, id)
+	if data, ok := args.Get(0).(*entity.NotificationHistoryEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPhoneVerificationService adalah mock implementasi dari service.PhoneVerificationServiceInterface
+type MockPhoneVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockPhoneVerificationService) SendOTP(ctx context.Context, userID int64, rawPhone string) (string, error) {
+	args := m.Called(ctx, userID, rawPhone)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPhoneVerificationService) VerifyOTP(ctx context.Context, userID int64, otp string) error {
+	args := m.Called(ctx, userID, otp)
+	return args.Error(0)
+}
@@ -0,0 +1,47 @@
+package mock
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPreferenceService adalah mock implementasi dari service.PreferenceServiceInterface
+type MockPreferenceService struct {
+	mock.Mock
+}
+
+func (m *MockPreferenceService) GetPreferences(ctx context.Context, userID int64) ([]entity.PreferenceEntity, error) {
+	args := m.Called(ctx, userID)
+	if data, ok := args.Get(0).([]entity.PreferenceEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPreferenceService) GetCustomerPreferences(ctx context.Context, customerID int64) ([]entity.PreferenceEntity, error) {
+	args := m.Called(ctx, customerID)
+	if data, ok := args.Get(0).([]entity.PreferenceEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPreferenceService) UpdatePreferences(ctx context.Context, userID int64, changes map[string]interface{}) ([]entity.PreferenceEntity, error) {
+	args := m.Called(ctx, userID, changes)
+	if data, ok := args.Get(0).([]entity.PreferenceEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPreferenceService) GetPreference(ctx context.Context, userID int64, key string) (interface{}, error) {
+	args := m.Called(ctx, userID, key)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockPreferenceService) IsNotificationEnabled(ctx context.Context, userID int64, queueName string) (bool, error) {
+	args := m.Called(ctx, userID, queueName)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,57 @@
+package mock
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+	"io"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPrivacyService adalah mock implementasi dari service.PrivacyServiceInterface
+type MockPrivacyService struct {
+	mock.Mock
+}
+
+func (m *MockPrivacyService) GetUserDataExport(ctx context.Context, userID int64) (*entity.UserDataExportEntity, error) {
+	args := m.Called(ctx, userID)
+	if data, ok := args.Get(0).(*entity.UserDataExportEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPrivacyService) OpenUserFile(ctx context.Context, path string) (io.ReadCloser, error) {
+	args := m.Called(ctx, path)
+	if data, ok := args.Get(0).(io.ReadCloser); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPrivacyService) RequestDeletion(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error) {
+	args := m.Called(ctx, userID)
+	if data, ok := args.Get(0).(*entity.DeletionRequestEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPrivacyService) GetDeletionRequest(ctx context.Context, userID int64) (*entity.DeletionRequestEntity, error) {
+	args := m.Called(ctx, userID)
+	if data, ok := args.Get(0).(*entity.DeletionRequestEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPrivacyService) CancelDeletion(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockPrivacyService) ProcessDueDeletions(ctx context.Context, now time.Time, limit int) (int, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Int(0), args.Error(1)
+}
@@ -38,3 +38,13 @@ func (m *MockRoleService) Update(ctx context.Context, req entity.RoleEntity) err
 	args := m.Called(ctx, req)
 	return args.Error(0)
 }
+
+func (m *MockRoleService) GetDeletedAll(ctx context.Context, search string) ([]entity.RoleEntity, error) {
+	args := m.Called(ctx, search)
+	return args.Get(0).([]entity.RoleEntity), args.Error(1)
+}
+
+func (m *MockRoleService) Restore(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
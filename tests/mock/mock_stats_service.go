@@ -0,0 +1,22 @@
+package mock
+
+import (
+	"clean-architecture/internal/domain/entity"
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStatsService adalah mock implementasi dari service.StatsServiceInterface
+type MockStatsService struct {
+	mock.Mock
+}
+
+func (m *MockStatsService) GetDashboardStats(ctx context.Context, from, to time.Time) (*entity.DashboardStatsEntity, error) {
+	args := m.Called(ctx, from, to)
+	if data, ok := args.Get(0).(*entity.DashboardStatsEntity); ok {
+		return data, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,132 @@
+package mock
+
+import (
+	"context"
+
+	"clean-architecture/internal/domain/entity"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserService adalah mock implementasi dari service.UserServiceInterface
+type MockUserService struct {
+	mock.Mock
+}
+
+func (m *MockUserService) SignIn(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, string, error) {
+	args := m.Called(ctx, req)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.String(1), args.Error(2)
+}
+
+func (m *MockUserService) SignInWithConsent(ctx context.Context, challengeToken string, documentIDs []int64, ipAddress string) (*entity.UserEntity, string, error) {
+	args := m.Called(ctx, challengeToken, documentIDs, ipAddress)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.String(1), args.Error(2)
+}
+
+func (m *MockUserService) CreateUserAccount(ctx context.Context, req entity.UserEntity) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ForgotPassword(ctx context.Context, req entity.UserEntity) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) VerifyToken(ctx context.Context, token string) (*entity.UserEntity, error) {
+	args := m.Called(ctx, token)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) UpdatePassword(ctx context.Context, req entity.UserEntity) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetProfileUser(ctx context.Context, userID int64) (*entity.UserEntity, error) {
+	args := m.Called(ctx, userID)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) UpdateDataUser(ctx context.Context, req entity.UserEntity) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetCustomerAll(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error) {
+	args := m.Called(ctx, query)
+	users, _ := args.Get(0).([]entity.UserEntity)
+	return users, args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockUserService) GetCustomerAllByCursor(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, *entity.CursorPageEntity, error) {
+	args := m.Called(ctx, query)
+	users, _ := args.Get(0).([]entity.UserEntity)
+	page, _ := args.Get(1).(*entity.CursorPageEntity)
+	return users, page, args.Error(2)
+}
+
+func (m *MockUserService) SearchCustomers(ctx context.Context, query entity.QueryStringEntity) ([]entity.CustomerSearchEntity, int64, int64, error) {
+	args := m.Called(ctx, query)
+	results, _ := args.Get(0).([]entity.CustomerSearchEntity)
+	return results, args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockUserService) GetCustomerNearby(ctx context.Context, query entity.NearbyQueryEntity) ([]entity.NearbyCustomerEntity, int64, int64, error) {
+	args := m.Called(ctx, query)
+	results, _ := args.Get(0).([]entity.NearbyCustomerEntity)
+	return results, args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockUserService) GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error) {
+	args := m.Called(ctx, customerID)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) CreateCustomer(ctx context.Context, req entity.UserEntity) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) UpdateCustomer(ctx context.Context, req entity.UserEntity) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockUserService) DeleteCustomer(ctx context.Context, customerID int64) error {
+	args := m.Called(ctx, customerID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) ExportCustomers(ctx context.Context, query entity.QueryStringEntity, handle func(entity.UserEntity) error) error {
+	args := m.Called(ctx, query, handle)
+	return args.Error(0)
+}
+
+func (m *MockUserService) GetDeletedCustomerAll(ctx context.Context, query entity.QueryStringEntity) ([]entity.UserEntity, int64, int64, error) {
+	args := m.Called(ctx, query)
+	users, _ := args.Get(0).([]entity.UserEntity)
+	return users, args.Get(1).(int64), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockUserService) RestoreCustomer(ctx context.Context, customerID int64) error {
+	args := m.Called(ctx, customerID)
+	return args.Error(0)
+}
+
+func (m *MockUserService) PatchProfile(ctx context.Context, patch entity.UserPatchEntity) (*entity.UserEntity, error) {
+	args := m.Called(ctx, patch)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.Error(1)
+}
+
+func (m *MockUserService) PatchCustomer(ctx context.Context, patch entity.UserPatchEntity) (*entity.UserEntity, error) {
+	args := m.Called(ctx, patch)
+	user, _ := args.Get(0).(*entity.UserEntity)
+	return user, args.Error(1)
+}
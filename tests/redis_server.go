@@ -0,0 +1,459 @@
+package tests
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisServer server Redis in-memory (RESP2) untuk test service yang memakai redis.
+// Hanya perintah yang dipakai service di repo ini yang didukung.
+type RedisServer struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	now     time.Time
+	strings map[string]string
+	sets    map[string]map[string]struct{}
+	hashes  map[string]map[string]string
+	expires map[string]time.Time
+}
+
+func NewRedisServer(t *testing.T) *RedisServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("redis server: %v", err)
+	}
+
+	s := &RedisServer{
+		listener: listener,
+		now:      time.Now(),
+		strings:  map[string]string{},
+		sets:     map[string]map[string]struct{}{},
+		hashes:   map[string]map[string]string{},
+		expires:  map[string]time.Time{},
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+// Client client go-redis yang terhubung ke server ini.
+func (s *RedisServer) Client() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:            s.listener.Addr().String(),
+		Protocol:        2,
+		DisableIdentity: true,
+	})
+}
+
+// FastForward memajukan waktu server agar key dengan TTL bisa expired tanpa menunggu.
+func (s *RedisServer) FastForward(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+// Exists true jika key masih ada (belum expired).
+func (s *RedisServer) Exists(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exists(key)
+}
+
+func (s *RedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+	var queued [][]string
+	inMulti := false
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		name := strings.ToUpper(args[0])
+		switch {
+		case name == "MULTI":
+			inMulti = true
+			queued = nil
+			writer.WriteString("+OK\r\n")
+		case name == "DISCARD":
+			inMulti = false
+			queued = nil
+			writer.WriteString("+OK\r\n")
+		case name == "EXEC":
+			inMulti = false
+			s.mu.Lock()
+			fmt.Fprintf(writer, "*%d\r\n", len(queued))
+			for _, cmd := range queued {
+				writer.WriteString(s.execute(cmd))
+			}
+			s.mu.Unlock()
+			queued = nil
+		case inMulti:
+			queued = append(queued, args)
+			writer.WriteString("+QUEUED\r\n")
+		default:
+			s.mu.Lock()
+			writer.WriteString(s.execute(args))
+			s.mu.Unlock()
+		}
+
+		if reader.Buffered() == 0 {
+			if err := writer.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(header, "$") {
+			return nil, errors.New("expected bulk string")
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args = append(args, string(data[:size]))
+	}
+	return args, nil
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *RedisServer) exists(key string) bool {
+	if at, ok := s.expires[key]; ok && !s.now.Before(at) {
+		s.delete(key)
+	}
+	_, isString := s.strings[key]
+	_, isSet := s.sets[key]
+	_, isHash := s.hashes[key]
+	return isString || isSet || isHash
+}
+
+func (s *RedisServer) delete(key string) bool {
+	_, isString := s.strings[key]
+	_, isSet := s.sets[key]
+	_, isHash := s.hashes[key]
+	delete(s.strings, key)
+	delete(s.sets, key)
+	delete(s.hashes, key)
+	delete(s.expires, key)
+	return isString || isSet || isHash
+}
+
+// execute menjalankan satu perintah, dipanggil dengan s.mu terkunci.
+func (s *RedisServer) execute(args []string) string {
+	name := strings.ToUpper(args[0])
+	for _, key := range commandKeys(name, args) {
+		s.exists(key)
+	}
+
+	switch name {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT", "CLIENT":
+		return "+OK\r\n"
+	case "GET":
+		if val, ok := s.strings[args[1]]; ok {
+			return bulk(val)
+		}
+		return "$-1\r\n"
+	case "GETDEL":
+		val, ok := s.strings[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		s.delete(args[1])
+		return bulk(val)
+	case "SET":
+		return s.set(args)
+	case "SETNX":
+		if _, ok := s.strings[args[1]]; ok {
+			return ":0\r\n"
+		}
+		s.strings[args[1]] = args[2]
+		return ":1\r\n"
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if s.delete(key) {
+				deleted++
+			}
+		}
+		return integer(int64(deleted))
+	case "EXISTS":
+		count := 0
+		for _, key := range args[1:] {
+			if s.exists(key) {
+				count++
+			}
+		}
+		return integer(int64(count))
+	case "INCR", "INCRBY":
+		by := int64(1)
+		if name == "INCRBY" {
+			by, _ = strconv.ParseInt(args[2], 10, 64)
+		}
+		current, _ := strconv.ParseInt(s.strings[args[1]], 10, 64)
+		current += by
+		s.strings[args[1]] = strconv.FormatInt(current, 10)
+		return integer(current)
+	case "EXPIRE", "PEXPIRE":
+		if !s.exists(args[1]) {
+			return ":0\r\n"
+		}
+		amount, _ := strconv.ParseInt(args[2], 10, 64)
+		unit := time.Second
+		if name == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		s.expires[args[1]] = s.now.Add(time.Duration(amount) * unit)
+		return ":1\r\n"
+	case "TTL", "PTTL":
+		if !s.exists(args[1]) {
+			return ":-2\r\n"
+		}
+		at, ok := s.expires[args[1]]
+		if !ok {
+			return ":-1\r\n"
+		}
+		if name == "PTTL" {
+			return integer(at.Sub(s.now).Milliseconds())
+		}
+		return integer(int64(at.Sub(s.now).Round(time.Second) / time.Second))
+	case "SADD":
+		set := s.sets[args[1]]
+		if set == nil {
+			set = map[string]struct{}{}
+			s.sets[args[1]] = set
+		}
+		added := 0
+		for _, member := range args[2:] {
+			if _, ok := set[member]; !ok {
+				set[member] = struct{}{}
+				added++
+			}
+		}
+		return integer(int64(added))
+	case "SREM":
+		removed := 0
+		for _, member := range args[2:] {
+			if _, ok := s.sets[args[1]][member]; ok {
+				delete(s.sets[args[1]], member)
+				removed++
+			}
+		}
+		return integer(int64(removed))
+	case "SCARD":
+		return integer(int64(len(s.sets[args[1]])))
+	case "SMEMBERS":
+		members := make([]string, 0, len(s.sets[args[1]]))
+		for member := range s.sets[args[1]] {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+		return array(members)
+	case "HSET":
+		hash := s.hashes[args[1]]
+		if hash == nil {
+			hash = map[string]string{}
+			s.hashes[args[1]] = hash
+		}
+		added := 0
+		for i := 2; i+1 < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				added++
+			}
+			hash[args[i]] = args[i+1]
+		}
+		return integer(int64(added))
+	case "HINCRBY":
+		hash := s.hashes[args[1]]
+		if hash == nil {
+			hash = map[string]string{}
+			s.hashes[args[1]] = hash
+		}
+		by, _ := strconv.ParseInt(args[3], 10, 64)
+		current, _ := strconv.ParseInt(hash[args[2]], 10, 64)
+		current += by
+		hash[args[2]] = strconv.FormatInt(current, 10)
+		return integer(current)
+	case "HGETALL":
+		fields := []string{}
+		for field, val := range s.hashes[args[1]] {
+			fields = append(fields, field, val)
+		}
+		return array(fields)
+	case "SCAN":
+		return s.scan(args)
+	}
+
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+func (s *RedisServer) set(args []string) string {
+	key, val := args[1], args[2]
+	var (
+		ttl     time.Duration
+		nx, xx  bool
+		keepTTL bool
+	)
+	for i := 3; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "EX":
+			seconds, _ := strconv.ParseInt(args[i+1], 10, 64)
+			ttl = time.Duration(seconds) * time.Second
+			i++
+		case "PX":
+			millis, _ := strconv.ParseInt(args[i+1], 10, 64)
+			ttl = time.Duration(millis) * time.Millisecond
+			i++
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "KEEPTTL":
+			keepTTL = true
+		}
+	}
+
+	_, exists := s.strings[key]
+	if (nx && exists) || (xx && !exists) {
+		return "$-1\r\n"
+	}
+
+	s.strings[key] = val
+	switch {
+	case ttl > 0:
+		s.expires[key] = s.now.Add(ttl)
+	case !keepTTL:
+		delete(s.expires, key)
+	}
+	return "+OK\r\n"
+}
+
+// scan semua key dikembalikan dalam satu halaman (cursor 0).
+func (s *RedisServer) scan(args []string) string {
+	pattern := "*"
+	for i := 2; i+1 < len(args); i++ {
+		if strings.ToUpper(args[i]) == "MATCH" {
+			pattern = args[i+1]
+		}
+	}
+
+	keys := []string{}
+	for key := range s.keys() {
+		if ok, _ := path.Match(pattern, key); ok && s.exists(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return "*2\r\n" + bulk("0") + array(keys)
+}
+
+func (s *RedisServer) keys() map[string]bool {
+	keys := map[string]bool{}
+	for key := range s.strings {
+		keys[key] = true
+	}
+	for key := range s.sets {
+		keys[key] = true
+	}
+	for key := range s.hashes {
+		keys[key] = true
+	}
+	return keys
+}
+
+// commandKeys key yang dibaca perintah, dicek expiry-nya sebelum perintah dijalankan.
+func commandKeys(name string, args []string) []string {
+	switch name {
+	case "DEL", "EXISTS":
+		return args[1:]
+	case "PING", "SELECT", "CLIENT", "SCAN":
+		return nil
+	}
+	if len(args) > 1 {
+		return args[1:2]
+	}
+	return nil
+}
+
+func bulk(val string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(val), val)
+}
+
+func integer(val int64) string {
+	return fmt.Sprintf(":%d\r\n", val)
+}
+
+func array(vals []string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "*%d\r\n", len(vals))
+	for _, val := range vals {
+		builder.WriteString(bulk(val))
+	}
+	return builder.String()
+}
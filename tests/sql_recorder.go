@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// RecordedQuery satu perintah SQL yang dikirim gorm beserta argumennya.
+type RecordedQuery struct {
+	SQL  string
+	Args []any
+}
+
+// SQLRows hasil query yang dikembalikan SQLRecorder, satu elemen Rows berisi nilai tiap kolom.
+// Match kosong berarti hasil dipakai query berikutnya apa pun SQL-nya.
+type SQLRows struct {
+	Match   string
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// SQLRecorder database palsu untuk test repository tanpa Postgres: semua perintah dicatat,
+// query mengembalikan hasil pertama dari AddRows yang Match-nya cocok (kosong jika tidak ada),
+// exec selalu berhasil.
+type SQLRecorder struct {
+	mu      sync.Mutex
+	queries []RecordedQuery
+	results []SQLRows
+}
+
+// NewGormDB gorm dengan dialect postgres yang terhubung ke SQLRecorder.
+func NewGormDB(t *testing.T) (*gorm.DB, *SQLRecorder) {
+	t.Helper()
+
+	recorder := &SQLRecorder{}
+	sqlDB := sql.OpenDB(recorder)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("gorm: %v", err)
+	}
+	return db, recorder
+}
+
+// AddRows menambahkan hasil untuk query berikutnya.
+func (r *SQLRecorder) AddRows(rows SQLRows) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, rows)
+}
+
+// Queries semua perintah yang sudah dijalankan.
+func (r *SQLRecorder) Queries() []RecordedQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedQuery(nil), r.queries...)
+}
+
+func (r *SQLRecorder) record(query string, args []driver.NamedValue) {
+	values := make([]any, 0, len(args))
+	for _, val := range args {
+		values = append(values, val.Value)
+	}
+	r.queries = append(r.queries, RecordedQuery{SQL: query, Args: values})
+}
+
+func (r *SQLRecorder) Connect(ctx context.Context) (driver.Conn, error) {
+	return &recorderConn{recorder: r}, nil
+}
+
+func (r *SQLRecorder) Driver() driver.Driver {
+	return recorderDriver{}
+}
+
+type recorderDriver struct{}
+
+func (recorderDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("use sql.OpenDB")
+}
+
+type recorderConn struct {
+	recorder *SQLRecorder
+}
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare is not supported")
+}
+
+func (c *recorderConn) Close() error {
+	return nil
+}
+
+func (c *recorderConn) Begin() (driver.Tx, error) {
+	return recorderTx{}, nil
+}
+
+func (c *recorderConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return recorderTx{}, nil
+}
+
+// CheckNamedValue semua tipe argumen diterima apa adanya.
+func (c *recorderConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *recorderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.recorder.mu.Lock()
+	defer c.recorder.mu.Unlock()
+	c.recorder.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recorderConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.recorder.mu.Lock()
+	defer c.recorder.mu.Unlock()
+	c.recorder.record(query, args)
+
+	result := SQLRows{}
+	for i, val := range c.recorder.results {
+		if val.Match == "" || strings.Contains(query, val.Match) {
+			result = val
+			c.recorder.results = append(c.recorder.results[:i], c.recorder.results[i+1:]...)
+			break
+		}
+	}
+	return &recorderRows{result: result}, nil
+}
+
+type recorderTx struct{}
+
+func (recorderTx) Commit() error   { return nil }
+func (recorderTx) Rollback() error { return nil }
+
+type recorderRows struct {
+	result SQLRows
+	next   int
+}
+
+func (r *recorderRows) Columns() []string {
+	return r.result.Columns
+}
+
+func (r *recorderRows) Close() error {
+	return nil
+}
+
+func (r *recorderRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.Rows[r.next])
+	r.next++
+	return nil
+}
@@ -6,4 +6,43 @@ const (
 	NOTIF_EMAIL_CREATE_CUSTOMER = "create_customer"
 	NOTIF_EMAIL_UPDATE_CUSTOMER = "update_customer"
 	PUSH_NOTIF                  = "push-notif"
+	NOTIF_SMS_PHONE_OTP         = "sms_phone_otp"
+	NOTIF_EMAIL_MARKETING       = "email_marketing"
+	NOTIF_SMS_MARKETING         = "sms_marketing"
+	NOTIF_PUSH_MARKETING        = "push_marketing"
+)
+
+const (
+	IMPORT_JOB_PENDING   = "pending"
+	IMPORT_JOB_RUNNING   = "running"
+	IMPORT_JOB_COMPLETED = "completed"
+	IMPORT_JOB_FAILED    = "failed"
+)
+
+// USER_UPLOAD_PREFIX folder object storage milik satu user, dipakai untuk export & erasure data.
+const USER_UPLOAD_PREFIX = "public/uploads/users/%d/"
+
+// EVENT_MESSAGE_PUBLISHED tipe event notifikasi yang dikirim ke KAFKA_TOPIC
+const EVENT_MESSAGE_PUBLISHED = "message_published"
+
+// Domain event user yang dikirim ke KAFKA_EVENT_TOPIC
+const (
+	EVENT_USER_REGISTERED  = "user.registered"
+	EVENT_USER_VERIFIED    = "user.verified"
+	EVENT_USER_UPDATED     = "user.updated"
+	EVENT_USER_DELETED     = "user.deleted"
+	EVENT_ROLE_ASSIGNED    = "role.assigned"
+	EVENT_PASSWORD_CHANGED = "password.changed"
+	EVENT_SESSION_REVOKED  = "session.revoked"
+)
+
+// Event dari notification service yang dikonsumsi worker
+const (
+	EVENT_NOTIFICATION_DELIVERED = "notification_delivered"
+	EVENT_NOTIFICATION_FAILED    = "notification_failed"
+)
+
+const (
+	DELIVERY_STATUS_DELIVERED = "delivered"
+	DELIVERY_STATUS_FAILED    = "failed"
 )
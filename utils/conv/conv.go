@@ -2,8 +2,12 @@ package conv
 
 import "strconv"
 
-func LatLngToString(f float64) string {
-	return strconv.FormatFloat(f, 'g', -1, 64)
+// LatLngToString mengubah koordinat ke string, koordinat kosong (nil) menjadi "".
+func LatLngToString(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
 }
 
 func StringToInt64(s string) (int64, error) {
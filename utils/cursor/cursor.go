@@ -0,0 +1,28 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// Encode mengubah data cursor menjadi string opaque (base64 url-safe dari JSON).
+func Encode(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode kebalikan dari Encode.
+func Decode(s string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return errors.New("invalid cursor")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errors.New("invalid cursor")
+	}
+	return nil
+}
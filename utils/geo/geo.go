@@ -0,0 +1,77 @@
+package geo
+
+import "math"
+
+const (
+	// EarthRadiusKm radius rata-rata bumi yang dipakai rumus haversine.
+	EarthRadiusKm = 6371.0
+
+	kmPerDegreeLat = math.Pi * EarthRadiusKm / 180
+)
+
+// Box area persegi berdasarkan koordinat. MinLng > MaxLng berarti box melewati antimeridian (±180).
+type Box struct {
+	MinLat float64
+	MinLng float64
+	MaxLat float64
+	MaxLng float64
+}
+
+// HaversineKm jarak dua titik dalam kilometer.
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBox box terkecil yang memuat lingkaran radiusKm dari titik (lat, lng).
+// Dipakai sebagai pre-filter yang bisa memakai index sebelum jarak haversine dihitung.
+func BoundingBox(lat, lng, radiusKm float64) Box {
+	deltaLat := radiusKm / kmPerDegreeLat
+	box := Box{
+		MinLat: math.Max(-90, lat-deltaLat),
+		MaxLat: math.Min(90, lat+deltaLat),
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	// Dekat kutub lingkaran mencakup semua longitude
+	if box.MinLat == -90 || box.MaxLat == 90 {
+		return box
+	}
+
+	deltaLng := radiusKm / (kmPerDegreeLat * math.Cos(radians(lat)))
+	if deltaLng >= 180 {
+		return box
+	}
+
+	box.MinLng = normalizeLng(lng - deltaLng)
+	box.MaxLng = normalizeLng(lng + deltaLng)
+	return box
+}
+
+// Center titik tengah box, memperhitungkan box yang melewati antimeridian.
+func (b Box) Center() (float64, float64) {
+	lat := (b.MinLat + b.MaxLat) / 2
+	if b.MinLng <= b.MaxLng {
+		return lat, (b.MinLng + b.MaxLng) / 2
+	}
+	return lat, normalizeLng((b.MinLng + b.MaxLng + 360) / 2)
+}
+
+func normalizeLng(lng float64) float64 {
+	for lng > 180 {
+		lng -= 360
+	}
+	for lng < -180 {
+		lng += 360
+	}
+	return lng
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
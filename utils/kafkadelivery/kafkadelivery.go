@@ -0,0 +1,42 @@
+package kafkadelivery
+
+import (
+	"context"
+	"errors"
+)
+
+type contextKey struct{}
+
+// ErrSkipped dilaporkan saat pesan sengaja tidak dikirim (misalnya user opt-out). Pesan dianggap
+// selesai ditangani dan tidak perlu dikirim ulang.
+var ErrSkipped = errors.New("message skipped")
+
+// ErrNoProducer dikembalikan saat service berjalan tanpa producer Kafka. Pesan belum pernah dicoba
+// dikirim sehingga tidak dihitung sebagai percobaan gagal dan tetap menunggu di outbox.
+var ErrNoProducer = errors.New("kafka producer is not configured")
+
+// Callback dipanggil tepat sekali untuk pesan yang diterima producer: err nil setelah broker
+// mengonfirmasi (ack), ErrSkipped jika pesan sengaja tidak dikirim, atau error lain jika pengiriman
+// gagal. Jika ProduceEvent langsung mengembalikan error, callback tidak dipanggil.
+type Callback func(err error)
+
+func NewContext(ctx context.Context, callback Callback) context.Context {
+	return context.WithValue(ctx, contextKey{}, callback)
+}
+
+// FromContext callback yang tersimpan di ctx, nil jika tidak ada.
+func FromContext(ctx context.Context) Callback {
+	if ctx == nil {
+		return nil
+	}
+	callback, _ := ctx.Value(contextKey{}).(Callback)
+	return callback
+}
+
+// Report memanggil callback di ctx jika ada, dipakai juga dengan ErrSkipped saat pesan sengaja
+// tidak dikirim agar pemanggil tahu pesan sudah selesai ditangani.
+func Report(ctx context.Context, err error) {
+	if callback := FromContext(ctx); callback != nil {
+		callback(err)
+	}
+}
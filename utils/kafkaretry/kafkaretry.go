@@ -0,0 +1,47 @@
+package kafkaretry
+
+import "fmt"
+
+// Header yang ditambahkan consumer saat meneruskan pesan ke retry topic / DLQ.
+// Header asli pesan tetap dibawa apa adanya.
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderAttempt           = "x-retry-attempt"
+	HeaderNotBefore         = "x-retry-not-before"
+	HeaderError             = "x-error"
+	HeaderFailedAt          = "x-failed-at"
+)
+
+var internalHeaders = []string{
+	HeaderOriginalTopic,
+	HeaderOriginalPartition,
+	HeaderOriginalOffset,
+	HeaderAttempt,
+	HeaderNotBefore,
+	HeaderError,
+	HeaderFailedAt,
+}
+
+// RetryTopic topic retry ke-attempt (dimulai dari 1) untuk topic utama, contoh notification-events.retry.1.
+func RetryTopic(topic string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", topic, attempt)
+}
+
+// DeadLetterTopic topic DLQ untuk topic utama, contoh notification-events.dlq.
+func DeadLetterTopic(topic string) string {
+	return topic + ".dlq"
+}
+
+// OriginalHeaders header pesan tanpa header retry/DLQ, dipakai saat replay ke topic utama.
+func OriginalHeaders(headers map[string]string) map[string]string {
+	result := make(map[string]string, len(headers))
+	for key, val := range headers {
+		result[key] = val
+	}
+	for _, key := range internalHeaders {
+		delete(result, key)
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package phone
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// DefaultCountryCode kode negara untuk nomor lokal (diawali 0) jika tidak dikonfigurasi.
+const DefaultCountryCode = "62"
+
+var (
+	ErrInvalidPhone = errors.New("invalid phone number")
+
+	separatorPattern = regexp.MustCompile(`[\s\-().]`)
+	e164Pattern      = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+)
+
+// NormalizeE164 mengubah nomor telepon ke format E.164 (+6281234567890).
+// Diterima: +62..., 0062..., 62... dan nomor lokal 08... (memakai countryCode).
+func NormalizeE164(raw, countryCode string) (string, error) {
+	if countryCode == "" {
+		countryCode = DefaultCountryCode
+	}
+	countryCode = strings.TrimPrefix(countryCode, "+")
+
+	number := separatorPattern.ReplaceAllString(strings.TrimSpace(raw), "")
+	switch {
+	case strings.HasPrefix(number, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = "+" + strings.TrimPrefix(number, "00")
+	case strings.HasPrefix(number, "0"):
+		number = "+" + countryCode + strings.TrimPrefix(number, "0")
+	default:
+		number = "+" + number
+	}
+
+	if !e164Pattern.MatchString(number) {
+		return "", ErrInvalidPhone
+	}
+	return number, nil
+}
@@ -0,0 +1,27 @@
+package protoschema
+
+import (
+	"context"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FileName nama file yang dipakai saat compile, schema di registry tidak punya nama file.
+const FileName = "schema.proto"
+
+// Compile compile satu schema .proto tanpa protoc. Import google/protobuf/* (Timestamp dan
+// well-known types lain) sudah tersedia.
+func Compile(source string) (protoreflect.FileDescriptor, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{FileName: source}),
+		}),
+	}
+
+	files, err := compiler.Compile(context.Background(), FileName)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
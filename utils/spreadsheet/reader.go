@@ -0,0 +1,116 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// DetectFormat menentukan format file berdasarkan ekstensi nama file.
+func DetectFormat(fileName string) (string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", errors.New("unsupported file format, use .csv or .xlsx")
+	}
+}
+
+// ErrTooManyRows file berisi lebih banyak baris data dari batas yang diberikan ke ReadRows.
+var ErrTooManyRows = errors.New("too many rows")
+
+// ReadRows membaca baris dari file CSV atau sheet pertama file XLSX. Baris pertama (header)
+// ikut dikembalikan. Pembacaan berhenti dengan ErrTooManyRows begitu jumlah baris data
+// melebihi maxRows, sehingga file besar tidak pernah dibaca seluruhnya. maxRows <= 0 berarti tanpa batas.
+func ReadRows(format string, r io.Reader, maxRows int) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		var records [][]string
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if records = append(records, record); exceedsMaxRows(records, maxRows) {
+				return nil, ErrTooManyRows
+			}
+		}
+
+	case FormatXLSX:
+		file, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("xlsx file has no sheet")
+		}
+
+		rows, err := file.Rows(sheets[0])
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var records [][]string
+		for rows.Next() {
+			record, err := rows.Columns()
+			if err != nil {
+				return nil, err
+			}
+			if records = append(records, record); exceedsMaxRows(records, maxRows) {
+				return nil, ErrTooManyRows
+			}
+		}
+		return records, rows.Error()
+
+	default:
+		return nil, errors.New("unsupported file format")
+	}
+}
+
+// exceedsMaxRows records sudah termasuk header.
+func exceedsMaxRows(records [][]string, maxRows int) bool {
+	return maxRows > 0 && len(records)-1 > maxRows
+}
+
+// HeaderIndex memetakan nama kolom header (lowercase & trim) ke index kolom.
+func HeaderIndex(header []string) map[string]int {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if key != "" {
+			index[key] = i
+		}
+	}
+	return index
+}
+
+// Cell mengambil nilai kolom berdasarkan nama header, string kosong jika tidak ada.
+func Cell(row []string, index map[string]int, name string) string {
+	i, ok := index[name]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
@@ -0,0 +1,123 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RowWriter menulis baris ke file CSV atau XLSX secara bertahap. Nilai string yang bisa
+// dibaca sebagai formula oleh aplikasi spreadsheet diawali ' (lihat escapeFormula).
+type RowWriter interface {
+	WriteRow(values []any) error
+	Flush() error
+	Close() error
+}
+
+func NewRowWriter(format string, w io.Writer) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvRowWriter{writer: csv.NewWriter(w)}, nil
+
+	case FormatXLSX:
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter("Sheet1")
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxRowWriter{file: file, stream: stream, out: w}, nil
+
+	default:
+		return nil, errors.New("unsupported file format")
+	}
+}
+
+type csvRowWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvRowWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, val := range values {
+		record[i] = escapeFormula(toString(val))
+	}
+	return c.writer.Write(record)
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvRowWriter) Close() error {
+	return c.Flush()
+}
+
+// xlsxRowWriter memakai StreamWriter excelize yang menyimpan baris ke temp file,
+// jadi memory tetap kecil walaupun jumlah baris besar. File baru dikirim saat Close.
+type xlsxRowWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+}
+
+func (x *xlsxRowWriter) WriteRow(values []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	escaped := make([]any, len(values))
+	for i, val := range values {
+		if str, ok := val.(string); ok {
+			val = escapeFormula(str)
+		}
+		escaped[i] = val
+	}
+	return x.stream.SetRow(cell, escaped)
+}
+
+func (x *xlsxRowWriter) Flush() error {
+	return nil
+}
+
+func (x *xlsxRowWriter) Close() error {
+	defer x.file.Close()
+
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+func toString(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formulaPrefixes karakter awal yang membuat Excel/LibreOffice/Sheets mengevaluasi sel sebagai formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// escapeFormula mencegah CSV/formula injection dari data user (misalnya nama "=HYPERLINK(...)").
+// Angka seperti -6.2 atau +6281234567890 tidak diubah karena tidak mungkin menjadi formula.
+func escapeFormula(val string) string {
+	if val == "" || !strings.ContainsRune(formulaPrefixes, rune(val[0])) {
+		return val
+	}
+	if _, err := strconv.ParseFloat(val, 64); err == nil {
+		return val
+	}
+	return "'" + val
+}
@@ -0,0 +1,53 @@
+package traceparent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+)
+
+// Header nama header W3C Trace Context, dipakai di HTTP maupun header pesan Kafka.
+const Header = "traceparent"
+
+var pattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+type contextKey struct{}
+
+// New traceparent baru (trace ID dan span ID acak, sampled).
+func New() string {
+	return fmt.Sprintf("00-%s-%s-01", randomHex(16), randomHex(8))
+}
+
+// Child span baru di trace yang sama dengan parent. Parent tidak valid menghasilkan trace baru.
+func Child(parent string) string {
+	match := pattern.FindStringSubmatch(parent)
+	if match == nil || match[1] == "00000000000000000000000000000000" {
+		return New()
+	}
+	return fmt.Sprintf("00-%s-%s-%s", match[1], randomHex(8), match[3])
+}
+
+func Valid(value string) bool {
+	return pattern.MatchString(value)
+}
+
+func NewContext(ctx context.Context, value string) context.Context {
+	return context.WithValue(ctx, contextKey{}, value)
+}
+
+// FromContext traceparent yang tersimpan di ctx, string kosong jika tidak ada.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	value, _ := ctx.Value(contextKey{}).(string)
+	return value
+}
+
+func randomHex(size int) string {
+	buf := make([]byte, size)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
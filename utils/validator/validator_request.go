@@ -2,6 +2,7 @@ package validator
 
 import (
 	"clean-architecture/internal/adapter/outbound/postgres/model"
+	"clean-architecture/utils/phone"
 	"context"
 	"errors"
 
@@ -16,9 +17,11 @@ type Validator struct {
 	Validator  *validator.Validate
 	Translator ut.Translator
 	DB         *gorm.DB
+	// PhoneCountryCode kode negara untuk nomor lokal (PHONE_DEFAULT_COUNTRY_CODE), sama dengan yang dipakai service
+	PhoneCountryCode string
 }
 
-func NewValidator(db *gorm.DB) *Validator {
+func NewValidator(db *gorm.DB, phoneCountryCode string) *Validator {
 	enLocale := en.New()
 	uni := ut.New(enLocale, enLocale)
 	trans, found := uni.GetTranslator("en")
@@ -29,9 +32,10 @@ func NewValidator(db *gorm.DB) *Validator {
 	validate := validator.New()
 
 	v := &Validator{
-		Validator:  validate,
-		Translator: trans,
-		DB:         db,
+		Validator:        validate,
+		Translator:       trans,
+		DB:               db,
+		PhoneCountryCode: phoneCountryCode,
 	}
 
 	// Register custom validation
@@ -64,13 +68,26 @@ func (v *Validator) registerCustomValidations() {
 	if err != nil {
 		log.Errorf("[Validator] failed to register uniqueEmail: %v", err)
 	}
+
+	err = v.Validator.RegisterValidation("phone", v.phone)
+	if err != nil {
+		log.Errorf("[Validator] failed to register phone: %v", err)
+	}
+}
+
+// phone memastikan nomor bisa dinormalisasi ke E.164, normalisasi sendiri dilakukan di service.
+func (v *Validator) phone(fl validator.FieldLevel) bool {
+	_, err := phone.NormalizeE164(fl.Field().String(), v.PhoneCountryCode)
+	return err == nil
 }
 
 func (v *Validator) uniqueEmail(fl validator.FieldLevel) bool {
 	email := fl.Field().String()
 
+	// Unscoped: email milik user yang di soft delete tetap dianggap terpakai
+	// sampai user tersebut di purge, supaya restore tidak bentrok unique index
 	var user model.User
-	err := v.DB.WithContext(context.Background()).
+	err := v.DB.WithContext(context.Background()).Unscoped().
 		Where("email = ?", email).
 		First(&user).Error
 